@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 
@@ -39,7 +40,10 @@ func main() {
 	repos := repository.NewRepositories(db)
 
 	// Initialize services
-	services := providers.NewServices(repos)
+	services := providers.NewServices(repos, cfg)
+
+	// Send queued payslip emails in the background
+	go services.PayslipMail.Run(context.Background())
 
 	// Initialize Gin router
 	r := gin.New()
@@ -66,6 +70,7 @@ func main() {
 	log.Printf("Server starting on port %s", port)
 	log.Printf("Default admin credentials: username=admin, password=admin123")
 	log.Printf("Employee credentials: username=employee1-100, password=employee1-100")
+	log.Printf("Seeded passwords have to be changed at the first login")
 
 	if err := r.Run(":" + port); err != nil {
 		log.Fatalf("Failed to start server: %v", err)
@@ -0,0 +1,179 @@
+// Package bankfile writes salary transfer batches in the upload formats accepted by banks and
+// reads the statements banks report the transfers back in
+package bankfile
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Batch is a set of salary transfers debited from one company account
+type Batch struct {
+	Reference     string // unique per file, max 35 characters
+	CreatedAt     time.Time
+	ExecutionDate time.Time
+	Currency      string
+	Description   string // remittance information printed on every transfer
+	CompanyCode   string // corporate ID assigned by the bank for bulk uploads
+	Debtor        Account
+	Transfers     []Transfer
+}
+
+// Account is a bank account and its holder
+type Account struct {
+	Name     string
+	BankCode string // key of the bank directory, e.g. BCA
+	Number   string
+}
+
+// Transfer pays one employee
+type Transfer struct {
+	Reference  string // end-to-end ID, max 35 characters
+	EmployeeID string
+	Creditor   Account
+	Amount     int64 // in minor units (cents)
+}
+
+// Totals are the control figures of a batch
+type Totals struct {
+	Count  int
+	Amount int64 // in minor units
+	// AccountHash is the sum of all creditor account numbers, modulo 10^15; banks
+	// use it to detect account numbers changed after the file was approved
+	AccountHash int64
+}
+
+// File is a written batch
+type File struct {
+	Format      string
+	Filename    string
+	ContentType string
+	Data        []byte
+	Totals      Totals
+	Checksum    string // SHA-256 of Data, hex encoded
+}
+
+// Format writes a batch in one bank upload format
+type Format interface {
+	Name() string
+	ContentType() string
+	Extension() string
+	Write(batch *Batch, totals Totals) ([]byte, error)
+}
+
+var formats = map[string]Format{}
+
+func register(format Format) {
+	formats[format.Name()] = format
+}
+
+// Formats lists the names of the supported formats
+func Formats() []string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Write validates the batch and writes it in the named format
+func Write(format string, batch *Batch) (*File, error) {
+	writer, ok := formats[format]
+	if !ok {
+		return nil, fmt.Errorf("unsupported disbursement format %q, use one of %s", format, strings.Join(Formats(), ", "))
+	}
+
+	if err := batch.Validate(); err != nil {
+		return nil, err
+	}
+
+	totals := batch.Totals()
+	data, err := writer.Write(batch, totals)
+	if err != nil {
+		return nil, err
+	}
+
+	checksum := sha256.Sum256(data)
+	return &File{
+		Format:      writer.Name(),
+		Filename:    fmt.Sprintf("%s-%s.%s", strings.ToLower(batch.Reference), writer.Name(), writer.Extension()),
+		ContentType: writer.ContentType(),
+		Data:        data,
+		Totals:      totals,
+		Checksum:    hex.EncodeToString(checksum[:]),
+	}, nil
+}
+
+// Validate checks the batch can be paid out
+func (b *Batch) Validate() error {
+	if b.Reference == "" || len(b.Reference) > 35 {
+		return errors.New("batch reference must be 1 to 35 characters")
+	}
+	if b.Debtor.Number == "" {
+		return errors.New("company debit account is not configured")
+	}
+	if len(b.Transfers) == 0 {
+		return errors.New("batch has no transfers")
+	}
+
+	for _, transfer := range b.Transfers {
+		if transfer.Amount <= 0 {
+			return fmt.Errorf("transfer %s: amount must be positive", transfer.Reference)
+		}
+		if !isDigits(transfer.Creditor.Number) {
+			return fmt.Errorf("transfer %s: invalid account number", transfer.Reference)
+		}
+		if len(transfer.Reference) > 35 {
+			return fmt.Errorf("transfer %s: reference exceeds 35 characters", transfer.Reference)
+		}
+	}
+	return nil
+}
+
+// Totals computes the control figures of the batch
+func (b *Batch) Totals() Totals {
+	const hashModulus = 1_000_000_000_000_000
+
+	totals := Totals{Count: len(b.Transfers)}
+	for _, transfer := range b.Transfers {
+		totals.Amount += transfer.Amount
+		totals.AccountHash = (totals.AccountHash + accountNumberValue(transfer.Creditor.Number)%hashModulus) % hashModulus
+	}
+	return totals
+}
+
+// FormatAmount renders minor units as a decimal amount, e.g. 500000050 -> 5000000.50
+func FormatAmount(amount int64) string {
+	return fmt.Sprintf("%d.%02d", amount/100, amount%100)
+}
+
+// accountNumberValue is the numeric value of the last 15 digits of an account number
+func accountNumberValue(number string) int64 {
+	if len(number) > 15 {
+		number = number[len(number)-15:]
+	}
+
+	var value int64
+	for _, digit := range number {
+		value = value*10 + int64(digit-'0')
+	}
+	return value
+}
+
+func isDigits(value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,328 @@
+package bankfile
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testBatch() *Batch {
+	return &Batch{
+		Reference:     "SAL202408A1B2C3D4",
+		CreatedAt:     time.Date(2024, 9, 1, 8, 30, 0, 0, time.UTC),
+		ExecutionDate: time.Date(2024, 9, 2, 0, 0, 0, 0, time.UTC),
+		Currency:      "IDR",
+		Description:   "Salary Aug 2024",
+		CompanyCode:   "MINIPAY",
+		Debtor:        Account{Name: "PT Mini Payroll Indonesia", BankCode: "BCA", Number: "1234567890"},
+		Transfers: []Transfer{
+			{Reference: "7f7c4a5e0b8d4b0c9e1f2a3b4c5d6e7f", EmployeeID: "employee1", Creditor: Account{Name: "Budi Santoso", BankCode: "BCA", Number: "0987654321"}, Amount: 500000050},
+			{Reference: "1a2b3c4d5e6f40718293a4b5c6d7e8f9", EmployeeID: "employee2", Creditor: Account{Name: "Siti Rahayu", BankCode: "MANDIRI", Number: "1230004567890"}, Amount: 375000000},
+		},
+	}
+}
+
+// bcaBatch has only BCA creditors, as the BCA payroll upload requires
+func bcaBatch() *Batch {
+	batch := testBatch()
+	batch.Transfers[1].Creditor = Account{Name: "Siti Rahayu", BankCode: "BCA", Number: "1230004567"}
+	return batch
+}
+
+func TestBatch_Totals(t *testing.T) {
+	totals := testBatch().Totals()
+
+	assert.Equal(t, 2, totals.Count)
+	assert.Equal(t, int64(875000050), totals.Amount)
+	assert.Equal(t, int64(987654321+1230004567890), totals.AccountHash)
+}
+
+func TestWrite(t *testing.T) {
+	for _, format := range Formats() {
+		t.Run(format, func(t *testing.T) {
+			batch := testBatch()
+			if format == "bca" {
+				batch = bcaBatch()
+			}
+
+			file, err := Write(format, batch)
+			require.NoError(t, err)
+
+			checksum := sha256.Sum256(file.Data)
+			assert.Equal(t, hex.EncodeToString(checksum[:]), file.Checksum)
+			assert.Equal(t, 2, file.Totals.Count)
+			assert.True(t, strings.HasPrefix(file.Filename, "sal202408a1b2c3d4-"+format+"."))
+		})
+	}
+
+	_, err := Write("swift-mt101", testBatch())
+	assert.ErrorContains(t, err, "unsupported disbursement format")
+}
+
+func TestWrite_Validation(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(batch *Batch)
+	}{
+		{name: "no transfers", modify: func(batch *Batch) { batch.Transfers = nil }},
+		{name: "no debit account", modify: func(batch *Batch) { batch.Debtor.Number = "" }},
+		{name: "zero amount", modify: func(batch *Batch) { batch.Transfers[0].Amount = 0 }},
+		{name: "non-numeric account", modify: func(batch *Batch) { batch.Transfers[1].Creditor.Number = "123-456" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batch := testBatch()
+			tt.modify(batch)
+
+			_, err := Write("csv", batch)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCSVFormat(t *testing.T) {
+	file, err := Write("csv", testBatch())
+	require.NoError(t, err)
+
+	rows, err := csv.NewReader(strings.NewReader(string(file.Data))).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 4)
+
+	assert.Equal(t, []string{"D", "1", "7f7c4a5e0b8d4b0c9e1f2a3b4c5d6e7f", "employee1", "Budi Santoso", "BCA", "CENAIDJA", "0987654321", "5000000.50", "IDR", "Salary Aug 2024"}, rows[1])
+	assert.Equal(t, "BMRIIDJA", rows[2][6])
+	assert.Equal(t, []string{"T", "2", "SAL202408A1B2C3D4", "", "PT Mini Payroll Indonesia", "BCA", "", "1230992222211", "8750000.50", "IDR", "2024-09-02"}, rows[3])
+}
+
+func TestPain001Format(t *testing.T) {
+	file, err := Write("pain001", testBatch())
+	require.NoError(t, err)
+
+	var document struct {
+		GroupHeader struct {
+			MessageID   string `xml:"MsgId"`
+			NumberOfTxs int    `xml:"NbOfTxs"`
+			ControlSum  string `xml:"CtrlSum"`
+			Initiator   string `xml:"InitgPty>Id>OrgId>Othr>Id"`
+		} `xml:"CstmrCdtTrfInitn>GrpHdr"`
+		PaymentInfo struct {
+			ControlSum    string `xml:"CtrlSum"`
+			Category      string `xml:"PmtTpInf>CtgyPurp>Cd"`
+			ExecutionDate string `xml:"ReqdExctnDt"`
+			DebtorAccount string `xml:"DbtrAcct>Id>Othr>Id"`
+			DebtorBIC     string `xml:"DbtrAgt>FinInstnId>BIC"`
+			Transfers     []struct {
+				EndToEndID string `xml:"PmtId>EndToEndId"`
+				Amount     struct {
+					Currency string `xml:"Ccy,attr"`
+					Value    string `xml:",chardata"`
+				} `xml:"Amt>InstdAmt"`
+				CreditorBIC string `xml:"CdtrAgt>FinInstnId>BIC"`
+				Account     string `xml:"CdtrAcct>Id>Othr>Id"`
+			} `xml:"CdtTrfTxInf"`
+		} `xml:"CstmrCdtTrfInitn>PmtInf"`
+	}
+	require.NoError(t, xml.Unmarshal(file.Data, &document))
+	assert.Contains(t, string(file.Data), `xmlns="urn:iso:std:iso:20022:tech:xsd:pain.001.001.03"`)
+
+	assert.Equal(t, "SAL202408A1B2C3D4", document.GroupHeader.MessageID)
+	assert.Equal(t, 2, document.GroupHeader.NumberOfTxs)
+	assert.Equal(t, "8750000.50", document.GroupHeader.ControlSum)
+	assert.Equal(t, "MINIPAY", document.GroupHeader.Initiator)
+	assert.Equal(t, "8750000.50", document.PaymentInfo.ControlSum)
+	assert.Equal(t, "SALA", document.PaymentInfo.Category)
+	assert.Equal(t, "2024-09-02", document.PaymentInfo.ExecutionDate)
+	assert.Equal(t, "1234567890", document.PaymentInfo.DebtorAccount)
+	assert.Equal(t, "CENAIDJA", document.PaymentInfo.DebtorBIC)
+
+	require.Len(t, document.PaymentInfo.Transfers, 2)
+	second := document.PaymentInfo.Transfers[1]
+	assert.Equal(t, "1a2b3c4d5e6f40718293a4b5c6d7e8f9", second.EndToEndID)
+	assert.Equal(t, "IDR", second.Amount.Currency)
+	assert.Equal(t, "3750000.00", second.Amount.Value)
+	assert.Equal(t, "BMRIIDJA", second.CreditorBIC)
+	assert.Equal(t, "1230004567890", second.Account)
+}
+
+func TestFixedWidthFormats(t *testing.T) {
+	tests := []struct {
+		format       string
+		batch        *Batch
+		recordLength int
+		records      int
+		wantHeader   string
+		wantDetail   string
+	}{
+		{
+			format:       "bca",
+			batch:        bcaBatch(),
+			recordLength: 100,
+			records:      3,
+			wantHeader:   "0MINIPAY   020920241234567890" + "00002" + "000000875000050" + "000002217658888" + "SAL202408A1B2C3D4   ",
+			wantDetail:   "1" + "00001" + "0987654321" + "000000500000050" + "EMPLOYEE1 " + "BUDI SANTOSO                  " + "SALARY AUG 2024   ",
+		},
+		{
+			format:       "mandiri",
+			batch:        testBatch(),
+			recordLength: 200,
+			records:      4,
+			wantHeader:   "H20240902" + "1234567890   " + "000002" + "00000000875000050" + "IDR",
+			wantDetail:   "D" + "0987654321          " + "BUDI SANTOSO                       " + "IDR" + "00000000500000050" + "014    " + "CENAIDJA   ",
+		},
+		{
+			format:       "bni",
+			batch:        testBatch(),
+			recordLength: 120,
+			records:      4,
+			wantHeader:   "HMINIPAY   20240902" + "1234567890" + "00002" + "00000000875000050",
+			wantDetail:   "D" + "00001" + "0987654321     " + "BUDI SANTOSO" + strings.Repeat(" ", 28) + "00000000500000050" + "014",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			file, err := Write(tt.format, tt.batch)
+			require.NoError(t, err)
+
+			records := strings.Split(strings.TrimSuffix(string(file.Data), "\r\n"), "\r\n")
+			require.Len(t, records, tt.records)
+			for _, record := range records {
+				assert.Len(t, record, tt.recordLength)
+			}
+			assert.True(t, strings.HasPrefix(records[0], tt.wantHeader), records[0])
+			assert.True(t, strings.HasPrefix(records[1], tt.wantDetail), records[1])
+		})
+	}
+}
+
+func TestFixedWidthFormats_Errors(t *testing.T) {
+	_, err := Write("bca", testBatch())
+	assert.ErrorContains(t, err, "only credits BCA accounts")
+
+	// BCA accounts are 10 digits; a longer number must not be cut
+	batch := bcaBatch()
+	batch.Transfers[0].Creditor.Number = "12345678901234567890"
+	_, err = Write("bca", batch)
+	assert.ErrorContains(t, err, "does not fit")
+}
+
+func TestParseStatement_CSV(t *testing.T) {
+	data := "\ufeffDate,Transaction_ID,Reference,Account_Number,Amount,Status,Reason\n" +
+		"2024-09-02,TRX001,7f7c4a5e0b8d4b0c9e1f2a3b4c5d6e7f,0987654321,-5000000.50,success,\n" +
+		"2024-09-02,TRX002,1a2b3c4d5e6f40718293a4b5c6d7e8f9,1230004567890,\"3,750,000.00\",rejected,AC04\n" +
+		"2024-09-05,TRX003,,,1200000,returned,\n"
+
+	entries, err := ParseStatement("", []byte(data))
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	date := time.Date(2024, 9, 2, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, StatementEntry{TransactionID: "TRX001", Reference: "7f7c4a5e0b8d4b0c9e1f2a3b4c5d6e7f", Account: "0987654321", Amount: 500000050, Status: EntryPaid, BookingDate: &date}, entries[0])
+	assert.Equal(t, int64(375000000), entries[1].Amount)
+	assert.Equal(t, EntryFailed, entries[1].Status)
+	assert.Equal(t, "AC04", entries[1].Reason)
+	assert.Equal(t, EntryReturned, entries[2].Status)
+	assert.Equal(t, int64(120000000), entries[2].Amount)
+
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{name: "missing reference column", data: "amount\n100\n", want: "no reference column"},
+		{name: "invalid amount", data: "reference,amount\nabc,12.345\n", want: "line 2: invalid amount"},
+		{name: "unknown status", data: "reference,amount,status\nabc,100,pending\n", want: "unknown status"},
+		{name: "invalid date", data: "reference,amount,date\nabc,100,02/09/2024\n", want: "invalid date"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseStatement("csv", []byte(tt.data))
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+
+	_, err = ParseStatement("mt940", []byte(data))
+	assert.ErrorContains(t, err, "unsupported statement format")
+}
+
+func TestParseStatement_Camt053(t *testing.T) {
+	data := `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+  <BkToCstmrStmt>
+    <Stmt>
+      <Ntry>
+        <Amt Ccy="IDR">8750000.50</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <Sts>BOOK</Sts>
+        <BookgDt><Dt>2024-09-02</Dt></BookgDt>
+        <AcctSvcrRef>STMT-1</AcctSvcrRef>
+        <NtryDtls>
+          <TxDtls>
+            <Refs><EndToEndId>7f7c4a5e0b8d4b0c9e1f2a3b4c5d6e7f</EndToEndId></Refs>
+            <AmtDtls><TxAmt><Amt Ccy="IDR">5000000.50</Amt></TxAmt></AmtDtls>
+            <RltdPties><CdtrAcct><Id><Othr><Id>0987654321</Id></Othr></Id></CdtrAcct></RltdPties>
+          </TxDtls>
+          <TxDtls>
+            <Refs><AcctSvcrRef>STMT-1-2</AcctSvcrRef><EndToEndId>1a2b3c4d5e6f40718293a4b5c6d7e8f9</EndToEndId></Refs>
+            <AmtDtls><TxAmt><Amt Ccy="IDR">3750000.00</Amt></TxAmt></AmtDtls>
+          </TxDtls>
+        </NtryDtls>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="IDR">3750000.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Sts>BOOK</Sts>
+        <BookgDt><Dt>2024-09-04</Dt></BookgDt>
+        <AcctSvcrRef>STMT-2</AcctSvcrRef>
+        <NtryDtls>
+          <TxDtls>
+            <Refs><EndToEndId>1a2b3c4d5e6f40718293a4b5c6d7e8f9</EndToEndId></Refs>
+            <RtrInf><Rsn><Cd>AC04</Cd></Rsn></RtrInf>
+          </TxDtls>
+        </NtryDtls>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="IDR">25000000.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Sts>BOOK</Sts>
+        <AcctSvcrRef>STMT-3</AcctSvcrRef>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="IDR">100000.00</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <Sts>PDNG</Sts>
+        <AcctSvcrRef>STMT-4</AcctSvcrRef>
+      </Ntry>
+    </Stmt>
+  </BkToCstmrStmt>
+</Document>`
+
+	entries, err := ParseStatement("", []byte(data))
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	assert.Equal(t, "STMT-1/1", entries[0].TransactionID)
+	assert.Equal(t, "7f7c4a5e0b8d4b0c9e1f2a3b4c5d6e7f", entries[0].Reference)
+	assert.Equal(t, "0987654321", entries[0].Account)
+	assert.Equal(t, int64(500000050), entries[0].Amount)
+	assert.Equal(t, EntryPaid, entries[0].Status)
+	assert.Equal(t, "2024-09-02", entries[0].BookingDate.Format("2006-01-02"))
+
+	assert.Equal(t, "STMT-1-2", entries[1].TransactionID)
+	assert.Equal(t, int64(375000000), entries[1].Amount)
+
+	assert.Equal(t, "STMT-2", entries[2].TransactionID)
+	assert.Equal(t, EntryReturned, entries[2].Status)
+	assert.Equal(t, "AC04", entries[2].Reason)
+	assert.Equal(t, int64(375000000), entries[2].Amount)
+
+	_, err = ParseStatement("camt053", []byte("<Document></Document>"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,41 @@
+package bankfile
+
+import (
+	"sort"
+	"strings"
+)
+
+// Bank identifies a bank in transfer files
+type Bank struct {
+	Code         string `json:"code"`
+	Name         string `json:"name"`
+	BIC          string `json:"bic"`
+	ClearingCode string `json:"clearing_code"` // Bank Indonesia (SKNBI) bank code
+}
+
+var banks = map[string]Bank{
+	"BCA":     {Code: "BCA", Name: "Bank Central Asia", BIC: "CENAIDJA", ClearingCode: "014"},
+	"MANDIRI": {Code: "MANDIRI", Name: "Bank Mandiri", BIC: "BMRIIDJA", ClearingCode: "008"},
+	"BNI":     {Code: "BNI", Name: "Bank Negara Indonesia", BIC: "BNINIDJA", ClearingCode: "009"},
+	"BRI":     {Code: "BRI", Name: "Bank Rakyat Indonesia", BIC: "BRINIDJA", ClearingCode: "002"},
+	"CIMB":    {Code: "CIMB", Name: "CIMB Niaga", BIC: "BNIAIDJA", ClearingCode: "022"},
+	"PERMATA": {Code: "PERMATA", Name: "Bank Permata", BIC: "BBBAIDJA", ClearingCode: "013"},
+	"DANAMON": {Code: "DANAMON", Name: "Bank Danamon", BIC: "BDINIDJA", ClearingCode: "011"},
+	"BSI":     {Code: "BSI", Name: "Bank Syariah Indonesia", BIC: "BSMDIDJA", ClearingCode: "451"},
+}
+
+// LookupBank finds a bank by its code, case-insensitively
+func LookupBank(code string) (Bank, bool) {
+	bank, ok := banks[strings.ToUpper(code)]
+	return bank, ok
+}
+
+// Banks lists the supported banks ordered by code
+func Banks() []Bank {
+	list := make([]Bank, 0, len(banks))
+	for _, bank := range banks {
+		list = append(list, bank)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Code < list[j].Code })
+	return list
+}
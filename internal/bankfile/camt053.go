@@ -0,0 +1,152 @@
+package bankfile
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// camt.053 bank-to-customer statement. Elements are matched without namespace, so every
+// message version from camt.053.001.02 on is read.
+type camtDocument struct {
+	Statements []camtStatement `xml:"BkToCstmrStmt>Stmt"`
+}
+
+type camtStatement struct {
+	Entries []camtEntry `xml:"Ntry"`
+}
+
+type camtEntry struct {
+	Reference    string            `xml:"NtryRef"`
+	Amount       string            `xml:"Amt"`
+	CreditDebit  string            `xml:"CdtDbtInd"`
+	Reversal     bool              `xml:"RvslInd"`
+	Status       camtStatus        `xml:"Sts"`
+	BookingDate  camtDate          `xml:"BookgDt"`
+	ServicerRef  string            `xml:"AcctSvcrRef"`
+	Transactions []camtTransaction `xml:"NtryDtls>TxDtls"`
+}
+
+// camtStatus is a plain code up to camt.053.001.07 and a Cd element from version 08
+type camtStatus struct {
+	Value string `xml:",chardata"`
+	Code  string `xml:"Cd"`
+}
+
+func (s camtStatus) code() string {
+	if s.Code != "" {
+		return strings.TrimSpace(s.Code)
+	}
+	return strings.TrimSpace(s.Value)
+}
+
+type camtDate struct {
+	Date     string `xml:"Dt"`
+	DateTime string `xml:"DtTm"`
+}
+
+func (d camtDate) time() *time.Time {
+	if d.Date != "" {
+		if date, err := time.Parse("2006-01-02", d.Date); err == nil {
+			return &date
+		}
+	}
+	if len(d.DateTime) >= 10 {
+		if date, err := time.Parse("2006-01-02", d.DateTime[:10]); err == nil {
+			return &date
+		}
+	}
+	return nil
+}
+
+type camtTransaction struct {
+	ServicerRef string `xml:"Refs>AcctSvcrRef"`
+	EndToEndID  string `xml:"Refs>EndToEndId"`
+	Amount      string `xml:"Amt"`               // camt.053.001.04 and later
+	TxAmount    string `xml:"AmtDtls>TxAmt>Amt"` // camt.053.001.02
+	Account     string `xml:"RltdPties>CdtrAcct>Id>Othr>Id"`
+	Return      string `xml:"RtrInf>Rsn>Cd"`
+}
+
+// parseCamt053 reads the booked entries of a camt.053 statement. Batch-booked entries yield one
+// StatementEntry per transaction. Debits are paid transfers; credits only count when they return a
+// transfer, which the bank marks with return information or the reversal indicator.
+func parseCamt053(data []byte) ([]StatementEntry, error) {
+	var document camtDocument
+	if err := xml.NewDecoder(bytes.NewReader(data)).Decode(&document); err != nil {
+		return nil, fmt.Errorf("invalid camt.053 statement: %w", err)
+	}
+	if len(document.Statements) == 0 {
+		return nil, errors.New("invalid camt.053 statement: no BkToCstmrStmt/Stmt element")
+	}
+
+	var entries []StatementEntry
+	for _, statement := range document.Statements {
+		for _, ntry := range statement.Entries {
+			if code := ntry.Status.code(); code != "" && code != "BOOK" {
+				continue
+			}
+
+			transactions := ntry.Transactions
+			if len(transactions) == 0 {
+				transactions = []camtTransaction{{}}
+			}
+
+			for i, tx := range transactions {
+				status := EntryPaid
+				switch {
+				case ntry.Reversal, tx.Return != "":
+					status = EntryReturned
+				case ntry.CreditDebit == "CRDT":
+					continue
+				}
+
+				value := firstNonEmpty(tx.Amount, tx.TxAmount)
+				if value == "" {
+					if len(transactions) > 1 {
+						return nil, fmt.Errorf("entry %s: transaction %d has no amount", ntry.ServicerRef, i+1)
+					}
+					value = ntry.Amount
+				}
+
+				amount, err := parseAmount(strings.TrimSpace(value))
+				if err != nil {
+					return nil, fmt.Errorf("entry %s: %w", ntry.ServicerRef, err)
+				}
+
+				transactionID := firstNonEmpty(tx.ServicerRef, ntry.ServicerRef, ntry.Reference)
+				if len(transactions) > 1 && tx.ServicerRef == "" {
+					transactionID = fmt.Sprintf("%s/%d", transactionID, i+1)
+				}
+
+				reference := strings.TrimSpace(tx.EndToEndID)
+				if reference == "NOTPROVIDED" {
+					reference = ""
+				}
+
+				entries = append(entries, StatementEntry{
+					TransactionID: transactionID,
+					Reference:     reference,
+					Account:       strings.TrimSpace(tx.Account),
+					Amount:        amount,
+					Status:        status,
+					BookingDate:   ntry.BookingDate.time(),
+					Reason:        strings.TrimSpace(tx.Return),
+				})
+			}
+		}
+	}
+	return entries, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,61 @@
+package bankfile
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+)
+
+func init() {
+	register(csvFormat{})
+}
+
+// csvFormat is a generic CSV with one D (detail) row per transfer and a closing T (trailer) row with the totals
+type csvFormat struct{}
+
+func (csvFormat) Name() string        { return "csv" }
+func (csvFormat) ContentType() string { return "text/csv" }
+func (csvFormat) Extension() string   { return "csv" }
+
+func (csvFormat) Write(batch *Batch, totals Totals) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+
+	rows := [][]string{{"record_type", "sequence", "reference", "employee_id", "account_name", "bank_code", "bic", "account_number", "amount", "currency", "description"}}
+	for i, transfer := range batch.Transfers {
+		bank, _ := LookupBank(transfer.Creditor.BankCode)
+		rows = append(rows, []string{
+			"D",
+			strconv.Itoa(i + 1),
+			transfer.Reference,
+			transfer.EmployeeID,
+			transfer.Creditor.Name,
+			bank.Code,
+			bank.BIC,
+			transfer.Creditor.Number,
+			FormatAmount(transfer.Amount),
+			batch.Currency,
+			batch.Description,
+		})
+	}
+
+	// The trailer carries the control figures in the count, account number and amount columns
+	rows = append(rows, []string{
+		"T",
+		strconv.Itoa(totals.Count),
+		batch.Reference,
+		"",
+		batch.Debtor.Name,
+		batch.Debtor.BankCode,
+		"",
+		strconv.FormatInt(totals.AccountHash, 10),
+		FormatAmount(totals.Amount),
+		batch.Currency,
+		batch.ExecutionDate.Format("2006-01-02"),
+	})
+
+	if err := writer.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
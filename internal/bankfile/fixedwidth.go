@@ -0,0 +1,234 @@
+package bankfile
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	register(bcaFormat)
+	register(mandiriFormat)
+	register(bniFormat)
+}
+
+// fixedWidthFormat writes a header record, one detail record per transfer and an optional trailer record,
+// each padded to recordLength and terminated by CRLF
+type fixedWidthFormat struct {
+	name         string
+	recordLength int
+	// onlyBank restricts the creditors to accounts at one bank, for in-house payroll uploads
+	onlyBank string
+	header   func(batch *Batch, totals Totals) []field
+	detail   func(batch *Batch, sequence int, transfer Transfer) []field
+	trailer  func(batch *Batch, totals Totals) []field // nil when the format has none
+}
+
+// field is one fixed-width column. Numeric fields are right aligned and zero padded, text fields
+// left aligned, space padded, upper-cased and cut to width. Identifiers are text that must not be cut.
+type field struct {
+	value      string
+	width      int
+	numeric    bool
+	identifier bool
+}
+
+func text(value string, width int) field {
+	return field{value: value, width: width}
+}
+
+// identifier is an account number, code or reference; a value too long for the field is an error
+func identifier(value string, width int) field {
+	return field{value: value, width: width, identifier: true}
+}
+
+func number(value int64, width int) field {
+	return field{value: strconv.FormatInt(value, 10), width: width, numeric: true}
+}
+
+// amount is an amount in minor units with two implied decimals
+func amount(value int64, width int) field {
+	return number(value, width)
+}
+
+func (f fixedWidthFormat) Name() string        { return f.name }
+func (f fixedWidthFormat) ContentType() string { return "text/plain" }
+func (f fixedWidthFormat) Extension() string   { return "txt" }
+
+func (f fixedWidthFormat) Write(batch *Batch, totals Totals) ([]byte, error) {
+	if f.onlyBank != "" {
+		for _, transfer := range batch.Transfers {
+			if bank, _ := LookupBank(transfer.Creditor.BankCode); bank.Code != f.onlyBank {
+				return nil, fmt.Errorf("transfer %s: the %s format only credits %s accounts, use csv or pain001 for other banks", transfer.Reference, f.name, f.onlyBank)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+
+	records := [][]field{f.header(batch, totals)}
+	for i, transfer := range batch.Transfers {
+		records = append(records, f.detail(batch, i+1, transfer))
+	}
+	if f.trailer != nil {
+		records = append(records, f.trailer(batch, totals))
+	}
+
+	for i, record := range records {
+		line, err := f.record(record)
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", i+1, err)
+		}
+		buf.WriteString(line + "\r\n")
+	}
+	return buf.Bytes(), nil
+}
+
+func (f fixedWidthFormat) record(fields []field) (string, error) {
+	var line strings.Builder
+	for _, field := range fields {
+		if field.numeric {
+			if len(field.value) > field.width {
+				return "", fmt.Errorf("value %s does not fit in %d digits", field.value, field.width)
+			}
+			line.WriteString(strings.Repeat("0", field.width-len(field.value)) + field.value)
+			continue
+		}
+
+		value := asciiUpper(field.value)
+		if len(value) > field.width {
+			if field.identifier {
+				return "", fmt.Errorf("%s does not fit in %d characters", field.value, field.width)
+			}
+			value = value[:field.width]
+		}
+		line.WriteString(value + strings.Repeat(" ", field.width-len(value)))
+	}
+
+	if line.Len() > f.recordLength {
+		return "", fmt.Errorf("record is %d characters, longer than %d", line.Len(), f.recordLength)
+	}
+	return line.String() + strings.Repeat(" ", f.recordLength-line.Len()), nil
+}
+
+// asciiUpper upper-cases text and replaces everything but printable ASCII, which bank hosts reject
+func asciiUpper(value string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(value) {
+		if r < ' ' || r > '~' {
+			r = ' '
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// bcaFormat follows the layout of the BCA (KlikBCA Bisnis) payroll upload: a header and
+// 100 character detail records without a trailer. It only credits BCA accounts.
+var bcaFormat = fixedWidthFormat{
+	name:         "bca",
+	recordLength: 100,
+	onlyBank:     "BCA",
+	header: func(batch *Batch, totals Totals) []field {
+		return []field{
+			text("0", 1),
+			identifier(batch.CompanyCode, 10),
+			text(batch.ExecutionDate.Format("02012006"), 8),
+			identifier(batch.Debtor.Number, 10),
+			number(int64(totals.Count), 5),
+			amount(totals.Amount, 15),
+			number(totals.AccountHash, 15),
+			identifier(batch.Reference, 20),
+		}
+	},
+	detail: func(batch *Batch, sequence int, transfer Transfer) []field {
+		return []field{
+			text("1", 1),
+			number(int64(sequence), 5),
+			identifier(transfer.Creditor.Number, 10),
+			amount(transfer.Amount, 15),
+			text(transfer.EmployeeID, 10),
+			text(transfer.Creditor.Name, 30),
+			text(batch.Description, 18),
+		}
+	},
+}
+
+// mandiriFormat follows the layout of the Mandiri (MCM) bulk transfer upload: header, 200 character
+// detail records carrying the beneficiary bank for interbank transfers, and a trailer
+var mandiriFormat = fixedWidthFormat{
+	name:         "mandiri",
+	recordLength: 200,
+	header: func(batch *Batch, totals Totals) []field {
+		return []field{
+			text("H", 1),
+			text(batch.ExecutionDate.Format("20060102"), 8),
+			identifier(batch.Debtor.Number, 13),
+			number(int64(totals.Count), 6),
+			amount(totals.Amount, 17),
+			text(batch.Currency, 3),
+			identifier(batch.Reference, 35),
+			identifier(batch.CompanyCode, 20),
+		}
+	},
+	detail: func(batch *Batch, sequence int, transfer Transfer) []field {
+		bank, _ := LookupBank(transfer.Creditor.BankCode)
+		return []field{
+			text("D", 1),
+			identifier(transfer.Creditor.Number, 20),
+			text(transfer.Creditor.Name, 35),
+			text(batch.Currency, 3),
+			amount(transfer.Amount, 17),
+			text(bank.ClearingCode, 7),
+			text(bank.BIC, 11),
+			text(batch.Description, 40),
+			identifier(transfer.Reference, 35),
+		}
+	},
+	trailer: func(batch *Batch, totals Totals) []field {
+		return []field{
+			text("T", 1),
+			number(int64(totals.Count), 6),
+			amount(totals.Amount, 17),
+			number(totals.AccountHash, 15),
+		}
+	},
+}
+
+// bniFormat follows the layout of the BNI Direct payroll upload: header, 120 character detail records and a trailer
+var bniFormat = fixedWidthFormat{
+	name:         "bni",
+	recordLength: 120,
+	header: func(batch *Batch, totals Totals) []field {
+		return []field{
+			text("H", 1),
+			identifier(batch.CompanyCode, 10),
+			text(batch.ExecutionDate.Format("20060102"), 8),
+			identifier(batch.Debtor.Number, 10),
+			number(int64(totals.Count), 5),
+			amount(totals.Amount, 17),
+			identifier(batch.Reference, 35),
+		}
+	},
+	detail: func(batch *Batch, sequence int, transfer Transfer) []field {
+		bank, _ := LookupBank(transfer.Creditor.BankCode)
+		return []field{
+			text("D", 1),
+			number(int64(sequence), 5),
+			identifier(transfer.Creditor.Number, 15),
+			text(transfer.Creditor.Name, 40),
+			amount(transfer.Amount, 17),
+			text(bank.ClearingCode, 3),
+			identifier(transfer.Reference, 35),
+		}
+	},
+	trailer: func(batch *Batch, totals Totals) []field {
+		return []field{
+			text("T", 1),
+			number(int64(totals.Count), 5),
+			amount(totals.Amount, 17),
+			number(totals.AccountHash, 15),
+		}
+	},
+}
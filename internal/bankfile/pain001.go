@@ -0,0 +1,174 @@
+package bankfile
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+)
+
+func init() {
+	register(pain001Format{})
+}
+
+// pain001Format is an ISO 20022 customer credit transfer initiation (pain.001.001.03) with one
+// batch-booked payment information block categorized as salary (SALA)
+type pain001Format struct{}
+
+func (pain001Format) Name() string        { return "pain001" }
+func (pain001Format) ContentType() string { return "application/xml" }
+func (pain001Format) Extension() string   { return "xml" }
+
+type painDocument struct {
+	XMLName  xml.Name       `xml:"urn:iso:std:iso:20022:tech:xsd:pain.001.001.03 Document"`
+	Initiate painInitiation `xml:"CstmrCdtTrfInitn"`
+}
+
+type painInitiation struct {
+	GroupHeader painGroupHeader `xml:"GrpHdr"`
+	PaymentInfo painPaymentInfo `xml:"PmtInf"`
+}
+
+type painGroupHeader struct {
+	MessageID       string    `xml:"MsgId"`
+	CreatedAt       string    `xml:"CreDtTm"`
+	NumberOfTxs     int       `xml:"NbOfTxs"`
+	ControlSum      string    `xml:"CtrlSum"`
+	InitiatingParty painParty `xml:"InitgPty"`
+}
+
+type painPaymentInfo struct {
+	ID            string               `xml:"PmtInfId"`
+	Method        string               `xml:"PmtMtd"`
+	BatchBooking  bool                 `xml:"BtchBookg"`
+	NumberOfTxs   int                  `xml:"NbOfTxs"`
+	ControlSum    string               `xml:"CtrlSum"`
+	PaymentType   painPaymentType      `xml:"PmtTpInf"`
+	ExecutionDate string               `xml:"ReqdExctnDt"`
+	Debtor        painParty            `xml:"Dbtr"`
+	DebtorAccount painAccount          `xml:"DbtrAcct"`
+	DebtorAgent   painAgent            `xml:"DbtrAgt"`
+	ChargeBearer  string               `xml:"ChrgBr"`
+	Transfers     []painCreditTransfer `xml:"CdtTrfTxInf"`
+}
+
+type painPaymentType struct {
+	CategoryPurpose painCode `xml:"CtgyPurp"`
+}
+
+type painCode struct {
+	Code string `xml:"Cd"`
+}
+
+type painParty struct {
+	Name           string     `xml:"Nm"`
+	OrganisationID *painOther `xml:"Id>OrgId>Othr,omitempty"`
+}
+
+type painAccount struct {
+	Other    painOther `xml:"Id>Othr"`
+	Currency string    `xml:"Ccy,omitempty"`
+}
+
+type painOther struct {
+	ID string `xml:"Id"`
+}
+
+type painAgent struct {
+	BIC   string     `xml:"FinInstnId>BIC,omitempty"`
+	Other *painOther `xml:"FinInstnId>Othr,omitempty"`
+}
+
+type painCreditTransfer struct {
+	PaymentID       painPaymentID `xml:"PmtId"`
+	Amount          painAmount    `xml:"Amt>InstdAmt"`
+	CreditorAgent   painAgent     `xml:"CdtrAgt"`
+	Creditor        painParty     `xml:"Cdtr"`
+	CreditorAccount painAccount   `xml:"CdtrAcct"`
+	Purpose         painCode      `xml:"Purp"`
+	Remittance      string        `xml:"RmtInf>Ustrd,omitempty"`
+}
+
+type painPaymentID struct {
+	EndToEndID string `xml:"EndToEndId"`
+}
+
+type painAmount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+func (pain001Format) Write(batch *Batch, totals Totals) ([]byte, error) {
+	controlSum := FormatAmount(totals.Amount)
+
+	payment := painPaymentInfo{
+		ID:            batch.Reference,
+		Method:        "TRF",
+		BatchBooking:  true,
+		NumberOfTxs:   totals.Count,
+		ControlSum:    controlSum,
+		PaymentType:   painPaymentType{CategoryPurpose: painCode{Code: "SALA"}},
+		ExecutionDate: batch.ExecutionDate.Format("2006-01-02"),
+		Debtor:        painParty{Name: truncate(batch.Debtor.Name, 70)},
+		DebtorAccount: painAccount{Other: painOther{ID: batch.Debtor.Number}, Currency: batch.Currency},
+		DebtorAgent:   painAgentFor(batch.Debtor.BankCode),
+		ChargeBearer:  "SLEV",
+	}
+
+	for _, transfer := range batch.Transfers {
+		payment.Transfers = append(payment.Transfers, painCreditTransfer{
+			PaymentID:       painPaymentID{EndToEndID: transfer.Reference},
+			Amount:          painAmount{Currency: batch.Currency, Value: FormatAmount(transfer.Amount)},
+			CreditorAgent:   painAgentFor(transfer.Creditor.BankCode),
+			Creditor:        painParty{Name: truncate(transfer.Creditor.Name, 70)},
+			CreditorAccount: painAccount{Other: painOther{ID: transfer.Creditor.Number}},
+			Purpose:         painCode{Code: "SALA"},
+			Remittance:      truncate(batch.Description, 140),
+		})
+	}
+
+	initiatingParty := painParty{Name: truncate(batch.Debtor.Name, 70)}
+	if batch.CompanyCode != "" {
+		initiatingParty.OrganisationID = &painOther{ID: batch.CompanyCode}
+	}
+
+	document := painDocument{
+		Initiate: painInitiation{
+			GroupHeader: painGroupHeader{
+				MessageID:       batch.Reference,
+				CreatedAt:       batch.CreatedAt.Format("2006-01-02T15:04:05"),
+				NumberOfTxs:     totals.Count,
+				ControlSum:      controlSum,
+				InitiatingParty: initiatingParty,
+			},
+			PaymentInfo: payment,
+		},
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return nil, err
+	}
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+// painAgentFor identifies a bank by BIC, or by the code it was given when it is not in the directory
+func painAgentFor(bankCode string) painAgent {
+	if bank, ok := LookupBank(bankCode); ok {
+		return painAgent{BIC: bank.BIC}
+	}
+	return painAgent{Other: &painOther{ID: bankCode}}
+}
+
+// truncate shortens text to max runes, the length limits of the ISO 20022 text fields
+func truncate(text string, max int) string {
+	text = strings.TrimSpace(text)
+	runes := []rune(text)
+	if len(runes) > max {
+		return string(runes[:max])
+	}
+	return text
+}
@@ -0,0 +1,160 @@
+package bankfile
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Outcomes of a transfer as reported by the bank
+const (
+	EntryPaid     = "paid"
+	EntryFailed   = "failed"
+	EntryReturned = "returned"
+)
+
+// StatementEntry is one transfer on a bank statement or upload confirmation
+type StatementEntry struct {
+	TransactionID string // the bank's reference of the booking
+	Reference     string // end-to-end ID given in the disbursement file
+	Account       string // creditor account number, when the bank reports it
+	Amount        int64  // in minor units, always positive
+	Status        string // EntryPaid, EntryFailed or EntryReturned
+	BookingDate   *time.Time
+	Reason        string // rejection or return reason, e.g. AC04
+}
+
+// StatementFormats lists the statement formats ParseStatement reads
+func StatementFormats() []string {
+	return []string{"camt053", "csv"}
+}
+
+// DetectStatementFormat tells the statement formats apart by content: XML is camt.053, anything else CSV
+func DetectStatementFormat(data []byte) string {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("<")) {
+		return "camt053"
+	}
+	return "csv"
+}
+
+// ParseStatement reads the salary transfers from a bank statement. An empty format is detected from the content.
+func ParseStatement(format string, data []byte) ([]StatementEntry, error) {
+	if format == "" {
+		format = DetectStatementFormat(data)
+	}
+
+	switch format {
+	case "csv":
+		return parseCSVStatement(data)
+	case "camt053":
+		return parseCamt053(data)
+	default:
+		return nil, fmt.Errorf("unsupported statement format %q, use one of %s", format, strings.Join(StatementFormats(), ", "))
+	}
+}
+
+// parseCSVStatement reads a CSV with a header row. The reference and amount columns are required;
+// status, transaction_id, date, account_number and reason are optional. Rows without a status are paid.
+func parseCSVStatement(data []byte) ([]StatementEntry, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("statement is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV statement: %w", err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{"reference", "amount"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("statement has no %s column", required)
+		}
+	}
+
+	column := func(row []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	var entries []StatementEntry
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV statement: %w", err)
+		}
+
+		amount, err := parseAmount(column(row, "amount"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		status, err := csvEntryStatus(column(row, "status"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		entry := StatementEntry{
+			TransactionID: column(row, "transaction_id"),
+			Reference:     column(row, "reference"),
+			Account:       column(row, "account_number"),
+			Amount:        amount,
+			Status:        status,
+			Reason:        column(row, "reason"),
+		}
+		if value := column(row, "date"); value != "" {
+			date, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid date %q, use YYYY-MM-DD", line, value)
+			}
+			entry.BookingDate = &date
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func csvEntryStatus(value string) (string, error) {
+	switch strings.ToLower(value) {
+	case "", "paid", "success", "ok", "booked", "completed":
+		return EntryPaid, nil
+	case "failed", "rejected", "error":
+		return EntryFailed, nil
+	case "returned", "reversed":
+		return EntryReturned, nil
+	default:
+		return "", fmt.Errorf("unknown status %q", value)
+	}
+}
+
+// parseAmount reads a decimal amount such as 5000000.50 or -5000000.5 into minor units, ignoring the sign
+func parseAmount(value string) (int64, error) {
+	value = strings.TrimLeft(strings.ReplaceAll(value, ",", ""), "+-")
+	whole, fraction, _ := strings.Cut(value, ".")
+	if len(fraction) > 2 || !isDigits(whole) || (fraction != "" && !isDigits(fraction)) {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+	cents, _ := strconv.ParseInt((fraction + "00")[:2], 10, 64)
+	return units*100 + cents, nil
+}
@@ -1,10 +1,17 @@
 package api
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 	"time"
 
-	"payslip-system/internal/middleware"
+	"payslip-system/internal/bankfile"
+	"payslip-system/internal/domains"
+	"payslip-system/internal/i18n"
+	"payslip-system/internal/models"
 	"payslip-system/internal/providers"
 
 	"github.com/gin-gonic/gin"
@@ -26,15 +33,26 @@ type LoginRequest struct {
 }
 
 type LoginResponse struct {
-	Token string `json:"token"`
-	User  struct {
-		ID       uuid.UUID `json:"id"`
-		Username string    `json:"username"`
-		Role     string    `json:"role"`
+	domains.TokenPair
+	User struct {
+		ID          uuid.UUID `json:"id"`
+		Username    string    `json:"username"`
+		Role        string    `json:"role"` // the role the user was created with
+		Roles       []string  `json:"roles"`
+		Permissions []string  `json:"permissions"`
 	} `json:"user"`
+	// RecoveryCodes are returned once, by the login that completed MFA enrollment
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+	// PasswordChangeRequired means the session can only change the password until it is changed
+	PasswordChangeRequired bool `json:"password_change_required,omitempty"`
+}
+
+// MFAChallengeResponse is the login response of users who need a second factor
+type MFAChallengeResponse struct {
+	MFARequired bool `json:"mfa_required"`
+	domains.MFAChallenge
 }
 
-// Auth handlers
 func (h *Handlers) Login(c *gin.Context) {
 	var req LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -42,95 +60,187 @@ func (h *Handlers) Login(c *gin.Context) {
 		return
 	}
 
-	user, _, err := h.services.Auth.Login(req.Username, req.Password)
+	user, challenge, err := h.services.Auth.Login(req.Username, req.Password, c.ClientIP(), c.GetString("request_id"))
+	var throttled *domains.LoginThrottledError
+	if errors.As(err, &throttled) {
+		c.Header("Retry-After", strconv.Itoa(throttled.RetryAfterSeconds()))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error(), "retry_after": throttled.RetryAfterSeconds()})
+		return
+	}
+	if errors.Is(err, domains.ErrPasswordLoginDisabled) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
 	}
 
-	token, err := middleware.GenerateToken(user)
+	if challenge != nil {
+		c.JSON(http.StatusOK, MFAChallengeResponse{MFARequired: true, MFAChallenge: *challenge})
+		return
+	}
+
+	h.startSession(c, user, nil)
+}
+
+// BeginSSOLogin starts a single sign-on login. The client sends the user to the authorization URL and keeps
+// the state to check against the one the identity provider returns.
+func (h *Handlers) BeginSSOLogin(c *gin.Context) {
+	login, err := h.services.SSO.BeginLogin(c.ClientIP())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		respondSSOError(c, err)
 		return
 	}
 
-	response := LoginResponse{
-		Token: token,
+	c.JSON(http.StatusOK, login)
+}
+
+type SSOCallbackRequest struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+}
+
+// CompleteSSOLogin redeems the code the identity provider redirected back with. The response is that of a
+// password login: the tokens, or an MFA challenge.
+func (h *Handlers) CompleteSSOLogin(c *gin.Context) {
+	var req SSOCallbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
-	response.User.ID = user.ID
-	response.User.Username = user.Username
-	response.User.Role = user.Role
 
-	c.JSON(http.StatusOK, response)
+	user, challenge, err := h.services.SSO.CompleteLogin(req.Code, req.State, c.ClientIP(), c.GetString("request_id"))
+	if err != nil {
+		respondSSOError(c, err)
+		return
+	}
+
+	if challenge != nil {
+		c.JSON(http.StatusOK, MFAChallengeResponse{MFARequired: true, MFAChallenge: *challenge})
+		return
+	}
+
+	h.startSession(c, user, nil)
 }
 
-// Attendance requests
-type SubmitAttendanceRequest struct {
-	Date        string `json:"date" binding:"required"`          // YYYY-MM-DD format
-	CheckInTime string `json:"check_in_time" binding:"required"` // HH:MM format
+func respondSSOError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domains.ErrSSODisabled):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domains.ErrSSONoRole):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, domains.ErrInvalidSSOLogin), errors.Is(err, domains.ErrSSOFailed), errors.Is(err, domains.ErrSSONoUser):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
 }
 
-func (h *Handlers) SubmitAttendance(c *gin.Context) {
-	var req SubmitAttendanceRequest
+type MFALoginRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required"` // TOTP or recovery code
+}
+
+// CompleteMFALogin answers the MFA challenge of a login and issues the tokens
+func (h *Handlers) CompleteMFALogin(c *gin.Context) {
+	var req MFALoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	userID := c.MustGet("user_id").(uuid.UUID)
-	clientIP := "-"
-	// Use this safer approach:
-	clientIPRaw, exists := c.Get("client_ip")
-	if exists {
-		clientIPStr, ok := clientIPRaw.(string)
-		if ok {
-			clientIP = clientIPStr
-		}
+	user, recoveryCodes, err := h.services.Auth.CompleteMFAChallenge(req.MFAToken, req.Code, c.ClientIP(), c.GetString("request_id"))
+	var throttled *domains.LoginThrottledError
+	if errors.As(err, &throttled) {
+		c.Header("Retry-After", strconv.Itoa(throttled.RetryAfterSeconds()))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error(), "retry_after": throttled.RetryAfterSeconds()})
+		return
 	}
-	requestID := "-"
-	// Use this safer approach:
-	requestIDRaw, exists := c.Get("request_id")
-	if exists {
-		requestIDStr, ok := requestIDRaw.(string)
-		if ok {
-			requestID = requestIDStr
+	if err != nil {
+		status := http.StatusUnauthorized
+		if errors.Is(err, domains.ErrMFANotEnrolled) {
+			status = http.StatusBadRequest
 		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
 	}
 
-	// Parse date
-	date, err := time.Parse("2006-01-02", req.Date)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format, use YYYY-MM-DD"})
+	h.startSession(c, user, recoveryCodes)
+}
+
+type MFAEnrollLoginRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+}
+
+// EnrollMFALogin sets up MFA for an admin whose login challenge requires enrollment
+func (h *Handlers) EnrollMFALogin(c *gin.Context) {
+	var req MFAEnrollLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Parse check-in time
-	checkInTime, err := time.Parse("15:04", req.CheckInTime)
+	enrollment, err := h.services.Auth.EnrollMFAChallenge(req.MFAToken)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid check-in time format, use HH:MM"})
+		respondMFAError(c, err)
 		return
 	}
 
-	// Combine date and time
-	checkInDateTime := time.Date(date.Year(), date.Month(), date.Day(),
-		checkInTime.Hour(), checkInTime.Minute(), 0, 0, date.Location())
+	c.JSON(http.StatusOK, enrollment)
+}
 
-	if err := h.services.Attendance.SubmitAttendance(userID, date, checkInDateTime, clientIP, requestID); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+// startSession issues the tokens of a login
+func (h *Handlers) startSession(c *gin.Context, user *models.User, recoveryCodes []string) {
+	clientIP := c.ClientIP()
+	requestID := c.GetString("request_id")
+
+	tokens, err := h.services.Auth.StartSession(user, c.Request.UserAgent(), clientIP, requestID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"message": "Attendance submitted successfully"})
+	roles, err := h.services.Role.GetUserRoles(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load roles"})
+		return
+	}
+
+	response := LoginResponse{
+		TokenPair:              *tokens,
+		RecoveryCodes:          recoveryCodes,
+		PasswordChangeRequired: user.MustChangePassword,
+	}
+	response.User.ID = user.ID
+	response.User.Username = user.Username
+	response.User.Role = user.Role
+	response.User.Roles = roles.Roles
+	response.User.Permissions = roles.Permissions
+
+	c.JSON(http.StatusOK, response)
 }
 
-// Overtime requests
-type SubmitOvertimeRequest struct {
-	Date  string  `json:"date" binding:"required"` // YYYY-MM-DD format
-	Hours float64 `json:"hours" binding:"required,gt=0,lte=3"`
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required"`
 }
 
-func (h *Handlers) SubmitOvertime(c *gin.Context) {
-	var req SubmitOvertimeRequest
+// EnrollMFA starts setting up an authenticator app for the current user
+func (h *Handlers) EnrollMFA(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	enrollment, err := h.services.MFA.Enroll(userID)
+	if err != nil {
+		respondMFAError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// ConfirmMFA enables MFA with a code from the enrolled app and returns the recovery codes
+func (h *Handlers) ConfirmMFA(c *gin.Context) {
+	var req MFACodeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -140,29 +250,39 @@ func (h *Handlers) SubmitOvertime(c *gin.Context) {
 	clientIP := c.MustGet("client_ip").(string)
 	requestID := c.MustGet("request_id").(string)
 
-	// Parse date
-	date, err := time.Parse("2006-01-02", req.Date)
+	codes, err := h.services.MFA.Confirm(userID, req.Code, clientIP, requestID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format, use YYYY-MM-DD"})
+		respondMFAError(c, err)
 		return
 	}
 
-	if err := h.services.Overtime.SubmitOvertime(userID, date, req.Hours, clientIP, requestID); err != nil {
+	c.JSON(http.StatusOK, gin.H{"message": "MFA enabled", "recovery_codes": codes})
+}
+
+// RegenerateRecoveryCodes replaces the current user's recovery codes
+func (h *Handlers) RegenerateRecoveryCodes(c *gin.Context) {
+	var req MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"message": "Overtime submitted successfully"})
-}
+	userID := c.MustGet("user_id").(uuid.UUID)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
 
-// Reimbursement requests
-type SubmitReimbursementRequest struct {
-	Amount      float64 `json:"amount" binding:"required,gt=0"`
-	Description string  `json:"description" binding:"required"`
+	codes, err := h.services.MFA.RegenerateRecoveryCodes(userID, req.Code, clientIP, requestID)
+	if err != nil {
+		respondMFAError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"recovery_codes": codes})
 }
 
-func (h *Handlers) SubmitReimbursement(c *gin.Context) {
-	var req SubmitReimbursementRequest
+// DisableMFA turns MFA off for an employee
+func (h *Handlers) DisableMFA(c *gin.Context) {
+	var req MFACodeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -172,108 +292,1799 @@ func (h *Handlers) SubmitReimbursement(c *gin.Context) {
 	clientIP := c.MustGet("client_ip").(string)
 	requestID := c.MustGet("request_id").(string)
 
-	if err := h.services.Reimbursement.SubmitReimbursement(userID, req.Amount, req.Description, clientIP, requestID); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if err := h.services.MFA.Disable(userID, req.Code, clientIP, requestID); err != nil {
+		respondMFAError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"message": "Reimbursement submitted successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "MFA disabled"})
 }
 
-// Payslip generation
-func (h *Handlers) GeneratePayslip(c *gin.Context) {
-	userID := c.MustGet("user_id").(uuid.UUID)
-	periodIDStr := c.Param("period_id")
-
-	periodID, err := uuid.Parse(periodIDStr)
+// ListLockouts returns the usernames and IP addresses locked out after failed logins
+func (h *Handlers) ListLockouts(c *gin.Context) {
+	lockouts, err := h.services.Auth.ListLockouts()
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid period ID"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	payslip, err := h.services.Payroll.GeneratePayslip(userID, periodID)
+	c.JSON(http.StatusOK, gin.H{"lockouts": lockouts})
+}
+
+// UnlockUser lifts the lockout of a user
+func (h *Handlers) UnlockUser(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	adminID := c.MustGet("user_id").(uuid.UUID)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
+
+	if err := h.services.Auth.UnlockUser(userID, adminID, clientIP, requestID); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, payslip)
+	c.JSON(http.StatusOK, gin.H{"message": "User unlocked"})
 }
 
-// Admin handlers
-type CreateAttendancePeriodRequest struct {
-	StartDate string `json:"start_date" binding:"required"` // YYYY-MM-DD format
-	EndDate   string `json:"end_date" binding:"required"`   // YYYY-MM-DD format
+// Unlock lifts a lockout of a username or an IP address
+func (h *Handlers) Unlock(c *gin.Context) {
+	lockoutID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid lockout ID"})
+		return
+	}
+
+	adminID := c.MustGet("user_id").(uuid.UUID)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
+
+	if err := h.services.Auth.Unlock(lockoutID, adminID, clientIP, requestID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Lockout lifted"})
 }
 
-func (h *Handlers) CreateAttendancePeriod(c *gin.Context) {
-	var req CreateAttendancePeriodRequest
+func respondMFAError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domains.ErrInvalidMFACode), errors.Is(err, domains.ErrInvalidMFAChallenge):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, domains.ErrMFAMandatory):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, domains.ErrMFAAlreadyEnabled):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// Refresh exchanges a refresh token for a new access and refresh token
+func (h *Handlers) Refresh(c *gin.Context) {
+	var req RefreshRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	adminID := c.MustGet("user_id").(uuid.UUID)
+	clientIP := c.ClientIP()
+	requestID := c.GetString("request_id")
+
+	tokens, err := h.services.Auth.Refresh(req.RefreshToken, c.Request.UserAgent(), clientIP, requestID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+func (h *Handlers) Logout(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	sessionID := c.MustGet("session_id").(uuid.UUID)
 	clientIP := c.MustGet("client_ip").(string)
 	requestID := c.MustGet("request_id").(string)
 
-	// Parse dates
-	startDate, err := time.Parse("2006-01-02", req.StartDate)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start date format, use YYYY-MM-DD"})
+	if err := h.services.Auth.Logout(sessionID, userID, clientIP, requestID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out"})
+}
+
+// RevokeSessions signs a user out of every session
+func (h *Handlers) RevokeSessions(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end date format, use YYYY-MM-DD"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
 		return
 	}
 
-	period, err := h.services.Admin.CreateAttendancePeriod(startDate, endDate, adminID, clientIP, requestID)
+	adminID := c.MustGet("user_id").(uuid.UUID)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
+
+	revoked, err := h.services.Auth.RevokeSessions(userID, adminID, clientIP, requestID)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, period)
+	c.JSON(http.StatusOK, gin.H{"message": "Sessions revoked", "revoked": revoked})
 }
 
-func (h *Handlers) ProcessPayroll(c *gin.Context) {
-	periodIDStr := c.Param("period_id")
-	periodID, err := uuid.Parse(periodIDStr)
+type ImpersonateRequest struct {
+	Reason string `json:"reason" binding:"required"` // e.g. the support ticket
+}
+
+// Impersonate issues a read-only token for viewing the API as a user
+func (h *Handlers) Impersonate(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid period ID"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req ImpersonateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	adminID := c.MustGet("user_id").(uuid.UUID)
+	sessionID := c.MustGet("session_id").(uuid.UUID)
 	clientIP := c.MustGet("client_ip").(string)
 	requestID := c.MustGet("request_id").(string)
 
-	if err := h.services.Payroll.ProcessPayroll(periodID, adminID, clientIP, requestID); err != nil {
+	token, err := h.services.Auth.Impersonate(userID, adminID, sessionID, req.Reason, clientIP, requestID)
+	if err != nil {
+		if errors.Is(err, domains.ErrImpersonationNotAllowed) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Payroll processed successfully"})
+	c.JSON(http.StatusOK, token)
 }
 
-func (h *Handlers) GeneratePayrollSummary(c *gin.Context) {
-	periodIDStr := c.Param("period_id")
-	periodID, err := uuid.Parse(periodIDStr)
+// ListActivity returns the caller's activity history, including admins viewing as them
+func (h *Handlers) ListActivity(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	entries, err := h.services.Auth.ListActivity(userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid period ID"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	summary, err := h.services.Payroll.GeneratePayrollSummary(periodID)
+	c.JSON(http.StatusOK, gin.H{"activity": entries})
+}
+
+// GetJWKS publishes the public keys access tokens are verified with
+func (h *Handlers) GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.services.Tokens.JWKS())
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+// ChangePassword sets a new password for the current user, signing out their other sessions
+func (h *Handlers) ChangePassword(c *gin.Context) {
+	var req ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	sessionID := c.MustGet("session_id").(uuid.UUID)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
+
+	err := h.services.Password.ChangePassword(userID, sessionID, req.CurrentPassword, req.NewPassword, clientIP, requestID)
 	if err != nil {
+		respondPasswordError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password changed"})
+}
+
+type ForgotPasswordRequest struct {
+	Username string `json:"username" binding:"required"`
+}
+
+// ForgotPassword queues an email with a reset token. The response is the same whether or not the user exists.
+func (h *Handlers) ForgotPassword(c *gin.Context) {
+	var req ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, summary)
+	err := h.services.Password.RequestReset(req.Username, c.ClientIP(), c.GetString("request_id"))
+	var throttled *domains.ResetThrottledError
+	if errors.As(err, &throttled) {
+		c.Header("Retry-After", strconv.Itoa(throttled.RetryAfterSeconds()))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error(), "retry_after": throttled.RetryAfterSeconds()})
+		return
+	}
+	if errors.Is(err, domains.ErrPasswordLoginDisabled) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to request password reset"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "If the user has an email address, a reset token has been sent to it"})
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+// ResetPassword sets a new password with a reset token, signing the user out everywhere
+func (h *Handlers) ResetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.services.Password.ResetPassword(req.Token, req.NewPassword, c.ClientIP(), c.GetString("request_id")); err != nil {
+		respondPasswordError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Password reset"})
+}
+
+// AdminResetPassword signs a user out and makes them set a new password
+func (h *Handlers) AdminResetPassword(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	adminID := c.MustGet("user_id").(uuid.UUID)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
+
+	result, err := h.services.Password.AdminReset(userID, adminID, clientIP, requestID)
+	if err != nil {
+		respondPasswordError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ListPermissions returns every permission roles can grant
+func (h *Handlers) ListPermissions(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"permissions": h.services.Role.ListPermissions()})
+}
+
+func (h *Handlers) ListRoles(c *gin.Context) {
+	roles, err := h.services.Role.ListRoles()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"roles": roles})
+}
+
+func (h *Handlers) CreateRole(c *gin.Context) {
+	var req domains.RoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	adminID := c.MustGet("user_id").(uuid.UUID)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
+
+	role, err := h.services.Role.CreateRole(req, adminID, clientIP, requestID)
+	if err != nil {
+		respondRoleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, role)
+}
+
+// UpdateRole replaces the description and permissions of a role
+func (h *Handlers) UpdateRole(c *gin.Context) {
+	roleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role ID"})
+		return
+	}
+
+	var req domains.RoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	adminID := c.MustGet("user_id").(uuid.UUID)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
+
+	role, err := h.services.Role.UpdateRole(roleID, req, adminID, clientIP, requestID)
+	if err != nil {
+		respondRoleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, role)
+}
+
+func (h *Handlers) DeleteRole(c *gin.Context) {
+	roleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role ID"})
+		return
+	}
+
+	adminID := c.MustGet("user_id").(uuid.UUID)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
+
+	if err := h.services.Role.DeleteRole(roleID, adminID, clientIP, requestID); err != nil {
+		respondRoleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Role deleted"})
+}
+
+// GetUserRoles returns the roles a user holds and the permissions they add up to
+func (h *Handlers) GetUserRoles(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	roles, err := h.services.Role.GetUserRoles(userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, roles)
+}
+
+type SetUserRolesRequest struct {
+	Roles []string `json:"roles" binding:"required"`
+}
+
+// SetUserRoles replaces the roles a user holds
+func (h *Handlers) SetUserRoles(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req SetUserRolesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	adminID := c.MustGet("user_id").(uuid.UUID)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
+
+	roles, err := h.services.Role.SetUserRoles(userID, req.Roles, adminID, clientIP, requestID)
+	if err != nil {
+		respondRoleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, roles)
+}
+
+func respondRoleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domains.ErrRoleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domains.ErrBuiltinRole):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, domains.ErrRoleExists), errors.Is(err, domains.ErrRoleInUse), errors.Is(err, domains.ErrLastRoleManager):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
+
+func (h *Handlers) ListAPIKeys(c *gin.Context) {
+	keys, err := h.services.APIKey.ListAPIKeys()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"api_keys": keys})
+}
+
+// CreateAPIKey issues an API key; the response is the only time the key is shown
+func (h *Handlers) CreateAPIKey(c *gin.Context) {
+	var req domains.APIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	adminID := c.MustGet("user_id").(uuid.UUID)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
+
+	key, err := h.services.APIKey.CreateAPIKey(req, adminID, clientIP, requestID)
+	if err != nil {
+		respondAPIKeyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, key)
+}
+
+func (h *Handlers) RevokeAPIKey(c *gin.Context) {
+	keyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid API key ID"})
+		return
+	}
+
+	adminID := c.MustGet("user_id").(uuid.UUID)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
+
+	if err := h.services.APIKey.RevokeAPIKey(keyID, adminID, clientIP, requestID); err != nil {
+		respondAPIKeyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "API key revoked"})
+}
+
+func respondAPIKeyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domains.ErrAPIKeyNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domains.ErrAPIKeyRevoked):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, domains.ErrNotHeld):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
+
+func respondPasswordError(c *gin.Context, err error) {
+	var policy *domains.PasswordPolicyError
+	switch {
+	case errors.As(err, &policy):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "violations": policy.Violations})
+	case errors.Is(err, domains.ErrPasswordReused):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, domains.ErrWrongPassword), errors.Is(err, domains.ErrInvalidResetToken):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.Is(err, domains.ErrPasswordLoginDisabled):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
+
+// Attendance requests
+type SubmitAttendanceRequest struct {
+	Date        string `json:"date" binding:"required"`          // YYYY-MM-DD format
+	CheckInTime string `json:"check_in_time" binding:"required"` // HH:MM format
+}
+
+func (h *Handlers) SubmitAttendance(c *gin.Context) {
+	var req SubmitAttendanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	clientIP := "-"
+	// Use this safer approach:
+	clientIPRaw, exists := c.Get("client_ip")
+	if exists {
+		clientIPStr, ok := clientIPRaw.(string)
+		if ok {
+			clientIP = clientIPStr
+		}
+	}
+	requestID := "-"
+	// Use this safer approach:
+	requestIDRaw, exists := c.Get("request_id")
+	if exists {
+		requestIDStr, ok := requestIDRaw.(string)
+		if ok {
+			requestID = requestIDStr
+		}
+	}
+
+	// Parse date
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format, use YYYY-MM-DD"})
+		return
+	}
+
+	// Parse check-in time
+	checkInTime, err := time.Parse("15:04", req.CheckInTime)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid check-in time format, use HH:MM"})
+		return
+	}
+
+	// Combine date and time
+	checkInDateTime := time.Date(date.Year(), date.Month(), date.Day(),
+		checkInTime.Hour(), checkInTime.Minute(), 0, 0, date.Location())
+
+	if err := h.services.Attendance.SubmitAttendance(userID, date, checkInDateTime, clientIP, requestID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Attendance submitted successfully"})
+}
+
+type RecordAttendanceRequest struct {
+	EmployeeID  string `json:"employee_id" binding:"required"`
+	Date        string `json:"date" binding:"required"`          // YYYY-MM-DD format
+	CheckInTime string `json:"check_in_time" binding:"required"` // HH:MM format
+}
+
+// RecordAttendance records the attendance of an employee on their behalf, for badge readers
+func (h *Handlers) RecordAttendance(c *gin.Context) {
+	var req RecordAttendanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	actor := c.MustGet("actor").(domains.Actor)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
+
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format, use YYYY-MM-DD"})
+		return
+	}
+	checkInTime, err := time.Parse("15:04", req.CheckInTime)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid check-in time format, use HH:MM"})
+		return
+	}
+	checkInDateTime := time.Date(date.Year(), date.Month(), date.Day(),
+		checkInTime.Hour(), checkInTime.Minute(), 0, 0, date.Location())
+
+	if err := h.services.Attendance.RecordAttendance(req.EmployeeID, date, checkInDateTime, actor, clientIP, requestID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Attendance recorded successfully"})
+}
+
+// Overtime requests
+type SubmitOvertimeRequest struct {
+	Date  string  `json:"date" binding:"required"` // YYYY-MM-DD format
+	Hours float64 `json:"hours" binding:"required,gt=0,lte=3"`
+}
+
+func (h *Handlers) SubmitOvertime(c *gin.Context) {
+	var req SubmitOvertimeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
+
+	// Parse date
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date format, use YYYY-MM-DD"})
+		return
+	}
+
+	if err := h.services.Overtime.SubmitOvertime(userID, date, req.Hours, clientIP, requestID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Overtime submitted successfully"})
+}
+
+// Reimbursement requests
+type SubmitReimbursementRequest struct {
+	Amount      float64 `json:"amount" binding:"required,gt=0"`
+	Description string  `json:"description" binding:"required"`
+}
+
+func (h *Handlers) SubmitReimbursement(c *gin.Context) {
+	var req SubmitReimbursementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
+
+	if err := h.services.Reimbursement.SubmitReimbursement(userID, req.Amount, req.Description, clientIP, requestID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Reimbursement submitted successfully"})
+}
+
+// Leave requests
+type SubmitLeaveRequest struct {
+	Type       string     `json:"type" binding:"required"`
+	StartDate  string     `json:"start_date" binding:"required"` // YYYY-MM-DD format
+	EndDate    string     `json:"end_date" binding:"required"`   // YYYY-MM-DD format, inclusive
+	Reason     string     `json:"reason"`
+	DelegateID *uuid.UUID `json:"delegate_id"` // decides on the requests of the user's team while the leave lasts
+}
+
+func (h *Handlers) SubmitLeave(c *gin.Context) {
+	var req SubmitLeaveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start date format, use YYYY-MM-DD"})
+		return
+	}
+	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end date format, use YYYY-MM-DD"})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
+
+	leave, err := h.services.Leave.SubmitLeave(userID, domains.LeaveRequest{
+		Type:       req.Type,
+		StartDate:  startDate,
+		EndDate:    endDate,
+		Reason:     req.Reason,
+		DelegateID: req.DelegateID,
+	}, clientIP, requestID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, leave)
+}
+
+func (h *Handlers) ListLeave(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	leaves, err := h.services.Leave.ListLeave(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"leave": leaves})
+}
+
+// GetTeam returns the reports of the manager, without salaries
+func (h *Handlers) GetTeam(c *gin.Context) {
+	managerID := c.MustGet("user_id").(uuid.UUID)
+
+	team, err := h.services.Team.GetTeam(managerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"team": team})
+}
+
+func (h *Handlers) ListTeamOvertime(c *gin.Context) {
+	h.listTeamRequests(c, domains.RequestKindOvertime)
+}
+
+func (h *Handlers) ListTeamReimbursements(c *gin.Context) {
+	h.listTeamRequests(c, domains.RequestKindReimbursement)
+}
+
+func (h *Handlers) ListTeamLeave(c *gin.Context) {
+	h.listTeamRequests(c, domains.RequestKindLeave)
+}
+
+// listTeamRequests lists the requests of the manager's team, optionally filtered by ?status=
+func (h *Handlers) listTeamRequests(c *gin.Context, kind string) {
+	approverID := c.MustGet("user_id").(uuid.UUID)
+
+	requests, err := h.services.Team.ListRequests(approverID, kind, c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"requests": requests})
+}
+
+type ApproveTeamRequestRequest struct {
+	Comment string `json:"comment"`
+}
+
+type RejectTeamRequestRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+func (h *Handlers) ApproveOvertime(c *gin.Context) {
+	h.decideTeamRequest(c, domains.RequestKindOvertime, true)
+}
+
+func (h *Handlers) RejectOvertime(c *gin.Context) {
+	h.decideTeamRequest(c, domains.RequestKindOvertime, false)
+}
+
+func (h *Handlers) ApproveReimbursement(c *gin.Context) {
+	h.decideTeamRequest(c, domains.RequestKindReimbursement, true)
+}
+
+func (h *Handlers) RejectReimbursement(c *gin.Context) {
+	h.decideTeamRequest(c, domains.RequestKindReimbursement, false)
+}
+
+func (h *Handlers) ApproveLeave(c *gin.Context) {
+	h.decideTeamRequest(c, domains.RequestKindLeave, true)
+}
+
+func (h *Handlers) RejectLeave(c *gin.Context) {
+	h.decideTeamRequest(c, domains.RequestKindLeave, false)
+}
+
+func (h *Handlers) decideTeamRequest(c *gin.Context, kind string, approve bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request ID"})
+		return
+	}
+
+	var comment string
+	if approve {
+		var req ApproveTeamRequestRequest
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+		comment = req.Comment
+	} else {
+		var req RejectTeamRequestRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		comment = req.Reason
+	}
+
+	approverID := c.MustGet("user_id").(uuid.UUID)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
+
+	request, err := h.services.Team.Decide(approverID, kind, id, approve, comment, clientIP, requestID)
+	if err != nil {
+		respondTeamError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, request)
+}
+
+func respondTeamError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domains.ErrRequestNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domains.ErrAlreadyDecided), errors.Is(err, domains.ErrPeriodProcessed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+}
+
+// Payslip generation
+func (h *Handlers) GeneratePayslip(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+	periodIDStr := c.Param("period_id")
+
+	periodID, err := uuid.Parse(periodIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid period ID"})
+		return
+	}
+
+	payslip, err := h.services.Payroll.GeneratePayslip(userID, periodID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// ?lang= overrides the employee's preferred language
+	language := c.Query("lang")
+
+	if wantsPDF(c) {
+		data, err := h.services.Payslip.RenderPDF(payslip, language)
+		if errors.Is(err, domains.ErrPayslipPasswordUnavailable) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render payslip PDF"})
+			return
+		}
+
+		filename := fmt.Sprintf("payslip-%s-%s.pdf", payslip.Period.StartDate.Format("2006-01"), payslip.Employee.Username)
+		c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", filename))
+		c.Data(http.StatusOK, mimePDF, data)
+		return
+	}
+
+	h.services.Payslip.Localize(payslip, language)
+	c.JSON(http.StatusOK, payslip)
+}
+
+const mimePDF = "application/pdf"
+
+type SetPayslipPINRequest struct {
+	PIN string `json:"pin" binding:"required"`
+}
+
+func (h *Handlers) SetPayslipPIN(c *gin.Context) {
+	var req SetPayslipPINRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
+
+	if err := h.services.Payslip.SetPIN(userID, req.PIN, clientIP, requestID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Payslip PIN updated successfully"})
+}
+
+type SetEmailRequest struct {
+	Email string `json:"email" binding:"required"`
+}
+
+func (h *Handlers) SetEmail(c *gin.Context) {
+	var req SetEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
+
+	if err := h.services.PayslipMail.SetEmail(userID, req.Email, clientIP, requestID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Email address updated successfully"})
+}
+
+// GetPayslipCertificate serves the certificate payslip signatures can be verified against
+func (h *Handlers) GetPayslipCertificate(c *gin.Context) {
+	c.Header("Content-Disposition", `attachment; filename="payslip-signing.pem"`)
+	c.Data(http.StatusOK, "application/x-pem-file", h.services.Payslip.SigningCertificate())
+}
+
+type SetPreferredLanguageRequest struct {
+	Language string `json:"language" binding:"required"`
+}
+
+func (h *Handlers) SetPreferredLanguage(c *gin.Context) {
+	var req SetPreferredLanguageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID := c.MustGet("user_id").(uuid.UUID)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
+
+	if err := h.services.Language.SetPreferredLanguage(userID, req.Language, clientIP, requestID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Preferred language updated successfully"})
+}
+
+func (h *Handlers) ListLanguages(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"languages": h.services.Language.ListLanguages()})
+}
+
+func (h *Handlers) GetLanguage(c *gin.Context) {
+	catalog, err := h.services.Language.GetLanguage(c.Param("code"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, catalog)
+}
+
+// SaveLanguage adds a language or replaces its message catalog; the code in the path wins over the body
+func (h *Handlers) SaveLanguage(c *gin.Context) {
+	var catalog i18n.Catalog
+	if err := c.ShouldBindJSON(&catalog); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	catalog.Code = c.Param("code")
+
+	actor := c.MustGet("actor").(domains.Actor)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
+
+	language, err := h.services.Language.SaveLanguage(&catalog, actor, clientIP, requestID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Language saved successfully",
+		"language": language,
+	})
+}
+
+// VerifyPayslip lets third parties confirm a payslip from the token in its QR code
+func (h *Handlers) VerifyPayslip(c *gin.Context) {
+	verification, err := h.services.Payslip.Verify(c.Param("token"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"valid": false, "error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, verification)
+}
+
+// wantsPDF reports whether the client asked for a PDF via ?format=pdf or the Accept header
+func wantsPDF(c *gin.Context) bool {
+	if format := c.Query("format"); format != "" {
+		return format == "pdf"
+	}
+	return c.NegotiateFormat(gin.MIMEJSON, mimePDF) == mimePDF
+}
+
+// Admin handlers
+type CreateAttendancePeriodRequest struct {
+	StartDate string `json:"start_date" binding:"required"` // YYYY-MM-DD format
+	EndDate   string `json:"end_date" binding:"required"`   // YYYY-MM-DD format
+}
+
+func (h *Handlers) CreateAttendancePeriod(c *gin.Context) {
+	var req CreateAttendancePeriodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	actor := c.MustGet("actor").(domains.Actor)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
+
+	// Parse dates
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid start date format, use YYYY-MM-DD"})
+		return
+	}
+
+	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end date format, use YYYY-MM-DD"})
+		return
+	}
+
+	period, err := h.services.Admin.CreateAttendancePeriod(startDate, endDate, actor, clientIP, requestID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, period)
+}
+
+func (h *Handlers) ProcessPayroll(c *gin.Context) {
+	periodIDStr := c.Param("period_id")
+	periodID, err := uuid.Parse(periodIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid period ID"})
+		return
+	}
+
+	actor := c.MustGet("actor").(domains.Actor)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
+
+	if err := h.services.Payroll.ProcessPayroll(periodID, actor, clientIP, requestID); err != nil {
+		respondPayrollError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Payroll prepared successfully and is awaiting approval"})
+}
+
+func (h *Handlers) GetPayrollRun(c *gin.Context) {
+	periodIDStr := c.Param("period_id")
+	periodID, err := uuid.Parse(periodIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid period ID"})
+		return
+	}
+
+	payroll, err := h.services.Payroll.GetPayrollRun(periodID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, payroll)
+}
+
+// Payroll approval requests
+type ApprovePayrollRequest struct {
+	Comment string `json:"comment"`
+}
+
+func (h *Handlers) ApprovePayroll(c *gin.Context) {
+	periodIDStr := c.Param("period_id")
+	periodID, err := uuid.Parse(periodIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid period ID"})
+		return
+	}
+
+	var req ApprovePayrollRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	actor := c.MustGet("actor").(domains.Actor)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
+
+	payroll, err := h.services.Payroll.ApprovePayroll(periodID, actor, req.Comment, clientIP, requestID)
+	if err != nil {
+		respondPayrollError(c, err)
+		return
+	}
+
+	if payroll.IsFinal() {
+		h.services.PayslipMail.PayrollFinalized(periodID, actor, clientIP, requestID)
+	}
+
+	c.JSON(http.StatusOK, payroll)
+}
+
+type RejectPayrollRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+func (h *Handlers) RejectPayroll(c *gin.Context) {
+	periodIDStr := c.Param("period_id")
+	periodID, err := uuid.Parse(periodIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid period ID"})
+		return
+	}
+
+	var req RejectPayrollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	actor := c.MustGet("actor").(domains.Actor)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
+
+	if err := h.services.Payroll.RejectPayroll(periodID, actor, req.Reason, clientIP, requestID); err != nil {
+		respondPayrollError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Payroll rejected, period reopened"})
+}
+
+// respondPayrollError answers for processing and deciding on payroll runs, which take a person
+func respondPayrollError(c *gin.Context, err error) {
+	if errors.Is(err, domains.ErrAPIKeyActor) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
+
+func (h *Handlers) DistributePayslips(c *gin.Context) {
+	periodIDStr := c.Param("period_id")
+	periodID, err := uuid.Parse(periodIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid period ID"})
+		return
+	}
+
+	actor := c.MustGet("actor").(domains.Actor)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
+
+	report, err := h.services.PayslipMail.DistributePayslips(periodID, actor, clientIP, requestID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, report)
+}
+
+func (h *Handlers) GetPayslipDistribution(c *gin.Context) {
+	periodIDStr := c.Param("period_id")
+	periodID, err := uuid.Parse(periodIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid period ID"})
+		return
+	}
+
+	report, err := h.services.PayslipMail.GetDistribution(periodID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (h *Handlers) RetryEmailDelivery(c *gin.Context) {
+	deliveryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid delivery ID"})
+		return
+	}
+
+	actor := c.MustGet("actor").(domains.Actor)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
+
+	delivery, err := h.services.PayslipMail.RetryDelivery(deliveryID, actor, clientIP, requestID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
+
+// GenerateDisbursementFile generates the salary transfers of a final payroll run for upload to the bank
+// and marks them sent. The file is returned, and can be downloaded again with DownloadDisbursementFile.
+func (h *Handlers) GenerateDisbursementFile(c *gin.Context) {
+	periodIDStr := c.Param("period_id")
+	periodID, err := uuid.Parse(periodIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid period ID"})
+		return
+	}
+
+	var executionDate time.Time
+	if value := c.Query("execution_date"); value != "" {
+		executionDate, err = time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid execution_date format. Use YYYY-MM-DD"})
+			return
+		}
+	}
+
+	actor := c.MustGet("actor").(domains.Actor)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
+
+	reissue := c.Query("reissue") == "true"
+
+	file, err := h.services.Disbursement.GenerateFile(periodID, c.Query("format"), executionDate, reissue, actor, clientIP, requestID)
+	var missing *domains.MissingBankAccountsError
+	if errors.As(err, &missing) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "employees": missing.Employees})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	writeDisbursementFile(c, http.StatusCreated, file)
+}
+
+// DownloadDisbursementFile downloads a file generated before, the one of the batch query parameter or the
+// run's latest
+func (h *Handlers) DownloadDisbursementFile(c *gin.Context) {
+	periodID, err := uuid.Parse(c.Param("period_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid period ID"})
+		return
+	}
+
+	file, err := h.services.Disbursement.GetFile(periodID, c.Query("batch"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	writeDisbursementFile(c, http.StatusOK, file)
+}
+
+// writeDisbursementFile repeats the control figures in headers so they can be checked against the bank's confirmation
+func writeDisbursementFile(c *gin.Context, status int, file *bankfile.File) {
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.Filename))
+	c.Header("X-Transfer-Count", strconv.Itoa(file.Totals.Count))
+	c.Header("X-Control-Sum", bankfile.FormatAmount(file.Totals.Amount))
+	c.Header("X-Account-Hash", strconv.FormatInt(file.Totals.AccountHash, 10))
+	c.Header("X-Checksum-SHA256", file.Checksum)
+	c.Data(status, file.ContentType, file.Data)
+}
+
+// EmployeeResponse is an employee as returned by the admin employee endpoints, the only responses
+// that carry the salary account number
+type EmployeeResponse struct {
+	*models.User
+	BankAccountNumber string `json:"bank_account_number,omitempty"`
+}
+
+func newEmployeeResponse(user *models.User) EmployeeResponse {
+	return EmployeeResponse{User: user, BankAccountNumber: user.BankAccountNumber}
+}
+
+type SetCostCenterRequest struct {
+	CostCenter string `json:"cost_center"`
+}
+
+func (h *Handlers) SetCostCenter(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid employee ID"})
+		return
+	}
+
+	var req SetCostCenterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	actor := c.MustGet("actor").(domains.Actor)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
+
+	user, err := h.services.Admin.SetCostCenter(userID, req.CostCenter, actor, clientIP, requestID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newEmployeeResponse(user))
+}
+
+type SetEmployeeIDRequest struct {
+	EmployeeID string `json:"employee_id"` // empty clears it
+}
+
+// SetEmployeeID sets the HR employee number single sign-on matches the employee on
+func (h *Handlers) SetEmployeeID(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid employee ID"})
+		return
+	}
+
+	var req SetEmployeeIDRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	actor := c.MustGet("actor").(domains.Actor)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
+
+	user, err := h.services.Admin.SetEmployeeID(userID, req.EmployeeID, actor, clientIP, requestID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newEmployeeResponse(user))
+}
+
+type SetBirthDateRequest struct {
+	BirthDate string `json:"birth_date"` // YYYY-MM-DD, empty clears it
+}
+
+// SetBirthDate sets the birthdate payslip PDFs open with when the employee has no payslip PIN
+func (h *Handlers) SetBirthDate(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid employee ID"})
+		return
+	}
+
+	var req SetBirthDateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var birthDate *time.Time
+	if req.BirthDate != "" {
+		date, err := time.Parse("2006-01-02", req.BirthDate)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid birth date format, use YYYY-MM-DD"})
+			return
+		}
+		birthDate = &date
+	}
+
+	actor := c.MustGet("actor").(domains.Actor)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
+
+	user, err := h.services.Admin.SetBirthDate(userID, birthDate, actor, clientIP, requestID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newEmployeeResponse(user))
+}
+
+type SetManagerRequest struct {
+	ManagerID *uuid.UUID `json:"manager_id"` // null removes the line manager
+}
+
+// SetManager sets the line manager an employee reports to
+func (h *Handlers) SetManager(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid employee ID"})
+		return
+	}
+
+	var req SetManagerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	actor := c.MustGet("actor").(domains.Actor)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
+
+	user, err := h.services.Team.SetManager(userID, req.ManagerID, actor, clientIP, requestID)
+	if err != nil {
+		respondTeamError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newEmployeeResponse(user))
+}
+
+// maxStatementSize bounds an uploaded bank statement
+const maxStatementSize = 10 << 20
+
+// ImportBankStatement reads a bank statement or upload confirmation, uploaded as the multipart field "file",
+// and updates the payment status of the payroll items it reports on
+func (h *Handlers) ImportBankStatement(c *gin.Context) {
+	periodIDStr := c.Param("period_id")
+	periodID, err := uuid.Parse(periodIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid period ID"})
+		return
+	}
+
+	upload, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Statement file is required"})
+		return
+	}
+	if upload.Size > maxStatementSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Statement file is too large"})
+		return
+	}
+
+	file, err := upload.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read statement file"})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxStatementSize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read statement file"})
+		return
+	}
+
+	actor := c.MustGet("actor").(domains.Actor)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
+
+	result, err := h.services.Reconciliation.ImportStatement(periodID, c.PostForm("format"), data, actor, clientIP, requestID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *Handlers) GetReconciliation(c *gin.Context) {
+	periodIDStr := c.Param("period_id")
+	periodID, err := uuid.Parse(periodIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid period ID"})
+		return
+	}
+
+	report, err := h.services.Reconciliation.GetReconciliation(periodID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (h *Handlers) SetBankAccount(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid employee ID"})
+		return
+	}
+
+	var req domains.BankAccount
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	actor := c.MustGet("actor").(domains.Actor)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
+
+	user, err := h.services.Disbursement.SetBankAccount(userID, req, actor, clientIP, requestID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newEmployeeResponse(user))
+}
+
+func (h *Handlers) PreviewPayroll(c *gin.Context) {
+	periodIDStr := c.Param("period_id")
+	periodID, err := uuid.Parse(periodIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid period ID"})
+		return
+	}
+
+	preview, err := h.services.Payroll.PreviewPayroll(periodID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
+
+func (h *Handlers) GeneratePayrollSummary(c *gin.Context) {
+	periodIDStr := c.Param("period_id")
+	periodID, err := uuid.Parse(periodIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid period ID"})
+		return
+	}
+
+	summary, err := h.services.Payroll.GeneratePayrollSummary(periodID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+func (h *Handlers) GetPayrollVariance(c *gin.Context) {
+	fromPeriodID, err := uuid.Parse(c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from period ID"})
+		return
+	}
+
+	toPeriodID, err := uuid.Parse(c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to period ID"})
+		return
+	}
+
+	var threshold float64
+	if thresholdStr := c.Query("threshold"); thresholdStr != "" {
+		threshold, err = strconv.ParseFloat(thresholdStr, 64)
+		if err != nil || threshold <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid threshold, use a positive percentage"})
+			return
+		}
+	}
+
+	report, err := h.services.Report.GetPayrollVariance(fromPeriodID, toPeriodID, threshold)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if c.Query("format") == "csv" {
+		data, err := h.services.Report.ExportPayrollVarianceCSV(report)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate CSV"})
+			return
+		}
+
+		filename := fmt.Sprintf("payroll-variance-%s-%s.csv", report.FromPeriod.StartDate.Format("2006-01"), report.ToPeriod.StartDate.Format("2006-01"))
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		c.Data(http.StatusOK, "text/csv", data)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetPayrollJournal exports the accounting journal of a final payroll run as JSON or, with format=csv, CSV.
+// split=cost_center posts one entry per cost center.
+func (h *Handlers) GetPayrollJournal(c *gin.Context) {
+	periodIDStr := c.Param("period_id")
+	periodID, err := uuid.Parse(periodIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid period ID"})
+		return
+	}
+
+	var byCostCenter bool
+	switch c.Query("split") {
+	case "":
+	case "cost_center":
+		byCostCenter = true
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid split, use cost_center"})
+		return
+	}
+
+	journal, err := h.services.Report.GetPayrollJournal(periodID, byCostCenter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if c.Query("format") == "csv" {
+		data, err := h.services.Report.ExportPayrollJournalCSV(journal)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate CSV"})
+			return
+		}
+
+		filename := fmt.Sprintf("payroll-journal-%s.csv", journal.Entries[0].Date)
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		c.Data(http.StatusOK, "text/csv", data)
+		return
+	}
+
+	c.JSON(http.StatusOK, journal)
+}
+
+func (h *Handlers) SetTaxProfile(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid employee ID"})
+		return
+	}
+
+	var req domains.TaxProfile
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	actor := c.MustGet("actor").(domains.Actor)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
+
+	user, err := h.services.Tax.SetTaxProfile(userID, req, actor, clientIP, requestID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newEmployeeResponse(user))
+}
+
+// GetTaxCertificate returns the employee's own 1721-A1 for a tax year, as JSON or PDF
+func (h *Handlers) GetTaxCertificate(c *gin.Context) {
+	userID := c.MustGet("user_id").(uuid.UUID)
+
+	year, err := strconv.Atoi(c.Param("year"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tax year"})
+		return
+	}
+
+	certificate, err := h.services.Tax.GetTaxCertificate(userID, year)
+	if errors.Is(err, domains.ErrTaxCertificateNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if wantsPDF(c) {
+		data, err := h.services.Tax.RenderCertificatePDF(certificate)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render tax certificate PDF"})
+			return
+		}
+
+		filename := fmt.Sprintf("1721-A1-%d-%s.pdf", year, certificate.Username)
+		c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", filename))
+		c.Data(http.StatusOK, mimePDF, data)
+		return
+	}
+
+	c.JSON(http.StatusOK, certificate)
+}
+
+// GetTaxCertificates returns the 1721-A1 of every employee for a tax year, or with ?format=zip their PDFs
+// and structured data in one archive
+func (h *Handlers) GetTaxCertificates(c *gin.Context) {
+	year, err := strconv.Atoi(c.Param("year"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tax year"})
+		return
+	}
+
+	certificates, err := h.services.Tax.GetTaxCertificates(year)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if c.Query("format") == "zip" {
+		data, err := h.services.Tax.ArchiveTaxCertificates(certificates)
+		if errors.Is(err, domains.ErrTaxCertificateNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate tax certificates"})
+			return
+		}
+
+		filename := fmt.Sprintf("1721-A1-%d.zip", year)
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		c.Data(http.StatusOK, "application/zip", data)
+		return
+	}
+
+	c.JSON(http.StatusOK, certificates)
+}
+
+func (h *Handlers) ValidateFiling(c *gin.Context) {
+	periodIDStr := c.Param("period_id")
+	periodID, err := uuid.Parse(periodIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid period ID"})
+		return
+	}
+
+	validation, err := h.services.Filing.ValidateFiling(periodID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, validation)
+}
+
+// ExportFiling downloads the PPh 21 return or a BPJS contribution report of a period
+func (h *Handlers) ExportFiling(c *gin.Context) {
+	periodIDStr := c.Param("period_id")
+	periodID, err := uuid.Parse(periodIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid period ID"})
+		return
+	}
+
+	actor := c.MustGet("actor").(domains.Actor)
+	clientIP := c.MustGet("client_ip").(string)
+	requestID := c.MustGet("request_id").(string)
+
+	file, err := h.services.Filing.ExportFiling(periodID, c.Param("report"), actor, clientIP, requestID)
+	var invalid *domains.FilingValidationError
+	if errors.As(err, &invalid) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "issues": invalid.Issues})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.Filename))
+	c.Header("X-Record-Count", strconv.Itoa(file.Count))
+	c.Header("X-Total-Amount", strconv.FormatInt(file.Amount, 10))
+	c.Header("X-Checksum-SHA256", file.Checksum)
+	c.Data(http.StatusOK, file.ContentType, file.Data)
 }
 
 // Health check
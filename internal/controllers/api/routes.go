@@ -3,6 +3,7 @@ package api
 import (
 	"payslip-system/internal/middleware"
 	"payslip-system/internal/providers"
+	"payslip-system/internal/rbac"
 	"payslip-system/internal/repository"
 
 	"github.com/gin-gonic/gin"
@@ -14,34 +15,121 @@ func SetupRoutes(r *gin.Engine, services *providers.Services) {
 	// Get repository for middleware (needed for auth middleware)
 	repos := &repository.Repositories{} // This would be passed from main
 
+	// Token verification keys for other services
+	r.GET("/.well-known/jwks.json", handlers.GetJWKS)
+
 	// Public routes
 	public := r.Group("/api/v1")
 	{
 		public.GET("/health", handlers.HealthCheck)
 		public.POST("/login", handlers.Login)
+		public.POST("/login/mfa", handlers.CompleteMFALogin)
+		public.POST("/login/mfa/enroll", handlers.EnrollMFALogin)
+		public.GET("/login/oidc", handlers.BeginSSOLogin)
+		public.POST("/login/oidc/callback", handlers.CompleteSSOLogin)
+		public.POST("/refresh", handlers.Refresh)
+		public.POST("/password/forgot", handlers.ForgotPassword)
+		public.POST("/password/reset", handlers.ResetPassword)
+		public.GET("/payslip-certificate", handlers.GetPayslipCertificate)
+		public.GET("/verify/:token", handlers.VerifyPayslip)
+	}
+
+	// Account routes, also open to users who must change their password
+	account := r.Group("/api/v1")
+	account.Use(middleware.AuthMiddleware(repos, services.Tokens), middleware.UsersOnly(), middleware.Idempotency(repos))
+	{
+		account.POST("/logout", handlers.Logout)
+		account.PUT("/password", handlers.ChangePassword)
 	}
 
 	// Protected routes (requires authentication)
 	protected := r.Group("/api/v1")
-	protected.Use(middleware.AuthMiddleware(repos))
+	protected.Use(middleware.AuthMiddleware(repos, services.Tokens), middleware.PasswordChangeMiddleware(), middleware.Idempotency(repos))
 	{
-		// Employee routes
+		protected.GET("/languages", handlers.ListLanguages)
+		protected.GET("/activity", middleware.UsersOnly(), handlers.ListActivity)
+		protected.POST("/mfa/enroll", middleware.UsersOnly(), handlers.EnrollMFA)
+		protected.POST("/mfa/confirm", middleware.UsersOnly(), handlers.ConfirmMFA)
+		protected.POST("/mfa/recovery-codes", middleware.UsersOnly(), handlers.RegenerateRecoveryCodes)
+		protected.POST("/mfa/disable", middleware.UsersOnly(), handlers.DisableMFA)
+
+		// Self-service routes; every route declares the permission it requires
 		employee := protected.Group("/employee")
-		employee.Use(middleware.EmployeeMiddleware())
 		{
-			employee.POST("/attendance", handlers.SubmitAttendance)
-			employee.POST("/overtime", handlers.SubmitOvertime)
-			employee.POST("/reimbursement", handlers.SubmitReimbursement)
-			employee.GET("/payslip/:period_id", handlers.GeneratePayslip)
+			employee.POST("/attendance", middleware.RequirePermission(rbac.AttendanceSubmit), handlers.SubmitAttendance)
+			employee.POST("/overtime", middleware.RequirePermission(rbac.OvertimeSubmit), handlers.SubmitOvertime)
+			employee.POST("/reimbursement", middleware.RequirePermission(rbac.ReimbursementSubmit), handlers.SubmitReimbursement)
+			employee.GET("/payslip/:period_id", middleware.RequirePermission(rbac.PayslipViewOwn), handlers.GeneratePayslip)
+			employee.POST("/payslip-pin", middleware.RequirePermission(rbac.PayslipViewOwn), handlers.SetPayslipPIN)
+			employee.PUT("/language", middleware.RequirePermission(rbac.PayslipViewOwn), handlers.SetPreferredLanguage)
+			employee.PUT("/email", middleware.RequirePermission(rbac.PayslipViewOwn), handlers.SetEmail)
+			employee.GET("/tax-certificate/:year", middleware.NoImpersonation(), middleware.RequirePermission(rbac.PayslipViewOwn), handlers.GetTaxCertificate)
+			employee.POST("/leave", middleware.RequirePermission(rbac.LeaveSubmit), handlers.SubmitLeave)
+			employee.GET("/leave", middleware.RequirePermission(rbac.LeaveSubmit), handlers.ListLeave)
 		}
 
-		// Admin routes
+		// Line management routes, scoped to the caller's direct and indirect reports
+		manager := protected.Group("/manager")
+		{
+			manager.GET("/team", middleware.RequirePermission(rbac.TeamView), handlers.GetTeam)
+			manager.GET("/overtime", middleware.RequirePermission(rbac.OvertimeApprove), handlers.ListTeamOvertime)
+			manager.POST("/overtime/:id/approve", middleware.RequirePermission(rbac.OvertimeApprove), handlers.ApproveOvertime)
+			manager.POST("/overtime/:id/reject", middleware.RequirePermission(rbac.OvertimeApprove), handlers.RejectOvertime)
+			manager.GET("/reimbursements", middleware.RequirePermission(rbac.ReimbursementApprove), handlers.ListTeamReimbursements)
+			manager.POST("/reimbursements/:id/approve", middleware.RequirePermission(rbac.ReimbursementApprove), handlers.ApproveReimbursement)
+			manager.POST("/reimbursements/:id/reject", middleware.RequirePermission(rbac.ReimbursementApprove), handlers.RejectReimbursement)
+			manager.GET("/leave", middleware.RequirePermission(rbac.LeaveApprove), handlers.ListTeamLeave)
+			manager.POST("/leave/:id/approve", middleware.RequirePermission(rbac.LeaveApprove), handlers.ApproveLeave)
+			manager.POST("/leave/:id/reject", middleware.RequirePermission(rbac.LeaveApprove), handlers.RejectLeave)
+		}
+
+		// Administration routes
 		admin := protected.Group("/admin")
-		admin.Use(middleware.AdminMiddleware())
 		{
-			admin.POST("/attendance-period", handlers.CreateAttendancePeriod)
-			admin.POST("/payroll/:period_id/process", handlers.ProcessPayroll)
-			admin.GET("/payroll/:period_id/summary", handlers.GeneratePayrollSummary)
+			admin.POST("/attendance-period", middleware.RequirePermission(rbac.AttendancePeriodManage), handlers.CreateAttendancePeriod)
+			admin.POST("/attendance", middleware.RequirePermission(rbac.AttendanceRecord), handlers.RecordAttendance)
+			admin.POST("/payroll/:period_id/process", middleware.RequirePermission(rbac.PayrollProcess), handlers.ProcessPayroll)
+			admin.GET("/payroll/:period_id/preview", middleware.RequirePermission(rbac.PayrollProcess), handlers.PreviewPayroll)
+			admin.GET("/payroll/:period_id", middleware.RequirePermission(rbac.PayrollViewSummary), handlers.GetPayrollRun)
+			admin.POST("/payroll/:period_id/approve", middleware.RequirePermission(rbac.PayrollApprove), handlers.ApprovePayroll)
+			admin.POST("/payroll/:period_id/reject", middleware.RequirePermission(rbac.PayrollApprove), handlers.RejectPayroll)
+			admin.POST("/payroll/:period_id/distribute", middleware.RequirePermission(rbac.PayslipDistribute), handlers.DistributePayslips)
+			admin.GET("/payroll/:period_id/distribution", middleware.RequirePermission(rbac.PayslipDistribute), handlers.GetPayslipDistribution)
+			admin.POST("/email-deliveries/:id/retry", middleware.RequirePermission(rbac.PayslipDistribute), handlers.RetryEmailDelivery)
+			admin.POST("/payroll/:period_id/disbursement", middleware.RequirePermission(rbac.DisbursementManage), handlers.GenerateDisbursementFile)
+			admin.GET("/payroll/:period_id/disbursement", middleware.NoImpersonation(), middleware.RequirePermission(rbac.DisbursementManage), handlers.DownloadDisbursementFile)
+			admin.POST("/payroll/:period_id/bank-statement", middleware.RequirePermission(rbac.DisbursementManage), handlers.ImportBankStatement)
+			admin.GET("/payroll/:period_id/reconciliation", middleware.RequirePermission(rbac.DisbursementManage), handlers.GetReconciliation)
+			admin.PUT("/employees/:id/bank-account", middleware.RequirePermission(rbac.EmployeeManage), handlers.SetBankAccount)
+			admin.PUT("/employees/:id/cost-center", middleware.RequirePermission(rbac.EmployeeManage), handlers.SetCostCenter)
+			admin.PUT("/employees/:id/employee-id", middleware.RequirePermission(rbac.EmployeeManage), handlers.SetEmployeeID)
+			admin.PUT("/employees/:id/birth-date", middleware.RequirePermission(rbac.EmployeeManage), handlers.SetBirthDate)
+			admin.PUT("/employees/:id/manager", middleware.RequirePermission(rbac.EmployeeManage), handlers.SetManager)
+			admin.GET("/payroll/:period_id/journal", middleware.RequirePermission(rbac.ReportView), handlers.GetPayrollJournal)
+			admin.PUT("/employees/:id/tax-profile", middleware.RequirePermission(rbac.EmployeeManage), handlers.SetTaxProfile)
+			admin.POST("/users/:id/revoke-sessions", middleware.RequirePermission(rbac.SecurityManage), handlers.RevokeSessions)
+			admin.POST("/users/:id/impersonate", middleware.UsersOnly(), middleware.RequirePermission(rbac.UserImpersonate), handlers.Impersonate)
+			admin.POST("/users/:id/unlock", middleware.RequirePermission(rbac.SecurityManage), handlers.UnlockUser)
+			admin.POST("/users/:id/reset-password", middleware.RequirePermission(rbac.SecurityManage), handlers.AdminResetPassword)
+			admin.GET("/lockouts", middleware.RequirePermission(rbac.SecurityManage), handlers.ListLockouts)
+			admin.DELETE("/lockouts/:id", middleware.RequirePermission(rbac.SecurityManage), handlers.Unlock)
+			admin.GET("/tax-certificates/:year", middleware.NoImpersonation(), middleware.RequirePermission(rbac.TaxReport), handlers.GetTaxCertificates)
+			admin.GET("/payroll/:period_id/filing", middleware.RequirePermission(rbac.TaxReport), handlers.ValidateFiling)
+			admin.GET("/payroll/:period_id/filing/:report", middleware.NoImpersonation(), middleware.RequirePermission(rbac.TaxReport), handlers.ExportFiling)
+			admin.GET("/payroll/:period_id/summary", middleware.RequirePermission(rbac.PayrollViewSummary), handlers.GeneratePayrollSummary)
+			admin.GET("/reports/payroll-variance", middleware.RequirePermission(rbac.ReportView), handlers.GetPayrollVariance)
+			admin.GET("/languages/:code", middleware.RequirePermission(rbac.LanguageManage), handlers.GetLanguage)
+			admin.PUT("/languages/:code", middleware.RequirePermission(rbac.LanguageManage), handlers.SaveLanguage)
+			admin.GET("/permissions", middleware.RequirePermission(rbac.RoleManage), handlers.ListPermissions)
+			admin.GET("/roles", middleware.RequirePermission(rbac.RoleManage), handlers.ListRoles)
+			admin.POST("/roles", middleware.RequirePermission(rbac.RoleManage), handlers.CreateRole)
+			admin.PUT("/roles/:id", middleware.RequirePermission(rbac.RoleManage), handlers.UpdateRole)
+			admin.DELETE("/roles/:id", middleware.RequirePermission(rbac.RoleManage), handlers.DeleteRole)
+			admin.GET("/users/:id/roles", middleware.RequirePermission(rbac.RoleManage), handlers.GetUserRoles)
+			admin.PUT("/users/:id/roles", middleware.RequirePermission(rbac.RoleManage), handlers.SetUserRoles)
+			admin.GET("/api-keys", middleware.RequirePermission(rbac.APIKeyManage), handlers.ListAPIKeys)
+			admin.POST("/api-keys", middleware.RequirePermission(rbac.APIKeyManage), handlers.CreateAPIKey)
+			admin.DELETE("/api-keys/:id", middleware.RequirePermission(rbac.APIKeyManage), handlers.RevokeAPIKey)
 		}
 	}
 }
@@ -50,34 +138,122 @@ func SetupRoutes(r *gin.Engine, services *providers.Services) {
 func SetupRoutesWithRepos(r *gin.Engine, services *providers.Services, repos *repository.Repositories) {
 	handlers := NewHandlers(services)
 
-	// Public routes
+	// Token verification keys for other services
+	r.GET("/.well-known/jwks.json", handlers.GetJWKS)
+
+	// Public routes. They are also served without the request logger, so their handlers read the client IP and
+	// request ID with c.ClientIP and c.GetString rather than from the values the logger sets.
 	public := r.Group("/api/v1")
 	{
 		public.GET("/health", handlers.HealthCheck)
 		public.POST("/login", handlers.Login)
+		public.POST("/login/mfa", handlers.CompleteMFALogin)
+		public.POST("/login/mfa/enroll", handlers.EnrollMFALogin)
+		public.GET("/login/oidc", handlers.BeginSSOLogin)
+		public.POST("/login/oidc/callback", handlers.CompleteSSOLogin)
+		public.POST("/refresh", handlers.Refresh)
+		public.POST("/password/forgot", handlers.ForgotPassword)
+		public.POST("/password/reset", handlers.ResetPassword)
+		public.GET("/payslip-certificate", handlers.GetPayslipCertificate)
+		public.GET("/verify/:token", handlers.VerifyPayslip)
+	}
+
+	// Account routes, also open to users who must change their password
+	account := r.Group("/api/v1")
+	account.Use(middleware.AuthMiddleware(repos, services.Tokens), middleware.UsersOnly(), middleware.Idempotency(repos))
+	{
+		account.POST("/logout", handlers.Logout)
+		account.PUT("/password", handlers.ChangePassword)
 	}
 
 	// Protected routes (requires authentication)
 	protected := r.Group("/api/v1")
-	protected.Use(middleware.AuthMiddleware(repos))
+	protected.Use(middleware.AuthMiddleware(repos, services.Tokens), middleware.PasswordChangeMiddleware(), middleware.Idempotency(repos))
 	{
-		// Employee routes
+		protected.GET("/languages", handlers.ListLanguages)
+		protected.GET("/activity", middleware.UsersOnly(), handlers.ListActivity)
+		protected.POST("/mfa/enroll", middleware.UsersOnly(), handlers.EnrollMFA)
+		protected.POST("/mfa/confirm", middleware.UsersOnly(), handlers.ConfirmMFA)
+		protected.POST("/mfa/recovery-codes", middleware.UsersOnly(), handlers.RegenerateRecoveryCodes)
+		protected.POST("/mfa/disable", middleware.UsersOnly(), handlers.DisableMFA)
+
+		// Self-service routes; every route declares the permission it requires
 		employee := protected.Group("/employee")
-		employee.Use(middleware.EmployeeMiddleware())
 		{
-			employee.POST("/attendance", handlers.SubmitAttendance)
-			employee.POST("/overtime", handlers.SubmitOvertime)
-			employee.POST("/reimbursement", handlers.SubmitReimbursement)
-			employee.GET("/payslip/:period_id", handlers.GeneratePayslip)
+			employee.POST("/attendance", middleware.RequirePermission(rbac.AttendanceSubmit), handlers.SubmitAttendance)
+			employee.POST("/overtime", middleware.RequirePermission(rbac.OvertimeSubmit), handlers.SubmitOvertime)
+			employee.POST("/reimbursement", middleware.RequirePermission(rbac.ReimbursementSubmit), handlers.SubmitReimbursement)
+			employee.GET("/payslip/:period_id", middleware.RequirePermission(rbac.PayslipViewOwn), handlers.GeneratePayslip)
+			employee.POST("/payslip-pin", middleware.RequirePermission(rbac.PayslipViewOwn), handlers.SetPayslipPIN)
+			employee.PUT("/language", middleware.RequirePermission(rbac.PayslipViewOwn), handlers.SetPreferredLanguage)
+			employee.PUT("/email", middleware.RequirePermission(rbac.PayslipViewOwn), handlers.SetEmail)
+			employee.GET("/tax-certificate/:year", middleware.NoImpersonation(), middleware.RequirePermission(rbac.PayslipViewOwn), handlers.GetTaxCertificate)
+			employee.POST("/leave", middleware.RequirePermission(rbac.LeaveSubmit), handlers.SubmitLeave)
+			employee.GET("/leave", middleware.RequirePermission(rbac.LeaveSubmit), handlers.ListLeave)
+		}
+
+		// Line management routes, scoped to the caller's direct and indirect reports
+		manager := protected.Group("/manager")
+		{
+			manager.GET("/team", middleware.RequirePermission(rbac.TeamView), handlers.GetTeam)
+			manager.GET("/overtime", middleware.RequirePermission(rbac.OvertimeApprove), handlers.ListTeamOvertime)
+			manager.POST("/overtime/:id/approve", middleware.RequirePermission(rbac.OvertimeApprove), handlers.ApproveOvertime)
+			manager.POST("/overtime/:id/reject", middleware.RequirePermission(rbac.OvertimeApprove), handlers.RejectOvertime)
+			manager.GET("/reimbursements", middleware.RequirePermission(rbac.ReimbursementApprove), handlers.ListTeamReimbursements)
+			manager.POST("/reimbursements/:id/approve", middleware.RequirePermission(rbac.ReimbursementApprove), handlers.ApproveReimbursement)
+			manager.POST("/reimbursements/:id/reject", middleware.RequirePermission(rbac.ReimbursementApprove), handlers.RejectReimbursement)
+			manager.GET("/leave", middleware.RequirePermission(rbac.LeaveApprove), handlers.ListTeamLeave)
+			manager.POST("/leave/:id/approve", middleware.RequirePermission(rbac.LeaveApprove), handlers.ApproveLeave)
+			manager.POST("/leave/:id/reject", middleware.RequirePermission(rbac.LeaveApprove), handlers.RejectLeave)
 		}
 
-		// Admin routes
+		// Administration routes
 		admin := protected.Group("/admin")
-		admin.Use(middleware.AdminMiddleware())
 		{
-			admin.POST("/attendance-period", handlers.CreateAttendancePeriod)
-			admin.POST("/payroll/:period_id/process", handlers.ProcessPayroll)
-			admin.GET("/payroll/:period_id/summary", handlers.GeneratePayrollSummary)
+			admin.POST("/attendance-period", middleware.RequirePermission(rbac.AttendancePeriodManage), handlers.CreateAttendancePeriod)
+			admin.POST("/attendance", middleware.RequirePermission(rbac.AttendanceRecord), handlers.RecordAttendance)
+			admin.POST("/payroll/:period_id/process", middleware.RequirePermission(rbac.PayrollProcess), handlers.ProcessPayroll)
+			admin.GET("/payroll/:period_id/preview", middleware.RequirePermission(rbac.PayrollProcess), handlers.PreviewPayroll)
+			admin.GET("/payroll/:period_id", middleware.RequirePermission(rbac.PayrollViewSummary), handlers.GetPayrollRun)
+			admin.POST("/payroll/:period_id/approve", middleware.RequirePermission(rbac.PayrollApprove), handlers.ApprovePayroll)
+			admin.POST("/payroll/:period_id/reject", middleware.RequirePermission(rbac.PayrollApprove), handlers.RejectPayroll)
+			admin.POST("/payroll/:period_id/distribute", middleware.RequirePermission(rbac.PayslipDistribute), handlers.DistributePayslips)
+			admin.GET("/payroll/:period_id/distribution", middleware.RequirePermission(rbac.PayslipDistribute), handlers.GetPayslipDistribution)
+			admin.POST("/email-deliveries/:id/retry", middleware.RequirePermission(rbac.PayslipDistribute), handlers.RetryEmailDelivery)
+			admin.POST("/payroll/:period_id/disbursement", middleware.RequirePermission(rbac.DisbursementManage), handlers.GenerateDisbursementFile)
+			admin.GET("/payroll/:period_id/disbursement", middleware.NoImpersonation(), middleware.RequirePermission(rbac.DisbursementManage), handlers.DownloadDisbursementFile)
+			admin.POST("/payroll/:period_id/bank-statement", middleware.RequirePermission(rbac.DisbursementManage), handlers.ImportBankStatement)
+			admin.GET("/payroll/:period_id/reconciliation", middleware.RequirePermission(rbac.DisbursementManage), handlers.GetReconciliation)
+			admin.PUT("/employees/:id/bank-account", middleware.RequirePermission(rbac.EmployeeManage), handlers.SetBankAccount)
+			admin.PUT("/employees/:id/cost-center", middleware.RequirePermission(rbac.EmployeeManage), handlers.SetCostCenter)
+			admin.PUT("/employees/:id/employee-id", middleware.RequirePermission(rbac.EmployeeManage), handlers.SetEmployeeID)
+			admin.PUT("/employees/:id/birth-date", middleware.RequirePermission(rbac.EmployeeManage), handlers.SetBirthDate)
+			admin.PUT("/employees/:id/manager", middleware.RequirePermission(rbac.EmployeeManage), handlers.SetManager)
+			admin.GET("/payroll/:period_id/journal", middleware.RequirePermission(rbac.ReportView), handlers.GetPayrollJournal)
+			admin.PUT("/employees/:id/tax-profile", middleware.RequirePermission(rbac.EmployeeManage), handlers.SetTaxProfile)
+			admin.POST("/users/:id/revoke-sessions", middleware.RequirePermission(rbac.SecurityManage), handlers.RevokeSessions)
+			admin.POST("/users/:id/impersonate", middleware.UsersOnly(), middleware.RequirePermission(rbac.UserImpersonate), handlers.Impersonate)
+			admin.POST("/users/:id/unlock", middleware.RequirePermission(rbac.SecurityManage), handlers.UnlockUser)
+			admin.POST("/users/:id/reset-password", middleware.RequirePermission(rbac.SecurityManage), handlers.AdminResetPassword)
+			admin.GET("/lockouts", middleware.RequirePermission(rbac.SecurityManage), handlers.ListLockouts)
+			admin.DELETE("/lockouts/:id", middleware.RequirePermission(rbac.SecurityManage), handlers.Unlock)
+			admin.GET("/tax-certificates/:year", middleware.NoImpersonation(), middleware.RequirePermission(rbac.TaxReport), handlers.GetTaxCertificates)
+			admin.GET("/payroll/:period_id/filing", middleware.RequirePermission(rbac.TaxReport), handlers.ValidateFiling)
+			admin.GET("/payroll/:period_id/filing/:report", middleware.NoImpersonation(), middleware.RequirePermission(rbac.TaxReport), handlers.ExportFiling)
+			admin.GET("/payroll/:period_id/summary", middleware.RequirePermission(rbac.PayrollViewSummary), handlers.GeneratePayrollSummary)
+			admin.GET("/reports/payroll-variance", middleware.RequirePermission(rbac.ReportView), handlers.GetPayrollVariance)
+			admin.GET("/languages/:code", middleware.RequirePermission(rbac.LanguageManage), handlers.GetLanguage)
+			admin.PUT("/languages/:code", middleware.RequirePermission(rbac.LanguageManage), handlers.SaveLanguage)
+			admin.GET("/permissions", middleware.RequirePermission(rbac.RoleManage), handlers.ListPermissions)
+			admin.GET("/roles", middleware.RequirePermission(rbac.RoleManage), handlers.ListRoles)
+			admin.POST("/roles", middleware.RequirePermission(rbac.RoleManage), handlers.CreateRole)
+			admin.PUT("/roles/:id", middleware.RequirePermission(rbac.RoleManage), handlers.UpdateRole)
+			admin.DELETE("/roles/:id", middleware.RequirePermission(rbac.RoleManage), handlers.DeleteRole)
+			admin.GET("/users/:id/roles", middleware.RequirePermission(rbac.RoleManage), handlers.GetUserRoles)
+			admin.PUT("/users/:id/roles", middleware.RequirePermission(rbac.RoleManage), handlers.SetUserRoles)
+			admin.GET("/api-keys", middleware.RequirePermission(rbac.APIKeyManage), handlers.ListAPIKeys)
+			admin.POST("/api-keys", middleware.RequirePermission(rbac.APIKeyManage), handlers.CreateAPIKey)
+			admin.DELETE("/api-keys/:id", middleware.RequirePermission(rbac.APIKeyManage), handlers.RevokeAPIKey)
 		}
 	}
 }
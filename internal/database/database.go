@@ -1,10 +1,12 @@
 package database
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"math/rand"
 	"payslip-system/internal/models"
+	"payslip-system/internal/rbac"
 	"payslip-system/internal/repository"
 	"time"
 
@@ -35,23 +37,92 @@ func Initialize(databaseURL string) (*gorm.DB, error) {
 }
 
 func Migrate(db *gorm.DB) error {
+	// Users from before roles were introduced get the role they were created with
+	backfillRoles := !db.Migrator().HasTable(&models.UserRole{})
+
 	err := db.AutoMigrate(
 		&models.User{},
+		&models.Role{},
+		&models.RolePermission{},
+		&models.UserRole{},
 		&models.AttendancePeriod{},
 		&models.Attendance{},
 		&models.Overtime{},
 		&models.Reimbursement{},
+		&models.Leave{},
 		&models.Payroll{},
 		&models.PayrollItem{},
+		&models.PayrollApproval{},
 		&models.AuditLog{},
+		&models.IdempotencyKey{},
+		&models.Language{},
+		&models.EmailDelivery{},
+		&models.DisbursementFile{},
+		&models.BankTransaction{},
+		&models.TaxCertificateIssue{},
+		&models.Session{},
+		&models.RefreshToken{},
+		&models.MFAChallenge{},
+		&models.MFARecoveryCode{},
+		&models.SSOLogin{},
+		&models.APIKey{},
+		&models.APIKeyPermission{},
+		&models.LoginThrottle{},
+		&models.PasswordHistory{},
+		&models.PasswordResetToken{},
 	)
 	if err != nil {
 		return fmt.Errorf("failed to migrate database: %w", err)
 	}
 
+	if err := syncBuiltinRoles(db); err != nil {
+		return fmt.Errorf("failed to sync builtin roles: %w", err)
+	}
+
+	if backfillRoles {
+		err := db.Exec(`INSERT INTO user_roles (user_id, role_id, created_at)
+			SELECT u.id, r.id, NOW() FROM users u JOIN roles r ON r.name = u.role
+			ON CONFLICT DO NOTHING`).Error
+		if err != nil {
+			return fmt.Errorf("failed to grant roles: %w", err)
+		}
+	}
+
 	return nil
 }
 
+// syncBuiltinRoles makes the builtin roles in the database match their definitions
+func syncBuiltinRoles(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, builtin := range rbac.BuiltinRoles {
+			var role models.Role
+			err := tx.Where("name = ?", builtin.Name).First(&role).Error
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+
+			role.Name = builtin.Name
+			role.Description = builtin.Description
+			role.Builtin = true
+			if err := tx.Save(&role).Error; err != nil {
+				return err
+			}
+
+			if err := tx.Where("role_id = ?", role.ID).Delete(&models.RolePermission{}).Error; err != nil {
+				return err
+			}
+			permissions := make([]models.RolePermission, len(builtin.Permissions))
+			for i, p := range builtin.Permissions {
+				permissions[i] = models.RolePermission{RoleID: role.ID, Permission: p}
+			}
+			if err := tx.Create(&permissions).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func SeedDatabase(repos *repository.Repositories) error {
 	log.Println("Seeding database...")
 
@@ -76,7 +147,10 @@ func SeedDatabase(repos *repository.Repositories) error {
 		Username: "admin",
 		Password: string(hashedPassword),
 		Role:     "admin",
+		Email:    "admin@example.com",
 		IsActive: true,
+		// Seeded passwords are guessable, they only work to set a new one
+		MustChangePassword: true,
 	}
 
 	if err := repos.DB.Create(admin).Error; err != nil {
@@ -85,6 +159,8 @@ func SeedDatabase(repos *repository.Repositories) error {
 
 	// Create 100 fake employees
 	employees := make([]*models.User, 100)
+	costCenters := []string{"ENG", "OPS", "SALES"}
+	ptkpStatuses := []string{"TK/0", "K/0", "K/1", "K/2"}
 	for i := 0; i < 100; i++ {
 		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(fmt.Sprintf("employee%d", i+1)), bcrypt.DefaultCost)
 		if err != nil {
@@ -92,13 +168,23 @@ func SeedDatabase(repos *repository.Repositories) error {
 		}
 
 		salary := float64(rand.Intn(5000000-3000000) + 3000000) // Random salary between 3M - 8M
+		birthDate := time.Date(1970+rand.Intn(35), time.Month(rand.Intn(12)+1), rand.Intn(28)+1, 0, 0, 0, 0, time.UTC)
 
 		employees[i] = &models.User{
-			Username: fmt.Sprintf("employee%d", i+1),
-			Password: string(hashedPassword),
-			Role:     "employee",
-			Salary:   &salary,
-			IsActive: true,
+			Username:           fmt.Sprintf("employee%d", i+1),
+			Password:           string(hashedPassword),
+			Role:               "employee",
+			Salary:             &salary,
+			Email:              fmt.Sprintf("employee%d@example.com", i+1),
+			IsActive:           true,
+			BirthDate:          &birthDate,
+			BankCode:           "BCA",
+			BankAccountNumber:  fmt.Sprintf("88%08d", i+1),
+			BankAccountName:    fmt.Sprintf("Employee %d", i+1),
+			CostCenter:         costCenters[i%len(costCenters)],
+			NIK:                fmt.Sprintf("317401%s%04d", birthDate.Format("020106"), i+1),
+			PTKPStatus:         ptkpStatuses[i%len(ptkpStatuses)],
+			MustChangePassword: true,
 		}
 	}
 
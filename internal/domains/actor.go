@@ -0,0 +1,34 @@
+package domains
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ErrAPIKeyActor is returned for changes that need a person, such as deciding on payroll, when made with an
+// API key
+var ErrAPIKeyActor = errors.New("this requires a user, not an API key")
+
+// Actor is who a request is made by: a user, or an API key acting for no user. An admin viewing the API as a
+// user acts as that user, with ImpersonatorID set to the admin.
+type Actor struct {
+	UserID         *uuid.UUID
+	APIKeyID       *uuid.UUID
+	ImpersonatorID *uuid.UUID
+}
+
+// UserActor is a user acting as themselves
+func UserActor(userID uuid.UUID) Actor {
+	return Actor{UserID: &userID}
+}
+
+// APIKeyActor is an API key
+func APIKeyActor(keyID uuid.UUID) Actor {
+	return Actor{APIKeyID: &keyID}
+}
+
+// IsAPIKey reports whether the actor is an API key
+func (a Actor) IsAPIKey() bool {
+	return a.APIKeyID != nil
+}
@@ -0,0 +1,41 @@
+package domains
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrAPIKeyNotFound = errors.New("API key not found")
+	ErrAPIKeyRevoked  = errors.New("API key is already revoked")
+	ErrNotHeld        = errors.New("you can only grant permissions you hold")
+)
+
+// APIKeyRequest issues an API key
+type APIKeyRequest struct {
+	Name        string     `json:"name" binding:"required"` // what the key is for, e.g. 'Badge readers, Jakarta office'
+	Permissions []string   `json:"permissions" binding:"required"`
+	ExpiresAt   *time.Time `json:"expires_at"` // the longest allowed when not set
+}
+
+// APIKeySummary is an API key without the key itself
+type APIKeySummary struct {
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	Prefix      string     `json:"prefix"`
+	Permissions []string   `json:"permissions"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	LastUsedIP  string     `json:"last_used_ip,omitempty"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	CreatedBy   *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// IssuedAPIKey is a new API key. The key is shown only this once.
+type IssuedAPIKey struct {
+	APIKeySummary
+	Key string `json:"key"`
+}
@@ -0,0 +1,67 @@
+package domains
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+var (
+	// ErrInvalidRefreshToken is returned for unknown and expired refresh tokens
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	// ErrSessionRevoked is returned when a token of a logged out or revoked session is used
+	ErrSessionRevoked = errors.New("session expired or revoked")
+	// ErrRefreshTokenReused is returned when a rotated refresh token is presented again. The token may have
+	// been stolen, so the session is revoked and the user has to log in again.
+	ErrRefreshTokenReused = errors.New("refresh token already used, session revoked")
+	// ErrInvalidCredentials is returned for a wrong password and for unknown usernames alike
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrPasswordLoginDisabled is returned by password logins, changes and resets when only single sign-on is
+	// allowed
+	ErrPasswordLoginDisabled = errors.New("password login is disabled, log in with single sign-on")
+)
+
+// LoginThrottledError is returned when a login is attempted before the wait imposed by earlier failures is
+// over. The password is not checked.
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+	Locked     bool // locked out, rather than delayed after a failure
+}
+
+func (e *LoginThrottledError) Error() string {
+	if e.Locked {
+		return fmt.Sprintf("too many failed logins, locked for %d more minutes", int(math.Ceil(e.RetryAfter.Minutes())))
+	}
+	return fmt.Sprintf("too many failed logins, retry in %d seconds", e.RetryAfterSeconds())
+}
+
+// RetryAfterSeconds is the wait rounded up, for the Retry-After header
+func (e *LoginThrottledError) RetryAfterSeconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+// ResetThrottledError is returned when a username or IP address asked for too many password resets. Unknown
+// usernames are counted too.
+type ResetThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ResetThrottledError) Error() string {
+	return fmt.Sprintf("too many password reset requests, retry in %d minutes", int(math.Ceil(e.RetryAfter.Minutes())))
+}
+
+// RetryAfterSeconds is the wait rounded up, for the Retry-After header
+func (e *ResetThrottledError) RetryAfterSeconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+// TokenPair is what a login or refresh returns: an access token for API calls and the refresh token that
+// replaces it when it expires
+type TokenPair struct {
+	AccessToken      string    `json:"token"`
+	TokenType        string    `json:"token_type"`
+	ExpiresIn        int       `json:"expires_in"` // seconds the access token is valid
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
@@ -0,0 +1,63 @@
+package domains
+
+import (
+	"fmt"
+	"strings"
+
+	"payslip-system/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// BankAccount is an employee's salary account
+type BankAccount struct {
+	BankCode      string `json:"bank_code" binding:"required"`
+	AccountNumber string `json:"account_number" binding:"required"`
+	AccountName   string `json:"account_name" binding:"required"`
+}
+
+// MissingBankAccountsError is returned when a transfer file is requested while employees to be paid have no salary account
+type MissingBankAccountsError struct {
+	Employees []string
+}
+
+func (e *MissingBankAccountsError) Error() string {
+	return fmt.Sprintf("%d employees have no bank account: %s", len(e.Employees), strings.Join(e.Employees, ", "))
+}
+
+// StatementImportResult summarizes an imported bank statement
+type StatementImportResult struct {
+	PeriodID     uuid.UUID                `json:"period_id"`
+	Entries      int                      `json:"entries"`    // transfers read from the statement
+	Duplicates   int                      `json:"duplicates"` // imported before and skipped
+	Matched      int                      `json:"matched"`
+	Unmatched    int                      `json:"unmatched"`
+	Updated      int                      `json:"updated"` // payroll items whose payment status changed
+	Transactions []models.BankTransaction `json:"transactions"`
+}
+
+// ReconciliationItem is the payment of one payroll item
+type ReconciliationItem struct {
+	PayrollItemID uuid.UUID `json:"payroll_item_id"`
+	UserID        uuid.UUID `json:"user_id"`
+	Username      string    `json:"username"`
+	Amount        float64   `json:"amount"`
+	PaymentStatus string    `json:"payment_status"`
+	PaymentBatch  string    `json:"payment_batch,omitempty"`
+	Reason        string    `json:"reason,omitempty"`
+}
+
+// ReconciliationReport compares a payroll run with what the bank reported
+type ReconciliationReport struct {
+	PayrollID   uuid.UUID      `json:"payroll_id"`
+	PeriodID    uuid.UUID      `json:"period_id"`
+	Counts      map[string]int `json:"counts"` // payroll items by payment status
+	TotalAmount float64        `json:"total_amount"`
+	PaidAmount  float64        `json:"paid_amount"`
+	// Reissue are the failed and returned payments, to be paid again
+	Reissue []ReconciliationItem `json:"reissue"`
+	// Outstanding are the payments the bank has not reported on yet
+	Outstanding []ReconciliationItem `json:"outstanding"`
+	// Unmatched are the statement entries no payroll item was found for
+	Unmatched []models.BankTransaction `json:"unmatched"`
+}
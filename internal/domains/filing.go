@@ -0,0 +1,33 @@
+package domains
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// Filing validation issue codes, reported on the employees of a processed period before it is exported
+const (
+	IssueMissingNIK        = "missing_nik"
+	IssueMissingNPWP       = "missing_npwp"
+	IssueMissingPTKPStatus = "missing_ptkp_status"
+	IssueInvalidTaxID      = "invalid_tax_id"
+)
+
+// FilingValidation lists what keeps a processed period from being filed; errors block the export
+type FilingValidation struct {
+	PeriodID      uuid.UUID                `json:"period_id"`
+	Reports       []string                 `json:"reports"` // the layouts it can be exported in
+	EmployeeCount int                      `json:"employee_count"`
+	Issues        []PayrollValidationIssue `json:"issues"`
+	Valid         bool                     `json:"valid"`
+}
+
+// FilingValidationError is returned when a filing is exported while its validation reports errors
+type FilingValidationError struct {
+	Issues []PayrollValidationIssue
+}
+
+func (e *FilingValidationError) Error() string {
+	return fmt.Sprintf("filing has %d validation issues, resolve them before exporting", len(e.Issues))
+}
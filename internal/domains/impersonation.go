@@ -0,0 +1,45 @@
+package domains
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrImpersonationNotAllowed is returned for viewing as oneself, an inactive user or a user with administrative
+// permissions
+var ErrImpersonationNotAllowed = errors.New("only other active users without administrative permissions can be viewed as")
+
+// ImpersonationToken lets an admin view the API as a user, read-only, until it expires or the admin logs out.
+// It cannot be refreshed.
+type ImpersonationToken struct {
+	AccessToken string    `json:"token"`
+	TokenType   string    `json:"token_type"`
+	ExpiresIn   int       `json:"expires_in"` // seconds
+	ExpiresAt   time.Time `json:"expires_at"`
+	UserID      uuid.UUID `json:"user_id"`
+	Username    string    `json:"username"`
+}
+
+// ActivityEntry is an entry of a user's activity history: something they did, or a request an admin made
+// viewing as them
+type ActivityEntry struct {
+	ID        uuid.UUID `json:"id"`
+	Action    string    `json:"action"`
+	TableName string    `json:"table_name"`
+	RecordID  uuid.UUID `json:"record_id"`
+	IPAddress string    `json:"ip_address"`
+	RequestID string    `json:"request_id"`
+	CreatedAt time.Time `json:"created_at"`
+	// ViewedAsBy is the admin, for entries of an admin viewing as the user
+	ViewedAsBy *ActivityActor `json:"viewed_as_by,omitempty"`
+	// Details of viewing as the user: the reason given, or the request made
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// ActivityActor is who acted
+type ActivityActor struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Username string    `json:"username"`
+}
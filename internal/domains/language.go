@@ -0,0 +1,8 @@
+package domains
+
+// LanguageSummary describes an available payslip language
+type LanguageSummary struct {
+	Code        string   `json:"code"`
+	Name        string   `json:"name"`
+	MissingKeys []string `json:"missing_keys,omitempty"` // messages that fall back to the default language
+}
@@ -0,0 +1,42 @@
+package domains
+
+import "github.com/google/uuid"
+
+// Pay components posted by the payroll journal
+const (
+	LedgerSalaryExpense        = "salary_expense"
+	LedgerOvertimeExpense      = "overtime_expense"
+	LedgerReimbursementExpense = "reimbursement_expense"
+	LedgerBPJSExpense          = "bpjs_expense"
+	LedgerTaxPayable           = "tax_payable"
+	LedgerBPJSPayable          = "bpjs_payable"
+	LedgerNetSalaryPayable     = "net_salary_payable"
+)
+
+// PayrollJournal is the accounting journal of a final payroll run, with one balanced entry, or one per cost center
+type PayrollJournal struct {
+	Journal   string         `json:"journal"`
+	PayrollID uuid.UUID      `json:"payroll_id"`
+	PeriodID  uuid.UUID      `json:"period_id"`
+	Currency  string         `json:"currency"`
+	Entries   []JournalEntry `json:"entries"`
+}
+
+type JournalEntry struct {
+	Reference   string        `json:"reference"`
+	Date        string        `json:"date"` // posting date, the last day of the period
+	Description string        `json:"description"`
+	CostCenter  string        `json:"cost_center,omitempty"`
+	Lines       []JournalLine `json:"lines"`
+	TotalDebit  float64       `json:"total_debit"`
+	TotalCredit float64       `json:"total_credit"`
+}
+
+type JournalLine struct {
+	Account     string  `json:"account"`
+	Component   string  `json:"component"`
+	Description string  `json:"description"`
+	CostCenter  string  `json:"cost_center,omitempty"`
+	Debit       float64 `json:"debit"`
+	Credit      float64 `json:"credit"`
+}
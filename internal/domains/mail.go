@@ -0,0 +1,16 @@
+package domains
+
+import (
+	"payslip-system/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// PayslipDistributionReport is the per-recipient status of the payslip emails of a payroll run
+type PayslipDistributionReport struct {
+	PayrollID  uuid.UUID              `json:"payroll_id"`
+	PeriodID   uuid.UUID              `json:"period_id"`
+	Queued     int                    `json:"queued"` // queued now, in DistributePayslips responses
+	Counts     map[string]int         `json:"counts"` // deliveries per status
+	Deliveries []models.EmailDelivery `json:"deliveries"`
+}
@@ -0,0 +1,35 @@
+package domains
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrInvalidMFACode is returned for wrong, expired and already used TOTP and recovery codes
+	ErrInvalidMFACode = errors.New("invalid authentication code")
+	// ErrInvalidMFAChallenge is returned for unknown, expired, answered and exhausted login challenges
+	ErrInvalidMFAChallenge = errors.New("invalid or expired mfa challenge, log in again")
+	ErrMFAAlreadyEnabled   = errors.New("mfa is already enabled")
+	ErrMFANotEnrolled      = errors.New("mfa enrollment has not been started")
+	ErrMFANotEnabled       = errors.New("mfa is not enabled")
+	// ErrMFAMandatory is returned when a user with administrative permissions tries to turn MFA off
+	ErrMFAMandatory = errors.New("mfa is mandatory for users with administrative permissions")
+)
+
+// MFAChallenge is returned by a login with the right password when a TOTP code is needed before tokens are
+// issued
+type MFAChallenge struct {
+	Token     string    `json:"mfa_token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// EnrollmentRequired is set for users with administrative permissions who have not set up MFA yet; they
+	// enroll with the challenge first
+	EnrollmentRequired bool `json:"enrollment_required"`
+}
+
+// MFAEnrollment is what an authenticator app is set up with. The secret is shown for manual entry.
+type MFAEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+	QRCode string `json:"qr_code"` // PNG data URI of the otpauth URI
+}
@@ -5,8 +5,13 @@
 package mock_domains
 
 import (
+	context "context"
+	bankfile "payslip-system/internal/bankfile"
 	domains "payslip-system/internal/domains"
+	filing "payslip-system/internal/filing"
+	i18n "payslip-system/internal/i18n"
 	models "payslip-system/internal/models"
+	rbac "payslip-system/internal/rbac"
 	reflect "reflect"
 	time "time"
 
@@ -38,18 +43,63 @@ func (m *MockIAdminService) EXPECT() *MockIAdminServiceMockRecorder {
 }
 
 // CreateAttendancePeriod mocks base method.
-func (m *MockIAdminService) CreateAttendancePeriod(startDate, endDate time.Time, adminID uuid.UUID, ipAddress, requestID string) (*models.AttendancePeriod, error) {
+func (m *MockIAdminService) CreateAttendancePeriod(startDate, endDate time.Time, actor domains.Actor, ipAddress, requestID string) (*models.AttendancePeriod, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateAttendancePeriod", startDate, endDate, adminID, ipAddress, requestID)
+	ret := m.ctrl.Call(m, "CreateAttendancePeriod", startDate, endDate, actor, ipAddress, requestID)
 	ret0, _ := ret[0].(*models.AttendancePeriod)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateAttendancePeriod indicates an expected call of CreateAttendancePeriod.
-func (mr *MockIAdminServiceMockRecorder) CreateAttendancePeriod(startDate, endDate, adminID, ipAddress, requestID interface{}) *gomock.Call {
+func (mr *MockIAdminServiceMockRecorder) CreateAttendancePeriod(startDate, endDate, actor, ipAddress, requestID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAttendancePeriod", reflect.TypeOf((*MockIAdminService)(nil).CreateAttendancePeriod), startDate, endDate, adminID, ipAddress, requestID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAttendancePeriod", reflect.TypeOf((*MockIAdminService)(nil).CreateAttendancePeriod), startDate, endDate, actor, ipAddress, requestID)
+}
+
+// SetBirthDate mocks base method.
+func (m *MockIAdminService) SetBirthDate(userID uuid.UUID, birthDate *time.Time, actor domains.Actor, ipAddress, requestID string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBirthDate", userID, birthDate, actor, ipAddress, requestID)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetBirthDate indicates an expected call of SetBirthDate.
+func (mr *MockIAdminServiceMockRecorder) SetBirthDate(userID, birthDate, actor, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBirthDate", reflect.TypeOf((*MockIAdminService)(nil).SetBirthDate), userID, birthDate, actor, ipAddress, requestID)
+}
+
+// SetCostCenter mocks base method.
+func (m *MockIAdminService) SetCostCenter(userID uuid.UUID, costCenter string, actor domains.Actor, ipAddress, requestID string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCostCenter", userID, costCenter, actor, ipAddress, requestID)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetCostCenter indicates an expected call of SetCostCenter.
+func (mr *MockIAdminServiceMockRecorder) SetCostCenter(userID, costCenter, actor, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCostCenter", reflect.TypeOf((*MockIAdminService)(nil).SetCostCenter), userID, costCenter, actor, ipAddress, requestID)
+}
+
+// SetEmployeeID mocks base method.
+func (m *MockIAdminService) SetEmployeeID(userID uuid.UUID, employeeID string, actor domains.Actor, ipAddress, requestID string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetEmployeeID", userID, employeeID, actor, ipAddress, requestID)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetEmployeeID indicates an expected call of SetEmployeeID.
+func (mr *MockIAdminServiceMockRecorder) SetEmployeeID(userID, employeeID, actor, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEmployeeID", reflect.TypeOf((*MockIAdminService)(nil).SetEmployeeID), userID, employeeID, actor, ipAddress, requestID)
 }
 
 // MockIAttendanceService is a mock of IAttendanceService interface.
@@ -75,6 +125,20 @@ func (m *MockIAttendanceService) EXPECT() *MockIAttendanceServiceMockRecorder {
 	return m.recorder
 }
 
+// RecordAttendance mocks base method.
+func (m *MockIAttendanceService) RecordAttendance(employeeID string, date, checkInTime time.Time, actor domains.Actor, ipAddress, requestID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordAttendance", employeeID, date, checkInTime, actor, ipAddress, requestID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordAttendance indicates an expected call of RecordAttendance.
+func (mr *MockIAttendanceServiceMockRecorder) RecordAttendance(employeeID, date, checkInTime, actor, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordAttendance", reflect.TypeOf((*MockIAttendanceService)(nil).RecordAttendance), employeeID, date, checkInTime, actor, ipAddress, requestID)
+}
+
 // SubmitAttendance mocks base method.
 func (m *MockIAttendanceService) SubmitAttendance(userID uuid.UUID, date, checkInTime time.Time, ipAddress, requestID string) error {
 	m.ctrl.T.Helper()
@@ -112,20 +176,183 @@ func (m *MockIAuthService) EXPECT() *MockIAuthServiceMockRecorder {
 	return m.recorder
 }
 
+// CompleteMFAChallenge mocks base method.
+func (m *MockIAuthService) CompleteMFAChallenge(mfaToken, code, ipAddress, requestID string) (*models.User, []string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteMFAChallenge", mfaToken, code, ipAddress, requestID)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].([]string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CompleteMFAChallenge indicates an expected call of CompleteMFAChallenge.
+func (mr *MockIAuthServiceMockRecorder) CompleteMFAChallenge(mfaToken, code, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteMFAChallenge", reflect.TypeOf((*MockIAuthService)(nil).CompleteMFAChallenge), mfaToken, code, ipAddress, requestID)
+}
+
+// EnrollMFAChallenge mocks base method.
+func (m *MockIAuthService) EnrollMFAChallenge(mfaToken string) (*domains.MFAEnrollment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnrollMFAChallenge", mfaToken)
+	ret0, _ := ret[0].(*domains.MFAEnrollment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnrollMFAChallenge indicates an expected call of EnrollMFAChallenge.
+func (mr *MockIAuthServiceMockRecorder) EnrollMFAChallenge(mfaToken interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnrollMFAChallenge", reflect.TypeOf((*MockIAuthService)(nil).EnrollMFAChallenge), mfaToken)
+}
+
+// Impersonate mocks base method.
+func (m *MockIAuthService) Impersonate(userID, adminID, sessionID uuid.UUID, reason, ipAddress, requestID string) (*domains.ImpersonationToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Impersonate", userID, adminID, sessionID, reason, ipAddress, requestID)
+	ret0, _ := ret[0].(*domains.ImpersonationToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Impersonate indicates an expected call of Impersonate.
+func (mr *MockIAuthServiceMockRecorder) Impersonate(userID, adminID, sessionID, reason, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Impersonate", reflect.TypeOf((*MockIAuthService)(nil).Impersonate), userID, adminID, sessionID, reason, ipAddress, requestID)
+}
+
+// ListActivity mocks base method.
+func (m *MockIAuthService) ListActivity(userID uuid.UUID) ([]domains.ActivityEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActivity", userID)
+	ret0, _ := ret[0].([]domains.ActivityEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActivity indicates an expected call of ListActivity.
+func (mr *MockIAuthServiceMockRecorder) ListActivity(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActivity", reflect.TypeOf((*MockIAuthService)(nil).ListActivity), userID)
+}
+
+// ListLockouts mocks base method.
+func (m *MockIAuthService) ListLockouts() ([]models.LoginThrottle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLockouts")
+	ret0, _ := ret[0].([]models.LoginThrottle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLockouts indicates an expected call of ListLockouts.
+func (mr *MockIAuthServiceMockRecorder) ListLockouts() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLockouts", reflect.TypeOf((*MockIAuthService)(nil).ListLockouts))
+}
+
 // Login mocks base method.
-func (m *MockIAuthService) Login(username, password string) (*models.User, string, error) {
+func (m *MockIAuthService) Login(username, password, ipAddress, requestID string) (*models.User, *domains.MFAChallenge, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Login", username, password)
+	ret := m.ctrl.Call(m, "Login", username, password, ipAddress, requestID)
 	ret0, _ := ret[0].(*models.User)
-	ret1, _ := ret[1].(string)
+	ret1, _ := ret[1].(*domains.MFAChallenge)
 	ret2, _ := ret[2].(error)
 	return ret0, ret1, ret2
 }
 
 // Login indicates an expected call of Login.
-func (mr *MockIAuthServiceMockRecorder) Login(username, password interface{}) *gomock.Call {
+func (mr *MockIAuthServiceMockRecorder) Login(username, password, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockIAuthService)(nil).Login), username, password, ipAddress, requestID)
+}
+
+// Logout mocks base method.
+func (m *MockIAuthService) Logout(sessionID, userID uuid.UUID, ipAddress, requestID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", sessionID, userID, ipAddress, requestID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockIAuthServiceMockRecorder) Logout(sessionID, userID, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockIAuthService)(nil).Logout), sessionID, userID, ipAddress, requestID)
+}
+
+// Refresh mocks base method.
+func (m *MockIAuthService) Refresh(refreshToken, userAgent, ipAddress, requestID string) (*domains.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", refreshToken, userAgent, ipAddress, requestID)
+	ret0, _ := ret[0].(*domains.TokenPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockIAuthServiceMockRecorder) Refresh(refreshToken, userAgent, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockIAuthService)(nil).Refresh), refreshToken, userAgent, ipAddress, requestID)
+}
+
+// RevokeSessions mocks base method.
+func (m *MockIAuthService) RevokeSessions(userID, adminID uuid.UUID, ipAddress, requestID string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSessions", userID, adminID, ipAddress, requestID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeSessions indicates an expected call of RevokeSessions.
+func (mr *MockIAuthServiceMockRecorder) RevokeSessions(userID, adminID, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSessions", reflect.TypeOf((*MockIAuthService)(nil).RevokeSessions), userID, adminID, ipAddress, requestID)
+}
+
+// StartSession mocks base method.
+func (m *MockIAuthService) StartSession(user *models.User, userAgent, ipAddress, requestID string) (*domains.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartSession", user, userAgent, ipAddress, requestID)
+	ret0, _ := ret[0].(*domains.TokenPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartSession indicates an expected call of StartSession.
+func (mr *MockIAuthServiceMockRecorder) StartSession(user, userAgent, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartSession", reflect.TypeOf((*MockIAuthService)(nil).StartSession), user, userAgent, ipAddress, requestID)
+}
+
+// Unlock mocks base method.
+func (m *MockIAuthService) Unlock(lockoutID, adminID uuid.UUID, ipAddress, requestID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unlock", lockoutID, adminID, ipAddress, requestID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unlock indicates an expected call of Unlock.
+func (mr *MockIAuthServiceMockRecorder) Unlock(lockoutID, adminID, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlock", reflect.TypeOf((*MockIAuthService)(nil).Unlock), lockoutID, adminID, ipAddress, requestID)
+}
+
+// UnlockUser mocks base method.
+func (m *MockIAuthService) UnlockUser(userID, adminID uuid.UUID, ipAddress, requestID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlockUser", userID, adminID, ipAddress, requestID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnlockUser indicates an expected call of UnlockUser.
+func (mr *MockIAuthServiceMockRecorder) UnlockUser(userID, adminID, ipAddress, requestID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockIAuthService)(nil).Login), username, password)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockUser", reflect.TypeOf((*MockIAuthService)(nil).UnlockUser), userID, adminID, ipAddress, requestID)
 }
 
 // ValidateToken mocks base method.
@@ -143,143 +370,1429 @@ func (mr *MockIAuthServiceMockRecorder) ValidateToken(tokenString interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateToken", reflect.TypeOf((*MockIAuthService)(nil).ValidateToken), tokenString)
 }
 
-// MockIOvertimeService is a mock of IOvertimeService interface.
-type MockIOvertimeService struct {
+// MockISSOService is a mock of ISSOService interface.
+type MockISSOService struct {
 	ctrl     *gomock.Controller
-	recorder *MockIOvertimeServiceMockRecorder
+	recorder *MockISSOServiceMockRecorder
 }
 
-// MockIOvertimeServiceMockRecorder is the mock recorder for MockIOvertimeService.
-type MockIOvertimeServiceMockRecorder struct {
-	mock *MockIOvertimeService
+// MockISSOServiceMockRecorder is the mock recorder for MockISSOService.
+type MockISSOServiceMockRecorder struct {
+	mock *MockISSOService
 }
 
-// NewMockIOvertimeService creates a new mock instance.
-func NewMockIOvertimeService(ctrl *gomock.Controller) *MockIOvertimeService {
-	mock := &MockIOvertimeService{ctrl: ctrl}
-	mock.recorder = &MockIOvertimeServiceMockRecorder{mock}
+// NewMockISSOService creates a new mock instance.
+func NewMockISSOService(ctrl *gomock.Controller) *MockISSOService {
+	mock := &MockISSOService{ctrl: ctrl}
+	mock.recorder = &MockISSOServiceMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockIOvertimeService) EXPECT() *MockIOvertimeServiceMockRecorder {
+func (m *MockISSOService) EXPECT() *MockISSOServiceMockRecorder {
 	return m.recorder
 }
 
-// SubmitOvertime mocks base method.
-func (m *MockIOvertimeService) SubmitOvertime(userID uuid.UUID, date time.Time, hours float64, ipAddress, requestID string) error {
+// BeginLogin mocks base method.
+func (m *MockISSOService) BeginLogin(ipAddress string) (*domains.SSOLogin, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SubmitOvertime", userID, date, hours, ipAddress, requestID)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "BeginLogin", ipAddress)
+	ret0, _ := ret[0].(*domains.SSOLogin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// SubmitOvertime indicates an expected call of SubmitOvertime.
-func (mr *MockIOvertimeServiceMockRecorder) SubmitOvertime(userID, date, hours, ipAddress, requestID interface{}) *gomock.Call {
+// BeginLogin indicates an expected call of BeginLogin.
+func (mr *MockISSOServiceMockRecorder) BeginLogin(ipAddress interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitOvertime", reflect.TypeOf((*MockIOvertimeService)(nil).SubmitOvertime), userID, date, hours, ipAddress, requestID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginLogin", reflect.TypeOf((*MockISSOService)(nil).BeginLogin), ipAddress)
 }
 
-// MockIPayrollService is a mock of IPayrollService interface.
-type MockIPayrollService struct {
+// CompleteLogin mocks base method.
+func (m *MockISSOService) CompleteLogin(code, state, ipAddress, requestID string) (*models.User, *domains.MFAChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteLogin", code, state, ipAddress, requestID)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(*domains.MFAChallenge)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CompleteLogin indicates an expected call of CompleteLogin.
+func (mr *MockISSOServiceMockRecorder) CompleteLogin(code, state, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteLogin", reflect.TypeOf((*MockISSOService)(nil).CompleteLogin), code, state, ipAddress, requestID)
+}
+
+// MockIMFAService is a mock of IMFAService interface.
+type MockIMFAService struct {
 	ctrl     *gomock.Controller
-	recorder *MockIPayrollServiceMockRecorder
+	recorder *MockIMFAServiceMockRecorder
 }
 
-// MockIPayrollServiceMockRecorder is the mock recorder for MockIPayrollService.
-type MockIPayrollServiceMockRecorder struct {
-	mock *MockIPayrollService
+// MockIMFAServiceMockRecorder is the mock recorder for MockIMFAService.
+type MockIMFAServiceMockRecorder struct {
+	mock *MockIMFAService
 }
 
-// NewMockIPayrollService creates a new mock instance.
-func NewMockIPayrollService(ctrl *gomock.Controller) *MockIPayrollService {
-	mock := &MockIPayrollService{ctrl: ctrl}
-	mock.recorder = &MockIPayrollServiceMockRecorder{mock}
+// NewMockIMFAService creates a new mock instance.
+func NewMockIMFAService(ctrl *gomock.Controller) *MockIMFAService {
+	mock := &MockIMFAService{ctrl: ctrl}
+	mock.recorder = &MockIMFAServiceMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockIPayrollService) EXPECT() *MockIPayrollServiceMockRecorder {
+func (m *MockIMFAService) EXPECT() *MockIMFAServiceMockRecorder {
 	return m.recorder
 }
 
-// GeneratePayrollSummary mocks base method.
-func (m *MockIPayrollService) GeneratePayrollSummary(periodID uuid.UUID) (*domains.PayrollSummaryResponse, error) {
+// Confirm mocks base method.
+func (m *MockIMFAService) Confirm(userID uuid.UUID, code, ipAddress, requestID string) ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GeneratePayrollSummary", periodID)
-	ret0, _ := ret[0].(*domains.PayrollSummaryResponse)
+	ret := m.ctrl.Call(m, "Confirm", userID, code, ipAddress, requestID)
+	ret0, _ := ret[0].([]string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GeneratePayrollSummary indicates an expected call of GeneratePayrollSummary.
-func (mr *MockIPayrollServiceMockRecorder) GeneratePayrollSummary(periodID interface{}) *gomock.Call {
+// Confirm indicates an expected call of Confirm.
+func (mr *MockIMFAServiceMockRecorder) Confirm(userID, code, ipAddress, requestID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GeneratePayrollSummary", reflect.TypeOf((*MockIPayrollService)(nil).GeneratePayrollSummary), periodID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Confirm", reflect.TypeOf((*MockIMFAService)(nil).Confirm), userID, code, ipAddress, requestID)
 }
 
-// GeneratePayslip mocks base method.
-func (m *MockIPayrollService) GeneratePayslip(userID, periodID uuid.UUID) (*domains.PayslipResponse, error) {
+// Disable mocks base method.
+func (m *MockIMFAService) Disable(userID uuid.UUID, code, ipAddress, requestID string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GeneratePayslip", userID, periodID)
-	ret0, _ := ret[0].(*domains.PayslipResponse)
+	ret := m.ctrl.Call(m, "Disable", userID, code, ipAddress, requestID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Disable indicates an expected call of Disable.
+func (mr *MockIMFAServiceMockRecorder) Disable(userID, code, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Disable", reflect.TypeOf((*MockIMFAService)(nil).Disable), userID, code, ipAddress, requestID)
+}
+
+// Enroll mocks base method.
+func (m *MockIMFAService) Enroll(userID uuid.UUID) (*domains.MFAEnrollment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Enroll", userID)
+	ret0, _ := ret[0].(*domains.MFAEnrollment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GeneratePayslip indicates an expected call of GeneratePayslip.
-func (mr *MockIPayrollServiceMockRecorder) GeneratePayslip(userID, periodID interface{}) *gomock.Call {
+// Enroll indicates an expected call of Enroll.
+func (mr *MockIMFAServiceMockRecorder) Enroll(userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GeneratePayslip", reflect.TypeOf((*MockIPayrollService)(nil).GeneratePayslip), userID, periodID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Enroll", reflect.TypeOf((*MockIMFAService)(nil).Enroll), userID)
 }
 
-// ProcessPayroll mocks base method.
-func (m *MockIPayrollService) ProcessPayroll(periodID, adminID uuid.UUID, ipAddress, requestID string) error {
+// RegenerateRecoveryCodes mocks base method.
+func (m *MockIMFAService) RegenerateRecoveryCodes(userID uuid.UUID, code, ipAddress, requestID string) ([]string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ProcessPayroll", periodID, adminID, ipAddress, requestID)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "RegenerateRecoveryCodes", userID, code, ipAddress, requestID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// ProcessPayroll indicates an expected call of ProcessPayroll.
-func (mr *MockIPayrollServiceMockRecorder) ProcessPayroll(periodID, adminID, ipAddress, requestID interface{}) *gomock.Call {
+// RegenerateRecoveryCodes indicates an expected call of RegenerateRecoveryCodes.
+func (mr *MockIMFAServiceMockRecorder) RegenerateRecoveryCodes(userID, code, ipAddress, requestID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessPayroll", reflect.TypeOf((*MockIPayrollService)(nil).ProcessPayroll), periodID, adminID, ipAddress, requestID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegenerateRecoveryCodes", reflect.TypeOf((*MockIMFAService)(nil).RegenerateRecoveryCodes), userID, code, ipAddress, requestID)
 }
 
-// MockIReimbursementService is a mock of IReimbursementService interface.
-type MockIReimbursementService struct {
+// MockIPasswordService is a mock of IPasswordService interface.
+type MockIPasswordService struct {
 	ctrl     *gomock.Controller
-	recorder *MockIReimbursementServiceMockRecorder
+	recorder *MockIPasswordServiceMockRecorder
 }
 
-// MockIReimbursementServiceMockRecorder is the mock recorder for MockIReimbursementService.
-type MockIReimbursementServiceMockRecorder struct {
-	mock *MockIReimbursementService
+// MockIPasswordServiceMockRecorder is the mock recorder for MockIPasswordService.
+type MockIPasswordServiceMockRecorder struct {
+	mock *MockIPasswordService
 }
 
-// NewMockIReimbursementService creates a new mock instance.
-func NewMockIReimbursementService(ctrl *gomock.Controller) *MockIReimbursementService {
-	mock := &MockIReimbursementService{ctrl: ctrl}
-	mock.recorder = &MockIReimbursementServiceMockRecorder{mock}
+// NewMockIPasswordService creates a new mock instance.
+func NewMockIPasswordService(ctrl *gomock.Controller) *MockIPasswordService {
+	mock := &MockIPasswordService{ctrl: ctrl}
+	mock.recorder = &MockIPasswordServiceMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockIReimbursementService) EXPECT() *MockIReimbursementServiceMockRecorder {
+func (m *MockIPasswordService) EXPECT() *MockIPasswordServiceMockRecorder {
 	return m.recorder
 }
 
-// SubmitReimbursement mocks base method.
-func (m *MockIReimbursementService) SubmitReimbursement(userID uuid.UUID, amount float64, description, ipAddress, requestID string) error {
+// AdminReset mocks base method.
+func (m *MockIPasswordService) AdminReset(userID, adminID uuid.UUID, ipAddress, requestID string) (*domains.PasswordResetResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SubmitReimbursement", userID, amount, description, ipAddress, requestID)
+	ret := m.ctrl.Call(m, "AdminReset", userID, adminID, ipAddress, requestID)
+	ret0, _ := ret[0].(*domains.PasswordResetResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdminReset indicates an expected call of AdminReset.
+func (mr *MockIPasswordServiceMockRecorder) AdminReset(userID, adminID, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminReset", reflect.TypeOf((*MockIPasswordService)(nil).AdminReset), userID, adminID, ipAddress, requestID)
+}
+
+// ChangePassword mocks base method.
+func (m *MockIPasswordService) ChangePassword(userID, sessionID uuid.UUID, currentPassword, newPassword, ipAddress, requestID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", userID, sessionID, currentPassword, newPassword, ipAddress, requestID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SubmitReimbursement indicates an expected call of SubmitReimbursement.
-func (mr *MockIReimbursementServiceMockRecorder) SubmitReimbursement(userID, amount, description, ipAddress, requestID interface{}) *gomock.Call {
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockIPasswordServiceMockRecorder) ChangePassword(userID, sessionID, currentPassword, newPassword, ipAddress, requestID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitReimbursement", reflect.TypeOf((*MockIReimbursementService)(nil).SubmitReimbursement), userID, amount, description, ipAddress, requestID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockIPasswordService)(nil).ChangePassword), userID, sessionID, currentPassword, newPassword, ipAddress, requestID)
+}
+
+// RequestReset mocks base method.
+func (m *MockIPasswordService) RequestReset(username, ipAddress, requestID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestReset", username, ipAddress, requestID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestReset indicates an expected call of RequestReset.
+func (mr *MockIPasswordServiceMockRecorder) RequestReset(username, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestReset", reflect.TypeOf((*MockIPasswordService)(nil).RequestReset), username, ipAddress, requestID)
+}
+
+// ResetPassword mocks base method.
+func (m *MockIPasswordService) ResetPassword(token, newPassword, ipAddress, requestID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", token, newPassword, ipAddress, requestID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockIPasswordServiceMockRecorder) ResetPassword(token, newPassword, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockIPasswordService)(nil).ResetPassword), token, newPassword, ipAddress, requestID)
+}
+
+// SendResetEmail mocks base method.
+func (m *MockIPasswordService) SendResetEmail(delivery *models.EmailDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendResetEmail", delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendResetEmail indicates an expected call of SendResetEmail.
+func (mr *MockIPasswordServiceMockRecorder) SendResetEmail(delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendResetEmail", reflect.TypeOf((*MockIPasswordService)(nil).SendResetEmail), delivery)
+}
+
+// MockIRoleService is a mock of IRoleService interface.
+type MockIRoleService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIRoleServiceMockRecorder
+}
+
+// MockIRoleServiceMockRecorder is the mock recorder for MockIRoleService.
+type MockIRoleServiceMockRecorder struct {
+	mock *MockIRoleService
+}
+
+// NewMockIRoleService creates a new mock instance.
+func NewMockIRoleService(ctrl *gomock.Controller) *MockIRoleService {
+	mock := &MockIRoleService{ctrl: ctrl}
+	mock.recorder = &MockIRoleServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIRoleService) EXPECT() *MockIRoleServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateRole mocks base method.
+func (m *MockIRoleService) CreateRole(req domains.RoleRequest, adminID uuid.UUID, ipAddress, requestID string) (*domains.RoleSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRole", req, adminID, ipAddress, requestID)
+	ret0, _ := ret[0].(*domains.RoleSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRole indicates an expected call of CreateRole.
+func (mr *MockIRoleServiceMockRecorder) CreateRole(req, adminID, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRole", reflect.TypeOf((*MockIRoleService)(nil).CreateRole), req, adminID, ipAddress, requestID)
+}
+
+// DeleteRole mocks base method.
+func (m *MockIRoleService) DeleteRole(roleID, adminID uuid.UUID, ipAddress, requestID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRole", roleID, adminID, ipAddress, requestID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRole indicates an expected call of DeleteRole.
+func (mr *MockIRoleServiceMockRecorder) DeleteRole(roleID, adminID, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRole", reflect.TypeOf((*MockIRoleService)(nil).DeleteRole), roleID, adminID, ipAddress, requestID)
+}
+
+// GetUserRoles mocks base method.
+func (m *MockIRoleService) GetUserRoles(userID uuid.UUID) (*domains.UserRoles, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRoles", userID)
+	ret0, _ := ret[0].(*domains.UserRoles)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserRoles indicates an expected call of GetUserRoles.
+func (mr *MockIRoleServiceMockRecorder) GetUserRoles(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRoles", reflect.TypeOf((*MockIRoleService)(nil).GetUserRoles), userID)
+}
+
+// ListPermissions mocks base method.
+func (m *MockIRoleService) ListPermissions() []rbac.Permission {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPermissions")
+	ret0, _ := ret[0].([]rbac.Permission)
+	return ret0
+}
+
+// ListPermissions indicates an expected call of ListPermissions.
+func (mr *MockIRoleServiceMockRecorder) ListPermissions() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPermissions", reflect.TypeOf((*MockIRoleService)(nil).ListPermissions))
+}
+
+// ListRoles mocks base method.
+func (m *MockIRoleService) ListRoles() ([]domains.RoleSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRoles")
+	ret0, _ := ret[0].([]domains.RoleSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRoles indicates an expected call of ListRoles.
+func (mr *MockIRoleServiceMockRecorder) ListRoles() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRoles", reflect.TypeOf((*MockIRoleService)(nil).ListRoles))
+}
+
+// SetUserRoles mocks base method.
+func (m *MockIRoleService) SetUserRoles(userID uuid.UUID, roles []string, adminID uuid.UUID, ipAddress, requestID string) (*domains.UserRoles, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserRoles", userID, roles, adminID, ipAddress, requestID)
+	ret0, _ := ret[0].(*domains.UserRoles)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetUserRoles indicates an expected call of SetUserRoles.
+func (mr *MockIRoleServiceMockRecorder) SetUserRoles(userID, roles, adminID, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserRoles", reflect.TypeOf((*MockIRoleService)(nil).SetUserRoles), userID, roles, adminID, ipAddress, requestID)
+}
+
+// UpdateRole mocks base method.
+func (m *MockIRoleService) UpdateRole(roleID uuid.UUID, req domains.RoleRequest, adminID uuid.UUID, ipAddress, requestID string) (*domains.RoleSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRole", roleID, req, adminID, ipAddress, requestID)
+	ret0, _ := ret[0].(*domains.RoleSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRole indicates an expected call of UpdateRole.
+func (mr *MockIRoleServiceMockRecorder) UpdateRole(roleID, req, adminID, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockIRoleService)(nil).UpdateRole), roleID, req, adminID, ipAddress, requestID)
+}
+
+// MockIAPIKeyService is a mock of IAPIKeyService interface.
+type MockIAPIKeyService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAPIKeyServiceMockRecorder
+}
+
+// MockIAPIKeyServiceMockRecorder is the mock recorder for MockIAPIKeyService.
+type MockIAPIKeyServiceMockRecorder struct {
+	mock *MockIAPIKeyService
+}
+
+// NewMockIAPIKeyService creates a new mock instance.
+func NewMockIAPIKeyService(ctrl *gomock.Controller) *MockIAPIKeyService {
+	mock := &MockIAPIKeyService{ctrl: ctrl}
+	mock.recorder = &MockIAPIKeyServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAPIKeyService) EXPECT() *MockIAPIKeyServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockIAPIKeyService) CreateAPIKey(req domains.APIKeyRequest, adminID uuid.UUID, ipAddress, requestID string) (*domains.IssuedAPIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", req, adminID, ipAddress, requestID)
+	ret0, _ := ret[0].(*domains.IssuedAPIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockIAPIKeyServiceMockRecorder) CreateAPIKey(req, adminID, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockIAPIKeyService)(nil).CreateAPIKey), req, adminID, ipAddress, requestID)
+}
+
+// ListAPIKeys mocks base method.
+func (m *MockIAPIKeyService) ListAPIKeys() ([]domains.APIKeySummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAPIKeys")
+	ret0, _ := ret[0].([]domains.APIKeySummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAPIKeys indicates an expected call of ListAPIKeys.
+func (mr *MockIAPIKeyServiceMockRecorder) ListAPIKeys() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIKeys", reflect.TypeOf((*MockIAPIKeyService)(nil).ListAPIKeys))
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockIAPIKeyService) RevokeAPIKey(keyID, adminID uuid.UUID, ipAddress, requestID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", keyID, adminID, ipAddress, requestID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockIAPIKeyServiceMockRecorder) RevokeAPIKey(keyID, adminID, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockIAPIKeyService)(nil).RevokeAPIKey), keyID, adminID, ipAddress, requestID)
+}
+
+// MockIOvertimeService is a mock of IOvertimeService interface.
+type MockIOvertimeService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIOvertimeServiceMockRecorder
+}
+
+// MockIOvertimeServiceMockRecorder is the mock recorder for MockIOvertimeService.
+type MockIOvertimeServiceMockRecorder struct {
+	mock *MockIOvertimeService
+}
+
+// NewMockIOvertimeService creates a new mock instance.
+func NewMockIOvertimeService(ctrl *gomock.Controller) *MockIOvertimeService {
+	mock := &MockIOvertimeService{ctrl: ctrl}
+	mock.recorder = &MockIOvertimeServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIOvertimeService) EXPECT() *MockIOvertimeServiceMockRecorder {
+	return m.recorder
+}
+
+// SubmitOvertime mocks base method.
+func (m *MockIOvertimeService) SubmitOvertime(userID uuid.UUID, date time.Time, hours float64, ipAddress, requestID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitOvertime", userID, date, hours, ipAddress, requestID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SubmitOvertime indicates an expected call of SubmitOvertime.
+func (mr *MockIOvertimeServiceMockRecorder) SubmitOvertime(userID, date, hours, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitOvertime", reflect.TypeOf((*MockIOvertimeService)(nil).SubmitOvertime), userID, date, hours, ipAddress, requestID)
+}
+
+// MockIPayrollService is a mock of IPayrollService interface.
+type MockIPayrollService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIPayrollServiceMockRecorder
+}
+
+// MockIPayrollServiceMockRecorder is the mock recorder for MockIPayrollService.
+type MockIPayrollServiceMockRecorder struct {
+	mock *MockIPayrollService
+}
+
+// NewMockIPayrollService creates a new mock instance.
+func NewMockIPayrollService(ctrl *gomock.Controller) *MockIPayrollService {
+	mock := &MockIPayrollService{ctrl: ctrl}
+	mock.recorder = &MockIPayrollServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIPayrollService) EXPECT() *MockIPayrollServiceMockRecorder {
+	return m.recorder
+}
+
+// ApprovePayroll mocks base method.
+func (m *MockIPayrollService) ApprovePayroll(periodID uuid.UUID, actor domains.Actor, comment, ipAddress, requestID string) (*models.Payroll, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApprovePayroll", periodID, actor, comment, ipAddress, requestID)
+	ret0, _ := ret[0].(*models.Payroll)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApprovePayroll indicates an expected call of ApprovePayroll.
+func (mr *MockIPayrollServiceMockRecorder) ApprovePayroll(periodID, actor, comment, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApprovePayroll", reflect.TypeOf((*MockIPayrollService)(nil).ApprovePayroll), periodID, actor, comment, ipAddress, requestID)
+}
+
+// GeneratePayrollSummary mocks base method.
+func (m *MockIPayrollService) GeneratePayrollSummary(periodID uuid.UUID) (*domains.PayrollSummaryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GeneratePayrollSummary", periodID)
+	ret0, _ := ret[0].(*domains.PayrollSummaryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GeneratePayrollSummary indicates an expected call of GeneratePayrollSummary.
+func (mr *MockIPayrollServiceMockRecorder) GeneratePayrollSummary(periodID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GeneratePayrollSummary", reflect.TypeOf((*MockIPayrollService)(nil).GeneratePayrollSummary), periodID)
+}
+
+// GeneratePayslip mocks base method.
+func (m *MockIPayrollService) GeneratePayslip(userID, periodID uuid.UUID) (*domains.PayslipResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GeneratePayslip", userID, periodID)
+	ret0, _ := ret[0].(*domains.PayslipResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GeneratePayslip indicates an expected call of GeneratePayslip.
+func (mr *MockIPayrollServiceMockRecorder) GeneratePayslip(userID, periodID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GeneratePayslip", reflect.TypeOf((*MockIPayrollService)(nil).GeneratePayslip), userID, periodID)
+}
+
+// GetPayrollRun mocks base method.
+func (m *MockIPayrollService) GetPayrollRun(periodID uuid.UUID) (*models.Payroll, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPayrollRun", periodID)
+	ret0, _ := ret[0].(*models.Payroll)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPayrollRun indicates an expected call of GetPayrollRun.
+func (mr *MockIPayrollServiceMockRecorder) GetPayrollRun(periodID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPayrollRun", reflect.TypeOf((*MockIPayrollService)(nil).GetPayrollRun), periodID)
+}
+
+// PreviewPayroll mocks base method.
+func (m *MockIPayrollService) PreviewPayroll(periodID uuid.UUID) (*domains.PayrollPreviewResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreviewPayroll", periodID)
+	ret0, _ := ret[0].(*domains.PayrollPreviewResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreviewPayroll indicates an expected call of PreviewPayroll.
+func (mr *MockIPayrollServiceMockRecorder) PreviewPayroll(periodID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreviewPayroll", reflect.TypeOf((*MockIPayrollService)(nil).PreviewPayroll), periodID)
+}
+
+// ProcessPayroll mocks base method.
+func (m *MockIPayrollService) ProcessPayroll(periodID uuid.UUID, actor domains.Actor, ipAddress, requestID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessPayroll", periodID, actor, ipAddress, requestID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProcessPayroll indicates an expected call of ProcessPayroll.
+func (mr *MockIPayrollServiceMockRecorder) ProcessPayroll(periodID, actor, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessPayroll", reflect.TypeOf((*MockIPayrollService)(nil).ProcessPayroll), periodID, actor, ipAddress, requestID)
+}
+
+// RejectPayroll mocks base method.
+func (m *MockIPayrollService) RejectPayroll(periodID uuid.UUID, actor domains.Actor, reason, ipAddress, requestID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectPayroll", periodID, actor, reason, ipAddress, requestID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RejectPayroll indicates an expected call of RejectPayroll.
+func (mr *MockIPayrollServiceMockRecorder) RejectPayroll(periodID, actor, reason, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectPayroll", reflect.TypeOf((*MockIPayrollService)(nil).RejectPayroll), periodID, actor, reason, ipAddress, requestID)
+}
+
+// MockIReimbursementService is a mock of IReimbursementService interface.
+type MockIReimbursementService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIReimbursementServiceMockRecorder
+}
+
+// MockIReimbursementServiceMockRecorder is the mock recorder for MockIReimbursementService.
+type MockIReimbursementServiceMockRecorder struct {
+	mock *MockIReimbursementService
+}
+
+// NewMockIReimbursementService creates a new mock instance.
+func NewMockIReimbursementService(ctrl *gomock.Controller) *MockIReimbursementService {
+	mock := &MockIReimbursementService{ctrl: ctrl}
+	mock.recorder = &MockIReimbursementServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIReimbursementService) EXPECT() *MockIReimbursementServiceMockRecorder {
+	return m.recorder
+}
+
+// SubmitReimbursement mocks base method.
+func (m *MockIReimbursementService) SubmitReimbursement(userID uuid.UUID, amount float64, description, ipAddress, requestID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitReimbursement", userID, amount, description, ipAddress, requestID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SubmitReimbursement indicates an expected call of SubmitReimbursement.
+func (mr *MockIReimbursementServiceMockRecorder) SubmitReimbursement(userID, amount, description, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitReimbursement", reflect.TypeOf((*MockIReimbursementService)(nil).SubmitReimbursement), userID, amount, description, ipAddress, requestID)
+}
+
+// MockILeaveService is a mock of ILeaveService interface.
+type MockILeaveService struct {
+	ctrl     *gomock.Controller
+	recorder *MockILeaveServiceMockRecorder
+}
+
+// MockILeaveServiceMockRecorder is the mock recorder for MockILeaveService.
+type MockILeaveServiceMockRecorder struct {
+	mock *MockILeaveService
+}
+
+// NewMockILeaveService creates a new mock instance.
+func NewMockILeaveService(ctrl *gomock.Controller) *MockILeaveService {
+	mock := &MockILeaveService{ctrl: ctrl}
+	mock.recorder = &MockILeaveServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockILeaveService) EXPECT() *MockILeaveServiceMockRecorder {
+	return m.recorder
+}
+
+// ListLeave mocks base method.
+func (m *MockILeaveService) ListLeave(userID uuid.UUID) ([]models.Leave, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLeave", userID)
+	ret0, _ := ret[0].([]models.Leave)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLeave indicates an expected call of ListLeave.
+func (mr *MockILeaveServiceMockRecorder) ListLeave(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLeave", reflect.TypeOf((*MockILeaveService)(nil).ListLeave), userID)
+}
+
+// SubmitLeave mocks base method.
+func (m *MockILeaveService) SubmitLeave(userID uuid.UUID, req domains.LeaveRequest, ipAddress, requestID string) (*models.Leave, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitLeave", userID, req, ipAddress, requestID)
+	ret0, _ := ret[0].(*models.Leave)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubmitLeave indicates an expected call of SubmitLeave.
+func (mr *MockILeaveServiceMockRecorder) SubmitLeave(userID, req, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitLeave", reflect.TypeOf((*MockILeaveService)(nil).SubmitLeave), userID, req, ipAddress, requestID)
+}
+
+// MockITeamService is a mock of ITeamService interface.
+type MockITeamService struct {
+	ctrl     *gomock.Controller
+	recorder *MockITeamServiceMockRecorder
+}
+
+// MockITeamServiceMockRecorder is the mock recorder for MockITeamService.
+type MockITeamServiceMockRecorder struct {
+	mock *MockITeamService
+}
+
+// NewMockITeamService creates a new mock instance.
+func NewMockITeamService(ctrl *gomock.Controller) *MockITeamService {
+	mock := &MockITeamService{ctrl: ctrl}
+	mock.recorder = &MockITeamServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockITeamService) EXPECT() *MockITeamServiceMockRecorder {
+	return m.recorder
+}
+
+// Decide mocks base method.
+func (m *MockITeamService) Decide(approverID uuid.UUID, kind string, id uuid.UUID, approve bool, comment, ipAddress, requestID string) (*domains.TeamRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decide", approverID, kind, id, approve, comment, ipAddress, requestID)
+	ret0, _ := ret[0].(*domains.TeamRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Decide indicates an expected call of Decide.
+func (mr *MockITeamServiceMockRecorder) Decide(approverID, kind, id, approve, comment, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decide", reflect.TypeOf((*MockITeamService)(nil).Decide), approverID, kind, id, approve, comment, ipAddress, requestID)
+}
+
+// GetTeam mocks base method.
+func (m *MockITeamService) GetTeam(managerID uuid.UUID) ([]domains.TeamMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTeam", managerID)
+	ret0, _ := ret[0].([]domains.TeamMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTeam indicates an expected call of GetTeam.
+func (mr *MockITeamServiceMockRecorder) GetTeam(managerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTeam", reflect.TypeOf((*MockITeamService)(nil).GetTeam), managerID)
+}
+
+// ListRequests mocks base method.
+func (m *MockITeamService) ListRequests(approverID uuid.UUID, kind, status string) ([]domains.TeamRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRequests", approverID, kind, status)
+	ret0, _ := ret[0].([]domains.TeamRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRequests indicates an expected call of ListRequests.
+func (mr *MockITeamServiceMockRecorder) ListRequests(approverID, kind, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRequests", reflect.TypeOf((*MockITeamService)(nil).ListRequests), approverID, kind, status)
+}
+
+// SetManager mocks base method.
+func (m *MockITeamService) SetManager(userID uuid.UUID, managerID *uuid.UUID, actor domains.Actor, ipAddress, requestID string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetManager", userID, managerID, actor, ipAddress, requestID)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetManager indicates an expected call of SetManager.
+func (mr *MockITeamServiceMockRecorder) SetManager(userID, managerID, actor, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetManager", reflect.TypeOf((*MockITeamService)(nil).SetManager), userID, managerID, actor, ipAddress, requestID)
+}
+
+// MockIReportService is a mock of IReportService interface.
+type MockIReportService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIReportServiceMockRecorder
+}
+
+// MockIReportServiceMockRecorder is the mock recorder for MockIReportService.
+type MockIReportServiceMockRecorder struct {
+	mock *MockIReportService
+}
+
+// NewMockIReportService creates a new mock instance.
+func NewMockIReportService(ctrl *gomock.Controller) *MockIReportService {
+	mock := &MockIReportService{ctrl: ctrl}
+	mock.recorder = &MockIReportServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIReportService) EXPECT() *MockIReportServiceMockRecorder {
+	return m.recorder
+}
+
+// ExportPayrollJournalCSV mocks base method.
+func (m *MockIReportService) ExportPayrollJournalCSV(journal *domains.PayrollJournal) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportPayrollJournalCSV", journal)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportPayrollJournalCSV indicates an expected call of ExportPayrollJournalCSV.
+func (mr *MockIReportServiceMockRecorder) ExportPayrollJournalCSV(journal interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportPayrollJournalCSV", reflect.TypeOf((*MockIReportService)(nil).ExportPayrollJournalCSV), journal)
+}
+
+// ExportPayrollVarianceCSV mocks base method.
+func (m *MockIReportService) ExportPayrollVarianceCSV(report *domains.PayrollVarianceReport) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportPayrollVarianceCSV", report)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportPayrollVarianceCSV indicates an expected call of ExportPayrollVarianceCSV.
+func (mr *MockIReportServiceMockRecorder) ExportPayrollVarianceCSV(report interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportPayrollVarianceCSV", reflect.TypeOf((*MockIReportService)(nil).ExportPayrollVarianceCSV), report)
+}
+
+// GetPayrollJournal mocks base method.
+func (m *MockIReportService) GetPayrollJournal(periodID uuid.UUID, byCostCenter bool) (*domains.PayrollJournal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPayrollJournal", periodID, byCostCenter)
+	ret0, _ := ret[0].(*domains.PayrollJournal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPayrollJournal indicates an expected call of GetPayrollJournal.
+func (mr *MockIReportServiceMockRecorder) GetPayrollJournal(periodID, byCostCenter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPayrollJournal", reflect.TypeOf((*MockIReportService)(nil).GetPayrollJournal), periodID, byCostCenter)
+}
+
+// GetPayrollVariance mocks base method.
+func (m *MockIReportService) GetPayrollVariance(fromPeriodID, toPeriodID uuid.UUID, thresholdPercent float64) (*domains.PayrollVarianceReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPayrollVariance", fromPeriodID, toPeriodID, thresholdPercent)
+	ret0, _ := ret[0].(*domains.PayrollVarianceReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPayrollVariance indicates an expected call of GetPayrollVariance.
+func (mr *MockIReportServiceMockRecorder) GetPayrollVariance(fromPeriodID, toPeriodID, thresholdPercent interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPayrollVariance", reflect.TypeOf((*MockIReportService)(nil).GetPayrollVariance), fromPeriodID, toPeriodID, thresholdPercent)
+}
+
+// MockIPayslipService is a mock of IPayslipService interface.
+type MockIPayslipService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIPayslipServiceMockRecorder
+}
+
+// MockIPayslipServiceMockRecorder is the mock recorder for MockIPayslipService.
+type MockIPayslipServiceMockRecorder struct {
+	mock *MockIPayslipService
+}
+
+// NewMockIPayslipService creates a new mock instance.
+func NewMockIPayslipService(ctrl *gomock.Controller) *MockIPayslipService {
+	mock := &MockIPayslipService{ctrl: ctrl}
+	mock.recorder = &MockIPayslipServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIPayslipService) EXPECT() *MockIPayslipServiceMockRecorder {
+	return m.recorder
+}
+
+// Localize mocks base method.
+func (m *MockIPayslipService) Localize(payslip *domains.PayslipResponse, language string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Localize", payslip, language)
+}
+
+// Localize indicates an expected call of Localize.
+func (mr *MockIPayslipServiceMockRecorder) Localize(payslip, language interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Localize", reflect.TypeOf((*MockIPayslipService)(nil).Localize), payslip, language)
+}
+
+// RenderPDF mocks base method.
+func (m *MockIPayslipService) RenderPDF(payslip *domains.PayslipResponse, language string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenderPDF", payslip, language)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenderPDF indicates an expected call of RenderPDF.
+func (mr *MockIPayslipServiceMockRecorder) RenderPDF(payslip, language interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenderPDF", reflect.TypeOf((*MockIPayslipService)(nil).RenderPDF), payslip, language)
+}
+
+// SetPIN mocks base method.
+func (m *MockIPayslipService) SetPIN(userID uuid.UUID, pin, ipAddress, requestID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPIN", userID, pin, ipAddress, requestID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPIN indicates an expected call of SetPIN.
+func (mr *MockIPayslipServiceMockRecorder) SetPIN(userID, pin, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPIN", reflect.TypeOf((*MockIPayslipService)(nil).SetPIN), userID, pin, ipAddress, requestID)
+}
+
+// SigningCertificate mocks base method.
+func (m *MockIPayslipService) SigningCertificate() []byte {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SigningCertificate")
+	ret0, _ := ret[0].([]byte)
+	return ret0
+}
+
+// SigningCertificate indicates an expected call of SigningCertificate.
+func (mr *MockIPayslipServiceMockRecorder) SigningCertificate() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SigningCertificate", reflect.TypeOf((*MockIPayslipService)(nil).SigningCertificate))
+}
+
+// Verify mocks base method.
+func (m *MockIPayslipService) Verify(token string) (*domains.PayslipVerification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", token)
+	ret0, _ := ret[0].(*domains.PayslipVerification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockIPayslipServiceMockRecorder) Verify(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockIPayslipService)(nil).Verify), token)
+}
+
+// MockILanguageService is a mock of ILanguageService interface.
+type MockILanguageService struct {
+	ctrl     *gomock.Controller
+	recorder *MockILanguageServiceMockRecorder
+}
+
+// MockILanguageServiceMockRecorder is the mock recorder for MockILanguageService.
+type MockILanguageServiceMockRecorder struct {
+	mock *MockILanguageService
+}
+
+// NewMockILanguageService creates a new mock instance.
+func NewMockILanguageService(ctrl *gomock.Controller) *MockILanguageService {
+	mock := &MockILanguageService{ctrl: ctrl}
+	mock.recorder = &MockILanguageServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockILanguageService) EXPECT() *MockILanguageServiceMockRecorder {
+	return m.recorder
+}
+
+// GetLanguage mocks base method.
+func (m *MockILanguageService) GetLanguage(code string) (*i18n.Catalog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLanguage", code)
+	ret0, _ := ret[0].(*i18n.Catalog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLanguage indicates an expected call of GetLanguage.
+func (mr *MockILanguageServiceMockRecorder) GetLanguage(code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLanguage", reflect.TypeOf((*MockILanguageService)(nil).GetLanguage), code)
+}
+
+// ListLanguages mocks base method.
+func (m *MockILanguageService) ListLanguages() []domains.LanguageSummary {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLanguages")
+	ret0, _ := ret[0].([]domains.LanguageSummary)
+	return ret0
+}
+
+// ListLanguages indicates an expected call of ListLanguages.
+func (mr *MockILanguageServiceMockRecorder) ListLanguages() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLanguages", reflect.TypeOf((*MockILanguageService)(nil).ListLanguages))
+}
+
+// SaveLanguage mocks base method.
+func (m *MockILanguageService) SaveLanguage(catalog *i18n.Catalog, actor domains.Actor, ipAddress, requestID string) (*domains.LanguageSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveLanguage", catalog, actor, ipAddress, requestID)
+	ret0, _ := ret[0].(*domains.LanguageSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveLanguage indicates an expected call of SaveLanguage.
+func (mr *MockILanguageServiceMockRecorder) SaveLanguage(catalog, actor, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveLanguage", reflect.TypeOf((*MockILanguageService)(nil).SaveLanguage), catalog, actor, ipAddress, requestID)
+}
+
+// SetPreferredLanguage mocks base method.
+func (m *MockILanguageService) SetPreferredLanguage(userID uuid.UUID, language, ipAddress, requestID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPreferredLanguage", userID, language, ipAddress, requestID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPreferredLanguage indicates an expected call of SetPreferredLanguage.
+func (mr *MockILanguageServiceMockRecorder) SetPreferredLanguage(userID, language, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPreferredLanguage", reflect.TypeOf((*MockILanguageService)(nil).SetPreferredLanguage), userID, language, ipAddress, requestID)
+}
+
+// Translator mocks base method.
+func (m *MockILanguageService) Translator(languages ...string) *i18n.Translator {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range languages {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Translator", varargs...)
+	ret0, _ := ret[0].(*i18n.Translator)
+	return ret0
+}
+
+// Translator indicates an expected call of Translator.
+func (mr *MockILanguageServiceMockRecorder) Translator(languages ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Translator", reflect.TypeOf((*MockILanguageService)(nil).Translator), languages...)
+}
+
+// MockIPayslipMailService is a mock of IPayslipMailService interface.
+type MockIPayslipMailService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIPayslipMailServiceMockRecorder
+}
+
+// MockIPayslipMailServiceMockRecorder is the mock recorder for MockIPayslipMailService.
+type MockIPayslipMailServiceMockRecorder struct {
+	mock *MockIPayslipMailService
+}
+
+// NewMockIPayslipMailService creates a new mock instance.
+func NewMockIPayslipMailService(ctrl *gomock.Controller) *MockIPayslipMailService {
+	mock := &MockIPayslipMailService{ctrl: ctrl}
+	mock.recorder = &MockIPayslipMailServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIPayslipMailService) EXPECT() *MockIPayslipMailServiceMockRecorder {
+	return m.recorder
+}
+
+// DistributePayslips mocks base method.
+func (m *MockIPayslipMailService) DistributePayslips(periodID uuid.UUID, actor domains.Actor, ipAddress, requestID string) (*domains.PayslipDistributionReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DistributePayslips", periodID, actor, ipAddress, requestID)
+	ret0, _ := ret[0].(*domains.PayslipDistributionReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DistributePayslips indicates an expected call of DistributePayslips.
+func (mr *MockIPayslipMailServiceMockRecorder) DistributePayslips(periodID, actor, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DistributePayslips", reflect.TypeOf((*MockIPayslipMailService)(nil).DistributePayslips), periodID, actor, ipAddress, requestID)
+}
+
+// GetDistribution mocks base method.
+func (m *MockIPayslipMailService) GetDistribution(periodID uuid.UUID) (*domains.PayslipDistributionReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDistribution", periodID)
+	ret0, _ := ret[0].(*domains.PayslipDistributionReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDistribution indicates an expected call of GetDistribution.
+func (mr *MockIPayslipMailServiceMockRecorder) GetDistribution(periodID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDistribution", reflect.TypeOf((*MockIPayslipMailService)(nil).GetDistribution), periodID)
+}
+
+// PayrollFinalized mocks base method.
+func (m *MockIPayslipMailService) PayrollFinalized(periodID uuid.UUID, actor domains.Actor, ipAddress, requestID string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "PayrollFinalized", periodID, actor, ipAddress, requestID)
+}
+
+// PayrollFinalized indicates an expected call of PayrollFinalized.
+func (mr *MockIPayslipMailServiceMockRecorder) PayrollFinalized(periodID, actor, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PayrollFinalized", reflect.TypeOf((*MockIPayslipMailService)(nil).PayrollFinalized), periodID, actor, ipAddress, requestID)
+}
+
+// ProcessQueue mocks base method.
+func (m *MockIPayslipMailService) ProcessQueue() (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessQueue")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProcessQueue indicates an expected call of ProcessQueue.
+func (mr *MockIPayslipMailServiceMockRecorder) ProcessQueue() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessQueue", reflect.TypeOf((*MockIPayslipMailService)(nil).ProcessQueue))
+}
+
+// RetryDelivery mocks base method.
+func (m *MockIPayslipMailService) RetryDelivery(deliveryID uuid.UUID, actor domains.Actor, ipAddress, requestID string) (*models.EmailDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RetryDelivery", deliveryID, actor, ipAddress, requestID)
+	ret0, _ := ret[0].(*models.EmailDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RetryDelivery indicates an expected call of RetryDelivery.
+func (mr *MockIPayslipMailServiceMockRecorder) RetryDelivery(deliveryID, actor, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryDelivery", reflect.TypeOf((*MockIPayslipMailService)(nil).RetryDelivery), deliveryID, actor, ipAddress, requestID)
+}
+
+// Run mocks base method.
+func (m *MockIPayslipMailService) Run(ctx context.Context) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Run", ctx)
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockIPayslipMailServiceMockRecorder) Run(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockIPayslipMailService)(nil).Run), ctx)
+}
+
+// SetEmail mocks base method.
+func (m *MockIPayslipMailService) SetEmail(userID uuid.UUID, email, ipAddress, requestID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetEmail", userID, email, ipAddress, requestID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetEmail indicates an expected call of SetEmail.
+func (mr *MockIPayslipMailServiceMockRecorder) SetEmail(userID, email, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEmail", reflect.TypeOf((*MockIPayslipMailService)(nil).SetEmail), userID, email, ipAddress, requestID)
+}
+
+// MockIDisbursementService is a mock of IDisbursementService interface.
+type MockIDisbursementService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIDisbursementServiceMockRecorder
+}
+
+// MockIDisbursementServiceMockRecorder is the mock recorder for MockIDisbursementService.
+type MockIDisbursementServiceMockRecorder struct {
+	mock *MockIDisbursementService
+}
+
+// NewMockIDisbursementService creates a new mock instance.
+func NewMockIDisbursementService(ctrl *gomock.Controller) *MockIDisbursementService {
+	mock := &MockIDisbursementService{ctrl: ctrl}
+	mock.recorder = &MockIDisbursementServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIDisbursementService) EXPECT() *MockIDisbursementServiceMockRecorder {
+	return m.recorder
+}
+
+// GenerateFile mocks base method.
+func (m *MockIDisbursementService) GenerateFile(periodID uuid.UUID, format string, executionDate time.Time, reissue bool, actor domains.Actor, ipAddress, requestID string) (*bankfile.File, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateFile", periodID, format, executionDate, reissue, actor, ipAddress, requestID)
+	ret0, _ := ret[0].(*bankfile.File)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateFile indicates an expected call of GenerateFile.
+func (mr *MockIDisbursementServiceMockRecorder) GenerateFile(periodID, format, executionDate, reissue, actor, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateFile", reflect.TypeOf((*MockIDisbursementService)(nil).GenerateFile), periodID, format, executionDate, reissue, actor, ipAddress, requestID)
+}
+
+// GetFile mocks base method.
+func (m *MockIDisbursementService) GetFile(periodID uuid.UUID, reference string) (*bankfile.File, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFile", periodID, reference)
+	ret0, _ := ret[0].(*bankfile.File)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFile indicates an expected call of GetFile.
+func (mr *MockIDisbursementServiceMockRecorder) GetFile(periodID, reference interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFile", reflect.TypeOf((*MockIDisbursementService)(nil).GetFile), periodID, reference)
+}
+
+// SetBankAccount mocks base method.
+func (m *MockIDisbursementService) SetBankAccount(userID uuid.UUID, account domains.BankAccount, actor domains.Actor, ipAddress, requestID string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBankAccount", userID, account, actor, ipAddress, requestID)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetBankAccount indicates an expected call of SetBankAccount.
+func (mr *MockIDisbursementServiceMockRecorder) SetBankAccount(userID, account, actor, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBankAccount", reflect.TypeOf((*MockIDisbursementService)(nil).SetBankAccount), userID, account, actor, ipAddress, requestID)
+}
+
+// MockIReconciliationService is a mock of IReconciliationService interface.
+type MockIReconciliationService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIReconciliationServiceMockRecorder
+}
+
+// MockIReconciliationServiceMockRecorder is the mock recorder for MockIReconciliationService.
+type MockIReconciliationServiceMockRecorder struct {
+	mock *MockIReconciliationService
+}
+
+// NewMockIReconciliationService creates a new mock instance.
+func NewMockIReconciliationService(ctrl *gomock.Controller) *MockIReconciliationService {
+	mock := &MockIReconciliationService{ctrl: ctrl}
+	mock.recorder = &MockIReconciliationServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIReconciliationService) EXPECT() *MockIReconciliationServiceMockRecorder {
+	return m.recorder
+}
+
+// GetReconciliation mocks base method.
+func (m *MockIReconciliationService) GetReconciliation(periodID uuid.UUID) (*domains.ReconciliationReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReconciliation", periodID)
+	ret0, _ := ret[0].(*domains.ReconciliationReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReconciliation indicates an expected call of GetReconciliation.
+func (mr *MockIReconciliationServiceMockRecorder) GetReconciliation(periodID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReconciliation", reflect.TypeOf((*MockIReconciliationService)(nil).GetReconciliation), periodID)
+}
+
+// ImportStatement mocks base method.
+func (m *MockIReconciliationService) ImportStatement(periodID uuid.UUID, format string, data []byte, actor domains.Actor, ipAddress, requestID string) (*domains.StatementImportResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportStatement", periodID, format, data, actor, ipAddress, requestID)
+	ret0, _ := ret[0].(*domains.StatementImportResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportStatement indicates an expected call of ImportStatement.
+func (mr *MockIReconciliationServiceMockRecorder) ImportStatement(periodID, format, data, actor, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportStatement", reflect.TypeOf((*MockIReconciliationService)(nil).ImportStatement), periodID, format, data, actor, ipAddress, requestID)
+}
+
+// MockITaxService is a mock of ITaxService interface.
+type MockITaxService struct {
+	ctrl     *gomock.Controller
+	recorder *MockITaxServiceMockRecorder
+}
+
+// MockITaxServiceMockRecorder is the mock recorder for MockITaxService.
+type MockITaxServiceMockRecorder struct {
+	mock *MockITaxService
+}
+
+// NewMockITaxService creates a new mock instance.
+func NewMockITaxService(ctrl *gomock.Controller) *MockITaxService {
+	mock := &MockITaxService{ctrl: ctrl}
+	mock.recorder = &MockITaxServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockITaxService) EXPECT() *MockITaxServiceMockRecorder {
+	return m.recorder
+}
+
+// ArchiveTaxCertificates mocks base method.
+func (m *MockITaxService) ArchiveTaxCertificates(certificates []domains.TaxCertificate) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchiveTaxCertificates", certificates)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ArchiveTaxCertificates indicates an expected call of ArchiveTaxCertificates.
+func (mr *MockITaxServiceMockRecorder) ArchiveTaxCertificates(certificates interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveTaxCertificates", reflect.TypeOf((*MockITaxService)(nil).ArchiveTaxCertificates), certificates)
+}
+
+// GetTaxCertificate mocks base method.
+func (m *MockITaxService) GetTaxCertificate(userID uuid.UUID, year int) (*domains.TaxCertificate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaxCertificate", userID, year)
+	ret0, _ := ret[0].(*domains.TaxCertificate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaxCertificate indicates an expected call of GetTaxCertificate.
+func (mr *MockITaxServiceMockRecorder) GetTaxCertificate(userID, year interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaxCertificate", reflect.TypeOf((*MockITaxService)(nil).GetTaxCertificate), userID, year)
+}
+
+// GetTaxCertificates mocks base method.
+func (m *MockITaxService) GetTaxCertificates(year int) ([]domains.TaxCertificate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaxCertificates", year)
+	ret0, _ := ret[0].([]domains.TaxCertificate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaxCertificates indicates an expected call of GetTaxCertificates.
+func (mr *MockITaxServiceMockRecorder) GetTaxCertificates(year interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaxCertificates", reflect.TypeOf((*MockITaxService)(nil).GetTaxCertificates), year)
+}
+
+// RenderCertificatePDF mocks base method.
+func (m *MockITaxService) RenderCertificatePDF(certificate *domains.TaxCertificate) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenderCertificatePDF", certificate)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenderCertificatePDF indicates an expected call of RenderCertificatePDF.
+func (mr *MockITaxServiceMockRecorder) RenderCertificatePDF(certificate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenderCertificatePDF", reflect.TypeOf((*MockITaxService)(nil).RenderCertificatePDF), certificate)
+}
+
+// SetTaxProfile mocks base method.
+func (m *MockITaxService) SetTaxProfile(userID uuid.UUID, profile domains.TaxProfile, actor domains.Actor, ipAddress, requestID string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTaxProfile", userID, profile, actor, ipAddress, requestID)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetTaxProfile indicates an expected call of SetTaxProfile.
+func (mr *MockITaxServiceMockRecorder) SetTaxProfile(userID, profile, actor, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTaxProfile", reflect.TypeOf((*MockITaxService)(nil).SetTaxProfile), userID, profile, actor, ipAddress, requestID)
+}
+
+// MockIFilingService is a mock of IFilingService interface.
+type MockIFilingService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIFilingServiceMockRecorder
+}
+
+// MockIFilingServiceMockRecorder is the mock recorder for MockIFilingService.
+type MockIFilingServiceMockRecorder struct {
+	mock *MockIFilingService
+}
+
+// NewMockIFilingService creates a new mock instance.
+func NewMockIFilingService(ctrl *gomock.Controller) *MockIFilingService {
+	mock := &MockIFilingService{ctrl: ctrl}
+	mock.recorder = &MockIFilingServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIFilingService) EXPECT() *MockIFilingServiceMockRecorder {
+	return m.recorder
+}
+
+// ExportFiling mocks base method.
+func (m *MockIFilingService) ExportFiling(periodID uuid.UUID, report string, actor domains.Actor, ipAddress, requestID string) (*filing.File, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportFiling", periodID, report, actor, ipAddress, requestID)
+	ret0, _ := ret[0].(*filing.File)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportFiling indicates an expected call of ExportFiling.
+func (mr *MockIFilingServiceMockRecorder) ExportFiling(periodID, report, actor, ipAddress, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportFiling", reflect.TypeOf((*MockIFilingService)(nil).ExportFiling), periodID, report, actor, ipAddress, requestID)
+}
+
+// ValidateFiling mocks base method.
+func (m *MockIFilingService) ValidateFiling(periodID uuid.UUID) (*domains.FilingValidation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateFiling", periodID)
+	ret0, _ := ret[0].(*domains.FilingValidation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidateFiling indicates an expected call of ValidateFiling.
+func (mr *MockIFilingServiceMockRecorder) ValidateFiling(periodID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateFiling", reflect.TypeOf((*MockIFilingService)(nil).ValidateFiling), periodID)
 }
@@ -0,0 +1,32 @@
+package domains
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrWrongPassword = errors.New("current password is incorrect")
+	// ErrInvalidResetToken is returned for unknown, expired and used password reset tokens
+	ErrInvalidResetToken = errors.New("invalid or expired reset token")
+	ErrPasswordReused    = errors.New("password was used recently, choose a different one")
+)
+
+// PasswordPolicyError is returned when a new password breaks the password policy
+type PasswordPolicyError struct {
+	Violations []string
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return "password " + strings.Join(e.Violations, ", ")
+}
+
+// PasswordResetResult is the outcome of an admin reset. The reset token is only returned when it could not be
+// emailed, for the admin to hand over.
+type PasswordResetResult struct {
+	Emailed         bool      `json:"emailed"`
+	ResetToken      string    `json:"reset_token,omitempty"`
+	ExpiresAt       time.Time `json:"expires_at"`
+	SessionsRevoked int64     `json:"sessions_revoked"`
+}
@@ -1,6 +1,21 @@
 package domains
 
-import "payslip-system/internal/models"
+import (
+	"errors"
+	"time"
+
+	"payslip-system/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ErrPayslipPasswordUnavailable is returned when a protected payslip is requested for an
+// employee with neither a payslip PIN nor a recorded birthdate
+var ErrPayslipPasswordUnavailable = errors.New("no payslip password available, set a payslip PIN first")
+
+// ErrPayslipNotVerifiable is returned for unknown or tampered verification tokens and for
+// payslips that were rejected after issue
+var ErrPayslipNotVerifiable = errors.New("payslip could not be verified")
 
 type PayslipResponse struct {
 	Employee            *models.User             `json:"employee"`
@@ -14,15 +29,93 @@ type PayslipResponse struct {
 	Reimbursements      []models.Reimbursement   `json:"reimbursements"`
 	ReimbursementAmount float64                  `json:"reimbursement_amount"`
 	TotalAmount         float64                  `json:"total_amount"`
+
+	// Statutory deductions, zero when the payroll does not withhold them
+	TaxAmount float64                   `json:"tax_amount"`
+	BPJS      *models.BPJSContributions `json:"bpjs,omitempty"`
+
+	// PayrollItemID is set once the payslip is issued from a finalized payroll
+	PayrollItemID *uuid.UUID `json:"payroll_item_id,omitempty"`
+
+	Localized *LocalizedPayslip `json:"localized,omitempty"`
+}
+
+// LocalizedPayslip carries the labels and formatted values for displaying a payslip in one language
+type LocalizedPayslip struct {
+	Language      string            `json:"language"`
+	Labels        map[string]string `json:"labels"`
+	Period        string            `json:"period"`
+	Amounts       map[string]string `json:"amounts"`
+	AmountInWords string            `json:"amount_in_words,omitempty"`
+}
+
+// PayslipVerification is what third parties see when checking a payslip's QR code
+type PayslipVerification struct {
+	Valid        bool      `json:"valid"`
+	EmployeeName string    `json:"employee_name"`
+	PeriodStart  time.Time `json:"period_start"`
+	PeriodEnd    time.Time `json:"period_end"`
+	NetPay       float64   `json:"net_pay"`
+	Currency     string    `json:"currency"`
 }
 
 type PayrollSummaryResponse struct {
 	Period      *models.AttendancePeriod `json:"period"`
 	Employees   []EmployeeSummary        `json:"employees"`
 	TotalAmount float64                  `json:"total_amount"`
+	// Provisional figures are calculated live or of a run awaiting approval, and may still change
+	Provisional bool `json:"provisional"`
 }
 
 type EmployeeSummary struct {
 	Employee    *models.User `json:"employee"`
 	TotalAmount float64      `json:"total_amount"`
 }
+
+// Payroll validation issue codes reported by a payroll preview
+const (
+	IssueMissingSalary             = "missing_salary"
+	IssueZeroAttendance            = "zero_attendance"
+	IssueOvertimeWithoutAttendance = "overtime_without_attendance"
+	IssueLargeReimbursement        = "large_reimbursement"
+	IssueNegativeNetPay            = "negative_net_pay"
+	IssueMissingTaxID              = "missing_tax_id"
+	IssuePendingApproval           = "pending_approval"
+)
+
+// Payroll validation issue severities; errors should be resolved before processing
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+type PayrollPreviewResponse struct {
+	Period        *models.AttendancePeriod `json:"period"`
+	Items         []PayrollPreviewItem     `json:"items"`
+	Issues        []PayrollValidationIssue `json:"issues"`
+	EmployeeCount int                      `json:"employee_count"`
+	TotalAmount   float64                  `json:"total_amount"`
+	Valid         bool                     `json:"valid"`
+}
+
+type PayrollPreviewItem struct {
+	Employee            *models.User `json:"employee"`
+	BaseSalary          float64      `json:"base_salary"`
+	AttendanceDays      int          `json:"attendance_days"`
+	WorkingDays         int          `json:"working_days"`
+	AttendanceAmount    float64      `json:"attendance_amount"`
+	OvertimeHours       float64      `json:"overtime_hours"`
+	OvertimeAmount      float64      `json:"overtime_amount"`
+	ReimbursementAmount float64      `json:"reimbursement_amount"`
+	TaxAmount           float64      `json:"tax_amount"`
+	BPJSAmount          float64      `json:"bpjs_amount"` // employee shares withheld
+	TotalAmount         float64      `json:"total_amount"`
+}
+
+type PayrollValidationIssue struct {
+	EmployeeID uuid.UUID `json:"employee_id"`
+	Username   string    `json:"username"`
+	Code       string    `json:"code"`
+	Severity   string    `json:"severity"`
+	Message    string    `json:"message"`
+}
@@ -0,0 +1,40 @@
+package domains
+
+import "payslip-system/internal/models"
+
+// Payroll components compared by the variance report
+const (
+	ComponentBaseSalary    = "base_salary"
+	ComponentAttendance    = "attendance"
+	ComponentOvertime      = "overtime"
+	ComponentReimbursement = "reimbursement"
+	ComponentDeductions    = "deductions"
+	ComponentNetPay        = "net_pay"
+)
+
+type PayrollVarianceReport struct {
+	FromPeriod       *models.AttendancePeriod `json:"from_period"`
+	ToPeriod         *models.AttendancePeriod `json:"to_period"`
+	ThresholdPercent float64                  `json:"threshold_percent"`
+	Employees        []EmployeeVariance       `json:"employees"`
+	Joiners          []EmployeeSummary        `json:"joiners"`
+	Leavers          []EmployeeSummary        `json:"leavers"`
+	Totals           []ComponentVariance      `json:"totals"`
+	FlaggedCount     int                      `json:"flagged_count"`
+}
+
+type EmployeeVariance struct {
+	Employee   *models.User        `json:"employee"`
+	Components []ComponentVariance `json:"components"`
+	Flagged    bool                `json:"flagged"`
+}
+
+type ComponentVariance struct {
+	Component string  `json:"component"`
+	Previous  float64 `json:"previous"`
+	Current   float64 `json:"current"`
+	Change    float64 `json:"change"`
+	// ChangePercent is nil when the previous amount was zero
+	ChangePercent *float64 `json:"change_percent"`
+	Flagged       bool     `json:"flagged"`
+}
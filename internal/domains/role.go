@@ -0,0 +1,39 @@
+package domains
+
+import (
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrRoleNotFound = errors.New("role not found")
+	ErrRoleExists   = errors.New("a role with this name already exists")
+	ErrBuiltinRole  = errors.New("builtin roles cannot be changed")
+	ErrRoleInUse    = errors.New("role is held by users, take it away from them first")
+	// ErrLastRoleManager keeps at least one active user able to manage roles
+	ErrLastRoleManager = errors.New("no other active user could manage roles")
+)
+
+// RoleRequest creates or changes a role
+type RoleRequest struct {
+	Name        string   `json:"name"` // lowercase letters, digits and underscores; ignored when changing a role
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions" binding:"required"`
+}
+
+// RoleSummary is a role with the permissions it grants
+type RoleSummary struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Builtin     bool      `json:"builtin"`
+	Permissions []string  `json:"permissions"`
+}
+
+// UserRoles is the roles a user holds and the permissions they add up to
+type UserRoles struct {
+	UserID      uuid.UUID `json:"user_id"`
+	Roles       []string  `json:"roles"`
+	Permissions []string  `json:"permissions"`
+}
@@ -1,24 +1,80 @@
 package domains
 
 import (
+	"context"
+	"payslip-system/internal/bankfile"
+	"payslip-system/internal/filing"
+	"payslip-system/internal/i18n"
 	"payslip-system/internal/models"
+	"payslip-system/internal/rbac"
 	"time"
 
 	"github.com/google/uuid"
 )
 
-//go:generate mockgen -destination=mocks/mocks.go -source=service.go IAdminService, IAttendanceService, IAuthService, IOvertimeService, IPayrollService, IReimbursementService
+//go:generate mockgen -destination=mocks/mocks.go -source=service.go IAdminService, IAttendanceService, IAuthService, ISSOService, IMFAService, IPasswordService, IRoleService, IAPIKeyService, IOvertimeService, IPayrollService, IReimbursementService, ILeaveService, ITeamService, IReportService, IPayslipService, ILanguageService, IPayslipMailService, IDisbursementService, IReconciliationService, ITaxService, IFilingService
 type IAdminService interface {
-	CreateAttendancePeriod(startDate, endDate time.Time, adminID uuid.UUID, ipAddress, requestID string) (*models.AttendancePeriod, error)
+	CreateAttendancePeriod(startDate, endDate time.Time, actor Actor, ipAddress, requestID string) (*models.AttendancePeriod, error)
+	SetCostCenter(userID uuid.UUID, costCenter string, actor Actor, ipAddress, requestID string) (*models.User, error)
+	SetEmployeeID(userID uuid.UUID, employeeID string, actor Actor, ipAddress, requestID string) (*models.User, error)
+	SetBirthDate(userID uuid.UUID, birthDate *time.Time, actor Actor, ipAddress, requestID string) (*models.User, error)
 }
 
 type IAttendanceService interface {
 	SubmitAttendance(userID uuid.UUID, date time.Time, checkInTime time.Time, ipAddress, requestID string) error
+	RecordAttendance(employeeID string, date time.Time, checkInTime time.Time, actor Actor, ipAddress, requestID string) error
 }
 
 type IAuthService interface {
-	Login(username, password string) (*models.User, string, error)
+	Login(username, password, ipAddress, requestID string) (*models.User, *MFAChallenge, error)
+	EnrollMFAChallenge(mfaToken string) (*MFAEnrollment, error)
+	CompleteMFAChallenge(mfaToken, code, ipAddress, requestID string) (*models.User, []string, error)
 	ValidateToken(tokenString string) (*models.User, error)
+	StartSession(user *models.User, userAgent, ipAddress, requestID string) (*TokenPair, error)
+	Refresh(refreshToken, userAgent, ipAddress, requestID string) (*TokenPair, error)
+	Logout(sessionID, userID uuid.UUID, ipAddress, requestID string) error
+	RevokeSessions(userID, adminID uuid.UUID, ipAddress, requestID string) (int64, error)
+	ListLockouts() ([]models.LoginThrottle, error)
+	UnlockUser(userID, adminID uuid.UUID, ipAddress, requestID string) error
+	Unlock(lockoutID, adminID uuid.UUID, ipAddress, requestID string) error
+	Impersonate(userID, adminID, sessionID uuid.UUID, reason, ipAddress, requestID string) (*ImpersonationToken, error)
+	ListActivity(userID uuid.UUID) ([]ActivityEntry, error)
+}
+
+type ISSOService interface {
+	BeginLogin(ipAddress string) (*SSOLogin, error)
+	CompleteLogin(code, state, ipAddress, requestID string) (*models.User, *MFAChallenge, error)
+}
+
+type IMFAService interface {
+	Enroll(userID uuid.UUID) (*MFAEnrollment, error)
+	Confirm(userID uuid.UUID, code, ipAddress, requestID string) ([]string, error)
+	Disable(userID uuid.UUID, code, ipAddress, requestID string) error
+	RegenerateRecoveryCodes(userID uuid.UUID, code, ipAddress, requestID string) ([]string, error)
+}
+
+type IPasswordService interface {
+	ChangePassword(userID, sessionID uuid.UUID, currentPassword, newPassword, ipAddress, requestID string) error
+	RequestReset(username, ipAddress, requestID string) error
+	SendResetEmail(delivery *models.EmailDelivery) error
+	ResetPassword(token, newPassword, ipAddress, requestID string) error
+	AdminReset(userID, adminID uuid.UUID, ipAddress, requestID string) (*PasswordResetResult, error)
+}
+
+type IRoleService interface {
+	ListPermissions() []rbac.Permission
+	ListRoles() ([]RoleSummary, error)
+	CreateRole(req RoleRequest, adminID uuid.UUID, ipAddress, requestID string) (*RoleSummary, error)
+	UpdateRole(roleID uuid.UUID, req RoleRequest, adminID uuid.UUID, ipAddress, requestID string) (*RoleSummary, error)
+	DeleteRole(roleID, adminID uuid.UUID, ipAddress, requestID string) error
+	GetUserRoles(userID uuid.UUID) (*UserRoles, error)
+	SetUserRoles(userID uuid.UUID, roles []string, adminID uuid.UUID, ipAddress, requestID string) (*UserRoles, error)
+}
+
+type IAPIKeyService interface {
+	ListAPIKeys() ([]APIKeySummary, error)
+	CreateAPIKey(req APIKeyRequest, adminID uuid.UUID, ipAddress, requestID string) (*IssuedAPIKey, error)
+	RevokeAPIKey(keyID, adminID uuid.UUID, ipAddress, requestID string) error
 }
 
 type IOvertimeService interface {
@@ -28,9 +84,82 @@ type IOvertimeService interface {
 type IPayrollService interface {
 	GeneratePayslip(userID, periodID uuid.UUID) (*PayslipResponse, error)
 	GeneratePayrollSummary(periodID uuid.UUID) (*PayrollSummaryResponse, error)
-	ProcessPayroll(periodID, adminID uuid.UUID, ipAddress, requestID string) error
+	ProcessPayroll(periodID uuid.UUID, actor Actor, ipAddress, requestID string) error
+	PreviewPayroll(periodID uuid.UUID) (*PayrollPreviewResponse, error)
+	GetPayrollRun(periodID uuid.UUID) (*models.Payroll, error)
+	ApprovePayroll(periodID uuid.UUID, actor Actor, comment, ipAddress, requestID string) (*models.Payroll, error)
+	RejectPayroll(periodID uuid.UUID, actor Actor, reason, ipAddress, requestID string) error
 }
 
 type IReimbursementService interface {
 	SubmitReimbursement(userID uuid.UUID, amount float64, description, ipAddress, requestID string) error
 }
+
+type ILeaveService interface {
+	SubmitLeave(userID uuid.UUID, req LeaveRequest, ipAddress, requestID string) (*models.Leave, error)
+	ListLeave(userID uuid.UUID) ([]models.Leave, error)
+}
+
+type ITeamService interface {
+	SetManager(userID uuid.UUID, managerID *uuid.UUID, actor Actor, ipAddress, requestID string) (*models.User, error)
+	GetTeam(managerID uuid.UUID) ([]TeamMember, error)
+	ListRequests(approverID uuid.UUID, kind, status string) ([]TeamRequest, error)
+	Decide(approverID uuid.UUID, kind string, id uuid.UUID, approve bool, comment, ipAddress, requestID string) (*TeamRequest, error)
+}
+
+type IReportService interface {
+	GetPayrollVariance(fromPeriodID, toPeriodID uuid.UUID, thresholdPercent float64) (*PayrollVarianceReport, error)
+	ExportPayrollVarianceCSV(report *PayrollVarianceReport) ([]byte, error)
+	GetPayrollJournal(periodID uuid.UUID, byCostCenter bool) (*PayrollJournal, error)
+	ExportPayrollJournalCSV(journal *PayrollJournal) ([]byte, error)
+}
+
+type IPayslipService interface {
+	RenderPDF(payslip *PayslipResponse, language string) ([]byte, error)
+	Localize(payslip *PayslipResponse, language string)
+	SetPIN(userID uuid.UUID, pin, ipAddress, requestID string) error
+	SigningCertificate() []byte
+	Verify(token string) (*PayslipVerification, error)
+}
+
+type ILanguageService interface {
+	ListLanguages() []LanguageSummary
+	GetLanguage(code string) (*i18n.Catalog, error)
+	SaveLanguage(catalog *i18n.Catalog, actor Actor, ipAddress, requestID string) (*LanguageSummary, error)
+	SetPreferredLanguage(userID uuid.UUID, language, ipAddress, requestID string) error
+	Translator(languages ...string) *i18n.Translator
+}
+
+type IPayslipMailService interface {
+	DistributePayslips(periodID uuid.UUID, actor Actor, ipAddress, requestID string) (*PayslipDistributionReport, error)
+	PayrollFinalized(periodID uuid.UUID, actor Actor, ipAddress, requestID string)
+	GetDistribution(periodID uuid.UUID) (*PayslipDistributionReport, error)
+	RetryDelivery(deliveryID uuid.UUID, actor Actor, ipAddress, requestID string) (*models.EmailDelivery, error)
+	SetEmail(userID uuid.UUID, email, ipAddress, requestID string) error
+	ProcessQueue() (int, error)
+	Run(ctx context.Context)
+}
+
+type IDisbursementService interface {
+	GenerateFile(periodID uuid.UUID, format string, executionDate time.Time, reissue bool, actor Actor, ipAddress, requestID string) (*bankfile.File, error)
+	GetFile(periodID uuid.UUID, reference string) (*bankfile.File, error)
+	SetBankAccount(userID uuid.UUID, account BankAccount, actor Actor, ipAddress, requestID string) (*models.User, error)
+}
+
+type IReconciliationService interface {
+	ImportStatement(periodID uuid.UUID, format string, data []byte, actor Actor, ipAddress, requestID string) (*StatementImportResult, error)
+	GetReconciliation(periodID uuid.UUID) (*ReconciliationReport, error)
+}
+
+type ITaxService interface {
+	SetTaxProfile(userID uuid.UUID, profile TaxProfile, actor Actor, ipAddress, requestID string) (*models.User, error)
+	GetTaxCertificate(userID uuid.UUID, year int) (*TaxCertificate, error)
+	GetTaxCertificates(year int) ([]TaxCertificate, error)
+	RenderCertificatePDF(certificate *TaxCertificate) ([]byte, error)
+	ArchiveTaxCertificates(certificates []TaxCertificate) ([]byte, error)
+}
+
+type IFilingService interface {
+	ValidateFiling(periodID uuid.UUID) (*FilingValidation, error)
+	ExportFiling(periodID uuid.UUID, report string, actor Actor, ipAddress, requestID string) (*filing.File, error)
+}
@@ -0,0 +1,27 @@
+package domains
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrSSODisabled = errors.New("single sign-on is not enabled")
+	// ErrInvalidSSOLogin is returned for unknown, expired and completed single sign-on logins
+	ErrInvalidSSOLogin = errors.New("invalid or expired single sign-on login")
+	// ErrSSOFailed is returned when the identity provider refuses the code or its ID token does not verify;
+	// the details are logged
+	ErrSSOFailed = errors.New("single sign-on failed")
+	// ErrSSONoUser is returned when no active user matches the identity, or the match is ambiguous
+	ErrSSONoUser = errors.New("no active user matches your identity")
+	// ErrSSONoRole is returned when role mapping is configured and none of the user's groups is mapped
+	ErrSSONoRole = errors.New("none of your groups grants access")
+)
+
+// SSOLogin starts a single sign-on login. The client sends the user to AuthorizationURL and, when the
+// provider redirects back, checks that the state it returns is State before passing the code on.
+type SSOLogin struct {
+	AuthorizationURL string    `json:"authorization_url"`
+	State            string    `json:"state"`
+	ExpiresAt        time.Time `json:"expires_at"`
+}
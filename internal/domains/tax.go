@@ -0,0 +1,70 @@
+package domains
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrTaxCertificateNotFound is returned when an employee was not paid by a final payroll run in the tax year
+var ErrTaxCertificateNotFound = errors.New("no processed payroll in this tax year")
+
+// TaxProfile is what PPh 21 is withheld and reported with; NIK and NPWP may be left empty
+type TaxProfile struct {
+	NIK        string `json:"nik"`
+	NPWP       string `json:"npwp"`
+	PTKPStatus string `json:"ptkp_status" binding:"required"` // TK/0 to TK/3 or K/0 to K/3
+}
+
+// TaxCertificate is the Form 1721-A1 of one employee and tax year: the income paid, its deductions and
+// the PPh 21 withheld, aggregated over the processed payroll items of the year so far. The numbered
+// fields follow the lines of the form.
+type TaxCertificate struct {
+	Number     string `json:"number"`
+	TaxYear    int    `json:"tax_year"`
+	FirstMonth int    `json:"first_month"` // masa perolehan penghasilan
+	LastMonth  int    `json:"last_month"`
+	Currency   string `json:"currency"`
+
+	EmployerName string `json:"employer_name"`
+	EmployerNPWP string `json:"employer_npwp"`
+
+	UserID     uuid.UUID `json:"user_id"`
+	Username   string    `json:"username"`
+	NIK        string    `json:"nik"`
+	NPWP       string    `json:"npwp"`
+	PTKPStatus string    `json:"ptkp_status"`
+
+	Salary            float64 `json:"salary"`             // 1. gaji
+	Overtime          float64 `json:"overtime"`           // 3. tunjangan lainnya, uang lembur
+	InsurancePremiums float64 `json:"insurance_premiums"` // 5. premi asuransi yang dibayar pemberi kerja
+	GrossIncome       float64 `json:"gross_income"`       // 8. jumlah penghasilan bruto
+
+	OccupationalCost     float64 `json:"occupational_cost"`     // 9. biaya jabatan
+	PensionContributions float64 `json:"pension_contributions"` // 10. iuran JHT and JP
+	TotalDeductions      float64 `json:"total_deductions"`      // 11. jumlah pengurangan
+
+	NetIncome     float64 `json:"net_income"`     // 12 and 14. penghasilan neto
+	PTKP          float64 `json:"ptkp"`           // 15. penghasilan tidak kena pajak
+	TaxableIncome float64 `json:"taxable_income"` // 16. penghasilan kena pajak
+	TaxDue        float64 `json:"tax_due"`        // 17 and 19. PPh 21 terutang
+	TaxWithheld   float64 `json:"tax_withheld"`   // 20. PPh 21 yang telah dipotong
+	// TaxBalance is still to be withheld when positive and was withheld in excess when negative
+	TaxBalance float64 `json:"tax_balance"`
+
+	Months []TaxCertificateMonth `json:"months"`
+
+	SignerName string    `json:"signer_name"`
+	SignerNPWP string    `json:"signer_npwp"`
+	IssuedAt   time.Time `json:"issued_at"`
+}
+
+// TaxCertificateMonth is one processed payroll item counted in a certificate
+type TaxCertificateMonth struct {
+	Month         int       `json:"month"`
+	PeriodID      uuid.UUID `json:"period_id"`
+	PayrollItemID uuid.UUID `json:"payroll_item_id"`
+	GrossIncome   float64   `json:"gross_income"`
+	TaxWithheld   float64   `json:"tax_withheld"`
+}
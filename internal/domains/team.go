@@ -0,0 +1,69 @@
+package domains
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrRequestNotFound is also returned for requests of users outside the approver's team
+	ErrRequestNotFound = errors.New("request not found")
+	ErrAlreadyDecided  = errors.New("request has already been decided")
+	ErrPeriodProcessed = errors.New("payroll has been processed for the period of the request")
+	ErrManagerCycle    = errors.New("a user cannot report to themselves or to one of their reports")
+)
+
+// Kinds of requests line managers decide on
+const (
+	RequestKindOvertime      = "overtime"
+	RequestKindReimbursement = "reimbursement"
+	RequestKindLeave         = "leave"
+)
+
+// LeaveRequest asks for leave. A manager may name a delegate, who decides on the requests of the manager's
+// team while the leave lasts.
+type LeaveRequest struct {
+	Type       string
+	StartDate  time.Time
+	EndDate    time.Time
+	Reason     string
+	DelegateID *uuid.UUID
+}
+
+// TeamMember is a report of a manager, without salary or other pay details
+type TeamMember struct {
+	ID        uuid.UUID  `json:"id"`
+	Username  string     `json:"username"`
+	Email     string     `json:"email,omitempty"`
+	ManagerID *uuid.UUID `json:"manager_id,omitempty"`
+	// DelegatedBy is the manager on leave through whom the member is in the team
+	DelegatedBy *uuid.UUID `json:"delegated_by,omitempty"`
+}
+
+// TeamRequest is an overtime, reimbursement or leave request of a team member. Only the fields of its kind
+// are set.
+type TeamRequest struct {
+	ID          uuid.UUID  `json:"id"`
+	Kind        string     `json:"kind"` // 'overtime', 'reimbursement' or 'leave'
+	UserID      uuid.UUID  `json:"user_id"`
+	Username    string     `json:"username"`
+	SubmittedAt time.Time  `json:"submitted_at"`
+	DelegatedBy *uuid.UUID `json:"delegated_by,omitempty"` // decided on behalf of this manager
+
+	Date        *time.Time `json:"date,omitempty"`
+	Hours       float64    `json:"hours,omitempty"`
+	Amount      float64    `json:"amount,omitempty"`
+	Description string     `json:"description,omitempty"`
+	LeaveType   string     `json:"leave_type,omitempty"`
+	StartDate   *time.Time `json:"start_date,omitempty"`
+	EndDate     *time.Time `json:"end_date,omitempty"`
+	Reason      string     `json:"reason,omitempty"`
+
+	Status          string     `json:"status"`
+	DecidedBy       *uuid.UUID `json:"decided_by,omitempty"`
+	OnBehalfOf      *uuid.UUID `json:"on_behalf_of,omitempty"`
+	DecidedAt       *time.Time `json:"decided_at,omitempty"`
+	DecisionComment string     `json:"decision_comment,omitempty"`
+}
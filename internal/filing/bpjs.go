@@ -0,0 +1,123 @@
+package filing
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+func init() {
+	register(bpjsEmployment{})
+	register(bpjsHealth{})
+}
+
+// validateParticipants checks that every employee can be matched to a BPJS participant
+func validateParticipants(filing *Filing) error {
+	for _, employee := range filing.Employees {
+		if len(employee.NIK) != 16 {
+			return fmt.Errorf("employee %s has no NIK", employee.EmployeeID)
+		}
+	}
+	return nil
+}
+
+func period(filing *Filing) string {
+	return fmt.Sprintf("%02d%04d", filing.Month, filing.Year)
+}
+
+func writeCSV(rows [][]string) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := csv.NewWriter(&buf).WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// bpjsEmployment is the monthly wage and contribution report of BPJS Ketenagakerjaan (SIPP)
+type bpjsEmployment struct{}
+
+func (bpjsEmployment) Name() string        { return "bpjs-ketenagakerjaan" }
+func (bpjsEmployment) ContentType() string { return "text/csv" }
+func (bpjsEmployment) Extension() string   { return "csv" }
+
+func (bpjsEmployment) Validate(filing *Filing) error {
+	if filing.Employer.BPJSNumber == "" {
+		return errors.New("BPJS Ketenagakerjaan company number is not configured")
+	}
+	return validateParticipants(filing)
+}
+
+func (bpjsEmployment) Write(filing *Filing) ([]byte, int64, error) {
+	rows := [][]string{{"npp", "periode", "nik", "nama", "tanggal_lahir", "upah", "jht_tk", "jht_pk", "jp_tk", "jp_pk", "jkk", "jkm", "total_iuran"}}
+	var total int64
+	for _, employee := range filing.Employees {
+		birthDate := ""
+		if employee.BirthDate != nil {
+			birthDate = employee.BirthDate.Format("02-01-2006")
+		}
+		due := employee.JHTEmployee + employee.JHTEmployer + employee.JPEmployee + employee.JPEmployer + employee.JKKEmployer + employee.JKMEmployer
+		rows = append(rows, []string{
+			filing.Employer.BPJSNumber,
+			period(filing),
+			employee.NIK,
+			employee.Name,
+			birthDate,
+			strconv.FormatInt(employee.Wage, 10),
+			strconv.FormatInt(employee.JHTEmployee, 10),
+			strconv.FormatInt(employee.JHTEmployer, 10),
+			strconv.FormatInt(employee.JPEmployee, 10),
+			strconv.FormatInt(employee.JPEmployer, 10),
+			strconv.FormatInt(employee.JKKEmployer, 10),
+			strconv.FormatInt(employee.JKMEmployer, 10),
+			strconv.FormatInt(due, 10),
+		})
+		total += due
+	}
+
+	data, err := writeCSV(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return data, total, nil
+}
+
+// bpjsHealth is the monthly wage and contribution report of BPJS Kesehatan (e-Dabu)
+type bpjsHealth struct{}
+
+func (bpjsHealth) Name() string        { return "bpjs-kesehatan" }
+func (bpjsHealth) ContentType() string { return "text/csv" }
+func (bpjsHealth) Extension() string   { return "csv" }
+
+func (bpjsHealth) Validate(filing *Filing) error {
+	if filing.Employer.HealthCode == "" {
+		return errors.New("BPJS Kesehatan company code is not configured")
+	}
+	return validateParticipants(filing)
+}
+
+func (bpjsHealth) Write(filing *Filing) ([]byte, int64, error) {
+	rows := [][]string{{"kode_badan_usaha", "periode", "nik", "nama", "upah", "iuran_pekerja", "iuran_pemberi_kerja", "total_iuran"}}
+	var total int64
+	for _, employee := range filing.Employees {
+		due := employee.HealthEmployee + employee.HealthEmployer
+		rows = append(rows, []string{
+			filing.Employer.HealthCode,
+			period(filing),
+			employee.NIK,
+			employee.Name,
+			strconv.FormatInt(employee.Wage, 10),
+			strconv.FormatInt(employee.HealthEmployee, 10),
+			strconv.FormatInt(employee.HealthEmployer, 10),
+			strconv.FormatInt(due, 10),
+		})
+		total += due
+	}
+
+	data, err := writeCSV(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return data, total, nil
+}
@@ -0,0 +1,140 @@
+package filing
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+func init() {
+	register(ebupotCSV{})
+	register(ebupotXML{})
+}
+
+// validateWithholding checks what the e-Bupot import requires of every record
+func validateWithholding(filing *Filing) error {
+	if len(filing.Employer.NPWP) != 15 && len(filing.Employer.NPWP) != 16 {
+		return errors.New("employer NPWP is not configured")
+	}
+	for _, employee := range filing.Employees {
+		if len(employee.TIN()) != 16 {
+			return fmt.Errorf("employee %s has no NIK or NPWP", employee.EmployeeID)
+		}
+	}
+	return nil
+}
+
+// employerTIN is the 16 digit form of the employer NPWP
+func employerTIN(employer Employer) string {
+	if len(employer.NPWP) == 15 {
+		return "0" + employer.NPWP
+	}
+	return employer.NPWP
+}
+
+// nitku defaults to the head office of the employer
+func nitku(employer Employer) string {
+	if employer.NITKU != "" {
+		return employer.NITKU
+	}
+	return employerTIN(employer) + "000000"
+}
+
+// ebupotCSV is one row per employee with the columns of the e-Bupot 21 monthly employee template
+type ebupotCSV struct{}
+
+func (ebupotCSV) Name() string                  { return "ebupot-csv" }
+func (ebupotCSV) ContentType() string           { return "text/csv" }
+func (ebupotCSV) Extension() string             { return "csv" }
+func (ebupotCSV) Validate(filing *Filing) error { return validateWithholding(filing) }
+
+func (ebupotCSV) Write(filing *Filing) ([]byte, int64, error) {
+	rows := [][]string{{"masa_pajak", "tahun_pajak", "npwp_pemotong", "id_tku", "npwp_nik", "nama", "status_ptkp", "kode_objek_pajak", "penghasilan_bruto", "pph_dipotong", "tanggal_pemotongan"}}
+	var total int64
+	for _, employee := range filing.Employees {
+		rows = append(rows, []string{
+			strconv.Itoa(filing.Month),
+			strconv.Itoa(filing.Year),
+			employerTIN(filing.Employer),
+			nitku(filing.Employer),
+			employee.TIN(),
+			employee.Name,
+			employee.PTKPStatus,
+			TaxObjectCode,
+			strconv.FormatInt(employee.Gross, 10),
+			strconv.FormatInt(employee.Tax, 10),
+			filing.WithholdingDate.Format("02/01/2006"),
+		})
+		total += employee.Tax
+	}
+
+	data, err := writeCSV(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	return data, total, nil
+}
+
+// ebupotXML is the bulk import of monthly employee withholding slips (BPMP) into Coretax
+type ebupotXML struct{}
+
+type bpmpDocument struct {
+	XMLName  xml.Name      `xml:"MmPayrollBulk"`
+	XSI      string        `xml:"xmlns:xsi,attr"`
+	TIN      string        `xml:"TIN"`
+	Payrolls []bpmpPayroll `xml:"ListOfMmPayroll>MmPayroll"`
+}
+
+type bpmpPayroll struct {
+	TaxPeriodMonth            int    `xml:"TaxPeriodMonth"`
+	TaxPeriodYear             int    `xml:"TaxPeriodYear"`
+	CounterpartOpt            string `xml:"CounterpartOpt"`
+	CounterpartPassport       string `xml:"CounterpartPassport"`
+	CounterpartTin            string `xml:"CounterpartTin"`
+	StatusTaxExemption        string `xml:"StatusTaxExemption"`
+	Position                  string `xml:"Position"`
+	TaxCertificate            string `xml:"TaxCertificate"`
+	TaxObjectCode             string `xml:"TaxObjectCode"`
+	Gross                     int64  `xml:"Gross"`
+	Tax                       int64  `xml:"Tax"`
+	IDPlaceOfBusinessActivity string `xml:"IDPlaceOfBusinessActivity"`
+	WithholdingDate           string `xml:"WithholdingDate"`
+}
+
+func (ebupotXML) Name() string                  { return "ebupot-xml" }
+func (ebupotXML) ContentType() string           { return "application/xml" }
+func (ebupotXML) Extension() string             { return "xml" }
+func (ebupotXML) Validate(filing *Filing) error { return validateWithholding(filing) }
+
+func (ebupotXML) Write(filing *Filing) ([]byte, int64, error) {
+	document := bpmpDocument{
+		XSI: "http://www.w3.org/2001/XMLSchema-instance",
+		TIN: employerTIN(filing.Employer),
+	}
+
+	var total int64
+	for _, employee := range filing.Employees {
+		document.Payrolls = append(document.Payrolls, bpmpPayroll{
+			TaxPeriodMonth:            filing.Month,
+			TaxPeriodYear:             filing.Year,
+			CounterpartOpt:            "Resident",
+			CounterpartTin:            employee.TIN(),
+			StatusTaxExemption:        employee.PTKPStatus,
+			Position:                  "Pegawai",
+			TaxCertificate:            "N/A",
+			TaxObjectCode:             TaxObjectCode,
+			Gross:                     employee.Gross,
+			Tax:                       employee.Tax,
+			IDPlaceOfBusinessActivity: nitku(filing.Employer),
+			WithholdingDate:           filing.WithholdingDate.Format("2006-01-02"),
+		})
+		total += employee.Tax
+	}
+
+	data, err := xml.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, 0, err
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), total, nil
+}
@@ -0,0 +1,138 @@
+// Package filing writes the monthly PPh 21 return and the BPJS contribution reports of a payroll in the
+// import layouts of the tax office and BPJS portals
+package filing
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TaxObjectCode is the e-Bupot object code of regular income of permanent employees
+const TaxObjectCode = "21-100-01"
+
+// Filing is the payroll of one month as it is reported
+type Filing struct {
+	Year            int
+	Month           int
+	WithholdingDate time.Time // the day PPh 21 was withheld, the last day of the period
+	Employer        Employer
+	Employees       []Employee
+}
+
+// Employer identifies the company to the tax office and BPJS
+type Employer struct {
+	Name  string
+	NPWP  string
+	NITKU string // place of business ID, the NPWP followed by a six digit branch number
+	// BPJSNumber is the NPP of the company at BPJS Ketenagakerjaan
+	BPJSNumber string
+	// HealthCode is the company code at BPJS Kesehatan
+	HealthCode string
+}
+
+// Employee is what one employee was paid and had withheld in the month. Amounts are in whole rupiah.
+type Employee struct {
+	EmployeeID string
+	Name       string
+	NIK        string
+	NPWP       string
+	PTKPStatus string
+	BirthDate  *time.Time
+
+	Gross int64 // income subject to PPh 21
+	Tax   int64 // PPh 21 withheld
+	Wage  int64 // base of the BPJS contributions
+
+	JHTEmployee    int64
+	JHTEmployer    int64
+	JPEmployee     int64
+	JPEmployer     int64
+	JKKEmployer    int64
+	JKMEmployer    int64
+	HealthEmployee int64
+	HealthEmployer int64
+}
+
+// TIN is the 16 digit taxpayer ID of the employee: the NIK for residents, otherwise the NPWP
+func (e Employee) TIN() string {
+	switch {
+	case e.NIK != "":
+		return e.NIK
+	case len(e.NPWP) == 15:
+		return "0" + e.NPWP
+	default:
+		return e.NPWP
+	}
+}
+
+// File is a written report
+type File struct {
+	Report      string
+	Filename    string
+	ContentType string
+	Data        []byte
+	Count       int
+	Amount      int64  // PPh 21 withheld or contributions due, in whole rupiah
+	Checksum    string // SHA-256 of Data, hex encoded
+}
+
+// Report writes a filing in one portal layout
+type Report interface {
+	Name() string
+	ContentType() string
+	Extension() string
+	Validate(filing *Filing) error
+	Write(filing *Filing) ([]byte, int64, error)
+}
+
+var reports = map[string]Report{}
+
+func register(report Report) {
+	reports[report.Name()] = report
+}
+
+// Reports lists the names of the supported reports
+func Reports() []string {
+	names := make([]string, 0, len(reports))
+	for name := range reports {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Write validates the filing and writes it as the named report
+func Write(report string, filing *Filing) (*File, error) {
+	writer, ok := reports[report]
+	if !ok {
+		return nil, fmt.Errorf("unsupported filing report %q, use one of %s", report, strings.Join(Reports(), ", "))
+	}
+
+	if len(filing.Employees) == 0 {
+		return nil, errors.New("filing has no employees")
+	}
+	if err := writer.Validate(filing); err != nil {
+		return nil, err
+	}
+
+	data, amount, err := writer.Write(filing)
+	if err != nil {
+		return nil, err
+	}
+
+	checksum := sha256.Sum256(data)
+	return &File{
+		Report:      writer.Name(),
+		Filename:    fmt.Sprintf("%s-%04d%02d.%s", writer.Name(), filing.Year, filing.Month, writer.Extension()),
+		ContentType: writer.ContentType(),
+		Data:        data,
+		Count:       len(filing.Employees),
+		Amount:      amount,
+		Checksum:    hex.EncodeToString(checksum[:]),
+	}, nil
+}
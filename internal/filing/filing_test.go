@@ -0,0 +1,158 @@
+package filing
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFiling() *Filing {
+	birthDate := time.Date(1990, 3, 15, 0, 0, 0, 0, time.UTC)
+	return &Filing{
+		Year:            2024,
+		Month:           8,
+		WithholdingDate: time.Date(2024, 8, 31, 0, 0, 0, 0, time.UTC),
+		Employer:        Employer{Name: "PT Mini Payroll Indonesia", NPWP: "012345678901000", BPJSNumber: "12345678", HealthCode: "01234567"},
+		Employees: []Employee{
+			{
+				EmployeeID: "employee1", Name: "Budi Santoso", NIK: "3174011503900001", PTKPStatus: "K/1", BirthDate: &birthDate,
+				Gross: 8544800, Tax: 126104, Wage: 8000000,
+				JHTEmployee: 160000, JHTEmployer: 296000, JPEmployee: 80000, JPEmployer: 160000,
+				JKKEmployer: 19200, JKMEmployer: 24000, HealthEmployee: 80000, HealthEmployer: 320000,
+			},
+			{
+				EmployeeID: "employee2", Name: "Siti Rahayu", NIK: "3174015507920002", NPWP: "098765432109000", PTKPStatus: "TK/0",
+				Gross: 5000000, Tax: 0, Wage: 5000000,
+				JHTEmployee: 100000, JHTEmployer: 185000, JPEmployee: 50000, JPEmployer: 100000,
+				JKKEmployer: 12000, JKMEmployer: 15000, HealthEmployee: 50000, HealthEmployer: 200000,
+			},
+		},
+	}
+}
+
+func readCSV(t *testing.T, data []byte) [][]string {
+	rows, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	require.NoError(t, err)
+	return rows
+}
+
+func TestEmployee_TIN(t *testing.T) {
+	assert.Equal(t, "3174011503900001", Employee{NIK: "3174011503900001", NPWP: "012345678901000"}.TIN())
+	assert.Equal(t, "0012345678901000", Employee{NPWP: "012345678901000"}.TIN())
+	assert.Equal(t, "1012345678901000", Employee{NPWP: "1012345678901000"}.TIN())
+	assert.Equal(t, "", Employee{}.TIN())
+}
+
+func TestWrite(t *testing.T) {
+	for _, report := range Reports() {
+		t.Run(report, func(t *testing.T) {
+			file, err := Write(report, testFiling())
+			require.NoError(t, err)
+
+			checksum := sha256.Sum256(file.Data)
+			assert.Equal(t, hex.EncodeToString(checksum[:]), file.Checksum)
+			assert.Equal(t, 2, file.Count)
+			assert.True(t, strings.HasPrefix(file.Filename, report+"-202408."))
+		})
+	}
+
+	_, err := Write("spt-masa", testFiling())
+	assert.ErrorContains(t, err, "unsupported filing report")
+}
+
+func TestWrite_Validation(t *testing.T) {
+	tests := []struct {
+		name   string
+		report string
+		modify func(filing *Filing)
+	}{
+		{name: "no employees", report: "ebupot-csv", modify: func(filing *Filing) { filing.Employees = nil }},
+		{name: "no employer NPWP", report: "ebupot-xml", modify: func(filing *Filing) { filing.Employer.NPWP = "" }},
+		{name: "no tax ID", report: "ebupot-csv", modify: func(filing *Filing) { filing.Employees[0].NIK = "" }},
+		{name: "no NIK", report: "bpjs-ketenagakerjaan", modify: func(filing *Filing) { filing.Employees[1].NIK = "" }},
+		{name: "no company number", report: "bpjs-ketenagakerjaan", modify: func(filing *Filing) { filing.Employer.BPJSNumber = "" }},
+		{name: "no company code", report: "bpjs-kesehatan", modify: func(filing *Filing) { filing.Employer.HealthCode = "" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filing := testFiling()
+			tt.modify(filing)
+
+			_, err := Write(tt.report, filing)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestEbupotCSV(t *testing.T) {
+	file, err := Write("ebupot-csv", testFiling())
+	require.NoError(t, err)
+
+	rows := readCSV(t, file.Data)
+	require.Len(t, rows, 3)
+	assert.Equal(t, []string{"8", "2024", "0012345678901000", "0012345678901000000000", "3174011503900001", "Budi Santoso", "K/1", "21-100-01", "8544800", "126104", "31/08/2024"}, rows[1])
+	assert.Equal(t, int64(126104), file.Amount)
+}
+
+func TestEbupotXML(t *testing.T) {
+	filing := testFiling()
+	filing.Employer.NITKU = "0012345678901000000001"
+	file, err := Write("ebupot-xml", filing)
+	require.NoError(t, err)
+
+	var document struct {
+		TIN      string `xml:"TIN"`
+		Payrolls []struct {
+			TaxPeriodMonth  int    `xml:"TaxPeriodMonth"`
+			TaxPeriodYear   int    `xml:"TaxPeriodYear"`
+			CounterpartTin  string `xml:"CounterpartTin"`
+			Status          string `xml:"StatusTaxExemption"`
+			TaxObjectCode   string `xml:"TaxObjectCode"`
+			Gross           int64  `xml:"Gross"`
+			Tax             int64  `xml:"Tax"`
+			PlaceOfBusiness string `xml:"IDPlaceOfBusinessActivity"`
+			WithholdingDate string `xml:"WithholdingDate"`
+		} `xml:"ListOfMmPayroll>MmPayroll"`
+	}
+	require.NoError(t, xml.Unmarshal(file.Data, &document))
+	assert.True(t, strings.HasPrefix(string(file.Data), xml.Header))
+
+	assert.Equal(t, "0012345678901000", document.TIN)
+	require.Len(t, document.Payrolls, 2)
+	first := document.Payrolls[0]
+	assert.Equal(t, [2]int{8, 2024}, [2]int{first.TaxPeriodMonth, first.TaxPeriodYear})
+	assert.Equal(t, "3174011503900001", first.CounterpartTin)
+	assert.Equal(t, "K/1", first.Status)
+	assert.Equal(t, TaxObjectCode, first.TaxObjectCode)
+	assert.Equal(t, int64(8544800), first.Gross)
+	assert.Equal(t, int64(126104), first.Tax)
+	assert.Equal(t, "0012345678901000000001", first.PlaceOfBusiness)
+	assert.Equal(t, "2024-08-31", first.WithholdingDate)
+}
+
+func TestBPJSReports(t *testing.T) {
+	file, err := Write("bpjs-ketenagakerjaan", testFiling())
+	require.NoError(t, err)
+
+	rows := readCSV(t, file.Data)
+	require.Len(t, rows, 3)
+	assert.Equal(t, []string{"12345678", "082024", "3174011503900001", "Budi Santoso", "15-03-1990", "8000000", "160000", "296000", "80000", "160000", "19200", "24000", "739200"}, rows[1])
+	assert.Equal(t, "", rows[2][4])
+	assert.Equal(t, int64(739200+462000), file.Amount)
+
+	file, err = Write("bpjs-kesehatan", testFiling())
+	require.NoError(t, err)
+
+	rows = readCSV(t, file.Data)
+	require.Len(t, rows, 3)
+	assert.Equal(t, []string{"01234567", "082024", "3174015507920002", "Siti Rahayu", "5000000", "50000", "200000", "250000"}, rows[2])
+	assert.Equal(t, int64(400000+250000), file.Amount)
+}
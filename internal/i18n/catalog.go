@@ -0,0 +1,91 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+)
+
+//go:embed locales/*.json
+var builtinFS embed.FS
+
+var languageCodePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Z]{2})?$`)
+
+// Catalog holds the messages and number/date conventions of one language
+type Catalog struct {
+	Code               string            `json:"code"`
+	Name               string            `json:"name"`
+	DecimalSeparator   string            `json:"decimal_separator"`
+	ThousandsSeparator string            `json:"thousands_separator"`
+	CurrencySymbols    map[string]string `json:"currency_symbols,omitempty"` // e.g. IDR -> Rp; the ISO code is used when missing
+	DateFormat         string            `json:"date_format"`                // Go layout, month names are translated
+	Months             []string          `json:"months,omitempty"`
+	Messages           map[string]string `json:"messages"`
+}
+
+// Validate checks the catalog is usable; missing messages fall back to the default language
+func (c *Catalog) Validate() error {
+	if !languageCodePattern.MatchString(c.Code) {
+		return errors.New("language code must look like 'id' or 'en-US'")
+	}
+	if c.Name == "" {
+		return errors.New("language name is required")
+	}
+	if c.DecimalSeparator == "" || c.ThousandsSeparator == "" || c.DecimalSeparator == c.ThousandsSeparator {
+		return errors.New("decimal and thousands separators are required and must differ")
+	}
+	if c.DateFormat == "" {
+		return errors.New("date format is required")
+	}
+	if len(c.Months) != 0 && len(c.Months) != 12 {
+		return errors.New("months must list all 12 month names")
+	}
+	return nil
+}
+
+// MissingKeys lists messages of the reference catalog that c does not translate
+func (c *Catalog) MissingKeys(reference *Catalog) []string {
+	var missing []string
+	for key := range reference.Messages {
+		if _, ok := c.Messages[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// ParseCatalog decodes a catalog from its JSON representation
+func ParseCatalog(data []byte) (*Catalog, error) {
+	var catalog Catalog
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("invalid catalog: %w", err)
+	}
+	return &catalog, nil
+}
+
+// builtinCatalogs returns the catalogs shipped with the application
+func builtinCatalogs() []*Catalog {
+	entries, err := builtinFS.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+
+	catalogs := make([]*Catalog, 0, len(entries))
+	for _, entry := range entries {
+		data, err := builtinFS.ReadFile(path.Join("locales", entry.Name()))
+		if err != nil {
+			panic(err)
+		}
+		catalog, err := ParseCatalog(data)
+		if err != nil {
+			panic(fmt.Sprintf("builtin catalog %s: %v", entry.Name(), err))
+		}
+		catalogs = append(catalogs, catalog)
+	}
+	return catalogs
+}
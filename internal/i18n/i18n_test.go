@@ -0,0 +1,112 @@
+package i18n
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslator_Money(t *testing.T) {
+	registry := NewRegistry("en", nil)
+
+	assert.Equal(t, "IDR 5,000,000.00", registry.Translator("en").Money("IDR", 5000000))
+	assert.Equal(t, "IDR 999.50", registry.Translator("en").Money("IDR", 999.5))
+	assert.Equal(t, "IDR -1,234.57", registry.Translator("en").Money("IDR", -1234.567))
+	assert.Equal(t, "Rp 5.000.000,00", registry.Translator("id").Money("IDR", 5000000))
+	assert.Equal(t, "USD 12,50", registry.Translator("id").Money("USD", 12.5))
+}
+
+func TestTranslator_T(t *testing.T) {
+	registry := NewRegistry("en", nil)
+	registry.Put(&Catalog{
+		Code:               "ms",
+		Name:               "Bahasa Melayu",
+		DecimalSeparator:   ".",
+		ThousandsSeparator: ",",
+		DateFormat:         "02/01/2006",
+		Messages:           map[string]string{"payslip.title": "Slip Gaji"},
+	})
+
+	assert.Equal(t, "Slip Gaji", registry.Translator("id").T("payslip.title"))
+	assert.Equal(t, "2 of 20 working days", registry.Translator("en").T("payslip.attendance_detail", "attended", 2, "working", 20))
+	// Missing messages fall back to the default language, unknown keys to the key itself
+	assert.Equal(t, "Slip Gaji", registry.Translator("ms").T("payslip.title"))
+	assert.Equal(t, "Net pay", registry.Translator("ms").T("payslip.net_pay"))
+	assert.Equal(t, "payslip.unknown", registry.Translator("ms").T("payslip.unknown"))
+	// Unknown languages use the default
+	assert.Equal(t, "en", registry.Translator("xx", "").Language())
+	assert.Equal(t, "ms", registry.Translator("xx", "ms").Language())
+}
+
+func TestTranslator_Date(t *testing.T) {
+	registry := NewRegistry("en", nil)
+	date := time.Date(2024, 8, 17, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "17 August 2024", registry.Translator("en").Date(date))
+	assert.Equal(t, "17 Agustus 2024", registry.Translator("id").Date(date))
+}
+
+func TestTranslator_AmountInWords(t *testing.T) {
+	registry := NewRegistry("en", nil)
+
+	tests := []struct {
+		language string
+		amount   float64
+		want     string
+	}{
+		{"en", 0, "zero rupiah"},
+		{"en", 15, "fifteen rupiah"},
+		{"en", 3875000, "three million eight hundred seventy-five thousand rupiah"},
+		{"en", 1000001.4, "one million one rupiah"},
+		{"en", -250, "minus two hundred fifty rupiah"},
+		{"id", 0, "nol rupiah"},
+		{"id", 11, "sebelas rupiah"},
+		{"id", 1115, "seribu seratus lima belas rupiah"},
+		{"id", 3875000, "tiga juta delapan ratus tujuh puluh lima ribu rupiah"},
+		{"id", 2000000000, "dua miliar rupiah"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, registry.Translator(tt.language).AmountInWords(tt.amount, "IDR"))
+	}
+}
+
+func TestRegistry_Source(t *testing.T) {
+	calls := 0
+	registry := NewRegistry("id", func() ([]*Catalog, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("database unavailable")
+		}
+		return []*Catalog{{Code: "id", Name: "Indonesia", DecimalSeparator: ",", ThousandsSeparator: ".", DateFormat: "02-01-2006", Messages: map[string]string{"payslip.title": "Slip"}}}, nil
+	})
+
+	// A failing source leaves the builtin catalogs in place and is retried
+	assert.Equal(t, "Slip Gaji", registry.Translator("id").T("payslip.title"))
+	assert.Equal(t, "Slip", registry.Translator("id").T("payslip.title"))
+	registry.Translator("id")
+	assert.Equal(t, 2, calls)
+}
+
+func TestCatalog_Validate(t *testing.T) {
+	valid := Catalog{Code: "fr", Name: "Français", DecimalSeparator: ",", ThousandsSeparator: " ", DateFormat: "02/01/2006"}
+	require.NoError(t, valid.Validate())
+
+	invalid := valid
+	invalid.Code = "French"
+	assert.Error(t, invalid.Validate())
+
+	invalid = valid
+	invalid.ThousandsSeparator = ","
+	assert.Error(t, invalid.Validate())
+
+	invalid = valid
+	invalid.Months = []string{"janvier"}
+	assert.Error(t, invalid.Validate())
+
+	missing := valid.MissingKeys(NewRegistry("en", nil).Translator("en").catalog)
+	assert.Contains(t, missing, "payslip.title")
+}
@@ -0,0 +1,125 @@
+package i18n
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Source loads catalogs added at runtime, which override builtin catalogs with the same code
+type Source func() ([]*Catalog, error)
+
+// Registry resolves languages to translators. Builtin catalogs are always available;
+// catalogs from the source are loaded on first use.
+type Registry struct {
+	mu              sync.RWMutex
+	defaultLanguage string
+	catalogs        map[string]*Catalog
+	source          Source
+	loaded          bool
+}
+
+func NewRegistry(defaultLanguage string, source Source) *Registry {
+	registry := &Registry{
+		defaultLanguage: defaultLanguage,
+		catalogs:        map[string]*Catalog{},
+		source:          source,
+	}
+	for _, catalog := range builtinCatalogs() {
+		registry.catalogs[catalog.Code] = catalog
+	}
+	if _, ok := registry.catalogs[defaultLanguage]; !ok {
+		registry.defaultLanguage = "en"
+	}
+	return registry
+}
+
+// DefaultLanguage is used when no preference is given or the preferred language is unknown
+func (r *Registry) DefaultLanguage() string {
+	return r.defaultLanguage
+}
+
+// Translator returns a translator for the first known language, falling back to the default
+func (r *Registry) Translator(languages ...string) *Translator {
+	r.ensureLoaded()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	fallback := r.catalogs[r.defaultLanguage]
+	for _, language := range languages {
+		if catalog, ok := r.catalogs[language]; ok {
+			return &Translator{catalog: catalog, fallback: fallback}
+		}
+	}
+	return &Translator{catalog: fallback, fallback: fallback}
+}
+
+// Has reports whether a catalog exists for the language
+func (r *Registry) Has(language string) bool {
+	r.ensureLoaded()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.catalogs[language]
+	return ok
+}
+
+// Catalog returns the catalog of a language
+func (r *Registry) Catalog(language string) (*Catalog, bool) {
+	r.ensureLoaded()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	catalog, ok := r.catalogs[language]
+	return catalog, ok
+}
+
+// Catalogs lists all available catalogs ordered by code
+func (r *Registry) Catalogs() []*Catalog {
+	r.ensureLoaded()
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	catalogs := make([]*Catalog, 0, len(r.catalogs))
+	for _, catalog := range r.catalogs {
+		catalogs = append(catalogs, catalog)
+	}
+	sort.Slice(catalogs, func(i, j int) bool { return catalogs[i].Code < catalogs[j].Code })
+	return catalogs
+}
+
+// Put adds or replaces a catalog
+func (r *Registry) Put(catalog *Catalog) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.catalogs[catalog.Code] = catalog
+}
+
+func (r *Registry) ensureLoaded() {
+	r.mu.RLock()
+	loaded := r.loaded || r.source == nil
+	r.mu.RUnlock()
+	if loaded {
+		return
+	}
+
+	catalogs, err := r.source()
+	if err != nil {
+		// Builtin catalogs keep working; the source is retried on the next lookup
+		logrus.WithError(err).Warn("Failed to load language catalogs")
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.loaded {
+		return
+	}
+	for _, catalog := range catalogs {
+		r.catalogs[catalog.Code] = catalog
+	}
+	r.loaded = true
+}
@@ -0,0 +1,109 @@
+package i18n
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Translator renders messages, numbers and dates for one language
+type Translator struct {
+	catalog  *Catalog
+	fallback *Catalog
+}
+
+// Language is the code of the language actually used
+func (t *Translator) Language() string {
+	return t.catalog.Code
+}
+
+// T returns the message for key with {name} placeholders replaced by the given name/value pairs,
+// e.g. T("payslip.overtime_hours", "hours", "2.5")
+func (t *Translator) T(key string, args ...interface{}) string {
+	message, ok := t.catalog.Messages[key]
+	if !ok {
+		if message, ok = t.fallback.Messages[key]; !ok {
+			message = key
+		}
+	}
+
+	for i := 0; i+1 < len(args); i += 2 {
+		message = strings.ReplaceAll(message, "{"+fmt.Sprint(args[i])+"}", fmt.Sprint(args[i+1]))
+	}
+	return message
+}
+
+// Messages returns all messages with the given key prefix, falling back per key to the default language
+func (t *Translator) Messages(prefix string) map[string]string {
+	messages := map[string]string{}
+	for _, catalog := range []*Catalog{t.fallback, t.catalog} {
+		for key, message := range catalog.Messages {
+			if strings.HasPrefix(key, prefix) {
+				messages[key] = message
+			}
+		}
+	}
+	return messages
+}
+
+// Number formats a number with the language's separators, e.g. 1.234,50 or 1,234.50
+func (t *Translator) Number(value float64, decimals int) string {
+	sign := ""
+	if value < 0 {
+		sign = "-"
+	}
+
+	scale := math.Pow10(decimals)
+	scaled := int64(math.Round(math.Abs(value) * scale))
+	whole := strconv.FormatInt(scaled/int64(scale), 10)
+
+	var grouped strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteString(t.catalog.ThousandsSeparator)
+		}
+		grouped.WriteRune(digit)
+	}
+
+	if decimals == 0 {
+		return sign + grouped.String()
+	}
+	fraction := fmt.Sprintf("%0*d", decimals, scaled%int64(scale))
+	return sign + grouped.String() + t.catalog.DecimalSeparator + fraction
+}
+
+// Money formats an amount with the local currency symbol, e.g. "Rp 5.000.000,00" or "IDR 5,000,000.00"
+func (t *Translator) Money(currency string, amount float64) string {
+	symbol := currency
+	if local, ok := t.catalog.CurrencySymbols[currency]; ok {
+		symbol = local
+	}
+	return symbol + " " + t.Number(amount, 2)
+}
+
+// Date formats a date with the language's layout and month names
+func (t *Translator) Date(date time.Time) string {
+	formatted := date.Format(t.catalog.DateFormat)
+	if len(t.catalog.Months) == 12 {
+		formatted = strings.Replace(formatted, date.Month().String(), t.catalog.Months[date.Month()-1], 1)
+	}
+	return formatted
+}
+
+// AmountInWords spells an amount rounded to whole currency units. Only languages with a
+// speller are supported; others get an empty string.
+func (t *Translator) AmountInWords(amount float64, currency string) string {
+	speller, ok := spellers[t.catalog.Code]
+	if !ok {
+		return ""
+	}
+
+	n := int64(math.Round(math.Abs(amount)))
+	words := speller.spell(n)
+	if amount < 0 && n > 0 {
+		words = speller.minus + " " + words
+	}
+	return words + " " + speller.currencyName(currency)
+}
@@ -0,0 +1,155 @@
+package i18n
+
+import (
+	"strings"
+)
+
+// speller spells whole numbers and currency names in one language
+type speller struct {
+	spell         func(n int64) string
+	minus         string
+	currencyNames map[string]string
+}
+
+func (s speller) currencyName(currency string) string {
+	if name, ok := s.currencyNames[strings.ToUpper(currency)]; ok {
+		return name
+	}
+	return currency
+}
+
+var spellers = map[string]speller{
+	"en": {
+		spell: SpellNumberEnglish,
+		minus: "minus",
+		currencyNames: map[string]string{
+			"IDR": "rupiah",
+			"USD": "US dollars",
+			"SGD": "Singapore dollars",
+		},
+	},
+	"id": {
+		spell: SpellNumberIndonesian,
+		minus: "minus",
+		currencyNames: map[string]string{
+			"IDR": "rupiah",
+			"USD": "dolar Amerika Serikat",
+			"SGD": "dolar Singapura",
+		},
+	},
+}
+
+var (
+	englishOnes = []string{
+		"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine",
+		"ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen",
+	}
+	englishTens   = []string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+	englishScales = []string{"", "thousand", "million", "billion", "trillion"}
+
+	indonesianOnes   = []string{"nol", "satu", "dua", "tiga", "empat", "lima", "enam", "tujuh", "delapan", "sembilan"}
+	indonesianScales = []string{"", "ribu", "juta", "miliar", "triliun"}
+)
+
+// SpellNumberEnglish spells a non-negative integer in English words
+func SpellNumberEnglish(n int64) string {
+	if n == 0 {
+		return englishOnes[0]
+	}
+
+	var parts []string
+	for scale := 0; n > 0; scale++ {
+		group := n % 1000
+		n /= 1000
+		if group == 0 {
+			continue
+		}
+
+		words := spellEnglishHundreds(int(group))
+		if englishScales[scale] != "" {
+			words += " " + englishScales[scale]
+		}
+		parts = append([]string{words}, parts...)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func spellEnglishHundreds(n int) string {
+	var parts []string
+	if n >= 100 {
+		parts = append(parts, englishOnes[n/100]+" hundred")
+		n %= 100
+	}
+
+	switch {
+	case n == 0:
+	case n < 20:
+		parts = append(parts, englishOnes[n])
+	case n%10 == 0:
+		parts = append(parts, englishTens[n/10])
+	default:
+		parts = append(parts, englishTens[n/10]+"-"+englishOnes[n%10])
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// SpellNumberIndonesian spells a non-negative integer in Indonesian words ("terbilang")
+func SpellNumberIndonesian(n int64) string {
+	if n == 0 {
+		return indonesianOnes[0]
+	}
+
+	var parts []string
+	for scale := 0; n > 0; scale++ {
+		group := n % 1000
+		n /= 1000
+		if group == 0 {
+			continue
+		}
+
+		var words string
+		switch {
+		case scale == 1 && group == 1:
+			// 1.000 is "seribu", not "satu ribu"
+			words = "seribu"
+		case indonesianScales[scale] != "":
+			words = spellIndonesianHundreds(int(group)) + " " + indonesianScales[scale]
+		default:
+			words = spellIndonesianHundreds(int(group))
+		}
+		parts = append([]string{words}, parts...)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func spellIndonesianHundreds(n int) string {
+	var parts []string
+	switch {
+	case n >= 200:
+		parts = append(parts, indonesianOnes[n/100]+" ratus")
+	case n >= 100:
+		parts = append(parts, "seratus")
+	}
+	n %= 100
+
+	switch {
+	case n == 0:
+	case n == 10:
+		parts = append(parts, "sepuluh")
+	case n == 11:
+		parts = append(parts, "sebelas")
+	case n < 10:
+		parts = append(parts, indonesianOnes[n])
+	case n < 20:
+		parts = append(parts, indonesianOnes[n%10]+" belas")
+	case n%10 == 0:
+		parts = append(parts, indonesianOnes[n/10]+" puluh")
+	default:
+		parts = append(parts, indonesianOnes[n/10]+" puluh "+indonesianOnes[n%10])
+	}
+
+	return strings.Join(parts, " ")
+}
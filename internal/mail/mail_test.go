@@ -0,0 +1,242 @@
+package mail
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	netmail "net/mail"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+
+	"payslip-system/internal/config"
+	"payslip-system/internal/i18n"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testMessage() *Message {
+	return &Message{
+		From:    netmail.Address{Name: "PT Mini Payroll", Address: "payroll@example.com"},
+		To:      netmail.Address{Name: "Employee One", Address: "employee1@example.com"},
+		Subject: "Slip gaji Anda untuk Agustus 2024",
+		Text:    "Gaji bersih: Rp 5.000.000,00",
+		HTML:    "<p>Gaji bersih: Rp 5.000.000,00</p>",
+		Attachments: []Attachment{
+			{Filename: "payslip-2024-08.pdf", ContentType: "application/pdf", Data: bytes.Repeat([]byte("%PDF-1.4"), 100)},
+		},
+	}
+}
+
+func TestMessage_Bytes(t *testing.T) {
+	data, err := testMessage().Bytes()
+	require.NoError(t, err)
+
+	parsed, err := netmail.ReadMessage(bytes.NewReader(data))
+	require.NoError(t, err)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Slip gaji Anda untuk Agustus 2024", subject)
+	assert.Contains(t, parsed.Header.Get("Message-ID"), "@example.com>")
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/mixed", mediaType)
+
+	parts := multipart.NewReader(parsed.Body, params["boundary"])
+
+	body, err := parts.NextPart()
+	require.NoError(t, err)
+	mediaType, params, err = mime.ParseMediaType(body.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	alternatives := multipart.NewReader(body, params["boundary"])
+	for _, want := range []string{"Gaji bersih: Rp 5.000.000,00", "<p>Gaji bersih: Rp 5.000.000,00</p>"} {
+		part, err := alternatives.NextPart()
+		require.NoError(t, err)
+		content, err := io.ReadAll(part) // NextPart decodes quoted-printable
+		require.NoError(t, err)
+		assert.Equal(t, want, string(content))
+	}
+
+	attachment, err := parts.NextPart()
+	require.NoError(t, err)
+	assert.Equal(t, "payslip-2024-08.pdf", attachment.FileName())
+	for _, line := range strings.Split(readAll(t, attachment), "\r\n") {
+		assert.LessOrEqual(t, len(line), 76)
+	}
+
+	_, err = parts.NextPart()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestRender(t *testing.T) {
+	registry := i18n.NewRegistry("en", nil)
+	data := PayslipData{
+		EmployeeName: "employee1",
+		Period:       "August 2024",
+		NetPay:       "IDR 5,000,000.00",
+		Company:      "PT Mini Payroll",
+		Attached:     true,
+		PortalURL:    "https://payroll.example.com",
+	}
+
+	content, err := Render(TemplatePayslip, registry.Translator("en"), data)
+	require.NoError(t, err)
+	assert.Equal(t, "Your payslip for August 2024", content.Subject)
+	assert.Contains(t, content.Text, "Dear employee1,\n\nYour payslip for August 2024 is ready. Net pay: IDR 5,000,000.00.")
+	assert.Contains(t, content.Text, "attached as a PDF")
+	assert.Contains(t, content.Text, "https://payroll.example.com")
+	assert.Contains(t, content.HTML, `<a href="https://payroll.example.com">`)
+
+	data.Attached = false
+	data.EmployeeName = "<b>employee1</b>"
+	content, err = Render(TemplatePayslip, registry.Translator("id"), data)
+	require.NoError(t, err)
+	assert.Equal(t, "Slip gaji Anda untuk August 2024", content.Subject)
+	assert.NotContains(t, content.Text, "PDF")
+	assert.Contains(t, content.HTML, "Yth. &lt;b&gt;employee1&lt;/b&gt;,")
+}
+
+func TestRender_PasswordReset(t *testing.T) {
+	registry := i18n.NewRegistry("en", nil)
+	data := PasswordResetData{
+		Name:      "employee1",
+		Company:   "PT Mini Payroll",
+		Token:     "q8X0m3-reset-token",
+		ExpiresIn: 60,
+	}
+
+	content, err := Render(TemplatePasswordReset, registry.Translator("en"), data)
+	require.NoError(t, err)
+	assert.Equal(t, "Reset your PT Mini Payroll payroll password", content.Subject)
+	assert.Contains(t, content.Text, "q8X0m3-reset-token")
+	assert.Contains(t, content.Text, "expires in 60 minutes")
+	assert.Contains(t, content.Text, "you can ignore this email")
+
+	data.ByAdmin = true
+	data.ResetURL = "https://payroll.example.com/reset?token=q8X0m3-reset-token"
+	content, err = Render(TemplatePasswordReset, registry.Translator("id"), data)
+	require.NoError(t, err)
+	assert.Equal(t, "Atur ulang kata sandi penggajian PT Mini Payroll Anda", content.Subject)
+	assert.Contains(t, content.Text, "Administrator telah mengatur ulang")
+	assert.NotContains(t, content.Text, "abaikan")
+	assert.Contains(t, content.HTML, `<a href="https://payroll.example.com/reset?token=q8X0m3-reset-token">`)
+}
+
+func TestSMTPSender_Send(t *testing.T) {
+	tests := []struct {
+		name          string
+		rcptReply     string
+		wantErr       bool
+		wantPermanent bool
+	}{
+		{name: "success", rcptReply: "250 OK"},
+		{name: "error - mailbox unavailable", rcptReply: "550 No such user", wantErr: true, wantPermanent: true},
+		{name: "error - try again later", rcptReply: "451 Try again later", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newFakeSMTPServer(t, tt.rcptReply)
+			sender := NewSMTPSender(config.MailConfig{Host: "127.0.0.1", Port: server.port, TLS: "none", Timeout: 5})
+
+			err := sender.Send(testMessage())
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Equal(t, tt.wantPermanent, IsPermanent(err))
+				return
+			}
+
+			require.NoError(t, err)
+			received := <-server.received
+			assert.Equal(t, "<payroll@example.com>", received.from)
+			assert.Equal(t, []string{"<employee1@example.com>"}, received.to)
+			assert.Contains(t, received.data, "Content-Type: multipart/mixed")
+		})
+	}
+}
+
+func readAll(t *testing.T, r io.Reader) string {
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(data)
+}
+
+type receivedMessage struct {
+	from string
+	to   []string
+	data string
+}
+
+type fakeSMTPServer struct {
+	port     int
+	received chan receivedMessage
+}
+
+// newFakeSMTPServer accepts one SMTP session, answering RCPT TO with rcptReply
+func newFakeSMTPServer(t *testing.T, rcptReply string) *fakeSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	server := &fakeSMTPServer{
+		port:     listener.Addr().(*net.TCPAddr).Port,
+		received: make(chan receivedMessage, 1),
+	}
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		text := textproto.NewConn(conn)
+		reply := func(line string) { text.PrintfLine("%s", line) }
+
+		var message receivedMessage
+		reply("220 localhost ESMTP")
+		for {
+			line, err := text.ReadLine()
+			if err != nil {
+				return
+			}
+
+			command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+			switch {
+			case command == "EHLO" || command == "HELO":
+				reply("250 localhost")
+			case strings.HasPrefix(strings.ToUpper(line), "MAIL FROM:"):
+				message.from = line[len("MAIL FROM:"):]
+				reply("250 OK")
+			case strings.HasPrefix(strings.ToUpper(line), "RCPT TO:"):
+				message.to = append(message.to, line[len("RCPT TO:"):])
+				reply(rcptReply)
+			case command == "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				data, err := io.ReadAll(bufio.NewReader(text.DotReader()))
+				if err != nil {
+					return
+				}
+				message.data = string(data)
+				server.received <- message
+				reply("250 OK: queued as " + strconv.Itoa(len(data)))
+			case command == "QUIT":
+				reply("221 Bye")
+				return
+			default:
+				reply("502 Command not implemented")
+			}
+		}
+	}()
+
+	return server
+}
@@ -0,0 +1,159 @@
+package mail
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	netmail "net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// Message is an email with a plain text body, an optional HTML alternative and attachments
+type Message struct {
+	From        netmail.Address
+	To          netmail.Address
+	Subject     string
+	Text        string
+	HTML        string
+	Attachments []Attachment
+}
+
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// Bytes encodes the message as MIME: multipart/mixed holding a multipart/alternative body and the attachments
+func (m *Message) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+
+	messageID, err := newMessageID(m.From.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	body := multipart.NewWriter(&buf)
+	headers := []string{
+		"From: " + m.From.String(),
+		"To: " + m.To.String(),
+		"Subject: " + mime.QEncoding.Encode("utf-8", m.Subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"Message-ID: " + messageID,
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/mixed; boundary=" + body.Boundary(),
+	}
+	buf.WriteString(strings.Join(headers, "\r\n") + "\r\n\r\n")
+
+	alternative, err := m.alternative()
+	if err != nil {
+		return nil, err
+	}
+	part, err := body.CreatePart(textproto.MIMEHeader{"Content-Type": {alternative.contentType}})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write(alternative.data); err != nil {
+		return nil, err
+	}
+
+	for _, attachment := range m.Attachments {
+		if err := writeAttachment(body, attachment); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := body.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+type encodedPart struct {
+	contentType string
+	data        []byte
+}
+
+// alternative encodes the text body and, when set, the HTML body as quoted-printable alternatives
+func (m *Message) alternative() (*encodedPart, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	if err := writeQuotedPrintable(writer, "text/plain; charset=utf-8", m.Text); err != nil {
+		return nil, err
+	}
+	// The preferred alternative comes last
+	if m.HTML != "" {
+		if err := writeQuotedPrintable(writer, "text/html; charset=utf-8", m.HTML); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return &encodedPart{contentType: "multipart/alternative; boundary=" + writer.Boundary(), data: buf.Bytes()}, nil
+}
+
+func writeQuotedPrintable(writer *multipart.Writer, contentType, content string) error {
+	part, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+
+	qp := quotedprintable.NewWriter(part)
+	if _, err := qp.Write([]byte(content)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+func writeAttachment(writer *multipart.Writer, attachment Attachment) error {
+	contentType := attachment.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	part, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {mime.FormatMediaType(contentType, map[string]string{"name": attachment.Filename})},
+		"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename})},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Base64 lines must not exceed 76 characters
+	encoded := base64.StdEncoding.EncodeToString(attachment.Data)
+	for len(encoded) > 76 {
+		if _, err := part.Write([]byte(encoded[:76] + "\r\n")); err != nil {
+			return err
+		}
+		encoded = encoded[76:]
+	}
+	_, err = part.Write([]byte(encoded + "\r\n"))
+	return err
+}
+
+func newMessageID(from string) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+
+	domain := "localhost"
+	if at := strings.LastIndex(from, "@"); at >= 0 && at < len(from)-1 {
+		domain = from[at+1:]
+	}
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(random), domain), nil
+}
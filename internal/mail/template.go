@@ -0,0 +1,93 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+
+	"payslip-system/internal/i18n"
+)
+
+//go:embed templates/*
+var templateFS embed.FS
+
+// Each template consists of templates/<name>.txt, which also defines "<name>.subject", and templates/<name>.html
+const (
+	TemplatePayslip       = "payslip"
+	TemplatePasswordReset = "password_reset"
+)
+
+// Content is a rendered email
+type Content struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// PayslipData is the data of the payslip template
+type PayslipData struct {
+	Language     string
+	EmployeeName string
+	Period       string
+	NetPay       string
+	Company      string
+	Attached     bool // the payslip PDF is attached
+	PortalURL    string
+}
+
+// PasswordResetData is the data of the password reset template
+type PasswordResetData struct {
+	Language  string
+	Name      string
+	Company   string
+	Token     string
+	ResetURL  string // links the token when a reset page is configured
+	ExpiresIn int    // minutes
+	ByAdmin   bool   // an admin reset the password, the user did not ask for it
+}
+
+// placeholderFuncs lets the templates parse once; Render binds "t" to the recipient's translator
+var placeholderFuncs = map[string]interface{}{
+	"t": func(key string, args ...interface{}) string { return key },
+}
+
+var (
+	textTemplates = texttemplate.Must(texttemplate.New("").Funcs(placeholderFuncs).ParseFS(templateFS, "templates/*.txt"))
+	htmlTemplates = htmltemplate.Must(htmltemplate.New("").Funcs(placeholderFuncs).ParseFS(templateFS, "templates/*.html"))
+)
+
+// Render renders a template in the translator's language. Messages come from the "email.<name>.*" catalog keys.
+func Render(name string, tr *i18n.Translator, data interface{}) (*Content, error) {
+	funcs := map[string]interface{}{"t": tr.T}
+
+	text, err := textTemplates.Clone()
+	if err != nil {
+		return nil, err
+	}
+	text.Funcs(funcs)
+
+	html, err := htmlTemplates.Clone()
+	if err != nil {
+		return nil, err
+	}
+	html.Funcs(funcs)
+
+	var subject, textBody, htmlBody bytes.Buffer
+	if err := text.ExecuteTemplate(&subject, name+".subject", data); err != nil {
+		return nil, err
+	}
+	if err := text.ExecuteTemplate(&textBody, name+".txt", data); err != nil {
+		return nil, err
+	}
+	if err := html.ExecuteTemplate(&htmlBody, name+".html", data); err != nil {
+		return nil, err
+	}
+
+	return &Content{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    textBody.String(),
+		HTML:    htmlBody.String(),
+	}, nil
+}
@@ -1,47 +1,91 @@
 package middleware
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"net/http"
-	"os"
 	"strings"
 	"time"
 
+	"payslip-system/internal/domains"
 	"payslip-system/internal/models"
+	"payslip-system/internal/rbac"
 	"payslip-system/internal/repository"
+	"payslip-system/internal/token"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
 )
 
+// APIKeyPrefix starts every API key, telling keys apart from access tokens
+const APIKeyPrefix = "psk_"
+
+// HashAPIKey is how API keys are stored: SHA-256, hex encoded
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
 type Claims struct {
-	UserID   uuid.UUID `json:"user_id"`
-	Username string    `json:"username"`
-	Role     string    `json:"role"`
+	UserID    uuid.UUID `json:"user_id"`
+	Username  string    `json:"username"`
+	Roles     []string  `json:"roles"` // as at issuance; access is checked against the roles held at the time of a request
+	SessionID uuid.UUID `json:"sid"`   // the login session; revoking it invalidates the token
+	// Act is the admin viewing the API as the user (RFC 8693). Such tokens belong to the admin's session and
+	// are read-only.
+	Act *Actor `json:"act,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(user *models.User) (string, error) {
-	expirationTime := time.Now().Add(24 * time.Hour)
+// Actor is who acts through a token issued for another user
+type Actor struct {
+	UserID   uuid.UUID `json:"sub"`
+	Username string    `json:"username"`
+}
+
+// GenerateToken issues an access token of a session, valid for ttl
+func GenerateToken(keys *token.KeySet, user *models.User, roles []string, sessionID uuid.UUID, ttl time.Duration) (string, error) {
+	return generateToken(keys, user, roles, sessionID, nil, ttl)
+}
+
+// GenerateImpersonationToken issues a read-only access token of a user for an admin viewing the API as them.
+// It belongs to the admin's session, so it stops working when they log out.
+func GenerateImpersonationToken(keys *token.KeySet, user *models.User, roles []string, admin *models.User, sessionID uuid.UUID, ttl time.Duration) (string, error) {
+	return generateToken(keys, user, roles, sessionID, &Actor{UserID: admin.ID, Username: admin.Username}, ttl)
+}
+
+func generateToken(keys *token.KeySet, user *models.User, roles []string, sessionID uuid.UUID, act *Actor, ttl time.Duration) (string, error) {
+	now := time.Now()
 	claims := &Claims{
-		UserID:   user.ID,
-		Username: user.Username,
-		Role:     user.Role,
+		UserID:    user.ID,
+		Username:  user.Username,
+		Roles:     roles,
+		SessionID: sessionID,
+		Act:       act,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        uuid.NewString(),
+			Subject:   user.ID.String(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		jwtSecret = "your-secret-key"
+	return keys.Sign(claims)
+}
+
+// ParseToken verifies the signature and expiry of an access token
+func ParseToken(keys *token.KeySet, tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	if err := keys.Parse(tokenString, claims); err != nil {
+		return nil, err
 	}
-	return token.SignedString([]byte(jwtSecret))
+	return claims, nil
 }
 
-func AuthMiddleware(repos *repository.Repositories) gin.HandlerFunc {
+func AuthMiddleware(repos *repository.Repositories, keys *token.KeySet) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -57,22 +101,30 @@ func AuthMiddleware(repos *repository.Repositories) gin.HandlerFunc {
 			return
 		}
 
-		claims := &Claims{}
-		jwtSecret := os.Getenv("JWT_SECRET")
-		if jwtSecret == "" {
-			jwtSecret = "your-secret-key"
+		if strings.HasPrefix(tokenString, APIKeyPrefix) {
+			authenticateAPIKey(c, repos, tokenString)
+			return
 		}
 
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			return []byte(jwtSecret), nil
-		})
-
-		if err != nil || !token.Valid {
+		claims, err := ParseToken(keys, tokenString)
+		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
 			c.Abort()
 			return
 		}
 
+		// Tokens of a logged out or revoked session are rejected before they expire
+		sessionUserID := claims.UserID
+		if claims.Act != nil {
+			sessionUserID = claims.Act.UserID
+		}
+		session, err := repos.Session.GetByID(claims.SessionID)
+		if err != nil || session.UserID != sessionUserID || session.RevokedAt != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Session expired or revoked"})
+			c.Abort()
+			return
+		}
+
 		// Verify user exists and is active
 		var user models.User
 		if err := repos.DB.Where("id = ? AND is_active = true", claims.UserID).First(&user).Error; err != nil {
@@ -81,19 +133,154 @@ func AuthMiddleware(repos *repository.Repositories) gin.HandlerFunc {
 			return
 		}
 
+		// Permissions are looked up on every request, so role changes apply right away
+		permissions, err := repos.Role.GetUserPermissions(user.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load permissions"})
+			c.Abort()
+			return
+		}
+
+		if claims.Act != nil {
+			authenticateImpersonation(c, repos, claims, &user, permissions)
+			return
+		}
+
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
-		c.Set("role", claims.Role)
+		c.Set("session_id", claims.SessionID)
 		c.Set("user", &user)
+		c.Set("actor", domains.UserActor(claims.UserID))
+		c.Set("permissions", rbac.NewSet(permissions))
+		c.Next()
+	}
+}
+
+// authenticateImpersonation lets an admin view the API as a user. The request runs as the user, with
+// the admin as the actor of anything services record; it may only use GET and HEAD, and is recorded in the
+// audit log for the user to see in their activity. GET routes with side effects use NoImpersonation.
+func authenticateImpersonation(c *gin.Context, repos *repository.Repositories, claims *Claims, user *models.User, permissions []string) {
+	if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Viewing as a user is read-only"})
+		c.Abort()
+		return
+	}
+
+	// The admin has to still be allowed to, and the user still not an admin
+	var admin models.User
+	if err := repos.DB.Where("id = ? AND is_active = true", claims.Act.UserID).First(&admin).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found or inactive"})
+		c.Abort()
+		return
+	}
+	adminPermissions, err := repos.Role.GetUserPermissions(admin.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load permissions"})
+		c.Abort()
+		return
+	}
+	if !rbac.NewSet(adminPermissions).Has(rbac.UserImpersonate) || rbac.Privileged(permissions) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Viewing as this user is not permitted"})
+		c.Abort()
+		return
+	}
+
+	// The view is recorded before the request runs, so no response goes out without its audit entry
+	details, _ := json.Marshal(map[string]interface{}{
+		"method": c.Request.Method,
+		"path":   c.Request.URL.Path,
+	})
+	err = repos.AuditLog.Create(&models.AuditLog{
+		ID:                 uuid.New(),
+		TableName:          "users",
+		RecordID:           user.ID,
+		Action:             "VIEW",
+		NewValues:          string(details),
+		UserID:             &admin.ID,
+		ImpersonatedUserID: &user.ID,
+		IPAddress:          c.GetString("client_ip"),
+		RequestID:          c.GetString("request_id"),
+		CreatedAt:          time.Now(),
+	})
+	if err != nil {
+		logrus.WithError(err).WithFields(logrus.Fields{"user_id": user.ID, "impersonator_id": admin.ID}).Error("Failed to record request made viewing as a user")
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record the request in the audit log"})
+		c.Abort()
+		return
+	}
+
+	c.Set("user_id", user.ID)
+	c.Set("username", user.Username)
+	c.Set("session_id", claims.SessionID)
+	c.Set("user", user)
+	c.Set("permissions", rbac.NewSet(permissions))
+	c.Set("impersonator_id", admin.ID)
+	c.Set("actor", domains.Actor{UserID: &user.ID, ImpersonatorID: &admin.ID})
+	c.Next()
+}
+
+// authenticateAPIKey lets an integration in with an API key. The key acts as itself: there is no user_id, and
+// services record the key as the actor. Permissions no longer grantable to keys are dropped, should a key
+// have been issued with them.
+func authenticateAPIKey(c *gin.Context, repos *repository.Repositories, apiKey string) {
+	now := time.Now()
+	key, err := repos.APIKey.GetByHash(HashAPIKey(apiKey))
+	if err != nil || key.RevokedAt != nil || (key.ExpiresAt != nil && now.After(*key.ExpiresAt)) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid, expired or revoked API key"})
+		c.Abort()
+		return
+	}
+
+	if err := repos.APIKey.MarkUsed(key.ID, c.ClientIP(), now); err != nil {
+		logrus.WithError(err).WithField("api_key_id", key.ID).Warn("Failed to record API key use")
+	}
+
+	var permissions []string
+	for _, p := range key.Permissions {
+		if rbac.APIKeyGrantable(p.Permission) {
+			permissions = append(permissions, p.Permission)
+		}
+	}
+
+	c.Set("username", key.Name)
+	c.Set("api_key_id", key.ID)
+	c.Set("actor", domains.APIKeyActor(key.ID))
+	c.Set("permissions", rbac.NewSet(permissions))
+	c.Next()
+}
+
+// UsersOnly keeps API keys out of routes concerning the account of a logged in user. Admins viewing as a user
+// are kept out by the read-only check already.
+func UsersOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, isKey := c.Get("api_key_id"); isKey {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Not available to API keys"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// NoImpersonation keeps admins viewing as a user out of GET routes that change something, such as numbering
+// tax certificates; the read-only check of impersonation only looks at the method
+func NoImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, impersonating := c.Get("impersonator_id"); impersonating {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Not available when viewing as a user"})
+			c.Abort()
+			return
+		}
 		c.Next()
 	}
 }
 
-func AdminMiddleware() gin.HandlerFunc {
+// PasswordChangeMiddleware keeps users who must change their password, such as seeded users or after an admin
+// reset, out of everything else until they have. API keys have no password.
+func PasswordChangeMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		role, exists := c.Get("role")
-		if !exists || role != "admin" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		if user, ok := c.Get("user"); ok && user.(*models.User).MustChangePassword {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Password change required"})
 			c.Abort()
 			return
 		}
@@ -101,11 +288,12 @@ func AdminMiddleware() gin.HandlerFunc {
 	}
 }
 
-func EmployeeMiddleware() gin.HandlerFunc {
+// RequirePermission lets only users holding the permission through one of their roles through
+func RequirePermission(permission string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		role, exists := c.Get("role")
-		if !exists || role != "employee" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Employee access required"})
+		permissions, exists := c.Get("permissions")
+		if !exists || !permissions.(rbac.Set).Has(permission) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Permission required", "permission": permission})
 			c.Abort()
 			return
 		}
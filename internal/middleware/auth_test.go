@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NoImpersonation(t *testing.T) {
+	tests := []struct {
+		name          string
+		impersonating bool
+		wantStatus    int
+	}{
+		{name: "user", wantStatus: http.StatusOK},
+		{name: "admin viewing as the user", impersonating: true, wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.Use(func(c *gin.Context) {
+				if tt.impersonating {
+					c.Set("impersonator_id", uuid.New())
+				}
+			})
+			called := false
+			r.GET("/api/v1/employee/tax-certificate/:year", NoImpersonation(), func(c *gin.Context) {
+				called = true
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/employee/tax-certificate/2024", nil))
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, !tt.impersonating, called)
+		})
+	}
+}
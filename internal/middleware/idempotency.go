@@ -0,0 +1,183 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"payslip-system/internal/domains"
+	"payslip-system/internal/models"
+	"payslip-system/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	idempotencyKeyTTL        = 24 * time.Hour
+	maxIdempotencyKeyLength  = 255
+)
+
+// responseRecorder tees the response body so it can be stored for replay
+type responseRecorder struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency makes POST requests carrying an Idempotency-Key header safe to retry.
+// The first request with a key is executed and its response stored; repeats with the
+// same fingerprint get the stored response, and reuse with a different request is a conflict.
+// Must run after AuthMiddleware since keys are scoped per user or API key.
+func Idempotency(repos *repository.Repositories) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key must be at most 255 characters"})
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		actor := c.MustGet("actor").(domains.Actor)
+		now := time.Now()
+		record := &models.IdempotencyKey{
+			ID:          uuid.New(),
+			UserID:      actor.UserID,
+			APIKeyID:    actor.APIKeyID,
+			Key:         key,
+			Method:      c.Request.Method,
+			Path:        c.Request.URL.Path,
+			RequestHash: fingerprintRequest(c.Request.Method, c.Request.URL.RequestURI(), body),
+			ExpiresAt:   now.Add(idempotencyKeyTTL),
+			CreatedAt:   now,
+		}
+
+		created, err := repos.IdempotencyKey.CreateIfAbsent(record)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store idempotency key"})
+			c.Abort()
+			return
+		}
+
+		if !created {
+			existing, err := repos.IdempotencyKey.GetByOwnerAndKey(actor.UserID, actor.APIKeyID, key)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load idempotency key"})
+				c.Abort()
+				return
+			}
+
+			// Expired keys are forgotten and the request is treated as new
+			if existing.ExpiresAt.Before(now) {
+				err = repos.IdempotencyKey.Delete(existing.ID)
+				if err == nil {
+					created, err = repos.IdempotencyKey.CreateIfAbsent(record)
+				}
+				if err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store idempotency key"})
+					c.Abort()
+					return
+				}
+				if !created {
+					c.JSON(http.StatusConflict, gin.H{"error": "A request with this Idempotency-Key is already in progress"})
+					c.Abort()
+					return
+				}
+			} else {
+				replayIdempotentResponse(c, existing, record.RequestHash)
+				return
+			}
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = recorder
+
+		// A panicking handler leaves no response to store, so the key is released before
+		// the panic reaches the recovery middleware and the client can retry
+		defer func() {
+			if r := recover(); r != nil {
+				releaseIdempotencyKey(repos, record.ID)
+				panic(r)
+			}
+		}()
+
+		c.Next()
+
+		// Server errors are not stored so the client can retry with the same key
+		if recorder.Status() >= http.StatusInternalServerError {
+			releaseIdempotencyKey(repos, record.ID)
+			return
+		}
+
+		completedAt := time.Now()
+		record.StatusCode = recorder.Status()
+		record.ContentType = recorder.Header().Get("Content-Type")
+		record.ResponseBody = recorder.body.String()
+		record.CompletedAt = &completedAt
+		if err := repos.IdempotencyKey.Update(record); err != nil {
+			logrus.WithError(err).Warn("Failed to store idempotent response")
+		}
+	}
+}
+
+func releaseIdempotencyKey(repos *repository.Repositories, id uuid.UUID) {
+	if err := repos.IdempotencyKey.Delete(id); err != nil {
+		logrus.WithError(err).Warn("Failed to release idempotency key")
+	}
+}
+
+func replayIdempotentResponse(c *gin.Context, existing *models.IdempotencyKey, requestHash string) {
+	if existing.RequestHash != requestHash {
+		c.JSON(http.StatusConflict, gin.H{"error": "Idempotency-Key was already used with a different request"})
+		c.Abort()
+		return
+	}
+
+	if existing.CompletedAt == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "A request with this Idempotency-Key is already in progress"})
+		c.Abort()
+		return
+	}
+
+	c.Header(IdempotentReplayedHeader, "true")
+	c.Data(existing.StatusCode, existing.ContentType, []byte(existing.ResponseBody))
+	c.Abort()
+}
+
+func fingerprintRequest(method, uri string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(uri))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,179 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"payslip-system/internal/domains"
+	"payslip-system/internal/models"
+	"payslip-system/internal/repository"
+	mock_repository "payslip-system/internal/repository/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// idempotencyStore keeps the keys of one user in memory, with the unique key constraint of the table
+type idempotencyStore struct {
+	mu   sync.Mutex
+	keys map[string]models.IdempotencyKey
+}
+
+func newIdempotencyRouter(t *testing.T, handler gin.HandlerFunc) (*gin.Engine, *idempotencyStore) {
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	store := &idempotencyStore{keys: map[string]models.IdempotencyKey{}}
+	mockRepo := mock_repository.NewMockIIdempotencyKeyRepository(ctrl)
+	mockRepo.EXPECT().CreateIfAbsent(gomock.Any()).DoAndReturn(func(record *models.IdempotencyKey) (bool, error) {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		if _, ok := store.keys[record.Key]; ok {
+			return false, nil
+		}
+		store.keys[record.Key] = *record
+		return true, nil
+	}).AnyTimes()
+	mockRepo.EXPECT().GetByOwnerAndKey(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(_, _ *uuid.UUID, key string) (*models.IdempotencyKey, error) {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		record, ok := store.keys[key]
+		if !ok {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return &record, nil
+	}).AnyTimes()
+	mockRepo.EXPECT().Update(gomock.Any()).DoAndReturn(func(record *models.IdempotencyKey) error {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		store.keys[record.Key] = *record
+		return nil
+	}).AnyTimes()
+	mockRepo.EXPECT().Delete(gomock.Any()).DoAndReturn(func(id uuid.UUID) error {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		for key, record := range store.keys {
+			if record.ID == id {
+				delete(store.keys, key)
+			}
+		}
+		return nil
+	}).AnyTimes()
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(gin.RecoveryWithWriter(io.Discard))
+	r.Use(func(c *gin.Context) {
+		c.Set("actor", domains.UserActor(uuid.New()))
+	})
+	r.Use(Idempotency(&repository.Repositories{IdempotencyKey: mockRepo}))
+	r.POST("/api/v1/overtime", handler)
+	return r, store
+}
+
+func postIdempotent(r *gin.Engine, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/overtime", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(IdempotencyKeyHeader, key)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func Test_Idempotency(t *testing.T) {
+	t.Run("success - repeated request gets the stored response", func(t *testing.T) {
+		calls := 0
+		r, _ := newIdempotencyRouter(t, func(c *gin.Context) {
+			calls++
+			c.JSON(http.StatusCreated, gin.H{"calls": calls})
+		})
+
+		first := postIdempotent(r, "key-1", `{"hours":2}`)
+		second := postIdempotent(r, "key-1", `{"hours":2}`)
+
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, http.StatusCreated, first.Code)
+		assert.Empty(t, first.Header().Get(IdempotentReplayedHeader))
+		assert.Equal(t, http.StatusCreated, second.Code)
+		assert.Equal(t, "true", second.Header().Get(IdempotentReplayedHeader))
+		assert.Equal(t, first.Body.String(), second.Body.String())
+		assert.Equal(t, first.Header().Get("Content-Type"), second.Header().Get("Content-Type"))
+	})
+
+	t.Run("error - key reused with a different body", func(t *testing.T) {
+		calls := 0
+		r, _ := newIdempotencyRouter(t, func(c *gin.Context) {
+			calls++
+			c.JSON(http.StatusCreated, gin.H{"calls": calls})
+		})
+
+		postIdempotent(r, "key-1", `{"hours":2}`)
+		w := postIdempotent(r, "key-1", `{"hours":3}`)
+
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "different request")
+	})
+
+	t.Run("error - request with the key still in progress", func(t *testing.T) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		calls := 0
+		r, _ := newIdempotencyRouter(t, func(c *gin.Context) {
+			calls++
+			close(started)
+			<-release
+			c.JSON(http.StatusCreated, gin.H{"calls": calls})
+		})
+
+		var first *httptest.ResponseRecorder
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			first = postIdempotent(r, "key-1", `{"hours":2}`)
+		}()
+		<-started
+
+		inFlight := postIdempotent(r, "key-1", `{"hours":2}`)
+		close(release)
+		<-done
+
+		assert.Equal(t, http.StatusConflict, inFlight.Code)
+		assert.Contains(t, inFlight.Body.String(), "already in progress")
+		assert.Equal(t, http.StatusCreated, first.Code)
+
+		replayed := postIdempotent(r, "key-1", `{"hours":2}`)
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, "true", replayed.Header().Get(IdempotentReplayedHeader))
+		assert.Equal(t, first.Body.String(), replayed.Body.String())
+	})
+
+	t.Run("success - key released when the handler panics", func(t *testing.T) {
+		calls := 0
+		r, store := newIdempotencyRouter(t, func(c *gin.Context) {
+			calls++
+			if calls == 1 {
+				panic("handler failed")
+			}
+			c.JSON(http.StatusCreated, gin.H{"calls": calls})
+		})
+
+		w := postIdempotent(r, "key-1", `{"hours":2}`)
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		require.Empty(t, store.keys)
+
+		retried := postIdempotent(r, "key-1", `{"hours":2}`)
+		assert.Equal(t, 2, calls)
+		assert.Equal(t, http.StatusCreated, retried.Code)
+		assert.Empty(t, retried.Header().Get(IdempotentReplayedHeader))
+		assert.Len(t, store.keys, 1)
+	})
+}
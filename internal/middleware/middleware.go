@@ -13,8 +13,8 @@ func CORS() gin.HandlerFunc {
 	config := cors.Config{
 		AllowAllOrigins: true,
 		AllowMethods:    []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
-		AllowHeaders:    []string{"Origin", "Content-Length", "Content-Type", "Authorization"},
-		ExposeHeaders:   []string{"Content-Length"},
+		AllowHeaders:    []string{"Origin", "Content-Length", "Content-Type", "Authorization", IdempotencyKeyHeader},
+		ExposeHeaders:   []string{"Content-Length", IdempotentReplayedHeader},
 		MaxAge:          12 * time.Hour,
 	}
 	return cors.New(config)
@@ -24,9 +24,51 @@ type User struct {
 	BaseModel
 	Username string   `json:"username" gorm:"unique;not null"`
 	Password string   `json:"-" gorm:"not null"`
-	Role     string   `json:"role" gorm:"not null;default:'employee'"` // 'admin' or 'employee'
+	Role     string   `json:"role" gorm:"not null;default:'employee'"` // role granted on creation; access comes from all roles held
 	Salary   *float64 `json:"salary,omitempty"`                        // Only for employees
 	IsActive bool     `json:"is_active" gorm:"default:true"`
+
+	Email             string     `json:"email,omitempty"` // payslips are emailed here
+	BirthDate         *time.Time `json:"birth_date,omitempty" gorm:"type:date"`
+	PayslipPIN        string     `json:"-"`                            // sealed, used as the payslip PDF password
+	PreferredLanguage string     `json:"preferred_language,omitempty"` // payslip language, e.g. 'id' or 'en'
+
+	// Salary account, maintained by admins
+	BankCode          string `json:"bank_code,omitempty"`         // e.g. 'BCA', 'MANDIRI'
+	BankAccountNumber string `json:"-"`                           // only returned by the admin employee endpoints
+	BankAccountName   string `json:"bank_account_name,omitempty"` // holder name as registered with the bank
+
+	CostCenter string `json:"cost_center,omitempty"` // salary costs are booked to it in the payroll journal
+
+	// Tax profile, maintained by admins
+	NIK        string `json:"nik,omitempty"`         // national identity number, 16 digits
+	NPWP       string `json:"npwp,omitempty"`        // taxpayer number, 15 or 16 digits
+	PTKPStatus string `json:"ptkp_status,omitempty"` // marital status and dependents for PPh 21, e.g. 'TK/0' or 'K/2'
+
+	// Multi-factor authentication; mandatory for admins
+	MFASecret    string     `json:"-"`                        // sealed TOTP secret, set once enrollment starts
+	MFAEnabledAt *time.Time `json:"mfa_enabled_at,omitempty"` // when enrollment was confirmed with a code
+	MFALastStep  int64      `json:"-"`                        // TOTP time step of the last accepted code, which cannot be used again
+
+	MustChangePassword bool       `json:"must_change_password"` // set by seeding and admin resets; only a password change is allowed
+	PasswordChangedAt  *time.Time `json:"password_changed_at,omitempty"`
+
+	// Line manager, who decides on the user's overtime, reimbursements and leave along with the managers above
+	ManagerID *uuid.UUID `json:"manager_id,omitempty" gorm:"type:uuid;index"`
+
+	// Single sign-on
+	EmployeeID  *string `json:"employee_id,omitempty" gorm:"uniqueIndex"` // HR employee number, matched against the identity provider's claim
+	OIDCSubject *string `json:"-" gorm:"uniqueIndex"`                     // identity provider subject, linked on the first login
+}
+
+// AfterCreate grants new users the role they were created with
+func (u *User) AfterCreate(tx *gorm.DB) error {
+	if u.Role == "" {
+		return nil
+	}
+	return tx.Exec(`INSERT INTO user_roles (user_id, role_id, created_at)
+		SELECT ?, id, ? FROM roles WHERE name = ?
+		ON CONFLICT DO NOTHING`, u.ID, time.Now(), u.Role).Error
 }
 
 // AttendancePeriod represents payroll periods set by admin
@@ -52,6 +94,28 @@ type Attendance struct {
 	AttendancePeriod AttendancePeriod `json:"attendance_period,omitempty"`
 }
 
+// Approval statuses of overtime, reimbursements and leave
+const (
+	ApprovalStatusPending  = "pending"
+	ApprovalStatusApproved = "approved"
+	ApprovalStatusRejected = "rejected"
+)
+
+// Approval is a line manager's decision on an employee's request. Requests of employees without a manager
+// are approved on submission; only approved overtime and reimbursements are paid.
+type Approval struct {
+	Status          string     `json:"status" gorm:"not null;default:'approved';index"` // records from before approvals existed are approved
+	DecidedBy       *uuid.UUID `json:"decided_by,omitempty" gorm:"type:uuid"`
+	OnBehalfOf      *uuid.UUID `json:"on_behalf_of,omitempty" gorm:"type:uuid"` // the manager on leave, when their delegate decided
+	DecidedAt       *time.Time `json:"decided_at,omitempty"`
+	DecisionComment string     `json:"decision_comment,omitempty"`
+}
+
+// IsApproved reports whether the request was approved
+func (a Approval) IsApproved() bool {
+	return a.Status == ApprovalStatusApproved
+}
+
 // Overtime represents employee overtime records
 type Overtime struct {
 	BaseModel
@@ -59,6 +123,7 @@ type Overtime struct {
 	AttendancePeriodID uuid.UUID `json:"attendance_period_id" gorm:"type:uuid;not null"`
 	Date               time.Time `json:"date" gorm:"not null"`
 	Hours              float64   `json:"hours" gorm:"not null"`
+	Approval           `gorm:"embedded"`
 
 	// Relationships
 	User             User             `json:"user,omitempty"`
@@ -72,23 +137,78 @@ type Reimbursement struct {
 	AttendancePeriodID uuid.UUID `json:"attendance_period_id" gorm:"type:uuid;not null"`
 	Amount             float64   `json:"amount" gorm:"not null"`
 	Description        string    `json:"description" gorm:"not null"`
+	Approval           `gorm:"embedded"`
 
 	// Relationships
 	User             User             `json:"user,omitempty"`
 	AttendancePeriod AttendancePeriod `json:"attendance_period,omitempty"`
 }
 
+// Leave types
+const (
+	LeaveTypeAnnual = "annual"
+	LeaveTypeSick   = "sick"
+	LeaveTypeUnpaid = "unpaid"
+)
+
+// Leave is an employee's request to be absent. While approved leave of a manager lasts, its delegate decides on
+// the requests of the manager's team.
+type Leave struct {
+	BaseModel
+	UserID     uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	Type       string     `json:"type" gorm:"not null"` // 'annual', 'sick' or 'unpaid'
+	StartDate  time.Time  `json:"start_date" gorm:"type:date;not null"`
+	EndDate    time.Time  `json:"end_date" gorm:"type:date;not null"` // inclusive
+	Reason     string     `json:"reason,omitempty"`
+	DelegateID *uuid.UUID `json:"delegate_id,omitempty" gorm:"type:uuid;index"`
+	Approval   `gorm:"embedded"`
+}
+
+// Payroll statuses for the maker-checker workflow
+const (
+	PayrollStatusPendingApproval = "pending_approval"
+	PayrollStatusApproved        = "approved"
+	PayrollStatusRejected        = "rejected"
+)
+
 // Payroll represents processed payroll for a period
 type Payroll struct {
 	BaseModel
-	AttendancePeriodID uuid.UUID `json:"attendance_period_id" gorm:"type:uuid;not null"`
-	TotalAmount        float64   `json:"total_amount" gorm:"not null"`
-	ProcessedBy        uuid.UUID `json:"processed_by" gorm:"type:uuid;not null"`
+	AttendancePeriodID uuid.UUID  `json:"attendance_period_id" gorm:"type:uuid;not null;uniqueIndex:idx_payrolls_live_period,where:status <> 'rejected'"` // only one live run per period
+	TotalAmount        float64    `json:"total_amount" gorm:"not null"`
+	ProcessedBy        uuid.UUID  `json:"processed_by" gorm:"type:uuid;not null"`
+	Status             string     `json:"status" gorm:"not null;default:'approved'"` // runs processed before approvals existed are final
+	RequiredApprovals  int        `json:"required_approvals" gorm:"not null;default:0"`
+	FinalizedAt        *time.Time `json:"finalized_at,omitempty"`
 
 	// Relationships
-	AttendancePeriod AttendancePeriod `json:"attendance_period,omitempty"`
-	ProcessedByUser  User             `json:"processed_by_user,omitempty" gorm:"foreignKey:ProcessedBy"`
-	PayrollItems     []PayrollItem    `json:"payroll_items,omitempty"`
+	AttendancePeriod AttendancePeriod  `json:"attendance_period,omitempty"`
+	ProcessedByUser  User              `json:"processed_by_user,omitempty" gorm:"foreignKey:ProcessedBy"`
+	PayrollItems     []PayrollItem     `json:"payroll_items,omitempty"`
+	Approvals        []PayrollApproval `json:"approvals,omitempty"`
+}
+
+// IsFinal reports whether the payroll has been approved and may be paid out
+func (p *Payroll) IsFinal() bool {
+	return p.Status == PayrollStatusApproved
+}
+
+// Payroll approval decisions
+const (
+	ApprovalDecisionApproved = "approved"
+	ApprovalDecisionRejected = "rejected"
+)
+
+// PayrollApproval records a checker's decision on a prepared payroll
+type PayrollApproval struct {
+	BaseModel
+	PayrollID  uuid.UUID `json:"payroll_id" gorm:"type:uuid;not null;uniqueIndex:idx_payroll_approver"`
+	ApproverID uuid.UUID `json:"approver_id" gorm:"type:uuid;not null;uniqueIndex:idx_payroll_approver"`
+	Decision   string    `json:"decision" gorm:"not null"` // 'approved' or 'rejected'
+	Comment    string    `json:"comment,omitempty"`
+
+	// Relationships
+	Approver User `json:"approver,omitempty" gorm:"foreignKey:ApproverID"`
 }
 
 // PayrollItem represents individual employee payroll calculation
@@ -105,25 +225,340 @@ type PayrollItem struct {
 	ReimbursementAmount float64   `json:"reimbursement_amount" gorm:"not null"`
 	TotalAmount         float64   `json:"total_amount" gorm:"not null"`
 
+	// Salary transfer, tracked from the disbursement file to the bank statement
+	PaymentStatus    string     `json:"payment_status" gorm:"not null;default:'pending'"` // 'pending', 'sent', 'paid', 'failed' or 'returned'
+	PaymentBatch     string     `json:"payment_batch,omitempty"`                          // reference of the last disbursement file it was in
+	PaymentReason    string     `json:"payment_reason,omitempty"`                         // why the bank rejected or returned it
+	PaymentUpdatedAt *time.Time `json:"payment_updated_at,omitempty"`
+	PaymentAttempts  int        `json:"payment_attempts" gorm:"not null;default:0"` // disbursement files it was in
+
+	// Statutory deductions, zero when the payroll does not withhold them. The employee BPJS shares and
+	// PPh 21 are deducted from TotalAmount; the employer shares are paid on top.
+	TaxAmount float64           `json:"tax_amount" gorm:"not null;default:0"` // PPh 21 withheld
+	BPJS      BPJSContributions `json:"bpjs" gorm:"embedded;embeddedPrefix:bpjs_"`
+
 	// Relationships
 	Payroll User `json:"payroll,omitempty"`
 	User    User `json:"user,omitempty"`
 }
 
+// BPJSContributions are the social security contributions on one month's wage: Ketenagakerjaan
+// (JHT old age savings, JP pension, JKK work accident, JKM death) and Kesehatan (health)
+type BPJSContributions struct {
+	JHTEmployee    float64 `json:"jht_employee" gorm:"not null;default:0"`
+	JPEmployee     float64 `json:"jp_employee" gorm:"not null;default:0"`
+	HealthEmployee float64 `json:"health_employee" gorm:"not null;default:0"`
+	JHTEmployer    float64 `json:"jht_employer" gorm:"not null;default:0"`
+	JPEmployer     float64 `json:"jp_employer" gorm:"not null;default:0"`
+	JKKEmployer    float64 `json:"jkk_employer" gorm:"not null;default:0"`
+	JKMEmployer    float64 `json:"jkm_employer" gorm:"not null;default:0"`
+	HealthEmployer float64 `json:"health_employer" gorm:"not null;default:0"`
+}
+
+// EmployeeTotal is what is withheld from the employee's pay
+func (c BPJSContributions) EmployeeTotal() float64 {
+	return c.JHTEmployee + c.JPEmployee + c.HealthEmployee
+}
+
+// EmployerTotal is what the company pays on top of the employee's pay
+func (c BPJSContributions) EmployerTotal() float64 {
+	return c.JHTEmployer + c.JPEmployer + c.JKKEmployer + c.JKMEmployer + c.HealthEmployer
+}
+
+// PensionContributions are the employee shares deductible from income for PPh 21
+func (c BPJSContributions) PensionContributions() float64 {
+	return c.JHTEmployee + c.JPEmployee
+}
+
+// TaxableBenefits are the employer premiums that count as the employee's income for PPh 21
+func (c BPJSContributions) TaxableBenefits() float64 {
+	return c.JKKEmployer + c.JKMEmployer + c.HealthEmployer
+}
+
 // AuditLog represents audit trail for significant changes
 type AuditLog struct {
+	ID                 uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	TableName          string     `json:"table_name" gorm:"not null"`
+	RecordID           uuid.UUID  `json:"record_id" gorm:"type:uuid;not null"`
+	Action             string     `json:"action" gorm:"not null"` // 'INSERT', 'UPDATE', 'DELETE'; 'IMPERSONATE' and 'VIEW' when viewing as a user
+	OldValues          string     `json:"old_values,omitempty" gorm:"type:jsonb"`
+	NewValues          string     `json:"new_values,omitempty" gorm:"type:jsonb"`
+	UserID             *uuid.UUID `json:"user_id,omitempty" gorm:"type:uuid"`
+	APIKeyID           *uuid.UUID `json:"api_key_id,omitempty" gorm:"type:uuid;index"`           // set instead of the user for actions of an API key
+	ImpersonatedUserID *uuid.UUID `json:"impersonated_user_id,omitempty" gorm:"type:uuid;index"` // the user an admin, the user, was viewing as
+	IPAddress          string     `json:"ip_address"`
+	RequestID          string     `json:"request_id"`
+	CreatedAt          time.Time  `json:"created_at"`
+}
+
+// IdempotencyKey stores the fingerprint and response of a mutating request so retries can be replayed
+type IdempotencyKey struct {
+	ID           uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID       *uuid.UUID `json:"user_id,omitempty" gorm:"type:uuid;uniqueIndex:idx_idempotency_user_key"`
+	APIKeyID     *uuid.UUID `json:"api_key_id,omitempty" gorm:"type:uuid;uniqueIndex:idx_idempotency_api_key_key"`
+	Key          string     `json:"key" gorm:"not null;size:255;uniqueIndex:idx_idempotency_user_key;uniqueIndex:idx_idempotency_api_key_key"`
+	Method       string     `json:"method" gorm:"not null"`
+	Path         string     `json:"path" gorm:"not null"`
+	RequestHash  string     `json:"request_hash" gorm:"not null"`
+	StatusCode   int        `json:"status_code"`
+	ContentType  string     `json:"content_type"`
+	ResponseBody string     `json:"-" gorm:"type:text"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt    time.Time  `json:"expires_at" gorm:"not null;index"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// Language is a message catalog added or customized by admins. It overrides the builtin catalog with the same code.
+type Language struct {
+	Code      string     `json:"code" gorm:"primaryKey;size:16"`
+	Name      string     `json:"name" gorm:"not null"`
+	Catalog   string     `json:"-" gorm:"type:text;not null"` // JSON encoded i18n.Catalog
+	UpdatedBy *uuid.UUID `json:"updated_by" gorm:"type:uuid"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// Email delivery statuses
+const (
+	EmailStatusQueued  = "queued"
+	EmailStatusSending = "sending"
+	EmailStatusSent    = "sent"
+	EmailStatusFailed  = "failed"
+)
+
+// EmailDelivery is a queued email to one user: the payslip of a payroll run, or a password reset the user
+// asked for
+type EmailDelivery struct {
+	BaseModel
+	PayrollID          *uuid.UUID `json:"payroll_id,omitempty" gorm:"type:uuid;uniqueIndex:idx_email_delivery_payroll_user"`
+	AttendancePeriodID *uuid.UUID `json:"attendance_period_id,omitempty" gorm:"type:uuid"`
+	UserID             uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_email_delivery_payroll_user"`
+	Template           string     `json:"template" gorm:"not null"`
+	Recipient          string     `json:"recipient"`
+	Status             string     `json:"status" gorm:"not null;index:idx_email_delivery_due"` // 'queued', 'sending', 'sent' or 'failed'
+	Attempts           int        `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt      time.Time  `json:"next_attempt_at" gorm:"not null;index:idx_email_delivery_due"` // while sending, when the claim expires
+	LastError          string     `json:"last_error,omitempty"`
+	Attached           bool       `json:"attached"` // whether the payslip PDF was attached
+	SentAt             *time.Time `json:"sent_at,omitempty"`
+
+	// Relationships
+	User User `json:"user,omitempty"`
+}
+
+// Payment statuses of a payroll item
+const (
+	PaymentStatusPending  = "pending"
+	PaymentStatusSent     = "sent"
+	PaymentStatusPaid     = "paid"
+	PaymentStatusFailed   = "failed"
+	PaymentStatusReturned = "returned"
+)
+
+// TaxCertificateIssue records the number a 1721-A1 was issued under, the first time it was for a completed tax
+// year, so the certificate keeps its number and date when issued again
+type TaxCertificateIssue struct {
+	ID       uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID   uuid.UUID `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_tax_certificate_user_year"`
+	TaxYear  int       `json:"tax_year" gorm:"not null;uniqueIndex:idx_tax_certificate_user_year;uniqueIndex:idx_tax_certificate_year_sequence"`
+	Sequence int       `json:"sequence" gorm:"not null;uniqueIndex:idx_tax_certificate_year_sequence"` // numbers the certificates of a year from 1
+	Month    int       `json:"month" gorm:"not null"`                                                  // last month of income, part of the number
+	IssuedAt time.Time `json:"issued_at" gorm:"not null"`
+}
+
+// DisbursementFile is a generated bank upload file, kept so the file handed to the bank can be downloaded again
+type DisbursementFile struct {
+	BaseModel
+	PayrollID   uuid.UUID `json:"payroll_id" gorm:"type:uuid;not null;index"`
+	Reference   string    `json:"reference" gorm:"not null;uniqueIndex"` // batch reference, the payment_batch of its items
+	Reissue     bool      `json:"reissue"`
+	Format      string    `json:"format" gorm:"not null"`
+	Filename    string    `json:"filename" gorm:"not null"`
+	ContentType string    `json:"content_type" gorm:"not null"`
+	Data        []byte    `json:"-" gorm:"not null"`
+	Count       int       `json:"count"`
+	Amount      int64     `json:"amount"`       // in minor units
+	AccountHash int64     `json:"account_hash"` // sum of the creditor account numbers, modulo 10^15
+	Checksum    string    `json:"checksum"`     // SHA-256 of Data, hex encoded
+}
+
+// BankTransaction is an entry of an imported bank statement, matched to the payroll item it pays when possible
+type BankTransaction struct {
+	BaseModel
+	AttendancePeriodID uuid.UUID  `json:"attendance_period_id" gorm:"type:uuid;not null;uniqueIndex:idx_bank_transaction_fingerprint"`
+	Fingerprint        string     `json:"-" gorm:"not null;uniqueIndex:idx_bank_transaction_fingerprint"` // identifies the entry when a statement is imported again
+	PayrollItemID      *uuid.UUID `json:"payroll_item_id,omitempty" gorm:"type:uuid;index"`
+	Source             string     `json:"source"` // statement format, 'csv' or 'camt053'
+	TransactionID      string     `json:"transaction_id"`
+	Reference          string     `json:"reference"`
+	Account            string     `json:"account,omitempty"`
+	Amount             float64    `json:"amount" gorm:"not null"`
+	Status             string     `json:"status" gorm:"not null"` // 'paid', 'failed' or 'returned'
+	BookingDate        *time.Time `json:"booking_date,omitempty" gorm:"type:date"`
+	Reason             string     `json:"reason,omitempty"`
+	Matched            bool       `json:"matched"`
+	Note               string     `json:"note,omitempty"` // why it was not matched or not applied
+}
+
+// Session reasons for revocation
+const (
+	SessionRevokedLogout  = "logout"
+	SessionRevokedByAdmin = "revoked_by_admin"
+	SessionRevokedReuse   = "refresh_token_reuse" // a rotated refresh token was presented again
+	// SessionRevokedPasswordChange ends the other sessions of a user whose password changed or was reset
+	SessionRevokedPasswordChange = "password_changed"
+)
+
+// Session is a login. Its access tokens carry its ID and stop working once it is revoked; its refresh
+// tokens rotate on every use.
+type Session struct {
+	BaseModel
+	UserID        uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	UserAgent     string     `json:"user_agent,omitempty"`
+	ExpiresAt     time.Time  `json:"expires_at" gorm:"not null"` // when its last refresh token expires
+	LastUsedAt    time.Time  `json:"last_used_at"`               // last login or refresh
+	RevokedAt     *time.Time `json:"revoked_at,omitempty"`
+	RevokedReason string     `json:"revoked_reason,omitempty"` // 'logout', 'revoked_by_admin', 'refresh_token_reuse' or 'password_changed'
+}
+
+// RefreshToken is one refresh token of a session. Only its hash is stored.
+type RefreshToken struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	SessionID uuid.UUID  `json:"session_id" gorm:"type:uuid;not null;index"`
+	TokenHash string     `json:"-" gorm:"not null;uniqueIndex"` // SHA-256, hex encoded
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at,omitempty"` // when it was rotated; presenting it again revokes the session
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// MFAChallenge is the second step of a login that needs a TOTP code. Only the hash of its token is stored.
+type MFAChallenge struct {
 	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	TableName string     `json:"table_name" gorm:"not null"`
-	RecordID  uuid.UUID  `json:"record_id" gorm:"type:uuid;not null"`
-	Action    string     `json:"action" gorm:"not null"` // 'INSERT', 'UPDATE', 'DELETE'
-	OldValues string     `json:"old_values,omitempty" gorm:"type:jsonb"`
-	NewValues string     `json:"new_values,omitempty" gorm:"type:jsonb"`
-	UserID    *uuid.UUID `json:"user_id,omitempty" gorm:"type:uuid"`
+	UserID    uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	TokenHash string     `json:"-" gorm:"not null;uniqueIndex"` // SHA-256, hex encoded
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	Attempts  int        `json:"attempts" gorm:"not null;default:0"` // wrong codes entered
+	UsedAt    *time.Time `json:"used_at,omitempty"`
 	IPAddress string     `json:"ip_address"`
-	RequestID string     `json:"request_id"`
 	CreatedAt time.Time  `json:"created_at"`
 }
 
+// SSOLogin is a login started at the identity provider, waiting for the code it returns. Only the hash of its
+// state is stored; the PKCE verifier and nonce never leave the server.
+type SSOLogin struct {
+	ID           uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	StateHash    string     `json:"-" gorm:"not null;uniqueIndex"` // SHA-256, hex encoded
+	CodeVerifier string     `json:"-" gorm:"not null"`
+	Nonce        string     `json:"-" gorm:"not null"`
+	ExpiresAt    time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt       *time.Time `json:"used_at,omitempty"`
+	IPAddress    string     `json:"ip_address"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// APIKey lets an integration, such as a badge reader or the HRIS, call the API without a user. It holds
+// permissions of its own; only the hash of the key is stored.
+type APIKey struct {
+	BaseModel
+	Name        string             `json:"name" gorm:"not null"`
+	Prefix      string             `json:"prefix" gorm:"not null"`        // the start of the key, to tell keys apart
+	KeyHash     string             `json:"-" gorm:"not null;uniqueIndex"` // SHA-256, hex encoded
+	ExpiresAt   *time.Time         `json:"expires_at,omitempty"`          // none for keys that do not expire
+	LastUsedAt  *time.Time         `json:"last_used_at,omitempty"`
+	LastUsedIP  string             `json:"last_used_ip,omitempty"`
+	RevokedAt   *time.Time         `json:"revoked_at,omitempty"`
+	RevokedBy   *uuid.UUID         `json:"revoked_by,omitempty" gorm:"type:uuid"`
+	Permissions []APIKeyPermission `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// APIKeyPermission is a permission an API key holds
+type APIKeyPermission struct {
+	APIKeyID   uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Permission string    `gorm:"primaryKey"`
+}
+
+// MFARecoveryCode is a single-use code that stands in for a TOTP code when the device is lost. Only its hash
+// is stored.
+type MFARecoveryCode struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID    uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	CodeHash  string     `json:"-" gorm:"not null"` // SHA-256, hex encoded
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// PasswordHistory is a previous password of a user, kept so it is not reused
+type PasswordHistory struct {
+	ID           uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID       uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	PasswordHash string    `json:"-" gorm:"not null"` // bcrypt
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// PasswordResetToken lets a user set a new password without the current one. It is emailed, works once and
+// only its hash is stored.
+type PasswordResetToken struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID    uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	TokenHash string     `json:"-" gorm:"not null;uniqueIndex"` // SHA-256, hex encoded
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`                     // also set when a later reset or password change supersedes it
+	CreatedBy *uuid.UUID `json:"created_by,omitempty" gorm:"type:uuid"` // the admin who reset the password
+	IPAddress string     `json:"ip_address"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Login throttle scopes
+const (
+	LoginThrottleUsername = "username"
+	LoginThrottleIP       = "ip"
+
+	// Password reset requests are counted apart from failed logins
+	LoginThrottleResetUsername = "reset_username"
+	LoginThrottleResetIP       = "reset_ip"
+)
+
+// LoginThrottle counts the recent failed logins of a username or an IP address. Unknown usernames are
+// counted too, so lockouts do not reveal which accounts exist.
+type LoginThrottle struct {
+	ID            uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Scope         string     `json:"scope" gorm:"not null;uniqueIndex:idx_login_throttles_scope_key"` // 'username', 'ip', 'reset_username' or 'reset_ip'
+	Key           string     `json:"key" gorm:"not null;uniqueIndex:idx_login_throttles_scope_key"`   // lowercased username or IP address
+	Failures      int        `json:"failures" gorm:"not null;default:0"`                              // within the failure window
+	LastFailureAt time.Time  `json:"last_failure_at"`
+	LockedUntil   *time.Time `json:"locked_until,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// Role is a named set of permissions. Builtin roles are defined in code and synced on startup; other roles
+// are managed through the API.
+type Role struct {
+	BaseModel
+	Name        string           `json:"name" gorm:"not null;uniqueIndex"`
+	Description string           `json:"description"`
+	Builtin     bool             `json:"builtin" gorm:"not null;default:false"`
+	Permissions []RolePermission `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
+// RolePermission is a permission a role grants
+type RolePermission struct {
+	RoleID     uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Permission string    `gorm:"primaryKey"` // e.g. 'payroll:process'
+}
+
+// UserRole is a role a user holds
+type UserRole struct {
+	UserID    uuid.UUID  `json:"user_id" gorm:"type:uuid;primaryKey"`
+	RoleID    uuid.UUID  `json:"role_id" gorm:"type:uuid;primaryKey;index"`
+	CreatedBy *uuid.UUID `json:"created_by,omitempty" gorm:"type:uuid"` // the admin who granted it; none when granted on creation
+	CreatedAt time.Time  `json:"created_at"`
+
+	// Relationships
+	User User `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+	Role Role `json:"-" gorm:"constraint:OnDelete:CASCADE"`
+}
+
 // BeforeCreate hook for all models with BaseModel
 func (b *BaseModel) BeforeCreate(tx *gorm.DB) error {
 	if b.ID == uuid.Nil {
@@ -0,0 +1,167 @@
+package oidc_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"payslip-system/internal/config"
+	"payslip-system/internal/oidc"
+	"payslip-system/internal/oidc/oidctest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const redirectURL = "https://payroll.example.com/sso/callback"
+
+func newProvider(t *testing.T, clientSecret string) (*oidctest.Server, *oidc.Provider) {
+	server, err := oidctest.NewServer("payroll", clientSecret, redirectURL)
+	require.NoError(t, err)
+	t.Cleanup(server.Close)
+
+	return server, oidc.NewProvider(config.OIDCConfig{
+		IssuerURL:       server.URL,
+		ClientID:        "payroll",
+		ClientSecret:    clientSecret,
+		RedirectURL:     redirectURL,
+		Scopes:          []string{"email", "profile"},
+		EmailClaim:      "email",
+		EmployeeIDClaim: "employee_id",
+		GroupsClaim:     "groups",
+		Timeout:         5,
+	})
+}
+
+func TestProvider_Login(t *testing.T) {
+	for _, clientSecret := range []string{"", "s3cret"} {
+		t.Run("client secret "+clientSecret, func(t *testing.T) {
+			server, provider := newProvider(t, clientSecret)
+			ctx := context.Background()
+
+			authorizationURL, err := provider.AuthorizationURL(ctx, "state-1", "nonce-1", "verifier-1")
+			require.NoError(t, err)
+
+			u, err := url.Parse(authorizationURL)
+			require.NoError(t, err)
+			assert.Equal(t, "openid email profile", u.Query().Get("scope"))
+			assert.Equal(t, oidc.CodeChallenge("verifier-1"), u.Query().Get("code_challenge"))
+
+			code, state, err := server.Authorize(authorizationURL, map[string]interface{}{
+				"sub":            "idp-42",
+				"email":          "Alice@Example.com",
+				"email_verified": true,
+				"employee_id":    1001, // some providers send numbers
+				"groups":         []string{"staff", "payroll-admins"},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, "state-1", state)
+
+			identity, err := provider.Exchange(ctx, code, "verifier-1", "nonce-1")
+			require.NoError(t, err)
+			assert.Equal(t, "idp-42", identity.Subject)
+			assert.Equal(t, "Alice@Example.com", identity.Email)
+			assert.True(t, identity.EmailVerified)
+			assert.Equal(t, "1001", identity.EmployeeID)
+			assert.Equal(t, []string{"staff", "payroll-admins"}, identity.Groups)
+
+			// Codes work once
+			_, err = provider.Exchange(ctx, code, "verifier-1", "nonce-1")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestProvider_Exchange_Refused(t *testing.T) {
+	tests := []struct {
+		name     string
+		claims   map[string]interface{}
+		verifier string
+		nonce    string
+		wantErr  string
+	}{
+		{name: "wrong code verifier", verifier: "stolen", wantErr: "PKCE"},
+		{name: "nonce of another login", nonce: "nonce-2", wantErr: "nonce"},
+		{name: "no nonce", claims: map[string]interface{}{"nonce": nil}, wantErr: "nonce"},
+		{name: "other audience", claims: map[string]interface{}{"aud": "intranet"}, wantErr: "audience"},
+		{name: "other issuer", claims: map[string]interface{}{"iss": "https://evil.example.com"}, wantErr: "issuer"},
+		{name: "expired", claims: map[string]interface{}{"exp": time.Now().Add(-time.Hour).Unix()}, wantErr: "expired"},
+		{name: "no subject", claims: map[string]interface{}{"sub": nil}, wantErr: "subject"},
+		{name: "several audiences without azp", claims: map[string]interface{}{"aud": []string{"payroll", "intranet"}}, wantErr: "not issued for this client"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, provider := newProvider(t, "s3cret")
+			ctx := context.Background()
+
+			authorizationURL, err := provider.AuthorizationURL(ctx, "state-1", "nonce-1", "verifier-1")
+			require.NoError(t, err)
+			code, _, err := server.Authorize(authorizationURL, tt.claims)
+			require.NoError(t, err)
+
+			verifier, nonce := "verifier-1", "nonce-1"
+			if tt.verifier != "" {
+				verifier = tt.verifier
+			}
+			if tt.nonce != "" {
+				nonce = tt.nonce
+			}
+
+			_, err = provider.Exchange(ctx, code, verifier, nonce)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestProvider_UnverifiedEmail(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims map[string]interface{}
+	}{
+		{name: "unverified", claims: map[string]interface{}{"email": "alice@example.com", "email_verified": false, "groups": "staff"}},
+		{name: "no email_verified claim", claims: map[string]interface{}{"email": "alice@example.com", "groups": "staff"}},
+		{name: "email_verified not a boolean", claims: map[string]interface{}{"email": "alice@example.com", "email_verified": "true", "groups": "staff"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, provider := newProvider(t, "")
+			ctx := context.Background()
+
+			authorizationURL, err := provider.AuthorizationURL(ctx, "state-1", "nonce-1", "verifier-1")
+			require.NoError(t, err)
+			code, _, err := server.Authorize(authorizationURL, tt.claims)
+			require.NoError(t, err)
+
+			identity, err := provider.Exchange(ctx, code, "verifier-1", "nonce-1")
+			require.NoError(t, err)
+			assert.Equal(t, "alice@example.com", identity.Email)
+			assert.False(t, identity.EmailVerified)
+			assert.Equal(t, []string{"staff"}, identity.Groups)
+		})
+	}
+}
+
+func TestProvider_DiscoveryIssuerMismatch(t *testing.T) {
+	// A discovery document claiming another issuer is not trusted
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"issuer":"https://login.example.com","authorization_endpoint":"https://login.example.com/authorize","token_endpoint":"https://login.example.com/token","jwks_uri":"https://login.example.com/jwks"}`))
+	}))
+	defer server.Close()
+
+	provider := oidc.NewProvider(config.OIDCConfig{IssuerURL: server.URL, ClientID: "payroll", RedirectURL: redirectURL, Timeout: 5})
+	_, err := provider.AuthorizationURL(context.Background(), "state", "nonce", "verifier")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not match")
+}
+
+func TestCodeChallenge(t *testing.T) {
+	// RFC 7636, appendix B
+	assert.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", oidc.CodeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+}
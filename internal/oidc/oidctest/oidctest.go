@@ -0,0 +1,205 @@
+// Package oidctest is a local OpenID Connect provider for tests. It serves discovery, keys and a token endpoint
+// that checks PKCE, and stands in for the user logging in at the provider with Authorize.
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"payslip-system/internal/oidc"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Server is a mock identity provider; its URL is the issuer
+type Server struct {
+	*httptest.Server
+	ClientID     string
+	ClientSecret string // when set, the token endpoint requires client_secret_basic
+	RedirectURL  string
+
+	key *rsa.PrivateKey
+	kid string
+
+	mu     sync.Mutex
+	grants map[string]grant
+}
+
+// grant is an authorization code waiting to be redeemed
+type grant struct {
+	challenge   string
+	nonce       string
+	redirectURI string
+	claims      map[string]interface{}
+}
+
+func NewServer(clientID, clientSecret, redirectURL string) (*Server, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+		key:          key,
+		kid:          "test-key",
+		grants:       map[string]grant{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", s.handleDiscovery)
+	mux.HandleFunc("/jwks", s.handleJWKS)
+	mux.HandleFunc("/token", s.handleToken)
+	s.Server = httptest.NewServer(mux)
+	return s, nil
+}
+
+// Authorize logs a user with the claims in through an authorization URL, as the provider's login page would.
+// It checks the request and returns the code and state the provider redirects back with. Claims override
+// those of the ID token, so tests can issue tokens with a wrong issuer, audience or nonce; a nil value
+// removes a claim.
+func (s *Server) Authorize(authorizationURL string, claims map[string]interface{}) (code, state string, err error) {
+	u, err := url.Parse(authorizationURL)
+	if err != nil {
+		return "", "", err
+	}
+	query := u.Query()
+
+	switch {
+	case u.Path != "/authorize":
+		return "", "", fmt.Errorf("unexpected authorization endpoint %s", u.Path)
+	case query.Get("response_type") != "code":
+		return "", "", errors.New("response_type must be code")
+	case query.Get("client_id") != s.ClientID:
+		return "", "", errors.New("unknown client_id")
+	case query.Get("redirect_uri") != s.RedirectURL:
+		return "", "", errors.New("redirect_uri is not registered")
+	case query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "":
+		return "", "", errors.New("S256 code challenge required")
+	case query.Get("state") == "" || query.Get("nonce") == "":
+		return "", "", errors.New("state and nonce required")
+	}
+
+	code = randomString()
+	s.mu.Lock()
+	s.grants[code] = grant{
+		challenge:   query.Get("code_challenge"),
+		nonce:       query.Get("nonce"),
+		redirectURI: query.Get("redirect_uri"),
+		claims:      claims,
+	}
+	s.mu.Unlock()
+	return code, query.Get("state"), nil
+}
+
+func (s *Server) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                           s.URL,
+		"authorization_endpoint":           s.URL + "/authorize",
+		"token_endpoint":                   s.URL + "/token",
+		"jwks_uri":                         s.URL + "/jwks",
+		"code_challenge_methods_supported": []string{"S256"},
+	})
+}
+
+func (s *Server) handleJWKS(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": s.kid,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(s.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.key.E)).Bytes()),
+		}},
+	})
+}
+
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ParseForm() != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	if s.ClientSecret != "" {
+		id, secret, ok := r.BasicAuth()
+		if !ok || id != s.ClientID || secret != s.ClientSecret {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+			return
+		}
+	} else if r.PostForm.Get("client_id") != s.ClientID {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	// Codes work once
+	s.mu.Lock()
+	g, ok := s.grants[r.PostForm.Get("code")]
+	delete(s.grants, r.PostForm.Get("code"))
+	s.mu.Unlock()
+
+	if !ok || g.redirectURI != r.PostForm.Get("redirect_uri") {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+	if oidc.CodeChallenge(r.PostForm.Get("code_verifier")) != g.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "PKCE verification failed"})
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":   s.URL,
+		"aud":   s.ClientID,
+		"sub":   "subject",
+		"iat":   now.Unix(),
+		"exp":   now.Add(5 * time.Minute).Unix(),
+		"nonce": g.nonce,
+	}
+	for name, value := range g.claims {
+		if value == nil {
+			delete(claims, name)
+			continue
+		}
+		claims[name] = value
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = s.kid
+	idToken, err := token.SignedString(s.key)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": randomString(),
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     idToken,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func randomString() string {
+	random := make([]byte, 16)
+	rand.Read(random)
+	return base64.RawURLEncoding.EncodeToString(random)
+}
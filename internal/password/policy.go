@@ -0,0 +1,76 @@
+// Package password checks new passwords against the password policy: length, commonly breached passwords
+// and the username
+package password
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"payslip-system/internal/config"
+)
+
+// MaxLength is the longest password bcrypt hashes in full
+const MaxLength = 72
+
+//go:embed common.txt
+var commonPasswords string
+
+// Policy is the password policy of new passwords. Reuse of previous passwords is checked by the caller,
+// which has the hashes.
+type Policy struct {
+	minLength int
+	breached  map[string]struct{}
+}
+
+// NewPolicy builds the policy, reading the breached password wordlist if one is configured. The wordlist has
+// one password per line, as published with breach corpora; it is matched case-insensitively.
+func NewPolicy(cfg config.PasswordConfig) (*Policy, error) {
+	p := &Policy{minLength: cfg.MinLength, breached: map[string]struct{}{}}
+	p.addWords(strings.NewReader(commonPasswords))
+
+	if cfg.BreachedListPath != "" {
+		file, err := os.Open(cfg.BreachedListPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open breached password list: %w", err)
+		}
+		defer file.Close()
+		if err := p.addWords(file); err != nil {
+			return nil, fmt.Errorf("failed to read breached password list: %w", err)
+		}
+	}
+	return p, nil
+}
+
+func (p *Policy) addWords(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if word := strings.ToLower(strings.TrimSpace(scanner.Text())); word != "" {
+			p.breached[word] = struct{}{}
+		}
+	}
+	return scanner.Err()
+}
+
+// Check returns the rules a new password breaks, none if it is acceptable
+func (p *Policy) Check(password, username string) []string {
+	var violations []string
+	if len(password) < p.minLength {
+		violations = append(violations, fmt.Sprintf("must be at least %d characters long", p.minLength))
+	}
+	if len(password) > MaxLength {
+		violations = append(violations, fmt.Sprintf("must be at most %d bytes long", MaxLength))
+	}
+
+	lower := strings.ToLower(password)
+	if _, ok := p.breached[lower]; ok {
+		violations = append(violations, "is a commonly used or breached password")
+	}
+	if username != "" && strings.Contains(lower, strings.ToLower(username)) {
+		violations = append(violations, "must not contain the username")
+	}
+	return violations
+}
@@ -0,0 +1,48 @@
+package password
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"payslip-system/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy_Check(t *testing.T) {
+	wordlist := filepath.Join(t.TempDir(), "breached.txt")
+	require.NoError(t, os.WriteFile(wordlist, []byte("Correct-Horse-Battery\r\nhunter2hunter2\n"), 0600))
+
+	policy, err := NewPolicy(config.PasswordConfig{MinLength: 10, BreachedListPath: wordlist})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		password   string
+		username   string
+		violations int
+	}{
+		{name: "acceptable", password: "glacier-tuesday-41", username: "employee1"},
+		{name: "too short", password: "a7#kq", username: "employee1", violations: 1},
+		{name: "too long for bcrypt", password: strings.Repeat("x9", 40), username: "employee1", violations: 1},
+		{name: "builtin common password", password: "Password123", username: "employee1", violations: 1},
+		{name: "seeded password", password: "employee123", username: "employee7", violations: 1},
+		{name: "wordlist, case-insensitively", password: "correct-horse-battery", username: "employee1", violations: 1},
+		{name: "contains the username", password: "Employee1-is-great", username: "employee1", violations: 1},
+		{name: "several rules", password: "admin123", username: "admin", violations: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Len(t, policy.Check(tt.password, tt.username), tt.violations)
+		})
+	}
+}
+
+func TestNewPolicy_MissingWordlist(t *testing.T) {
+	_, err := NewPolicy(config.PasswordConfig{MinLength: 10, BreachedListPath: filepath.Join(t.TempDir(), "missing.txt")})
+	assert.Error(t, err)
+}
@@ -0,0 +1,112 @@
+package pdf
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+)
+
+const (
+	pageMargin = 15.0
+	lineHeight = 6.0
+)
+
+// document wraps fpdf with the building blocks shared by the generated documents
+type document struct {
+	*fpdf.Fpdf
+	tr     func(string) string
+	width  float64
+	accent [3]int
+}
+
+// amountRow is a labelled amount in an itemized table
+type amountRow struct {
+	label  string
+	detail string
+	amount float64
+}
+
+func newDocument(paperSize, accentColor string) *document {
+	doc := fpdf.New("P", "mm", paperSize, "")
+	doc.SetMargins(pageMargin, pageMargin, pageMargin)
+	doc.SetAutoPageBreak(true, 20)
+	doc.SetCreator("payslip-system", false)
+
+	pageWidth, _ := doc.GetPageSize()
+	return &document{
+		Fpdf:   doc,
+		tr:     doc.UnicodeTranslatorFromDescriptor(""),
+		width:  pageWidth - 2*pageMargin,
+		accent: parseHexColor(accentColor),
+	}
+}
+
+// footer prints text centered at the bottom of every page
+func (d *document) footer(text string) {
+	d.SetFooterFunc(func() {
+		d.SetY(-15)
+		d.SetFont("Helvetica", "I", 8)
+		d.SetTextColor(120, 120, 120)
+		d.CellFormat(0, 5, d.tr(text), "", 0, "C", false, 0, "")
+	})
+}
+
+func (d *document) sectionTitle(title string) {
+	d.Ln(4)
+	d.SetFont("Helvetica", "B", 11)
+	d.SetFillColor(d.accent[0], d.accent[1], d.accent[2])
+	d.SetTextColor(255, 255, 255)
+	d.CellFormat(d.width, lineHeight+1, d.tr(" "+title), "", 1, "L", true, 0, "")
+	d.SetTextColor(0, 0, 0)
+}
+
+// keyValueRows prints label/value pairs in two columns
+func (d *document) keyValueRows(rows [][2]string) {
+	d.SetFont("Helvetica", "", 10)
+	labelWidth := d.width * 0.35
+	for _, row := range rows {
+		d.SetFont("Helvetica", "B", 10)
+		d.CellFormat(labelWidth, lineHeight, d.tr(row[0]), "", 0, "L", false, 0, "")
+		d.SetFont("Helvetica", "", 10)
+		d.CellFormat(d.width-labelWidth, lineHeight, d.tr(row[1]), "", 1, "L", false, 0, "")
+	}
+}
+
+// amountTable prints itemized amounts followed by a bold total line
+func (d *document) amountTable(rows []amountRow, totalLabel string, total float64, format func(float64) string) {
+	amountWidth := d.width * 0.3
+	labelWidth := d.width - amountWidth
+
+	d.SetFont("Helvetica", "", 10)
+	for _, row := range rows {
+		label := row.label
+		if row.detail != "" {
+			label += " (" + row.detail + ")"
+		}
+		d.CellFormat(labelWidth, lineHeight, d.tr(label), "B", 0, "L", false, 0, "")
+		d.CellFormat(amountWidth, lineHeight, d.tr(format(row.amount)), "B", 1, "R", false, 0, "")
+	}
+
+	d.SetFont("Helvetica", "B", 10)
+	d.CellFormat(labelWidth, lineHeight, d.tr(totalLabel), "", 0, "L", false, 0, "")
+	d.CellFormat(amountWidth, lineHeight, d.tr(format(total)), "", 1, "R", false, 0, "")
+}
+
+func (d *document) bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := d.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func parseHexColor(hex string) [3]int {
+	hex = strings.TrimPrefix(hex, "#")
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if len(hex) != 6 || err != nil {
+		return [3]int{31, 78, 121}
+	}
+	return [3]int{int(value >> 16 & 0xFF), int(value >> 8 & 0xFF), int(value & 0xFF)}
+}
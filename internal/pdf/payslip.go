@@ -0,0 +1,255 @@
+package pdf
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"payslip-system/internal/config"
+	"payslip-system/internal/domains"
+	"payslip-system/internal/i18n"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// PayslipRenderer renders payslips as PDF documents using the configured template
+type PayslipRenderer struct {
+	cfg    config.PayslipConfig
+	signer *Signer
+}
+
+// NewPayslipRenderer creates a renderer; documents are signed when signer is not nil
+func NewPayslipRenderer(cfg config.PayslipConfig, signer *Signer) *PayslipRenderer {
+	return &PayslipRenderer{cfg: cfg, signer: signer}
+}
+
+// RenderOptions are the per-document settings of a rendered payslip
+type RenderOptions struct {
+	// Translator provides labels and number formats; required
+	Translator *i18n.Translator
+	// Password encrypts the document when not empty
+	Password string
+	// VerificationURL is printed as a QR code when not empty
+	VerificationURL string
+}
+
+// Render draws the payslip and signs it when a signer is configured
+func (r *PayslipRenderer) Render(payslip *domains.PayslipResponse, opts RenderOptions) ([]byte, error) {
+	if payslip == nil || payslip.Employee == nil || payslip.Period == nil {
+		return nil, fmt.Errorf("incomplete payslip")
+	}
+	if opts.Translator == nil {
+		return nil, fmt.Errorf("no translator given")
+	}
+
+	p := &payslipPage{cfg: r.cfg, tr: opts.Translator, payslip: payslip}
+
+	doc := newDocument(r.cfg.PaperSize, r.cfg.AccentColor)
+	doc.SetTitle(fmt.Sprintf("%s %s - %s", p.title(), p.periodLabel(), payslip.Employee.Username), true)
+	doc.SetAuthor(r.cfg.CompanyName, true)
+	doc.footer(p.footer())
+
+	var prot *protection
+	if opts.Password != "" {
+		var err error
+		if prot, err = newProtection(opts.Password, r.cfg.OwnerPassword); err != nil {
+			return nil, fmt.Errorf("failed to protect payslip PDF: %w", err)
+		}
+		prot.apply(doc)
+	}
+
+	doc.AddPage()
+
+	p.header(doc)
+	p.employeeDetails(doc)
+	p.earningsAndDeductions(doc)
+	p.reimbursements(doc)
+	p.netPay(doc)
+	if opts.VerificationURL != "" {
+		if err := p.verification(doc, opts.VerificationURL); err != nil {
+			return nil, fmt.Errorf("failed to render verification code: %w", err)
+		}
+	}
+
+	data, err := doc.bytes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to render payslip PDF: %w", err)
+	}
+
+	if r.signer != nil {
+		reason := fmt.Sprintf("%s issued by %s", p.title(), r.cfg.CompanyName)
+		if data, err = r.signer.sign(data, prot, reason, time.Now()); err != nil {
+			return nil, fmt.Errorf("failed to sign payslip PDF: %w", err)
+		}
+	}
+	return data, nil
+}
+
+// payslipPage draws one payslip in one language
+type payslipPage struct {
+	cfg     config.PayslipConfig
+	tr      *i18n.Translator
+	payslip *domains.PayslipResponse
+}
+
+func (p *payslipPage) header(doc *document) {
+	textX := pageMargin
+	if p.cfg.LogoPath != "" {
+		if _, err := os.Stat(p.cfg.LogoPath); err == nil {
+			doc.ImageOptions(p.cfg.LogoPath, pageMargin, pageMargin, 0, 16, false, fpdf.ImageOptions{ReadDpi: true}, 0, "")
+			textX += 30
+		}
+	}
+
+	doc.SetXY(textX, pageMargin)
+	doc.SetFont("Helvetica", "B", 15)
+	doc.CellFormat(0, 7, doc.tr(p.cfg.CompanyName), "", 1, "L", false, 0, "")
+	if p.cfg.CompanyAddress != "" {
+		doc.SetX(textX)
+		doc.SetFont("Helvetica", "", 9)
+		doc.CellFormat(0, 5, doc.tr(p.cfg.CompanyAddress), "", 1, "L", false, 0, "")
+	}
+
+	doc.SetXY(pageMargin, pageMargin)
+	doc.SetFont("Helvetica", "B", 18)
+	doc.SetTextColor(doc.accent[0], doc.accent[1], doc.accent[2])
+	doc.CellFormat(doc.width, 8, doc.tr(strings.ToUpper(p.title())), "", 1, "R", false, 0, "")
+	doc.SetFont("Helvetica", "", 10)
+	doc.SetTextColor(0, 0, 0)
+	doc.CellFormat(doc.width, 5, doc.tr(p.periodLabel()), "", 1, "R", false, 0, "")
+
+	doc.SetY(pageMargin + 20)
+	doc.SetDrawColor(doc.accent[0], doc.accent[1], doc.accent[2])
+	doc.Line(pageMargin, doc.GetY(), pageMargin+doc.width, doc.GetY())
+	doc.SetDrawColor(200, 200, 200)
+}
+
+func (p *payslipPage) employeeDetails(doc *document) {
+	payslip := p.payslip
+	doc.sectionTitle(p.tr.T("payslip.section.employee"))
+	doc.keyValueRows([][2]string{
+		{p.tr.T("payslip.employee_name"), payslip.Employee.Username},
+		{p.tr.T("payslip.employee_id"), payslip.Employee.ID.String()},
+		{p.tr.T("payslip.pay_period"), p.periodLabel()},
+		{p.tr.T("payslip.base_salary"), p.money(payslip.BaseSalary)},
+		{p.tr.T("payslip.attendance"), p.tr.T("payslip.attendance_detail", "attended", payslip.AttendanceDays, "working", payslip.WorkingDays)},
+		{p.tr.T("payslip.overtime"), p.overtimeHours()},
+	})
+}
+
+func (p *payslipPage) earningsAndDeductions(doc *document) {
+	payslip := p.payslip
+	gross := payslip.AttendanceAmount + payslip.OvertimeAmount + payslip.ReimbursementAmount
+
+	doc.sectionTitle(p.tr.T("payslip.section.earnings"))
+	doc.amountTable([]amountRow{
+		{label: p.tr.T("payslip.salary"), detail: p.tr.T("payslip.days_attended", "days", payslip.AttendanceDays), amount: payslip.AttendanceAmount},
+		{label: p.tr.T("payslip.overtime"), detail: p.overtimeHours(), amount: payslip.OvertimeAmount},
+		{label: p.tr.T("payslip.reimbursements"), amount: payslip.ReimbursementAmount},
+	}, p.tr.T("payslip.gross_earnings"), gross, p.money)
+
+	// Deductions are whatever separates gross earnings from net pay; PPh 21 and BPJS are itemized
+	deductions := gross - payslip.TotalAmount
+	other := deductions
+	var rows []amountRow
+	if payslip.TaxAmount != 0 {
+		rows = append(rows, amountRow{label: p.tr.T("payslip.tax"), amount: payslip.TaxAmount})
+		other -= payslip.TaxAmount
+	}
+	if payslip.BPJS != nil {
+		rows = append(rows, amountRow{label: p.tr.T("payslip.bpjs"), amount: payslip.BPJS.EmployeeTotal()})
+		other -= payslip.BPJS.EmployeeTotal()
+	}
+	if other > 0.005 {
+		rows = append(rows, amountRow{label: p.tr.T("payslip.deductions"), amount: other})
+	}
+
+	doc.sectionTitle(p.tr.T("payslip.section.deductions"))
+	doc.amountTable(rows, p.tr.T("payslip.total_deductions"), deductions, p.money)
+}
+
+func (p *payslipPage) reimbursements(doc *document) {
+	payslip := p.payslip
+	if len(payslip.Reimbursements) == 0 {
+		return
+	}
+
+	rows := make([]amountRow, 0, len(payslip.Reimbursements))
+	for _, reimbursement := range payslip.Reimbursements {
+		rows = append(rows, amountRow{label: reimbursement.Description, amount: reimbursement.Amount})
+	}
+
+	doc.sectionTitle(p.tr.T("payslip.section.reimbursements"))
+	doc.amountTable(rows, p.tr.T("payslip.total_reimbursements"), payslip.ReimbursementAmount, p.money)
+}
+
+func (p *payslipPage) netPay(doc *document) {
+	doc.Ln(6)
+	doc.SetFillColor(235, 241, 247)
+	doc.SetFont("Helvetica", "B", 13)
+	doc.CellFormat(doc.width*0.5, 10, doc.tr(" "+p.tr.T("payslip.net_pay")), "", 0, "L", true, 0, "")
+	doc.CellFormat(doc.width*0.5, 10, doc.tr(p.money(p.payslip.TotalAmount)+" "), "", 1, "R", true, 0, "")
+
+	words := p.tr.AmountInWords(p.payslip.TotalAmount, p.cfg.Currency)
+	if words == "" {
+		return
+	}
+	doc.SetFont("Helvetica", "I", 10)
+	doc.MultiCell(doc.width, 5, doc.tr(" "+p.tr.T("payslip.in_words", "words", strings.ToUpper(words[:1])+words[1:])), "", "L", true)
+}
+
+// verification prints a QR code linking to the public payslip verification page
+func (p *payslipPage) verification(doc *document, url string) error {
+	const size = 28.0
+
+	doc.Ln(8)
+	_, pageHeight := doc.GetPageSize()
+	if doc.GetY()+size > pageHeight-25 {
+		doc.AddPage()
+	}
+
+	top := doc.GetY()
+	if err := doc.qrCode(url, pageMargin, top, size); err != nil {
+		return err
+	}
+
+	doc.SetXY(pageMargin+size+5, top+4)
+	doc.SetFont("Helvetica", "B", 10)
+	doc.CellFormat(0, 5, doc.tr(p.tr.T("payslip.verify_title")), "", 2, "L", false, 0, "")
+	doc.SetFont("Helvetica", "", 8)
+	doc.MultiCell(doc.width-size-5, 4, doc.tr(p.tr.T("payslip.verify_text", "company", p.cfg.CompanyName)), "", "L", false)
+	doc.SetX(pageMargin + size + 5)
+	doc.SetTextColor(doc.accent[0], doc.accent[1], doc.accent[2])
+	doc.MultiCell(doc.width-size-5, 4, url, "", "L", false)
+	doc.SetTextColor(0, 0, 0)
+	doc.SetY(top + size)
+	return nil
+}
+
+// title and footer come from the catalog unless overridden in the configuration
+func (p *payslipPage) title() string {
+	if p.cfg.Title != "" {
+		return p.cfg.Title
+	}
+	return p.tr.T("payslip.title")
+}
+
+func (p *payslipPage) footer() string {
+	if p.cfg.Footer != "" {
+		return p.cfg.Footer
+	}
+	return p.tr.T("payslip.footer", "company", p.cfg.CompanyName)
+}
+
+func (p *payslipPage) periodLabel() string {
+	return p.tr.Date(p.payslip.Period.StartDate) + " - " + p.tr.Date(p.payslip.Period.EndDate)
+}
+
+func (p *payslipPage) overtimeHours() string {
+	return p.tr.T("payslip.overtime_hours", "hours", p.tr.Number(p.payslip.OvertimeHours, 1))
+}
+
+func (p *payslipPage) money(amount float64) string {
+	return p.tr.Money(p.cfg.Currency, amount)
+}
@@ -0,0 +1,110 @@
+package pdf
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"encoding/hex"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"payslip-system/internal/config"
+	"payslip-system/internal/domains"
+	"payslip-system/internal/i18n"
+	"payslip-system/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mozilla.org/pkcs7"
+)
+
+var testPayslipConfig = config.PayslipConfig{
+	CompanyName: "PT Test",
+	PaperSize:   "A4",
+	Currency:    "IDR",
+	AccentColor: "#1F4E79",
+}
+
+func testPayslip() *domains.PayslipResponse {
+	return &domains.PayslipResponse{
+		Employee:         &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "employee1"},
+		Period:           &models.AttendancePeriod{StartDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)},
+		BaseSalary:       5000000,
+		AttendanceDays:   18,
+		WorkingDays:      23,
+		AttendanceAmount: 3000000,
+		OvertimeHours:    10,
+		OvertimeAmount:   625000,
+		Reimbursements: []models.Reimbursement{
+			{Amount: 250000, Description: "Transportation"},
+		},
+		ReimbursementAmount: 250000,
+		TotalAmount:         3875000,
+	}
+}
+
+var testTranslators = i18n.NewRegistry("en", nil)
+
+func TestPayslipRenderer_Render(t *testing.T) {
+	renderer := NewPayslipRenderer(testPayslipConfig, nil)
+
+	for _, language := range []string{"en", "id"} {
+		data, err := renderer.Render(testPayslip(), RenderOptions{Translator: testTranslators.Translator(language)})
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(data, []byte("%PDF-")))
+		assert.NotContains(t, string(data), "/Encrypt")
+		assert.NotContains(t, string(data), "/ByteRange")
+	}
+
+	_, err := renderer.Render(&domains.PayslipResponse{}, RenderOptions{Translator: testTranslators.Translator("en")})
+	assert.Error(t, err)
+
+	_, err = renderer.Render(testPayslip(), RenderOptions{})
+	assert.Error(t, err)
+}
+
+func TestPayslipRenderer_RenderSignedAndProtected(t *testing.T) {
+	signer, err := NewSelfSignedSigner("PT Test")
+	require.NoError(t, err)
+	renderer := NewPayslipRenderer(testPayslipConfig, signer)
+
+	for _, password := range []string{"", "123456"} {
+		data, err := renderer.Render(testPayslip(), RenderOptions{Translator: testTranslators.Translator("id"), Password: password, VerificationURL: "http://localhost:8080/api/v1/verify/token"})
+		require.NoError(t, err)
+		assert.Equal(t, password != "", bytes.Contains(data, []byte("/Encrypt")))
+		assert.Contains(t, string(data), "/SubFilter /adbe.pkcs7.detached")
+
+		signed, signature := splitSignature(t, data)
+		p7, err := pkcs7.Parse(signature)
+		require.NoError(t, err)
+		p7.Content = signed
+		assert.NoError(t, p7.Verify())
+
+		// Any change to the signed bytes breaks the signature
+		tampered := bytes.Replace(signed, []byte("/Type /Catalog"), []byte("/Type /Catalgo"), 1)
+		p7.Content = tampered
+		assert.Error(t, p7.Verify())
+	}
+}
+
+// splitSignature returns the bytes covered by the document signature and the DER signature itself
+func splitSignature(t *testing.T, data []byte) ([]byte, []byte) {
+	match := regexp.MustCompile(`/ByteRange \[0 (\d+) (\d+) (\d+)\]`).FindSubmatch(data)
+	require.NotNil(t, match)
+
+	start, _ := strconv.Atoi(string(match[1]))
+	end, _ := strconv.Atoi(string(match[2]))
+	length, _ := strconv.Atoi(string(match[3]))
+	require.Equal(t, len(data), end+length)
+
+	padded, err := hex.DecodeString(string(data[start+1 : end-1]))
+	require.NoError(t, err)
+	var signature asn1.RawValue
+	_, err = asn1.Unmarshal(padded, &signature)
+	require.NoError(t, err)
+
+	signed := append(append([]byte{}, data[:start]...), data[end:]...)
+	return signed, signature.FullBytes
+}
@@ -0,0 +1,75 @@
+package pdf
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/rc4"
+	"encoding/binary"
+	"encoding/hex"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// protectPermissions lets recipients print but not modify or copy the document
+const protectPermissions = fpdf.CnProtectPrint
+
+// passwordPadding is the standard security handler padding string (PDF 1.7, 7.6.3.3)
+var passwordPadding = []byte{
+	0x28, 0xBF, 0x4E, 0x5E, 0x4E, 0x75, 0x8A, 0x41,
+	0x64, 0x00, 0x4E, 0x56, 0xFF, 0xFA, 0x01, 0x08,
+	0x2E, 0x2E, 0x00, 0xB6, 0xD0, 0x68, 0x3E, 0x80,
+	0x2F, 0x0C, 0xA9, 0xFE, 0x64, 0x53, 0x69, 0x7A,
+}
+
+// protection holds the passwords a document is encrypted with. fpdf only supports the
+// revision 2 standard security handler (40-bit RC4), so the password keeps casual readers
+// out but is not a substitute for transport security.
+type protection struct {
+	userPassword  string
+	ownerPassword string
+}
+
+func newProtection(userPassword, ownerPassword string) (*protection, error) {
+	if ownerPassword == "" {
+		random := make([]byte, 16)
+		if _, err := rand.Read(random); err != nil {
+			return nil, err
+		}
+		ownerPassword = hex.EncodeToString(random)
+	}
+	return &protection{userPassword: userPassword, ownerPassword: ownerPassword}, nil
+}
+
+func (p *protection) apply(doc *document) {
+	doc.SetProtection(protectPermissions, p.userPassword, p.ownerPassword)
+}
+
+// fileKey recomputes the key fpdf derives for the document, so objects appended
+// after rendering can be encrypted the same way
+func (p *protection) fileKey() []byte {
+	user := append([]byte(p.userPassword), passwordPadding...)[:32]
+	owner := append([]byte(p.ownerPassword), passwordPadding...)[:32]
+
+	ownerDigest := md5.Sum(owner)
+	cipher, _ := rc4.NewCipher(ownerDigest[:5])
+	oValue := make([]byte, 32)
+	cipher.XORKeyStream(oValue, user)
+
+	flags := byte(192 | protectPermissions)
+	buf := append(append(user, oValue...), flags, 0xff, 0xff, 0xff)
+	sum := md5.Sum(buf)
+	return sum[:5]
+}
+
+// encryptString encrypts a string belonging to the given object and returns it as a PDF hex string
+func (p *protection) encryptString(objNum int, value string) string {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint32(b, uint32(objNum))
+	key := append(p.fileKey(), b[0], b[1], b[2], 0, 0)
+	objectKey := md5.Sum(key)
+
+	cipher, _ := rc4.NewCipher(objectKey[:10])
+	out := make([]byte, len(value))
+	cipher.XORKeyStream(out, []byte(value))
+	return "<" + hex.EncodeToString(out) + ">"
+}
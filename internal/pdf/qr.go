@@ -0,0 +1,30 @@
+package pdf
+
+import (
+	"image/color"
+
+	"github.com/boombuler/barcode/qr"
+)
+
+// qrCode draws content as a vector QR code with its top left corner at x, y
+func (d *document) qrCode(content string, x, y, size float64) error {
+	code, err := qr.Encode(content, qr.M, qr.Auto)
+	if err != nil {
+		return err
+	}
+
+	bounds := code.Bounds()
+	modules := bounds.Dx()
+	moduleSize := size / float64(modules)
+
+	d.SetFillColor(0, 0, 0)
+	for row := bounds.Min.Y; row < bounds.Max.Y; row++ {
+		for col := bounds.Min.X; col < bounds.Max.X; col++ {
+			if code.At(col, row) != color.Black {
+				continue
+			}
+			d.Rect(x+float64(col-bounds.Min.X)*moduleSize, y+float64(row-bounds.Min.Y)*moduleSize, moduleSize, moduleSize, "F")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,348 @@
+package pdf
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"go.mozilla.org/pkcs7"
+)
+
+const (
+	// signatureSize is the space reserved for the DER encoded CMS signature
+	signatureSize = 8192
+	// byteRangeWidth is the space reserved for the /ByteRange array
+	byteRangeWidth = 48
+	// firstPageObject is the object number fpdf gives the first page
+	firstPageObject = 3
+)
+
+var (
+	startXrefPattern = regexp.MustCompile(`startxref\s+(\d+)\s+%%EOF\s*$`)
+	sizePattern      = regexp.MustCompile(`/Size (\d+)`)
+	rootPattern      = regexp.MustCompile(`/Root (\d+) 0 R`)
+	infoPattern      = regexp.MustCompile(`/Info (\d+) 0 R`)
+	encryptPattern   = regexp.MustCompile(`/Encrypt (\d+) 0 R`)
+)
+
+// Signer applies detached PKCS#7 signatures to rendered PDF documents
+type Signer struct {
+	key   crypto.Signer
+	cert  *x509.Certificate
+	chain []*x509.Certificate
+}
+
+// LoadSigner reads a PEM encoded private key and certificate chain, leaf first
+func LoadSigner(keyPath, certPath string) (*Signer, error) {
+	keyPEM, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key: %w", err)
+	}
+	certPEM, err := os.ReadFile(certPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing certificate: %w", err)
+	}
+
+	key, err := parsePrivateKey(keyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	var certs []*x509.Certificate
+	for block, rest := pem.Decode(certPEM); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse signing certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no certificate found in signing certificate file")
+	}
+
+	public, ok := key.Public().(interface{ Equal(crypto.PublicKey) bool })
+	if !ok || !public.Equal(certs[0].PublicKey) {
+		return nil, errors.New("signing key does not match certificate")
+	}
+
+	return &Signer{key: key, cert: certs[0], chain: certs[1:]}, nil
+}
+
+// NewSelfSignedSigner creates a throwaway key and certificate for the given organization.
+// Signatures still detect tampering, but recipients cannot tie them to a trusted issuer.
+func NewSelfSignedSigner(organization string) (*Signer, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 62))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:   organization + " Payslip Signing",
+			Organization: []string{organization},
+		},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.AddDate(1, 0, 0),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageContentCommitment,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Signer{key: key, cert: cert}, nil
+}
+
+// CertificatePEM returns the signing certificate chain so recipients can verify signatures
+func (s *Signer) CertificatePEM() []byte {
+	var buf bytes.Buffer
+	for _, cert := range append([]*x509.Certificate{s.cert}, s.chain...) {
+		pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	}
+	return buf.Bytes()
+}
+
+// sign appends an incremental update holding an invisible signature field to a document
+// produced by fpdf. Strings in the update are encrypted when the document is protected.
+func (s *Signer) sign(data []byte, prot *protection, reason string, signedAt time.Time) ([]byte, error) {
+	match := startXrefPattern.FindSubmatch(data)
+	if match == nil {
+		return nil, errors.New("document has no cross-reference table")
+	}
+	prevXref, _ := strconv.Atoi(string(match[1]))
+
+	offsets, trailer, err := readXref(data, prevXref)
+	if err != nil {
+		return nil, err
+	}
+
+	size, _ := strconv.Atoi(submatch(sizePattern, trailer))
+	rootObject, _ := strconv.Atoi(submatch(rootPattern, trailer))
+	if size == 0 || rootObject == 0 {
+		return nil, errors.New("document trailer is incomplete")
+	}
+
+	page, err := readObject(data, offsets, firstPageObject)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Contains(page, []byte("/Annots")) {
+		return nil, errors.New("signing pages with annotations is not supported")
+	}
+	catalog, err := readObject(data, offsets, rootObject)
+	if err != nil {
+		return nil, err
+	}
+
+	text := func(objNum int, value string) string {
+		if prot != nil {
+			return prot.encryptString(objNum, value)
+		}
+		return "<" + hex.EncodeToString([]byte(value)) + ">"
+	}
+
+	sigObject, fieldObject := size, size+1
+
+	var buf bytes.Buffer
+	buf.Write(data)
+	if !bytes.HasSuffix(data, []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+	updated := map[int]int{}
+
+	// Signature dictionary; /Contents is never encrypted
+	updated[sigObject] = buf.Len()
+	fmt.Fprintf(&buf, "%d 0 obj\n<</Type /Sig /Filter /Adobe.PPKLite /SubFilter /adbe.pkcs7.detached\n", sigObject)
+	fmt.Fprintf(&buf, "/Reason %s\n/M %s\n/ByteRange ", text(sigObject, reason), text(sigObject, pdfDate(signedAt)))
+	byteRangeAt := buf.Len()
+	buf.Write(bytes.Repeat([]byte(" "), byteRangeWidth))
+	buf.WriteString("\n/Contents ")
+	contentsStart := buf.Len()
+	buf.WriteString("<" + string(bytes.Repeat([]byte("0"), signatureSize*2)) + ">")
+	contentsEnd := buf.Len()
+	buf.WriteString(">>\nendobj\n")
+
+	// Invisible signature field on the first page
+	updated[fieldObject] = buf.Len()
+	fmt.Fprintf(&buf, "%d 0 obj\n<</Type /Annot /Subtype /Widget /FT /Sig /T %s /V %d 0 R /P %d 0 R /Rect [0 0 0 0] /F 132>>\nendobj\n",
+		fieldObject, text(fieldObject, "Signature1"), sigObject, firstPageObject)
+
+	updated[firstPageObject] = buf.Len()
+	fmt.Fprintf(&buf, "%d 0 obj\n%s\n/Annots [%d 0 R]>>\nendobj\n", firstPageObject, bytes.TrimSuffix(page, []byte(">>")), fieldObject)
+
+	updated[rootObject] = buf.Len()
+	fmt.Fprintf(&buf, "%d 0 obj\n%s\n/AcroForm <</Fields [%d 0 R] /SigFlags 3>>\n>>\nendobj\n", rootObject, bytes.TrimSuffix(catalog, []byte(">>")), fieldObject)
+
+	xrefAt := buf.Len()
+	objects := make([]int, 0, len(updated))
+	for objNum := range updated {
+		objects = append(objects, objNum)
+	}
+	sort.Ints(objects)
+	buf.WriteString("xref\n0 1\n0000000000 65535 f \n")
+	for _, objNum := range objects {
+		fmt.Fprintf(&buf, "%d 1\n%010d 00000 n \n", objNum, updated[objNum])
+	}
+
+	fmt.Fprintf(&buf, "trailer\n<<\n/Size %d\n/Root %d 0 R\n", size+2, rootObject)
+	if info := submatch(infoPattern, trailer); info != "" {
+		fmt.Fprintf(&buf, "/Info %s 0 R\n", info)
+	}
+	if encrypt := submatch(encryptPattern, trailer); encrypt != "" {
+		fmt.Fprintf(&buf, "/Encrypt %s 0 R\n/ID [()()]\n", encrypt)
+	}
+	fmt.Fprintf(&buf, "/Prev %d\n>>\nstartxref\n%d\n%%%%EOF\n", prevXref, xrefAt)
+
+	out := buf.Bytes()
+	byteRange := fmt.Sprintf("[0 %d %d %d]", contentsStart, contentsEnd, len(out)-contentsEnd)
+	copy(out[byteRangeAt:], byteRange)
+
+	signed := make([]byte, 0, len(out)-(contentsEnd-contentsStart))
+	signed = append(signed, out[:contentsStart]...)
+	signed = append(signed, out[contentsEnd:]...)
+
+	signature, err := s.signDetached(signed)
+	if err != nil {
+		return nil, err
+	}
+	if len(signature) > signatureSize {
+		return nil, errors.New("signature does not fit the reserved space")
+	}
+	hex.Encode(out[contentsStart+1:], signature)
+
+	return out, nil
+}
+
+func (s *Signer) signDetached(content []byte) ([]byte, error) {
+	signedData, err := pkcs7.NewSignedData(content)
+	if err != nil {
+		return nil, err
+	}
+	signedData.SetDigestAlgorithm(pkcs7.OIDDigestAlgorithmSHA256)
+	if err := signedData.AddSignerChain(s.cert, s.key, s.chain, pkcs7.SignerInfoConfig{}); err != nil {
+		return nil, fmt.Errorf("failed to sign document: %w", err)
+	}
+	signedData.Detach()
+	return signedData.Finish()
+}
+
+// readXref parses the classic cross-reference table fpdf writes, returning object offsets and the trailer
+func readXref(data []byte, at int) (map[int]int, []byte, error) {
+	if at >= len(data) || !bytes.HasPrefix(data[at:], []byte("xref\n")) {
+		return nil, nil, errors.New("invalid cross-reference offset")
+	}
+
+	end := bytes.Index(data[at:], []byte("trailer"))
+	if end < 0 {
+		return nil, nil, errors.New("document has no trailer")
+	}
+	lines := bytes.Split(bytes.TrimSpace(data[at+len("xref\n"):at+end]), []byte("\n"))
+
+	offsets := map[int]int{}
+	objNum := 0
+	for _, line := range lines {
+		fields := bytes.Fields(line)
+		switch {
+		case len(fields) == 2:
+			objNum, _ = strconv.Atoi(string(fields[0]))
+		case len(fields) == 3:
+			if string(fields[2]) == "n" {
+				offset, _ := strconv.Atoi(string(fields[0]))
+				offsets[objNum] = offset
+			}
+			objNum++
+		}
+	}
+
+	trailer := data[at+end:]
+	if i := bytes.Index(trailer, []byte("startxref")); i >= 0 {
+		trailer = trailer[:i]
+	}
+	return offsets, trailer, nil
+}
+
+// readObject returns the dictionary of an uncompressed object, e.g. "<</Type /Page ...>>"
+func readObject(data []byte, offsets map[int]int, objNum int) ([]byte, error) {
+	offset, ok := offsets[objNum]
+	if !ok || offset >= len(data) {
+		return nil, fmt.Errorf("object %d not found", objNum)
+	}
+
+	header := []byte(fmt.Sprintf("%d 0 obj", objNum))
+	body := data[offset:]
+	if !bytes.HasPrefix(body, header) {
+		return nil, fmt.Errorf("object %d not found", objNum)
+	}
+	end := bytes.Index(body, []byte("endobj"))
+	if end < 0 {
+		return nil, fmt.Errorf("object %d is not terminated", objNum)
+	}
+
+	dict := bytes.TrimSpace(body[len(header):end])
+	if !bytes.HasPrefix(dict, []byte("<<")) || !bytes.HasSuffix(dict, []byte(">>")) {
+		return nil, fmt.Errorf("object %d is not a dictionary", objNum)
+	}
+	return dict, nil
+}
+
+func submatch(pattern *regexp.Regexp, data []byte) string {
+	if match := pattern.FindSubmatch(data); match != nil {
+		return string(match[1])
+	}
+	return ""
+}
+
+func pdfDate(t time.Time) string {
+	return "D:" + t.UTC().Format("20060102150405") + "Z"
+}
+
+func parsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found in signing key file")
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		if signer, ok := key.(crypto.Signer); ok {
+			return signer, nil
+		}
+		return nil, errors.New("unsupported signing key type")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, errors.New("failed to parse signing key")
+}
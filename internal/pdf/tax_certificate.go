@@ -0,0 +1,154 @@
+package pdf
+
+import (
+	"fmt"
+	"time"
+
+	"payslip-system/internal/config"
+	"payslip-system/internal/domains"
+	"payslip-system/internal/i18n"
+)
+
+// TaxCertificateRenderer renders 1721-A1 annual tax certificates. The form is prescribed by the tax office
+// in Indonesian, so its labels are not translated.
+type TaxCertificateRenderer struct {
+	cfg config.PayslipConfig
+}
+
+// NewTaxCertificateRenderer creates a renderer using the company details and styling of the payslip template
+func NewTaxCertificateRenderer(cfg config.PayslipConfig) *TaxCertificateRenderer {
+	return &TaxCertificateRenderer{cfg: cfg}
+}
+
+// certificateLine is one numbered line of the income and tax calculation
+type certificateLine struct {
+	number string
+	label  string
+	amount float64
+	total  bool
+}
+
+// Render draws the certificate; tr formats its amounts and dates
+func (r *TaxCertificateRenderer) Render(certificate *domains.TaxCertificate, tr *i18n.Translator) ([]byte, error) {
+	if certificate == nil {
+		return nil, fmt.Errorf("incomplete tax certificate")
+	}
+	if tr == nil {
+		return nil, fmt.Errorf("no translator given")
+	}
+
+	money := func(amount float64) string {
+		return tr.Number(amount, 0)
+	}
+
+	doc := newDocument(r.cfg.PaperSize, r.cfg.AccentColor)
+	doc.SetTitle(fmt.Sprintf("1721-A1 %d - %s", certificate.TaxYear, certificate.Username), true)
+	doc.SetAuthor(certificate.EmployerName, true)
+	doc.footer(fmt.Sprintf("Bukti pemotongan ini dibuat secara elektronik oleh %s.", certificate.EmployerName))
+	doc.AddPage()
+
+	doc.SetFont("Helvetica", "B", 15)
+	doc.CellFormat(doc.width, 7, doc.tr(certificate.EmployerName), "", 1, "L", false, 0, "")
+	if r.cfg.CompanyAddress != "" {
+		doc.SetFont("Helvetica", "", 9)
+		doc.CellFormat(doc.width, 5, doc.tr(r.cfg.CompanyAddress), "", 1, "L", false, 0, "")
+	}
+
+	doc.Ln(3)
+	doc.SetFont("Helvetica", "B", 13)
+	doc.SetTextColor(doc.accent[0], doc.accent[1], doc.accent[2])
+	doc.CellFormat(doc.width, 7, doc.tr("BUKTI PEMOTONGAN PAJAK PENGHASILAN PASAL 21"), "", 1, "C", false, 0, "")
+	doc.SetTextColor(0, 0, 0)
+	doc.SetFont("Helvetica", "", 10)
+	doc.CellFormat(doc.width, 5, doc.tr("Formulir 1721-A1 - Pegawai Tetap"), "", 1, "C", false, 0, "")
+	// Year to date statements are not numbered
+	if certificate.Number != "" {
+		doc.CellFormat(doc.width, 5, doc.tr("Nomor: "+certificate.Number), "", 1, "C", false, 0, "")
+	}
+
+	period := fmt.Sprintf("%02d - %02d / %d", certificate.FirstMonth, certificate.LastMonth, certificate.TaxYear)
+	if certificate.TaxYear == certificate.IssuedAt.Year() && certificate.LastMonth < 12 {
+		period += " (tahun berjalan)"
+	}
+
+	doc.sectionTitle("A. Identitas Penerima Penghasilan")
+	doc.keyValueRows([][2]string{
+		{"NPWP", orDash(certificate.NPWP)},
+		{"NIK", orDash(certificate.NIK)},
+		{"Nama", certificate.Username},
+		{"Status PTKP", certificate.PTKPStatus},
+		{"Masa perolehan", period},
+	})
+
+	doc.sectionTitle("B. Rincian Penghasilan dan Penghitungan PPh Pasal 21 (" + certificate.Currency + ")")
+	numberedLines(doc, []certificateLine{
+		{number: "1", label: "Gaji/pensiun atau THT/JHT", amount: certificate.Salary},
+		{number: "3", label: "Tunjangan lainnya, uang lembur dan sebagainya", amount: certificate.Overtime},
+		{number: "5", label: "Premi asuransi yang dibayar pemberi kerja", amount: certificate.InsurancePremiums},
+		{number: "8", label: "Jumlah penghasilan bruto", amount: certificate.GrossIncome, total: true},
+		{number: "9", label: "Biaya jabatan", amount: certificate.OccupationalCost},
+		{number: "10", label: "Iuran pensiun atau iuran THT/JHT", amount: certificate.PensionContributions},
+		{number: "11", label: "Jumlah pengurangan", amount: certificate.TotalDeductions, total: true},
+		{number: "12", label: "Jumlah penghasilan neto", amount: certificate.NetIncome},
+		{number: "14", label: "Jumlah penghasilan neto untuk penghitungan PPh Pasal 21", amount: certificate.NetIncome},
+		{number: "15", label: "Penghasilan tidak kena pajak (PTKP)", amount: certificate.PTKP},
+		{number: "16", label: "Penghasilan kena pajak setahun", amount: certificate.TaxableIncome},
+		{number: "17", label: "PPh Pasal 21 atas penghasilan kena pajak", amount: certificate.TaxDue},
+		{number: "19", label: "PPh Pasal 21 terutang", amount: certificate.TaxDue, total: true},
+		{number: "20", label: "PPh Pasal 21 yang telah dipotong", amount: certificate.TaxWithheld, total: true},
+	}, money)
+
+	switch {
+	case certificate.TaxBalance > 0:
+		doc.keyValueRows([][2]string{{"Kurang potong", money(certificate.TaxBalance)}})
+	case certificate.TaxBalance < 0:
+		doc.keyValueRows([][2]string{{"Lebih potong", money(-certificate.TaxBalance)}})
+	}
+
+	doc.sectionTitle("C. Identitas Pemotong")
+	doc.keyValueRows([][2]string{
+		{"NPWP", orDash(certificate.EmployerNPWP)},
+		{"Nama", certificate.EmployerName},
+		{"Tanggal", tr.Date(certificate.IssuedAt.In(time.Local))},
+	})
+
+	if certificate.SignerName != "" {
+		doc.Ln(10)
+		doc.SetFont("Helvetica", "", 10)
+		doc.CellFormat(doc.width, 5, doc.tr(certificate.SignerName), "", 1, "R", false, 0, "")
+		if certificate.SignerNPWP != "" {
+			doc.CellFormat(doc.width, 5, doc.tr("NPWP "+certificate.SignerNPWP), "", 1, "R", false, 0, "")
+		}
+	}
+
+	data, err := doc.bytes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to render tax certificate PDF: %w", err)
+	}
+	return data, nil
+}
+
+// numberedLines prints the numbered lines of the form; totals are bold
+func numberedLines(doc *document, lines []certificateLine, format func(float64) string) {
+	numberWidth := 10.0
+	amountWidth := doc.width * 0.3
+	labelWidth := doc.width - numberWidth - amountWidth
+
+	for _, line := range lines {
+		style := ""
+		if line.total {
+			style = "B"
+		}
+		doc.SetFont("Helvetica", style, 10)
+		doc.CellFormat(numberWidth, lineHeight, line.number, "B", 0, "R", false, 0, "")
+		doc.CellFormat(labelWidth, lineHeight, doc.tr(" "+line.label), "B", 0, "L", false, 0, "")
+		doc.CellFormat(amountWidth, lineHeight, doc.tr(format(line.amount)), "B", 1, "R", false, 0, "")
+	}
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
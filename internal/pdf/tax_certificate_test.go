@@ -0,0 +1,50 @@
+package pdf
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"payslip-system/internal/domains"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaxCertificateRenderer_Render(t *testing.T) {
+	renderer := NewTaxCertificateRenderer(testPayslipConfig)
+
+	certificate := &domains.TaxCertificate{
+		Number:               "1.1-12.24-0000001",
+		TaxYear:              2024,
+		FirstMonth:           1,
+		LastMonth:            12,
+		Currency:             "IDR",
+		EmployerName:         "PT Test",
+		EmployerNPWP:         "0123456789012000",
+		Username:             "employee1",
+		NIK:                  "3174011503900001",
+		PTKPStatus:           "K/1",
+		Salary:               96000000,
+		InsurancePremiums:    6537600,
+		GrossIncome:          102537600,
+		OccupationalCost:     5126880,
+		PensionContributions: 4145688,
+		TotalDeductions:      9272568,
+		NetIncome:            93265032,
+		PTKP:                 63000000,
+		TaxableIncome:        30265000,
+		TaxDue:               1513250,
+		TaxWithheld:          1513248,
+		TaxBalance:           2,
+		SignerName:           "Finance Manager",
+		IssuedAt:             time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+	}
+
+	data, err := renderer.Render(certificate, testTranslators.Translator("id"))
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(data, []byte("%PDF-")))
+
+	_, err = renderer.Render(nil, testTranslators.Translator("id"))
+	assert.Error(t, err)
+}
@@ -1,27 +1,104 @@
 package providers
 
 import (
+	"payslip-system/internal/config"
 	"payslip-system/internal/domains"
+	"payslip-system/internal/mail"
+	"payslip-system/internal/password"
 	"payslip-system/internal/repository"
 	"payslip-system/internal/service"
+	"payslip-system/internal/token"
+
+	"github.com/sirupsen/logrus"
 )
 
 type Services struct {
-	Auth          domains.IAuthService
-	Attendance    domains.IAttendanceService
-	Overtime      domains.IOvertimeService
-	Reimbursement domains.IReimbursementService
-	Payroll       domains.IPayrollService
-	Admin         domains.IAdminService
+	Auth           domains.IAuthService
+	SSO            domains.ISSOService
+	MFA            domains.IMFAService
+	Password       domains.IPasswordService
+	Role           domains.IRoleService
+	APIKey         domains.IAPIKeyService
+	Attendance     domains.IAttendanceService
+	Overtime       domains.IOvertimeService
+	Reimbursement  domains.IReimbursementService
+	Leave          domains.ILeaveService
+	Team           domains.ITeamService
+	Payroll        domains.IPayrollService
+	Admin          domains.IAdminService
+	Report         domains.IReportService
+	Payslip        domains.IPayslipService
+	Language       domains.ILanguageService
+	PayslipMail    domains.IPayslipMailService
+	Disbursement   domains.IDisbursementService
+	Reconciliation domains.IReconciliationService
+	Tax            domains.ITaxService
+	Filing         domains.IFilingService
+	Tokens         *token.KeySet
 }
 
-func NewServices(repos *repository.Repositories) *Services {
+func NewServices(repos *repository.Repositories, cfg *config.Config) *Services {
+	languages := service.NewLanguageService(repos, cfg.I18n)
+	payroll := service.NewPayrollService(repos, cfg.Payroll, cfg.Tax)
+	payslips := service.NewPayslipService(repos, cfg.Payslip, languages)
+	tokens := loadTokenKeys(cfg.Auth, cfg.Environment)
+	mfa := service.NewMFAService(repos, cfg.Auth.MFA)
+	sender := mail.NewSMTPSender(cfg.Mail)
+	auth := service.NewAuthService(repos, cfg.Auth, tokens, mfa)
+	passwords := service.NewPasswordService(repos, cfg.Auth.Password, loadPasswordPolicy(cfg.Auth.Password), cfg.Mail, cfg.Payslip.CompanyName, languages, sender, cfg.Auth.PasswordLoginDisabled)
+
 	return &Services{
-		Auth:          service.NewAuthService(repos),
-		Attendance:    service.NewAttendanceService(repos),
-		Overtime:      service.NewOvertimeService(repos),
-		Reimbursement: service.NewReimbursementService(repos),
-		Payroll:       service.NewPayrollService(repos),
-		Admin:         service.NewAdminService(repos),
+		Auth:           auth,
+		SSO:            service.NewSSOService(repos, cfg.Auth, auth),
+		MFA:            mfa,
+		Password:       passwords,
+		Role:           service.NewRoleService(repos),
+		APIKey:         service.NewAPIKeyService(repos, cfg.Auth.APIKeyMaxTTL),
+		Attendance:     service.NewAttendanceService(repos),
+		Overtime:       service.NewOvertimeService(repos),
+		Reimbursement:  service.NewReimbursementService(repos),
+		Leave:          service.NewLeaveService(repos),
+		Team:           service.NewTeamService(repos),
+		Payroll:        payroll,
+		Admin:          service.NewAdminService(repos),
+		Report:         service.NewReportService(repos, cfg.Payroll, cfg.Ledger, cfg.Payslip.Currency),
+		Payslip:        payslips,
+		Language:       languages,
+		PayslipMail:    service.NewPayslipMailService(repos, cfg.Mail, cfg.Payslip.CompanyName, payroll, payslips, passwords, languages, sender),
+		Disbursement:   service.NewDisbursementService(repos, cfg.Disbursement, cfg.Payslip.Currency),
+		Reconciliation: service.NewReconciliationService(repos),
+		Tax:            service.NewTaxService(repos, cfg.Tax, cfg.Payslip, languages),
+		Filing:         service.NewFilingService(repos, cfg.Tax),
+		Tokens:         tokens,
+	}
+}
+
+// loadTokenKeys loads the configured token keys. Only development falls back to a throwaway key.
+func loadTokenKeys(cfg config.AuthConfig, environment string) *token.KeySet {
+	if len(cfg.Keys) > 0 {
+		keys, err := token.LoadKeySet(cfg.SigningKeyID, cfg.Keys)
+		if err != nil {
+			logrus.WithError(err).Fatal("Failed to load token signing keys")
+		}
+		return keys
+	}
+
+	if environment != "development" {
+		logrus.Fatal("auth.keys must be set")
+	}
+
+	logrus.Warn("No token signing keys configured, signing with a throwaway key; tokens do not survive a restart")
+	keys, err := token.NewEphemeralKeySet()
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to create token signing key")
+	}
+	return keys
+}
+
+func loadPasswordPolicy(cfg config.PasswordConfig) *password.Policy {
+	policy, err := password.NewPolicy(cfg)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to load password policy")
 	}
+	return policy
 }
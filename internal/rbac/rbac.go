@@ -0,0 +1,193 @@
+// Package rbac defines the permissions routes require and the builtin roles granting them. Users hold any
+// number of roles and may do what one of them permits.
+package rbac
+
+// Self-service permissions, held by employees
+const (
+	AttendanceSubmit    = "attendance:submit"
+	OvertimeSubmit      = "overtime:submit"
+	ReimbursementSubmit = "reimbursement:submit"
+	LeaveSubmit         = "leave:submit"
+	PayslipViewOwn      = "payslip:view_own"
+)
+
+// Line management permissions. They reach only the holder's direct and indirect reports, and the reports of
+// managers on leave who delegated to the holder.
+const (
+	TeamView             = "team:view"
+	OvertimeApprove      = "overtime:approve"
+	ReimbursementApprove = "reimbursement:approve"
+	LeaveApprove         = "leave:approve"
+)
+
+// Administrative permissions
+const (
+	AttendancePeriodManage = "attendance_period:manage"
+	AttendanceRecord       = "attendance:record"
+	PayrollProcess         = "payroll:process"
+	PayrollApprove         = "payroll:approve"
+	PayrollViewSummary     = "payroll:view_summary"
+	PayslipDistribute      = "payslip:distribute"
+	DisbursementManage     = "disbursement:manage"
+	ReportView             = "report:view"
+	TaxReport              = "tax:report"
+	EmployeeManage         = "employee:manage"
+	SecurityManage         = "security:manage"
+	RoleManage             = "role:manage"
+	LanguageManage         = "language:manage"
+	APIKeyManage           = "api_key:manage"
+	UserImpersonate        = "user:impersonate"
+)
+
+// Permission describes a permission for role administrators
+type Permission struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	SelfService bool   `json:"self_service"` // concerns only the user's own records
+}
+
+// Permissions lists every permission
+var Permissions = []Permission{
+	{Name: AttendanceSubmit, Description: "Submit own attendance", SelfService: true},
+	{Name: OvertimeSubmit, Description: "Submit own overtime", SelfService: true},
+	{Name: ReimbursementSubmit, Description: "Submit own reimbursements", SelfService: true},
+	{Name: LeaveSubmit, Description: "Request leave and see own leave requests", SelfService: true},
+	{Name: PayslipViewOwn, Description: "View own payslips and tax certificates, and set how payslips are delivered", SelfService: true},
+	{Name: AttendancePeriodManage, Description: "Create attendance periods"},
+	{Name: AttendanceRecord, Description: "Record attendance of any employee, as badge readers do"},
+	{Name: PayrollProcess, Description: "Preview and process payroll"},
+	{Name: PayrollApprove, Description: "Approve or reject processed payroll"},
+	{Name: PayrollViewSummary, Description: "View payroll runs and summaries, including all salaries"},
+	{Name: PayslipDistribute, Description: "Email payslips and retry deliveries"},
+	{Name: DisbursementManage, Description: "Generate bank disbursement files and reconcile bank statements"},
+	{Name: ReportView, Description: "View payroll variance reports and journals"},
+	{Name: TaxReport, Description: "Export tax certificates and monthly tax and BPJS returns"},
+	{Name: EmployeeManage, Description: "Maintain bank accounts, cost centers and tax profiles of employees"},
+	{Name: SecurityManage, Description: "Revoke sessions, lift lockouts and reset passwords"},
+	{Name: RoleManage, Description: "Manage roles and the roles users hold"},
+	{Name: LanguageManage, Description: "Manage payslip languages"},
+	{Name: APIKeyManage, Description: "Issue and revoke API keys of integrations"},
+	{Name: UserImpersonate, Description: "View the API as an employee, read-only, to help them with support requests"},
+	{Name: TeamView, Description: "List own direct and indirect reports, without their salaries"},
+	{Name: OvertimeApprove, Description: "Approve or reject overtime of own reports"},
+	{Name: ReimbursementApprove, Description: "Approve or reject reimbursements of own reports"},
+	{Name: LeaveApprove, Description: "Approve or reject leave of own reports"},
+}
+
+// Builtin role names
+const (
+	RoleAdmin    = "admin"
+	RoleEmployee = "employee"
+	RoleHRViewer = "hr_viewer"
+	RoleFinance  = "finance"
+	RoleManager  = "manager"
+)
+
+// Role is a builtin role. Builtin roles are kept in sync with these definitions and cannot be changed
+// through the API.
+type Role struct {
+	Name        string
+	Description string
+	Permissions []string
+}
+
+// BuiltinRoles lists the builtin roles
+var BuiltinRoles = []Role{
+	{
+		Name:        RoleAdmin,
+		Description: "Full administration, without self-service; admins who are paid also hold the employee role",
+		Permissions: administrative(),
+	},
+	{
+		Name:        RoleEmployee,
+		Description: "Self-service of employees",
+		Permissions: []string{AttendanceSubmit, OvertimeSubmit, ReimbursementSubmit, LeaveSubmit, PayslipViewOwn},
+	},
+	{
+		Name:        RoleHRViewer,
+		Description: "Read-only access to payroll summaries and reports",
+		Permissions: []string{PayrollViewSummary, ReportView},
+	},
+	{
+		Name:        RoleFinance,
+		Description: "Payroll approval, disbursement, reconciliation and tax reporting",
+		Permissions: []string{PayrollViewSummary, PayrollApprove, DisbursementManage, ReportView, TaxReport},
+	},
+	{
+		Name:        RoleManager,
+		Description: "Line managers deciding on the overtime, reimbursements and leave of their team; held next to employee",
+		Permissions: []string{TeamView, OvertimeApprove, ReimbursementApprove, LeaveApprove},
+	},
+}
+
+func administrative() []string {
+	var names []string
+	for _, p := range Permissions {
+		if !p.SelfService {
+			names = append(names, p.Name)
+		}
+	}
+	return names
+}
+
+// Lookup returns the permission of a name
+func Lookup(name string) (Permission, bool) {
+	for _, p := range Permissions {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Permission{}, false
+}
+
+// IsBuiltin reports whether a role name is taken by a builtin role
+func IsBuiltin(name string) bool {
+	for _, role := range BuiltinRoles {
+		if role.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Privileged reports whether permissions reach beyond self-service; users holding them need a second factor
+func Privileged(permissions []string) bool {
+	for _, name := range permissions {
+		if p, ok := Lookup(name); !ok || !p.SelfService {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKeyGrantable reports whether API keys may hold a permission. Keys act for no user, so self-service and
+// line management, reaching the holder's own records and reports, mean nothing to them. Approving payroll takes
+// a person, and keys neither issue keys nor manage roles, security or impersonation.
+func APIKeyGrantable(name string) bool {
+	p, ok := Lookup(name)
+	if !ok || p.SelfService {
+		return false
+	}
+	switch name {
+	case TeamView, OvertimeApprove, ReimbursementApprove, LeaveApprove, PayrollApprove, RoleManage, SecurityManage,
+		APIKeyManage, UserImpersonate:
+		return false
+	}
+	return true
+}
+
+// Set is the permissions a user holds through all of their roles
+type Set map[string]struct{}
+
+func NewSet(permissions []string) Set {
+	s := make(Set, len(permissions))
+	for _, p := range permissions {
+		s[p] = struct{}{}
+	}
+	return s
+}
+
+func (s Set) Has(permission string) bool {
+	_, ok := s[permission]
+	return ok
+}
@@ -0,0 +1,69 @@
+package rbac
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuiltinRoles(t *testing.T) {
+	names := map[string]bool{}
+	for _, role := range BuiltinRoles {
+		assert.False(t, names[role.Name], "duplicate role %s", role.Name)
+		names[role.Name] = true
+
+		assert.NotEmpty(t, role.Permissions, role.Name)
+		for _, p := range role.Permissions {
+			_, ok := Lookup(p)
+			assert.True(t, ok, "role %s grants unknown permission %s", role.Name, p)
+		}
+	}
+
+	// Admins administer, but hold no self-service of their own
+	admin := NewSet(BuiltinRoles[0].Permissions)
+	assert.True(t, admin.Has(RoleManage))
+	assert.True(t, admin.Has(PayrollProcess))
+	assert.False(t, admin.Has(PayslipViewOwn))
+
+	// Managers approve for their team without seeing company-wide salaries
+	for _, role := range BuiltinRoles {
+		if role.Name == RoleManager {
+			manager := NewSet(role.Permissions)
+			assert.True(t, manager.Has(OvertimeApprove))
+			assert.False(t, manager.Has(PayrollViewSummary))
+			assert.False(t, manager.Has(ReportView))
+		}
+	}
+}
+
+func TestPrivileged(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions []string
+		want        bool
+	}{
+		{name: "none"},
+		{name: "self-service", permissions: []string{AttendanceSubmit, PayslipViewOwn}},
+		{name: "read-only administration", permissions: []string{PayslipViewOwn, ReportView}, want: true},
+		{name: "unknown permission", permissions: []string{"payroll:everything"}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Privileged(tt.permissions))
+		})
+	}
+}
+
+func TestAPIKeyGrantable(t *testing.T) {
+	assert.True(t, APIKeyGrantable(AttendanceRecord))
+	assert.True(t, APIKeyGrantable(EmployeeManage))
+	assert.False(t, APIKeyGrantable(AttendanceSubmit))
+	assert.False(t, APIKeyGrantable(OvertimeApprove))
+	assert.False(t, APIKeyGrantable(APIKeyManage))
+	assert.False(t, APIKeyGrantable(RoleManage))
+	assert.False(t, APIKeyGrantable(SecurityManage))
+	assert.False(t, APIKeyGrantable(PayrollApprove))
+	assert.False(t, APIKeyGrantable(UserImpersonate))
+	assert.False(t, APIKeyGrantable("payroll:everything"))
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"time"
+
+	"payslip-system/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// apiKeyUsageInterval is how stale last use may get before a request records it again, sparing busy
+// integrations a write per request
+const apiKeyUsageInterval = time.Minute
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) IAPIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+// Create stores a key with its permissions
+func (r *apiKeyRepository) Create(key *models.APIKey) error {
+	return r.db.Create(key).Error
+}
+
+func (r *apiKeyRepository) GetAll() ([]models.APIKey, error) {
+	var keys []models.APIKey
+	err := r.db.Preload("Permissions").Order("created_at DESC").Find(&keys).Error
+	return keys, err
+}
+
+func (r *apiKeyRepository) GetByID(id uuid.UUID) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.Preload("Permissions").First(&key, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *apiKeyRepository) GetByHash(hash string) (*models.APIKey, error) {
+	var key models.APIKey
+	if err := r.db.Preload("Permissions").Where("key_hash = ?", hash).First(&key).Error; err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// Revoke revokes the key unless it already was, reporting whether this call revoked it
+func (r *apiKeyRepository) Revoke(id, revokedBy uuid.UUID, at time.Time) (bool, error) {
+	result := r.db.Model(&models.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"revoked_at": at,
+			"revoked_by": revokedBy,
+			"updated_at": at,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// MarkUsed records a use of the key, at most once a minute
+func (r *apiKeyRepository) MarkUsed(id uuid.UUID, ipAddress string, at time.Time) error {
+	return r.db.Model(&models.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ? OR last_used_ip <> ?)", id, at.Add(-apiKeyUsageInterval), ipAddress).
+		UpdateColumns(map[string]interface{}{
+			"last_used_at": at,
+			"last_used_ip": ipAddress,
+		}).Error
+}
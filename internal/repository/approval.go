@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"payslip-system/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// decide records a decision on a pending overtime, reimbursement or leave request. It reports false when the
+// request has been decided already, so concurrent decisions cannot overwrite each other.
+func decide(db *gorm.DB, model interface{}, id uuid.UUID, approval models.Approval) (bool, error) {
+	result := db.Model(model).Where("id = ? AND status = ?", id, models.ApprovalStatusPending).Updates(map[string]interface{}{
+		"status":           approval.Status,
+		"decided_by":       approval.DecidedBy,
+		"on_behalf_of":     approval.OnBehalfOf,
+		"decided_at":       approval.DecidedAt,
+		"decision_comment": approval.DecisionComment,
+	})
+	return result.RowsAffected == 1, result.Error
+}
@@ -1,13 +1,19 @@
 package repository
 
 import (
+	"errors"
 	"payslip-system/internal/models"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrPeriodLocked is returned when payroll processing holds the attendance period row
+var ErrPeriodLocked = errors.New("attendance period is locked for payroll processing")
+
 type attendancePeriodRepository struct {
 	db *gorm.DB
 }
@@ -41,6 +47,33 @@ func (r *attendancePeriodRepository) GetActive() (*models.AttendancePeriod, erro
 	return &period, nil
 }
 
+// GetByIDForUpdate locks the period row until the surrounding transaction ends
+func (r *attendancePeriodRepository) GetByIDForUpdate(id uuid.UUID) (*models.AttendancePeriod, error) {
+	var period models.AttendancePeriod
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&period, id).Error; err != nil {
+		return nil, err
+	}
+	return &period, nil
+}
+
+// GetActiveForSubmission returns the active period holding a share lock until the surrounding
+// transaction ends, so payroll processing cannot start while a submission is being written.
+// Fails fast with ErrPeriodLocked while payroll processing holds the row.
+func (r *attendancePeriodRepository) GetActiveForSubmission() (*models.AttendancePeriod, error) {
+	var period models.AttendancePeriod
+	now := time.Now()
+	err := r.db.Clauses(clause.Locking{Strength: "SHARE", Options: "NOWAIT"}).
+		Where("start_date <= ? AND end_date >= ? AND is_processed = false", now, now).First(&period).Error
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "55P03" { // lock_not_available
+			return nil, ErrPeriodLocked
+		}
+		return nil, err
+	}
+	return &period, nil
+}
+
 func (r *attendancePeriodRepository) Create(period *models.AttendancePeriod) error {
 	return r.db.Create(period).Error
 }
@@ -19,6 +19,16 @@ func (r *auditLogRepository) Create(log *models.AuditLog) error {
 	return r.db.Create(log).Error
 }
 
+// GetActivity returns the latest entries of what a user did, and of what admins did viewing as them
+func (r *auditLogRepository) GetActivity(userID uuid.UUID, limit int) ([]models.AuditLog, error) {
+	var logs []models.AuditLog
+	if err := r.db.Where("user_id = ? OR impersonated_user_id = ?", userID, userID).
+		Order("created_at DESC").Limit(limit).Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
 func (r *auditLogRepository) GetByTableAndRecord(tableName string, recordID uuid.UUID) ([]models.AuditLog, error) {
 	var logs []models.AuditLog
 	if err := r.db.Where("table_name = ? AND record_id = ?", tableName, recordID).
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"payslip-system/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type bankTransactionRepository struct {
+	db *gorm.DB
+}
+
+func NewBankTransactionRepository(db *gorm.DB) IBankTransactionRepository {
+	return &bankTransactionRepository{db: db}
+}
+
+// Import stores the transaction unless the same statement entry was imported for the period before, reporting
+// whether it was inserted. A new transaction's payment, when given, is saved in the same transaction; only its
+// payment columns, as the amounts of a processed item never change.
+func (r *bankTransactionRepository) Import(transaction *models.BankTransaction, payment *models.PayrollItem) (bool, error) {
+	created := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(transaction)
+		if result.Error != nil {
+			return result.Error
+		}
+		created = result.RowsAffected == 1
+		if !created || payment == nil {
+			return nil
+		}
+		return tx.Model(payment).Select("PaymentStatus", "PaymentReason", "PaymentUpdatedAt").Updates(payment).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	return created, nil
+}
+
+func (r *bankTransactionRepository) GetByPeriodID(periodID uuid.UUID) ([]models.BankTransaction, error) {
+	var transactions []models.BankTransaction
+	if err := r.db.Where("attendance_period_id = ?", periodID).
+		Order("booking_date ASC, created_at ASC").Find(&transactions).Error; err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
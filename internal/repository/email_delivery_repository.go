@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"time"
+
+	"payslip-system/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type emailDeliveryRepository struct {
+	db *gorm.DB
+}
+
+func NewEmailDeliveryRepository(db *gorm.DB) IEmailDeliveryRepository {
+	return &emailDeliveryRepository{db: db}
+}
+
+// CreateIfAbsent queues the delivery unless the employee already has one for the payroll run, reporting whether
+// it was inserted. Password reset emails have no payroll run and are always queued.
+func (r *emailDeliveryRepository) CreateIfAbsent(delivery *models.EmailDelivery) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(delivery)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *emailDeliveryRepository) GetByID(id uuid.UUID) (*models.EmailDelivery, error) {
+	var delivery models.EmailDelivery
+	if err := r.db.Preload("User").First(&delivery, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (r *emailDeliveryRepository) GetByPayrollID(payrollID uuid.UUID) ([]models.EmailDelivery, error) {
+	var deliveries []models.EmailDelivery
+	if err := r.db.Where("payroll_id = ?", payrollID).Preload("User").
+		Order("created_at ASC").Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// ClaimDue marks up to limit due deliveries as sending until the lease expires, counting the attempt, and
+// returns them. Concurrent workers skip each other's rows, and deliveries left sending by a crashed worker
+// become due again when their lease expires.
+func (r *emailDeliveryRepository) ClaimDue(now time.Time, limit int, lease time.Duration) ([]models.EmailDelivery, error) {
+	var ids []uuid.UUID
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.EmailDelivery{}).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status IN ? AND next_attempt_at <= ?", []string{models.EmailStatusQueued, models.EmailStatusSending}, now).
+			Order("next_attempt_at ASC").Limit(limit).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		return tx.Model(&models.EmailDelivery{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"status":          models.EmailStatusSending,
+			"attempts":        gorm.Expr("attempts + 1"),
+			"next_attempt_at": now.Add(lease),
+			"updated_at":      now,
+		}).Error
+	})
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	var deliveries []models.EmailDelivery
+	if err := r.db.Preload("User").Where("id IN ?", ids).Order("next_attempt_at ASC").Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *emailDeliveryRepository) Update(delivery *models.EmailDelivery) error {
+	return r.db.Omit("User").Save(delivery).Error
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"payslip-system/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type idempotencyKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyKeyRepository(db *gorm.DB) IIdempotencyKeyRepository {
+	return &idempotencyKeyRepository{db: db}
+}
+
+// CreateIfAbsent inserts the record unless its user or API key already holds the key, reporting whether it was inserted
+func (r *idempotencyKeyRepository) CreateIfAbsent(record *models.IdempotencyKey) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(record)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// GetByOwnerAndKey returns the key held by the API key if one is given, by the user otherwise
+func (r *idempotencyKeyRepository) GetByOwnerAndKey(userID, apiKeyID *uuid.UUID, key string) (*models.IdempotencyKey, error) {
+	query := r.db.Where("key = ?", key)
+	if apiKeyID != nil {
+		query = query.Where("api_key_id = ?", *apiKeyID)
+	} else {
+		query = query.Where("user_id = ?", userID)
+	}
+
+	var record models.IdempotencyKey
+	if err := query.First(&record).Error; err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (r *idempotencyKeyRepository) Update(record *models.IdempotencyKey) error {
+	return r.db.Save(record).Error
+}
+
+func (r *idempotencyKeyRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.IdempotencyKey{}, "id = ?", id).Error
+}
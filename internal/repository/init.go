@@ -15,8 +15,21 @@ type Repositories struct {
 	Attendance       IAttendanceRepository
 	Overtime         IOvertimeRepository
 	Reimbursement    IReimbursementRepository
+	Leave            ILeaveRepository
 	Payroll          IPayrollRepository
 	AuditLog         IAuditLogRepository
+	IdempotencyKey   IIdempotencyKeyRepository
+	Language         ILanguageRepository
+	EmailDelivery    IEmailDeliveryRepository
+	BankTransaction  IBankTransactionRepository
+	Session          ISessionRepository
+	MFA              IMFARepository
+	SSO              ISSORepository
+	APIKey           IAPIKeyRepository
+	LoginThrottle    ILoginThrottleRepository
+	Password         IPasswordRepository
+	Role             IRoleRepository
+	TaxCertificate   ITaxCertificateRepository
 }
 
 func NewRepositories(db *gorm.DB) *Repositories {
@@ -27,16 +40,33 @@ func NewRepositories(db *gorm.DB) *Repositories {
 		Attendance:       NewAttendanceRepository(db),
 		Overtime:         NewOvertimeRepository(db),
 		Reimbursement:    NewReimbursementRepository(db),
+		Leave:            NewLeaveRepository(db),
 		Payroll:          NewPayrollRepository(db),
 		AuditLog:         NewAuditLogRepository(db),
+		IdempotencyKey:   NewIdempotencyKeyRepository(db),
+		Language:         NewLanguageRepository(db),
+		EmailDelivery:    NewEmailDeliveryRepository(db),
+		BankTransaction:  NewBankTransactionRepository(db),
+		Session:          NewSessionRepository(db),
+		MFA:              NewMFARepository(db),
+		SSO:              NewSSORepository(db),
+		APIKey:           NewAPIKeyRepository(db),
+		LoginThrottle:    NewLoginThrottleRepository(db),
+		Password:         NewPasswordRepository(db),
+		Role:             NewRoleRepository(db),
+		TaxCertificate:   NewTaxCertificateRepository(db),
 	}
 }
 
-//go:generate mockgen -destination=mocks/mocks.go -source=init.go IUserRepository, IAttendancePeriodRepository, IAttendanceRepository, IOvertimeRepository, IPayrollRepository, IReimbursementRepository, IAuditLogRepository
+//go:generate mockgen -destination=mocks/mocks.go -source=init.go IUserRepository, IAttendancePeriodRepository, IAttendanceRepository, IOvertimeRepository, IPayrollRepository, IReimbursementRepository, ILeaveRepository, IAuditLogRepository, IIdempotencyKeyRepository, ILanguageRepository, IEmailDeliveryRepository, IBankTransactionRepository, ISessionRepository, IMFARepository, ISSORepository, IAPIKeyRepository, ILoginThrottleRepository, IPasswordRepository, IRoleRepository
 type IUserRepository interface {
 	GetByID(id uuid.UUID) (*models.User, error)
 	GetByUsername(username string) (*models.User, error)
+	GetByOIDCSubject(subject string) (*models.User, error)
+	GetByEmployeeID(employeeID string) (*models.User, error)
+	GetByEmail(email string) ([]models.User, error)
 	GetAllEmployees() ([]models.User, error)
+	GetReports(managerID uuid.UUID) ([]models.User, error)
 	Create(user *models.User) error
 	Update(user *models.User) error
 }
@@ -45,6 +75,8 @@ type IAttendancePeriodRepository interface {
 	GetByID(id uuid.UUID) (*models.AttendancePeriod, error)
 	GetAll() ([]models.AttendancePeriod, error)
 	GetActive() (*models.AttendancePeriod, error)
+	GetByIDForUpdate(id uuid.UUID) (*models.AttendancePeriod, error)
+	GetActiveForSubmission() (*models.AttendancePeriod, error)
 	Create(period *models.AttendancePeriod) error
 	Update(period *models.AttendancePeriod) error
 }
@@ -59,23 +91,150 @@ type IAttendanceRepository interface {
 type IOvertimeRepository interface {
 	GetByUserAndPeriod(userID, periodID uuid.UUID) ([]models.Overtime, error)
 	GetByUserAndDate(userID uuid.UUID, date time.Time) (*models.Overtime, error)
+	GetByID(id uuid.UUID) (*models.Overtime, error)
+	GetByUsers(userIDs []uuid.UUID, status string) ([]models.Overtime, error)
 	Create(overtime *models.Overtime) error
+	Decide(id uuid.UUID, approval models.Approval) (bool, error)
 }
 
 type IReimbursementRepository interface {
 	GetByUserAndPeriod(userID, periodID uuid.UUID) ([]models.Reimbursement, error)
+	GetByID(id uuid.UUID) (*models.Reimbursement, error)
+	GetByUsers(userIDs []uuid.UUID, status string) ([]models.Reimbursement, error)
 	Create(reimbursement *models.Reimbursement) error
+	Decide(id uuid.UUID, approval models.Approval) (bool, error)
+}
+
+type ILeaveRepository interface {
+	Create(leave *models.Leave) error
+	GetByID(id uuid.UUID) (*models.Leave, error)
+	GetByUser(userID uuid.UUID) ([]models.Leave, error)
+	GetByUsers(userIDs []uuid.UUID, status string) ([]models.Leave, error)
+	HasOverlap(userID uuid.UUID, start, end time.Time) (bool, error)
+	GetDelegatedTo(delegateID uuid.UUID, day time.Time) ([]models.Leave, error)
+	Decide(id uuid.UUID, approval models.Approval) (bool, error)
 }
 
 type IPayrollRepository interface {
 	GetByPeriodID(periodID uuid.UUID) (*models.Payroll, error)
 	GetPayrollItemsByPeriodAndUser(periodID, userID uuid.UUID) (*models.PayrollItem, error)
 	GetAllPayrollItemsByPeriod(periodID uuid.UUID) ([]models.PayrollItem, error)
+	GetYearToDateItems(userID uuid.UUID, before time.Time) ([]models.PayrollItem, error)
 	Create(payroll *models.Payroll) error
 	CreatePayrollItem(item *models.PayrollItem) error
+	GetRunByPeriodID(periodID uuid.UUID) (*models.Payroll, error)
+	GetRunByPeriodIDForUpdate(periodID uuid.UUID) (*models.Payroll, error)
+	Update(payroll *models.Payroll) error
+	CreateApproval(approval *models.PayrollApproval) error
+	GetApprovals(payrollID uuid.UUID) ([]models.PayrollApproval, error)
+	GetByID(id uuid.UUID) (*models.Payroll, error)
+	GetPayrollItemByID(id uuid.UUID) (*models.PayrollItem, error)
+	SaveDisbursementFile(file *models.DisbursementFile, itemIDs []uuid.UUID) (bool, error)
+	GetDisbursementFile(payrollID uuid.UUID, reference string) (*models.DisbursementFile, error)
 }
 
 type IAuditLogRepository interface {
 	Create(log *models.AuditLog) error
+	GetActivity(userID uuid.UUID, limit int) ([]models.AuditLog, error)
 	GetByTableAndRecord(tableName string, recordID uuid.UUID) ([]models.AuditLog, error)
 }
+
+type IIdempotencyKeyRepository interface {
+	CreateIfAbsent(record *models.IdempotencyKey) (bool, error)
+	GetByOwnerAndKey(userID, apiKeyID *uuid.UUID, key string) (*models.IdempotencyKey, error)
+	Update(record *models.IdempotencyKey) error
+	Delete(id uuid.UUID) error
+}
+
+type ILanguageRepository interface {
+	GetAll() ([]models.Language, error)
+	Upsert(language *models.Language) error
+}
+
+type IEmailDeliveryRepository interface {
+	CreateIfAbsent(delivery *models.EmailDelivery) (bool, error)
+	GetByID(id uuid.UUID) (*models.EmailDelivery, error)
+	GetByPayrollID(payrollID uuid.UUID) ([]models.EmailDelivery, error)
+	ClaimDue(now time.Time, limit int, lease time.Duration) ([]models.EmailDelivery, error)
+	Update(delivery *models.EmailDelivery) error
+}
+
+type IBankTransactionRepository interface {
+	Import(transaction *models.BankTransaction, payment *models.PayrollItem) (bool, error)
+	GetByPeriodID(periodID uuid.UUID) ([]models.BankTransaction, error)
+}
+
+type ISessionRepository interface {
+	Create(session *models.Session) error
+	GetByID(id uuid.UUID) (*models.Session, error)
+	Update(session *models.Session) error
+	RevokeAllForUser(userID uuid.UUID, reason string, at time.Time) (int64, error)
+	RevokeOthersForUser(userID, keepSessionID uuid.UUID, reason string, at time.Time) (int64, error)
+	CreateRefreshToken(token *models.RefreshToken) error
+	GetRefreshTokenByHash(hash string) (*models.RefreshToken, error)
+	MarkRefreshTokenUsed(id uuid.UUID, at time.Time) (bool, error)
+}
+
+type IMFARepository interface {
+	CreateChallenge(challenge *models.MFAChallenge) error
+	GetChallengeByHash(hash string) (*models.MFAChallenge, error)
+	RecordFailedAttempt(id uuid.UUID) error
+	MarkChallengeUsed(id uuid.UUID, at time.Time) (bool, error)
+	MarkTOTPStepUsed(userID uuid.UUID, step int64) (bool, error)
+	ReplaceRecoveryCodes(userID uuid.UUID, codes []models.MFARecoveryCode) error
+	GetUnusedRecoveryCodes(userID uuid.UUID) ([]models.MFARecoveryCode, error)
+	MarkRecoveryCodeUsed(id uuid.UUID, at time.Time) (bool, error)
+	DeleteRecoveryCodes(userID uuid.UUID) error
+}
+
+type ISSORepository interface {
+	CreateLogin(login *models.SSOLogin) error
+	GetLoginByStateHash(hash string) (*models.SSOLogin, error)
+	MarkLoginUsed(id uuid.UUID, at time.Time) (bool, error)
+}
+
+type IAPIKeyRepository interface {
+	Create(key *models.APIKey) error
+	GetAll() ([]models.APIKey, error)
+	GetByID(id uuid.UUID) (*models.APIKey, error)
+	GetByHash(hash string) (*models.APIKey, error)
+	Revoke(id, revokedBy uuid.UUID, at time.Time) (bool, error)
+	MarkUsed(id uuid.UUID, ipAddress string, at time.Time) error
+}
+
+type ILoginThrottleRepository interface {
+	GetByID(id uuid.UUID) (*models.LoginThrottle, error)
+	GetByKey(scope, key string) (*models.LoginThrottle, error)
+	GetLocked(at time.Time) ([]models.LoginThrottle, error)
+	RecordFailure(scope, key string, at time.Time, window time.Duration) (*models.LoginThrottle, error)
+	Lock(id uuid.UUID, until time.Time) error
+	Delete(id uuid.UUID) error
+	DeleteByKey(scope, key string) error
+}
+
+type IPasswordRepository interface {
+	SetPassword(user *models.User, previous *models.PasswordHistory, at time.Time) error
+	GetHistory(userID uuid.UUID, limit int) ([]models.PasswordHistory, error)
+	CreateResetToken(token *models.PasswordResetToken) error
+	GetResetTokenByHash(hash string) (*models.PasswordResetToken, error)
+	MarkResetTokenUsed(id uuid.UUID, at time.Time) (bool, error)
+	InvalidateResetTokens(userID uuid.UUID, at time.Time) error
+}
+
+type IRoleRepository interface {
+	GetAll() ([]models.Role, error)
+	GetByID(id uuid.UUID) (*models.Role, error)
+	GetByNames(names []string) ([]models.Role, error)
+	Create(role *models.Role) error
+	Update(role *models.Role) error
+	Delete(id uuid.UUID) error
+	CountHolders(roleID uuid.UUID) (int64, error)
+	GetUserRoles(userID uuid.UUID) ([]models.Role, error)
+	SetUserRoles(userID uuid.UUID, roleIDs []uuid.UUID, grantedBy uuid.UUID, at time.Time) error
+	GetUserPermissions(userID uuid.UUID) ([]string, error)
+	CountUsersWithPermission(permission string) (int64, error)
+}
+
+type ITaxCertificateRepository interface {
+	Issue(userID uuid.UUID, year, month int, at time.Time) (*models.TaxCertificateIssue, error)
+}
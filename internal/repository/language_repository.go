@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"payslip-system/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type languageRepository struct {
+	db *gorm.DB
+}
+
+func NewLanguageRepository(db *gorm.DB) ILanguageRepository {
+	return &languageRepository{db: db}
+}
+
+func (r *languageRepository) GetAll() ([]models.Language, error) {
+	var languages []models.Language
+	if err := r.db.Order("code ASC").Find(&languages).Error; err != nil {
+		return nil, err
+	}
+	return languages, nil
+}
+
+// Upsert creates the language or replaces the catalog stored under its code
+func (r *languageRepository) Upsert(language *models.Language) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "code"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "catalog", "updated_by", "updated_at"}),
+	}).Create(language).Error
+}
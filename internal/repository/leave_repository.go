@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"time"
+
+	"payslip-system/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type leaveRepository struct {
+	db *gorm.DB
+}
+
+func NewLeaveRepository(db *gorm.DB) ILeaveRepository {
+	return &leaveRepository{db: db}
+}
+
+func (r *leaveRepository) Create(leave *models.Leave) error {
+	return r.db.Create(leave).Error
+}
+
+func (r *leaveRepository) GetByID(id uuid.UUID) (*models.Leave, error) {
+	var leave models.Leave
+	if err := r.db.First(&leave, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &leave, nil
+}
+
+func (r *leaveRepository) GetByUser(userID uuid.UUID) ([]models.Leave, error) {
+	var leaves []models.Leave
+	err := r.db.Where("user_id = ?", userID).Order("start_date DESC").Find(&leaves).Error
+	return leaves, err
+}
+
+// GetByUsers returns the leave of users, latest first; an empty status returns all
+func (r *leaveRepository) GetByUsers(userIDs []uuid.UUID, status string) ([]models.Leave, error) {
+	var leaves []models.Leave
+	query := r.db.Where("user_id IN ?", userIDs)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("start_date DESC").Find(&leaves).Error
+	return leaves, err
+}
+
+// HasOverlap reports whether the user has pending or approved leave on any day between start and end
+func (r *leaveRepository) HasOverlap(userID uuid.UUID, start, end time.Time) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Leave{}).
+		Where("user_id = ? AND status <> ? AND start_date <= ? AND end_date >= ?", userID, models.ApprovalStatusRejected, end, start).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetDelegatedTo returns the approved leave, lasting on day, whose managers delegated to the user
+func (r *leaveRepository) GetDelegatedTo(delegateID uuid.UUID, day time.Time) ([]models.Leave, error) {
+	var leaves []models.Leave
+	err := r.db.Where("delegate_id = ? AND status = ? AND start_date <= ? AND end_date >= ?", delegateID, models.ApprovalStatusApproved, day, day).
+		Find(&leaves).Error
+	return leaves, err
+}
+
+// Decide records a decision on pending leave; false when it has been decided already
+func (r *leaveRepository) Decide(id uuid.UUID, approval models.Approval) (bool, error) {
+	return decide(r.db, &models.Leave{}, id, approval)
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"time"
+
+	"payslip-system/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type loginThrottleRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginThrottleRepository(db *gorm.DB) ILoginThrottleRepository {
+	return &loginThrottleRepository{db: db}
+}
+
+func (r *loginThrottleRepository) GetByID(id uuid.UUID) (*models.LoginThrottle, error) {
+	var throttle models.LoginThrottle
+	if err := r.db.First(&throttle, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+func (r *loginThrottleRepository) GetByKey(scope, key string) (*models.LoginThrottle, error) {
+	var throttle models.LoginThrottle
+	if err := r.db.Where("scope = ? AND key = ?", scope, key).First(&throttle).Error; err != nil {
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+// GetLocked returns the lockouts still in force at the given time
+func (r *loginThrottleRepository) GetLocked(at time.Time) ([]models.LoginThrottle, error) {
+	var throttles []models.LoginThrottle
+	err := r.db.Where("locked_until > ?", at).Order("locked_until DESC").Find(&throttles).Error
+	return throttles, err
+}
+
+// RecordFailure counts a failed login, or a password reset request. Failures older than the window are forgotten, so counting starts over.
+// Concurrent failures of the same key are serialized by a row lock.
+func (r *loginThrottleRepository) RecordFailure(scope, key string, at time.Time, window time.Duration) (*models.LoginThrottle, error) {
+	var throttle models.LoginThrottle
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.LoginThrottle{
+			ID:            uuid.New(),
+			Scope:         scope,
+			Key:           key,
+			LastFailureAt: at,
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("scope = ? AND key = ?", scope, key).
+			First(&throttle).Error; err != nil {
+			return err
+		}
+
+		if throttle.LastFailureAt.Before(at.Add(-window)) {
+			throttle.Failures = 0
+		}
+		throttle.Failures++
+		throttle.LastFailureAt = at
+		return tx.Save(&throttle).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+func (r *loginThrottleRepository) Lock(id uuid.UUID, until time.Time) error {
+	return r.db.Model(&models.LoginThrottle{}).
+		Where("id = ?", id).
+		Update("locked_until", until).Error
+}
+
+func (r *loginThrottleRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.LoginThrottle{}, "id = ?", id).Error
+}
+
+func (r *loginThrottleRepository) DeleteByKey(scope, key string) error {
+	return r.db.Where("scope = ? AND key = ?", scope, key).Delete(&models.LoginThrottle{}).Error
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"time"
+
+	"payslip-system/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type mfaRepository struct {
+	db *gorm.DB
+}
+
+func NewMFARepository(db *gorm.DB) IMFARepository {
+	return &mfaRepository{db: db}
+}
+
+func (r *mfaRepository) CreateChallenge(challenge *models.MFAChallenge) error {
+	return r.db.Create(challenge).Error
+}
+
+func (r *mfaRepository) GetChallengeByHash(hash string) (*models.MFAChallenge, error) {
+	var challenge models.MFAChallenge
+	if err := r.db.Where("token_hash = ?", hash).First(&challenge).Error; err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+func (r *mfaRepository) RecordFailedAttempt(id uuid.UUID) error {
+	return r.db.Model(&models.MFAChallenge{}).
+		Where("id = ?", id).
+		Update("attempts", gorm.Expr("attempts + 1")).Error
+}
+
+// MarkChallengeUsed marks the challenge answered unless it already was, reporting whether this call marked it
+func (r *mfaRepository) MarkChallengeUsed(id uuid.UUID, at time.Time) (bool, error) {
+	result := r.db.Model(&models.MFAChallenge{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", at)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// MarkTOTPStepUsed stores the step of an accepted TOTP code unless the user already used it or a later
+// one, reporting whether this call stored it
+func (r *mfaRepository) MarkTOTPStepUsed(userID uuid.UUID, step int64) (bool, error) {
+	result := r.db.Model(&models.User{}).
+		Where("id = ? AND (mfa_last_step IS NULL OR mfa_last_step < ?)", userID, step).
+		Update("mfa_last_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ReplaceRecoveryCodes deletes the user's recovery codes, used or not, and stores the new ones
+func (r *mfaRepository) ReplaceRecoveryCodes(userID uuid.UUID, codes []models.MFARecoveryCode) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.MFARecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+func (r *mfaRepository) GetUnusedRecoveryCodes(userID uuid.UUID) ([]models.MFARecoveryCode, error) {
+	var codes []models.MFARecoveryCode
+	err := r.db.Where("user_id = ? AND used_at IS NULL", userID).Find(&codes).Error
+	return codes, err
+}
+
+// MarkRecoveryCodeUsed spends a recovery code unless it already was, reporting whether this call spent it
+func (r *mfaRepository) MarkRecoveryCodeUsed(id uuid.UUID, at time.Time) (bool, error) {
+	result := r.db.Model(&models.MFARecoveryCode{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", at)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *mfaRepository) DeleteRecoveryCodes(userID uuid.UUID) error {
+	return r.db.Where("user_id = ?", userID).Delete(&models.MFARecoveryCode{}).Error
+}
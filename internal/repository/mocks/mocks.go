@@ -65,6 +65,36 @@ func (mr *MockIUserRepositoryMockRecorder) GetAllEmployees() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllEmployees", reflect.TypeOf((*MockIUserRepository)(nil).GetAllEmployees))
 }
 
+// GetByEmail mocks base method.
+func (m *MockIUserRepository) GetByEmail(email string) ([]models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByEmail", email)
+	ret0, _ := ret[0].([]models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByEmail indicates an expected call of GetByEmail.
+func (mr *MockIUserRepositoryMockRecorder) GetByEmail(email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByEmail", reflect.TypeOf((*MockIUserRepository)(nil).GetByEmail), email)
+}
+
+// GetByEmployeeID mocks base method.
+func (m *MockIUserRepository) GetByEmployeeID(employeeID string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByEmployeeID", employeeID)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByEmployeeID indicates an expected call of GetByEmployeeID.
+func (mr *MockIUserRepositoryMockRecorder) GetByEmployeeID(employeeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByEmployeeID", reflect.TypeOf((*MockIUserRepository)(nil).GetByEmployeeID), employeeID)
+}
+
 // GetByID mocks base method.
 func (m *MockIUserRepository) GetByID(id uuid.UUID) (*models.User, error) {
 	m.ctrl.T.Helper()
@@ -80,6 +110,21 @@ func (mr *MockIUserRepositoryMockRecorder) GetByID(id interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockIUserRepository)(nil).GetByID), id)
 }
 
+// GetByOIDCSubject mocks base method.
+func (m *MockIUserRepository) GetByOIDCSubject(subject string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByOIDCSubject", subject)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByOIDCSubject indicates an expected call of GetByOIDCSubject.
+func (mr *MockIUserRepositoryMockRecorder) GetByOIDCSubject(subject interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByOIDCSubject", reflect.TypeOf((*MockIUserRepository)(nil).GetByOIDCSubject), subject)
+}
+
 // GetByUsername mocks base method.
 func (m *MockIUserRepository) GetByUsername(username string) (*models.User, error) {
 	m.ctrl.T.Helper()
@@ -95,6 +140,21 @@ func (mr *MockIUserRepositoryMockRecorder) GetByUsername(username interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUsername", reflect.TypeOf((*MockIUserRepository)(nil).GetByUsername), username)
 }
 
+// GetReports mocks base method.
+func (m *MockIUserRepository) GetReports(managerID uuid.UUID) ([]models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReports", managerID)
+	ret0, _ := ret[0].([]models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReports indicates an expected call of GetReports.
+func (mr *MockIUserRepositoryMockRecorder) GetReports(managerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReports", reflect.TypeOf((*MockIUserRepository)(nil).GetReports), managerID)
+}
+
 // Update mocks base method.
 func (m *MockIUserRepository) Update(user *models.User) error {
 	m.ctrl.T.Helper()
@@ -161,6 +221,21 @@ func (mr *MockIAttendancePeriodRepositoryMockRecorder) GetActive() *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActive", reflect.TypeOf((*MockIAttendancePeriodRepository)(nil).GetActive))
 }
 
+// GetActiveForSubmission mocks base method.
+func (m *MockIAttendancePeriodRepository) GetActiveForSubmission() (*models.AttendancePeriod, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveForSubmission")
+	ret0, _ := ret[0].(*models.AttendancePeriod)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveForSubmission indicates an expected call of GetActiveForSubmission.
+func (mr *MockIAttendancePeriodRepositoryMockRecorder) GetActiveForSubmission() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveForSubmission", reflect.TypeOf((*MockIAttendancePeriodRepository)(nil).GetActiveForSubmission))
+}
+
 // GetAll mocks base method.
 func (m *MockIAttendancePeriodRepository) GetAll() ([]models.AttendancePeriod, error) {
 	m.ctrl.T.Helper()
@@ -191,6 +266,21 @@ func (mr *MockIAttendancePeriodRepositoryMockRecorder) GetByID(id interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockIAttendancePeriodRepository)(nil).GetByID), id)
 }
 
+// GetByIDForUpdate mocks base method.
+func (m *MockIAttendancePeriodRepository) GetByIDForUpdate(id uuid.UUID) (*models.AttendancePeriod, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByIDForUpdate", id)
+	ret0, _ := ret[0].(*models.AttendancePeriod)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByIDForUpdate indicates an expected call of GetByIDForUpdate.
+func (mr *MockIAttendancePeriodRepositoryMockRecorder) GetByIDForUpdate(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDForUpdate", reflect.TypeOf((*MockIAttendancePeriodRepository)(nil).GetByIDForUpdate), id)
+}
+
 // Update mocks base method.
 func (m *MockIAttendancePeriodRepository) Update(period *models.AttendancePeriod) error {
 	m.ctrl.T.Helper()
@@ -323,6 +413,36 @@ func (mr *MockIOvertimeRepositoryMockRecorder) Create(overtime interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIOvertimeRepository)(nil).Create), overtime)
 }
 
+// Decide mocks base method.
+func (m *MockIOvertimeRepository) Decide(id uuid.UUID, approval models.Approval) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decide", id, approval)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Decide indicates an expected call of Decide.
+func (mr *MockIOvertimeRepositoryMockRecorder) Decide(id, approval interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decide", reflect.TypeOf((*MockIOvertimeRepository)(nil).Decide), id, approval)
+}
+
+// GetByID mocks base method.
+func (m *MockIOvertimeRepository) GetByID(id uuid.UUID) (*models.Overtime, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*models.Overtime)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockIOvertimeRepositoryMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockIOvertimeRepository)(nil).GetByID), id)
+}
+
 // GetByUserAndDate mocks base method.
 func (m *MockIOvertimeRepository) GetByUserAndDate(userID uuid.UUID, date time.Time) (*models.Overtime, error) {
 	m.ctrl.T.Helper()
@@ -353,6 +473,21 @@ func (mr *MockIOvertimeRepositoryMockRecorder) GetByUserAndPeriod(userID, period
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserAndPeriod", reflect.TypeOf((*MockIOvertimeRepository)(nil).GetByUserAndPeriod), userID, periodID)
 }
 
+// GetByUsers mocks base method.
+func (m *MockIOvertimeRepository) GetByUsers(userIDs []uuid.UUID, status string) ([]models.Overtime, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUsers", userIDs, status)
+	ret0, _ := ret[0].([]models.Overtime)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUsers indicates an expected call of GetByUsers.
+func (mr *MockIOvertimeRepositoryMockRecorder) GetByUsers(userIDs, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUsers", reflect.TypeOf((*MockIOvertimeRepository)(nil).GetByUsers), userIDs, status)
+}
+
 // MockIReimbursementRepository is a mock of IReimbursementRepository interface.
 type MockIReimbursementRepository struct {
 	ctrl     *gomock.Controller
@@ -390,6 +525,36 @@ func (mr *MockIReimbursementRepositoryMockRecorder) Create(reimbursement interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIReimbursementRepository)(nil).Create), reimbursement)
 }
 
+// Decide mocks base method.
+func (m *MockIReimbursementRepository) Decide(id uuid.UUID, approval models.Approval) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decide", id, approval)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Decide indicates an expected call of Decide.
+func (mr *MockIReimbursementRepositoryMockRecorder) Decide(id, approval interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decide", reflect.TypeOf((*MockIReimbursementRepository)(nil).Decide), id, approval)
+}
+
+// GetByID mocks base method.
+func (m *MockIReimbursementRepository) GetByID(id uuid.UUID) (*models.Reimbursement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*models.Reimbursement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockIReimbursementRepositoryMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockIReimbursementRepository)(nil).GetByID), id)
+}
+
 // GetByUserAndPeriod mocks base method.
 func (m *MockIReimbursementRepository) GetByUserAndPeriod(userID, periodID uuid.UUID) ([]models.Reimbursement, error) {
 	m.ctrl.T.Helper()
@@ -405,6 +570,148 @@ func (mr *MockIReimbursementRepositoryMockRecorder) GetByUserAndPeriod(userID, p
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserAndPeriod", reflect.TypeOf((*MockIReimbursementRepository)(nil).GetByUserAndPeriod), userID, periodID)
 }
 
+// GetByUsers mocks base method.
+func (m *MockIReimbursementRepository) GetByUsers(userIDs []uuid.UUID, status string) ([]models.Reimbursement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUsers", userIDs, status)
+	ret0, _ := ret[0].([]models.Reimbursement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUsers indicates an expected call of GetByUsers.
+func (mr *MockIReimbursementRepositoryMockRecorder) GetByUsers(userIDs, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUsers", reflect.TypeOf((*MockIReimbursementRepository)(nil).GetByUsers), userIDs, status)
+}
+
+// MockILeaveRepository is a mock of ILeaveRepository interface.
+type MockILeaveRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockILeaveRepositoryMockRecorder
+}
+
+// MockILeaveRepositoryMockRecorder is the mock recorder for MockILeaveRepository.
+type MockILeaveRepositoryMockRecorder struct {
+	mock *MockILeaveRepository
+}
+
+// NewMockILeaveRepository creates a new mock instance.
+func NewMockILeaveRepository(ctrl *gomock.Controller) *MockILeaveRepository {
+	mock := &MockILeaveRepository{ctrl: ctrl}
+	mock.recorder = &MockILeaveRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockILeaveRepository) EXPECT() *MockILeaveRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockILeaveRepository) Create(leave *models.Leave) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", leave)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockILeaveRepositoryMockRecorder) Create(leave interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockILeaveRepository)(nil).Create), leave)
+}
+
+// Decide mocks base method.
+func (m *MockILeaveRepository) Decide(id uuid.UUID, approval models.Approval) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decide", id, approval)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Decide indicates an expected call of Decide.
+func (mr *MockILeaveRepositoryMockRecorder) Decide(id, approval interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decide", reflect.TypeOf((*MockILeaveRepository)(nil).Decide), id, approval)
+}
+
+// GetByID mocks base method.
+func (m *MockILeaveRepository) GetByID(id uuid.UUID) (*models.Leave, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*models.Leave)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockILeaveRepositoryMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockILeaveRepository)(nil).GetByID), id)
+}
+
+// GetByUser mocks base method.
+func (m *MockILeaveRepository) GetByUser(userID uuid.UUID) ([]models.Leave, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUser", userID)
+	ret0, _ := ret[0].([]models.Leave)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUser indicates an expected call of GetByUser.
+func (mr *MockILeaveRepositoryMockRecorder) GetByUser(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUser", reflect.TypeOf((*MockILeaveRepository)(nil).GetByUser), userID)
+}
+
+// GetByUsers mocks base method.
+func (m *MockILeaveRepository) GetByUsers(userIDs []uuid.UUID, status string) ([]models.Leave, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUsers", userIDs, status)
+	ret0, _ := ret[0].([]models.Leave)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUsers indicates an expected call of GetByUsers.
+func (mr *MockILeaveRepositoryMockRecorder) GetByUsers(userIDs, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUsers", reflect.TypeOf((*MockILeaveRepository)(nil).GetByUsers), userIDs, status)
+}
+
+// GetDelegatedTo mocks base method.
+func (m *MockILeaveRepository) GetDelegatedTo(delegateID uuid.UUID, day time.Time) ([]models.Leave, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDelegatedTo", delegateID, day)
+	ret0, _ := ret[0].([]models.Leave)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDelegatedTo indicates an expected call of GetDelegatedTo.
+func (mr *MockILeaveRepositoryMockRecorder) GetDelegatedTo(delegateID, day interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDelegatedTo", reflect.TypeOf((*MockILeaveRepository)(nil).GetDelegatedTo), delegateID, day)
+}
+
+// HasOverlap mocks base method.
+func (m *MockILeaveRepository) HasOverlap(userID uuid.UUID, start, end time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasOverlap", userID, start, end)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasOverlap indicates an expected call of HasOverlap.
+func (mr *MockILeaveRepositoryMockRecorder) HasOverlap(userID, start, end interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasOverlap", reflect.TypeOf((*MockILeaveRepository)(nil).HasOverlap), userID, start, end)
+}
+
 // MockIPayrollRepository is a mock of IPayrollRepository interface.
 type MockIPayrollRepository struct {
 	ctrl     *gomock.Controller
@@ -442,6 +749,20 @@ func (mr *MockIPayrollRepositoryMockRecorder) Create(payroll interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIPayrollRepository)(nil).Create), payroll)
 }
 
+// CreateApproval mocks base method.
+func (m *MockIPayrollRepository) CreateApproval(approval *models.PayrollApproval) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateApproval", approval)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateApproval indicates an expected call of CreateApproval.
+func (mr *MockIPayrollRepositoryMockRecorder) CreateApproval(approval interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApproval", reflect.TypeOf((*MockIPayrollRepository)(nil).CreateApproval), approval)
+}
+
 // CreatePayrollItem mocks base method.
 func (m *MockIPayrollRepository) CreatePayrollItem(item *models.PayrollItem) error {
 	m.ctrl.T.Helper()
@@ -471,6 +792,36 @@ func (mr *MockIPayrollRepositoryMockRecorder) GetAllPayrollItemsByPeriod(periodI
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllPayrollItemsByPeriod", reflect.TypeOf((*MockIPayrollRepository)(nil).GetAllPayrollItemsByPeriod), periodID)
 }
 
+// GetApprovals mocks base method.
+func (m *MockIPayrollRepository) GetApprovals(payrollID uuid.UUID) ([]models.PayrollApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApprovals", payrollID)
+	ret0, _ := ret[0].([]models.PayrollApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApprovals indicates an expected call of GetApprovals.
+func (mr *MockIPayrollRepositoryMockRecorder) GetApprovals(payrollID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApprovals", reflect.TypeOf((*MockIPayrollRepository)(nil).GetApprovals), payrollID)
+}
+
+// GetByID mocks base method.
+func (m *MockIPayrollRepository) GetByID(id uuid.UUID) (*models.Payroll, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*models.Payroll)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockIPayrollRepositoryMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockIPayrollRepository)(nil).GetByID), id)
+}
+
 // GetByPeriodID mocks base method.
 func (m *MockIPayrollRepository) GetByPeriodID(periodID uuid.UUID) (*models.Payroll, error) {
 	m.ctrl.T.Helper()
@@ -486,6 +837,36 @@ func (mr *MockIPayrollRepositoryMockRecorder) GetByPeriodID(periodID interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByPeriodID", reflect.TypeOf((*MockIPayrollRepository)(nil).GetByPeriodID), periodID)
 }
 
+// GetDisbursementFile mocks base method.
+func (m *MockIPayrollRepository) GetDisbursementFile(payrollID uuid.UUID, reference string) (*models.DisbursementFile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDisbursementFile", payrollID, reference)
+	ret0, _ := ret[0].(*models.DisbursementFile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDisbursementFile indicates an expected call of GetDisbursementFile.
+func (mr *MockIPayrollRepositoryMockRecorder) GetDisbursementFile(payrollID, reference interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDisbursementFile", reflect.TypeOf((*MockIPayrollRepository)(nil).GetDisbursementFile), payrollID, reference)
+}
+
+// GetPayrollItemByID mocks base method.
+func (m *MockIPayrollRepository) GetPayrollItemByID(id uuid.UUID) (*models.PayrollItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPayrollItemByID", id)
+	ret0, _ := ret[0].(*models.PayrollItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPayrollItemByID indicates an expected call of GetPayrollItemByID.
+func (mr *MockIPayrollRepositoryMockRecorder) GetPayrollItemByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPayrollItemByID", reflect.TypeOf((*MockIPayrollRepository)(nil).GetPayrollItemByID), id)
+}
+
 // GetPayrollItemsByPeriodAndUser mocks base method.
 func (m *MockIPayrollRepository) GetPayrollItemsByPeriodAndUser(periodID, userID uuid.UUID) (*models.PayrollItem, error) {
 	m.ctrl.T.Helper()
@@ -501,31 +882,105 @@ func (mr *MockIPayrollRepositoryMockRecorder) GetPayrollItemsByPeriodAndUser(per
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPayrollItemsByPeriodAndUser", reflect.TypeOf((*MockIPayrollRepository)(nil).GetPayrollItemsByPeriodAndUser), periodID, userID)
 }
 
-// MockIAuditLogRepository is a mock of IAuditLogRepository interface.
-type MockIAuditLogRepository struct {
-	ctrl     *gomock.Controller
-	recorder *MockIAuditLogRepositoryMockRecorder
+// GetRunByPeriodID mocks base method.
+func (m *MockIPayrollRepository) GetRunByPeriodID(periodID uuid.UUID) (*models.Payroll, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRunByPeriodID", periodID)
+	ret0, _ := ret[0].(*models.Payroll)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// MockIAuditLogRepositoryMockRecorder is the mock recorder for MockIAuditLogRepository.
-type MockIAuditLogRepositoryMockRecorder struct {
-	mock *MockIAuditLogRepository
+// GetRunByPeriodID indicates an expected call of GetRunByPeriodID.
+func (mr *MockIPayrollRepositoryMockRecorder) GetRunByPeriodID(periodID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRunByPeriodID", reflect.TypeOf((*MockIPayrollRepository)(nil).GetRunByPeriodID), periodID)
 }
 
-// NewMockIAuditLogRepository creates a new mock instance.
-func NewMockIAuditLogRepository(ctrl *gomock.Controller) *MockIAuditLogRepository {
-	mock := &MockIAuditLogRepository{ctrl: ctrl}
-	mock.recorder = &MockIAuditLogRepositoryMockRecorder{mock}
-	return mock
+// GetRunByPeriodIDForUpdate mocks base method.
+func (m *MockIPayrollRepository) GetRunByPeriodIDForUpdate(periodID uuid.UUID) (*models.Payroll, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRunByPeriodIDForUpdate", periodID)
+	ret0, _ := ret[0].(*models.Payroll)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockIAuditLogRepository) EXPECT() *MockIAuditLogRepositoryMockRecorder {
-	return m.recorder
+// GetRunByPeriodIDForUpdate indicates an expected call of GetRunByPeriodIDForUpdate.
+func (mr *MockIPayrollRepositoryMockRecorder) GetRunByPeriodIDForUpdate(periodID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRunByPeriodIDForUpdate", reflect.TypeOf((*MockIPayrollRepository)(nil).GetRunByPeriodIDForUpdate), periodID)
 }
 
-// Create mocks base method.
-func (m *MockIAuditLogRepository) Create(log *models.AuditLog) error {
+// GetYearToDateItems mocks base method.
+func (m *MockIPayrollRepository) GetYearToDateItems(userID uuid.UUID, before time.Time) ([]models.PayrollItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetYearToDateItems", userID, before)
+	ret0, _ := ret[0].([]models.PayrollItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetYearToDateItems indicates an expected call of GetYearToDateItems.
+func (mr *MockIPayrollRepositoryMockRecorder) GetYearToDateItems(userID, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetYearToDateItems", reflect.TypeOf((*MockIPayrollRepository)(nil).GetYearToDateItems), userID, before)
+}
+
+// SaveDisbursementFile mocks base method.
+func (m *MockIPayrollRepository) SaveDisbursementFile(file *models.DisbursementFile, itemIDs []uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveDisbursementFile", file, itemIDs)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveDisbursementFile indicates an expected call of SaveDisbursementFile.
+func (mr *MockIPayrollRepositoryMockRecorder) SaveDisbursementFile(file, itemIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveDisbursementFile", reflect.TypeOf((*MockIPayrollRepository)(nil).SaveDisbursementFile), file, itemIDs)
+}
+
+// Update mocks base method.
+func (m *MockIPayrollRepository) Update(payroll *models.Payroll) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", payroll)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockIPayrollRepositoryMockRecorder) Update(payroll interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockIPayrollRepository)(nil).Update), payroll)
+}
+
+// MockIAuditLogRepository is a mock of IAuditLogRepository interface.
+type MockIAuditLogRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAuditLogRepositoryMockRecorder
+}
+
+// MockIAuditLogRepositoryMockRecorder is the mock recorder for MockIAuditLogRepository.
+type MockIAuditLogRepositoryMockRecorder struct {
+	mock *MockIAuditLogRepository
+}
+
+// NewMockIAuditLogRepository creates a new mock instance.
+func NewMockIAuditLogRepository(ctrl *gomock.Controller) *MockIAuditLogRepository {
+	mock := &MockIAuditLogRepository{ctrl: ctrl}
+	mock.recorder = &MockIAuditLogRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAuditLogRepository) EXPECT() *MockIAuditLogRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockIAuditLogRepository) Create(log *models.AuditLog) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Create", log)
 	ret0, _ := ret[0].(error)
@@ -538,6 +993,21 @@ func (mr *MockIAuditLogRepositoryMockRecorder) Create(log interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIAuditLogRepository)(nil).Create), log)
 }
 
+// GetActivity mocks base method.
+func (m *MockIAuditLogRepository) GetActivity(userID uuid.UUID, limit int) ([]models.AuditLog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActivity", userID, limit)
+	ret0, _ := ret[0].([]models.AuditLog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActivity indicates an expected call of GetActivity.
+func (mr *MockIAuditLogRepositoryMockRecorder) GetActivity(userID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActivity", reflect.TypeOf((*MockIAuditLogRepository)(nil).GetActivity), userID, limit)
+}
+
 // GetByTableAndRecord mocks base method.
 func (m *MockIAuditLogRepository) GetByTableAndRecord(tableName string, recordID uuid.UUID) ([]models.AuditLog, error) {
 	m.ctrl.T.Helper()
@@ -552,3 +1022,1215 @@ func (mr *MockIAuditLogRepositoryMockRecorder) GetByTableAndRecord(tableName, re
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByTableAndRecord", reflect.TypeOf((*MockIAuditLogRepository)(nil).GetByTableAndRecord), tableName, recordID)
 }
+
+// MockIIdempotencyKeyRepository is a mock of IIdempotencyKeyRepository interface.
+type MockIIdempotencyKeyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIIdempotencyKeyRepositoryMockRecorder
+}
+
+// MockIIdempotencyKeyRepositoryMockRecorder is the mock recorder for MockIIdempotencyKeyRepository.
+type MockIIdempotencyKeyRepositoryMockRecorder struct {
+	mock *MockIIdempotencyKeyRepository
+}
+
+// NewMockIIdempotencyKeyRepository creates a new mock instance.
+func NewMockIIdempotencyKeyRepository(ctrl *gomock.Controller) *MockIIdempotencyKeyRepository {
+	mock := &MockIIdempotencyKeyRepository{ctrl: ctrl}
+	mock.recorder = &MockIIdempotencyKeyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIIdempotencyKeyRepository) EXPECT() *MockIIdempotencyKeyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateIfAbsent mocks base method.
+func (m *MockIIdempotencyKeyRepository) CreateIfAbsent(record *models.IdempotencyKey) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIfAbsent", record)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateIfAbsent indicates an expected call of CreateIfAbsent.
+func (mr *MockIIdempotencyKeyRepositoryMockRecorder) CreateIfAbsent(record interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIfAbsent", reflect.TypeOf((*MockIIdempotencyKeyRepository)(nil).CreateIfAbsent), record)
+}
+
+// Delete mocks base method.
+func (m *MockIIdempotencyKeyRepository) Delete(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockIIdempotencyKeyRepositoryMockRecorder) Delete(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockIIdempotencyKeyRepository)(nil).Delete), id)
+}
+
+// GetByOwnerAndKey mocks base method.
+func (m *MockIIdempotencyKeyRepository) GetByOwnerAndKey(userID, apiKeyID *uuid.UUID, key string) (*models.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByOwnerAndKey", userID, apiKeyID, key)
+	ret0, _ := ret[0].(*models.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByOwnerAndKey indicates an expected call of GetByOwnerAndKey.
+func (mr *MockIIdempotencyKeyRepositoryMockRecorder) GetByOwnerAndKey(userID, apiKeyID, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByOwnerAndKey", reflect.TypeOf((*MockIIdempotencyKeyRepository)(nil).GetByOwnerAndKey), userID, apiKeyID, key)
+}
+
+// Update mocks base method.
+func (m *MockIIdempotencyKeyRepository) Update(record *models.IdempotencyKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", record)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockIIdempotencyKeyRepositoryMockRecorder) Update(record interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockIIdempotencyKeyRepository)(nil).Update), record)
+}
+
+// MockILanguageRepository is a mock of ILanguageRepository interface.
+type MockILanguageRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockILanguageRepositoryMockRecorder
+}
+
+// MockILanguageRepositoryMockRecorder is the mock recorder for MockILanguageRepository.
+type MockILanguageRepositoryMockRecorder struct {
+	mock *MockILanguageRepository
+}
+
+// NewMockILanguageRepository creates a new mock instance.
+func NewMockILanguageRepository(ctrl *gomock.Controller) *MockILanguageRepository {
+	mock := &MockILanguageRepository{ctrl: ctrl}
+	mock.recorder = &MockILanguageRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockILanguageRepository) EXPECT() *MockILanguageRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetAll mocks base method.
+func (m *MockILanguageRepository) GetAll() ([]models.Language, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll")
+	ret0, _ := ret[0].([]models.Language)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockILanguageRepositoryMockRecorder) GetAll() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockILanguageRepository)(nil).GetAll))
+}
+
+// Upsert mocks base method.
+func (m *MockILanguageRepository) Upsert(language *models.Language) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", language)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockILanguageRepositoryMockRecorder) Upsert(language interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockILanguageRepository)(nil).Upsert), language)
+}
+
+// MockIEmailDeliveryRepository is a mock of IEmailDeliveryRepository interface.
+type MockIEmailDeliveryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIEmailDeliveryRepositoryMockRecorder
+}
+
+// MockIEmailDeliveryRepositoryMockRecorder is the mock recorder for MockIEmailDeliveryRepository.
+type MockIEmailDeliveryRepositoryMockRecorder struct {
+	mock *MockIEmailDeliveryRepository
+}
+
+// NewMockIEmailDeliveryRepository creates a new mock instance.
+func NewMockIEmailDeliveryRepository(ctrl *gomock.Controller) *MockIEmailDeliveryRepository {
+	mock := &MockIEmailDeliveryRepository{ctrl: ctrl}
+	mock.recorder = &MockIEmailDeliveryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIEmailDeliveryRepository) EXPECT() *MockIEmailDeliveryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimDue mocks base method.
+func (m *MockIEmailDeliveryRepository) ClaimDue(now time.Time, limit int, lease time.Duration) ([]models.EmailDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDue", now, limit, lease)
+	ret0, _ := ret[0].([]models.EmailDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDue indicates an expected call of ClaimDue.
+func (mr *MockIEmailDeliveryRepositoryMockRecorder) ClaimDue(now, limit, lease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDue", reflect.TypeOf((*MockIEmailDeliveryRepository)(nil).ClaimDue), now, limit, lease)
+}
+
+// CreateIfAbsent mocks base method.
+func (m *MockIEmailDeliveryRepository) CreateIfAbsent(delivery *models.EmailDelivery) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIfAbsent", delivery)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateIfAbsent indicates an expected call of CreateIfAbsent.
+func (mr *MockIEmailDeliveryRepositoryMockRecorder) CreateIfAbsent(delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIfAbsent", reflect.TypeOf((*MockIEmailDeliveryRepository)(nil).CreateIfAbsent), delivery)
+}
+
+// GetByID mocks base method.
+func (m *MockIEmailDeliveryRepository) GetByID(id uuid.UUID) (*models.EmailDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*models.EmailDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockIEmailDeliveryRepositoryMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockIEmailDeliveryRepository)(nil).GetByID), id)
+}
+
+// GetByPayrollID mocks base method.
+func (m *MockIEmailDeliveryRepository) GetByPayrollID(payrollID uuid.UUID) ([]models.EmailDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByPayrollID", payrollID)
+	ret0, _ := ret[0].([]models.EmailDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByPayrollID indicates an expected call of GetByPayrollID.
+func (mr *MockIEmailDeliveryRepositoryMockRecorder) GetByPayrollID(payrollID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByPayrollID", reflect.TypeOf((*MockIEmailDeliveryRepository)(nil).GetByPayrollID), payrollID)
+}
+
+// Update mocks base method.
+func (m *MockIEmailDeliveryRepository) Update(delivery *models.EmailDelivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", delivery)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockIEmailDeliveryRepositoryMockRecorder) Update(delivery interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockIEmailDeliveryRepository)(nil).Update), delivery)
+}
+
+// MockIBankTransactionRepository is a mock of IBankTransactionRepository interface.
+type MockIBankTransactionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIBankTransactionRepositoryMockRecorder
+}
+
+// MockIBankTransactionRepositoryMockRecorder is the mock recorder for MockIBankTransactionRepository.
+type MockIBankTransactionRepositoryMockRecorder struct {
+	mock *MockIBankTransactionRepository
+}
+
+// NewMockIBankTransactionRepository creates a new mock instance.
+func NewMockIBankTransactionRepository(ctrl *gomock.Controller) *MockIBankTransactionRepository {
+	mock := &MockIBankTransactionRepository{ctrl: ctrl}
+	mock.recorder = &MockIBankTransactionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIBankTransactionRepository) EXPECT() *MockIBankTransactionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetByPeriodID mocks base method.
+func (m *MockIBankTransactionRepository) GetByPeriodID(periodID uuid.UUID) ([]models.BankTransaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByPeriodID", periodID)
+	ret0, _ := ret[0].([]models.BankTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByPeriodID indicates an expected call of GetByPeriodID.
+func (mr *MockIBankTransactionRepositoryMockRecorder) GetByPeriodID(periodID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByPeriodID", reflect.TypeOf((*MockIBankTransactionRepository)(nil).GetByPeriodID), periodID)
+}
+
+// Import mocks base method.
+func (m *MockIBankTransactionRepository) Import(transaction *models.BankTransaction, payment *models.PayrollItem) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", transaction, payment)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockIBankTransactionRepositoryMockRecorder) Import(transaction, payment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockIBankTransactionRepository)(nil).Import), transaction, payment)
+}
+
+// MockISessionRepository is a mock of ISessionRepository interface.
+type MockISessionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockISessionRepositoryMockRecorder
+}
+
+// MockISessionRepositoryMockRecorder is the mock recorder for MockISessionRepository.
+type MockISessionRepositoryMockRecorder struct {
+	mock *MockISessionRepository
+}
+
+// NewMockISessionRepository creates a new mock instance.
+func NewMockISessionRepository(ctrl *gomock.Controller) *MockISessionRepository {
+	mock := &MockISessionRepository{ctrl: ctrl}
+	mock.recorder = &MockISessionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockISessionRepository) EXPECT() *MockISessionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockISessionRepository) Create(session *models.Session) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", session)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockISessionRepositoryMockRecorder) Create(session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockISessionRepository)(nil).Create), session)
+}
+
+// CreateRefreshToken mocks base method.
+func (m *MockISessionRepository) CreateRefreshToken(token *models.RefreshToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRefreshToken", token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRefreshToken indicates an expected call of CreateRefreshToken.
+func (mr *MockISessionRepositoryMockRecorder) CreateRefreshToken(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRefreshToken", reflect.TypeOf((*MockISessionRepository)(nil).CreateRefreshToken), token)
+}
+
+// GetByID mocks base method.
+func (m *MockISessionRepository) GetByID(id uuid.UUID) (*models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockISessionRepositoryMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockISessionRepository)(nil).GetByID), id)
+}
+
+// GetRefreshTokenByHash mocks base method.
+func (m *MockISessionRepository) GetRefreshTokenByHash(hash string) (*models.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefreshTokenByHash", hash)
+	ret0, _ := ret[0].(*models.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefreshTokenByHash indicates an expected call of GetRefreshTokenByHash.
+func (mr *MockISessionRepositoryMockRecorder) GetRefreshTokenByHash(hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefreshTokenByHash", reflect.TypeOf((*MockISessionRepository)(nil).GetRefreshTokenByHash), hash)
+}
+
+// MarkRefreshTokenUsed mocks base method.
+func (m *MockISessionRepository) MarkRefreshTokenUsed(id uuid.UUID, at time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRefreshTokenUsed", id, at)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkRefreshTokenUsed indicates an expected call of MarkRefreshTokenUsed.
+func (mr *MockISessionRepositoryMockRecorder) MarkRefreshTokenUsed(id, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRefreshTokenUsed", reflect.TypeOf((*MockISessionRepository)(nil).MarkRefreshTokenUsed), id, at)
+}
+
+// RevokeAllForUser mocks base method.
+func (m *MockISessionRepository) RevokeAllForUser(userID uuid.UUID, reason string, at time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAllForUser", userID, reason, at)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeAllForUser indicates an expected call of RevokeAllForUser.
+func (mr *MockISessionRepositoryMockRecorder) RevokeAllForUser(userID, reason, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAllForUser", reflect.TypeOf((*MockISessionRepository)(nil).RevokeAllForUser), userID, reason, at)
+}
+
+// RevokeOthersForUser mocks base method.
+func (m *MockISessionRepository) RevokeOthersForUser(userID, keepSessionID uuid.UUID, reason string, at time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeOthersForUser", userID, keepSessionID, reason, at)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeOthersForUser indicates an expected call of RevokeOthersForUser.
+func (mr *MockISessionRepositoryMockRecorder) RevokeOthersForUser(userID, keepSessionID, reason, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOthersForUser", reflect.TypeOf((*MockISessionRepository)(nil).RevokeOthersForUser), userID, keepSessionID, reason, at)
+}
+
+// Update mocks base method.
+func (m *MockISessionRepository) Update(session *models.Session) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", session)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockISessionRepositoryMockRecorder) Update(session interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockISessionRepository)(nil).Update), session)
+}
+
+// MockIMFARepository is a mock of IMFARepository interface.
+type MockIMFARepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIMFARepositoryMockRecorder
+}
+
+// MockIMFARepositoryMockRecorder is the mock recorder for MockIMFARepository.
+type MockIMFARepositoryMockRecorder struct {
+	mock *MockIMFARepository
+}
+
+// NewMockIMFARepository creates a new mock instance.
+func NewMockIMFARepository(ctrl *gomock.Controller) *MockIMFARepository {
+	mock := &MockIMFARepository{ctrl: ctrl}
+	mock.recorder = &MockIMFARepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIMFARepository) EXPECT() *MockIMFARepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateChallenge mocks base method.
+func (m *MockIMFARepository) CreateChallenge(challenge *models.MFAChallenge) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateChallenge", challenge)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateChallenge indicates an expected call of CreateChallenge.
+func (mr *MockIMFARepositoryMockRecorder) CreateChallenge(challenge interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChallenge", reflect.TypeOf((*MockIMFARepository)(nil).CreateChallenge), challenge)
+}
+
+// DeleteRecoveryCodes mocks base method.
+func (m *MockIMFARepository) DeleteRecoveryCodes(userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRecoveryCodes", userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRecoveryCodes indicates an expected call of DeleteRecoveryCodes.
+func (mr *MockIMFARepositoryMockRecorder) DeleteRecoveryCodes(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRecoveryCodes", reflect.TypeOf((*MockIMFARepository)(nil).DeleteRecoveryCodes), userID)
+}
+
+// GetChallengeByHash mocks base method.
+func (m *MockIMFARepository) GetChallengeByHash(hash string) (*models.MFAChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChallengeByHash", hash)
+	ret0, _ := ret[0].(*models.MFAChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChallengeByHash indicates an expected call of GetChallengeByHash.
+func (mr *MockIMFARepositoryMockRecorder) GetChallengeByHash(hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChallengeByHash", reflect.TypeOf((*MockIMFARepository)(nil).GetChallengeByHash), hash)
+}
+
+// GetUnusedRecoveryCodes mocks base method.
+func (m *MockIMFARepository) GetUnusedRecoveryCodes(userID uuid.UUID) ([]models.MFARecoveryCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnusedRecoveryCodes", userID)
+	ret0, _ := ret[0].([]models.MFARecoveryCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnusedRecoveryCodes indicates an expected call of GetUnusedRecoveryCodes.
+func (mr *MockIMFARepositoryMockRecorder) GetUnusedRecoveryCodes(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnusedRecoveryCodes", reflect.TypeOf((*MockIMFARepository)(nil).GetUnusedRecoveryCodes), userID)
+}
+
+// MarkChallengeUsed mocks base method.
+func (m *MockIMFARepository) MarkChallengeUsed(id uuid.UUID, at time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkChallengeUsed", id, at)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkChallengeUsed indicates an expected call of MarkChallengeUsed.
+func (mr *MockIMFARepositoryMockRecorder) MarkChallengeUsed(id, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkChallengeUsed", reflect.TypeOf((*MockIMFARepository)(nil).MarkChallengeUsed), id, at)
+}
+
+// MarkRecoveryCodeUsed mocks base method.
+func (m *MockIMFARepository) MarkRecoveryCodeUsed(id uuid.UUID, at time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRecoveryCodeUsed", id, at)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkRecoveryCodeUsed indicates an expected call of MarkRecoveryCodeUsed.
+func (mr *MockIMFARepositoryMockRecorder) MarkRecoveryCodeUsed(id, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRecoveryCodeUsed", reflect.TypeOf((*MockIMFARepository)(nil).MarkRecoveryCodeUsed), id, at)
+}
+
+// MarkTOTPStepUsed mocks base method.
+func (m *MockIMFARepository) MarkTOTPStepUsed(userID uuid.UUID, step int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkTOTPStepUsed", userID, step)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkTOTPStepUsed indicates an expected call of MarkTOTPStepUsed.
+func (mr *MockIMFARepositoryMockRecorder) MarkTOTPStepUsed(userID, step interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkTOTPStepUsed", reflect.TypeOf((*MockIMFARepository)(nil).MarkTOTPStepUsed), userID, step)
+}
+
+// RecordFailedAttempt mocks base method.
+func (m *MockIMFARepository) RecordFailedAttempt(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordFailedAttempt", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordFailedAttempt indicates an expected call of RecordFailedAttempt.
+func (mr *MockIMFARepositoryMockRecorder) RecordFailedAttempt(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordFailedAttempt", reflect.TypeOf((*MockIMFARepository)(nil).RecordFailedAttempt), id)
+}
+
+// ReplaceRecoveryCodes mocks base method.
+func (m *MockIMFARepository) ReplaceRecoveryCodes(userID uuid.UUID, codes []models.MFARecoveryCode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceRecoveryCodes", userID, codes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceRecoveryCodes indicates an expected call of ReplaceRecoveryCodes.
+func (mr *MockIMFARepositoryMockRecorder) ReplaceRecoveryCodes(userID, codes interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceRecoveryCodes", reflect.TypeOf((*MockIMFARepository)(nil).ReplaceRecoveryCodes), userID, codes)
+}
+
+// MockISSORepository is a mock of ISSORepository interface.
+type MockISSORepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockISSORepositoryMockRecorder
+}
+
+// MockISSORepositoryMockRecorder is the mock recorder for MockISSORepository.
+type MockISSORepositoryMockRecorder struct {
+	mock *MockISSORepository
+}
+
+// NewMockISSORepository creates a new mock instance.
+func NewMockISSORepository(ctrl *gomock.Controller) *MockISSORepository {
+	mock := &MockISSORepository{ctrl: ctrl}
+	mock.recorder = &MockISSORepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockISSORepository) EXPECT() *MockISSORepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateLogin mocks base method.
+func (m *MockISSORepository) CreateLogin(login *models.SSOLogin) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLogin", login)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateLogin indicates an expected call of CreateLogin.
+func (mr *MockISSORepositoryMockRecorder) CreateLogin(login interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLogin", reflect.TypeOf((*MockISSORepository)(nil).CreateLogin), login)
+}
+
+// GetLoginByStateHash mocks base method.
+func (m *MockISSORepository) GetLoginByStateHash(hash string) (*models.SSOLogin, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLoginByStateHash", hash)
+	ret0, _ := ret[0].(*models.SSOLogin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLoginByStateHash indicates an expected call of GetLoginByStateHash.
+func (mr *MockISSORepositoryMockRecorder) GetLoginByStateHash(hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLoginByStateHash", reflect.TypeOf((*MockISSORepository)(nil).GetLoginByStateHash), hash)
+}
+
+// MarkLoginUsed mocks base method.
+func (m *MockISSORepository) MarkLoginUsed(id uuid.UUID, at time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkLoginUsed", id, at)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkLoginUsed indicates an expected call of MarkLoginUsed.
+func (mr *MockISSORepositoryMockRecorder) MarkLoginUsed(id, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkLoginUsed", reflect.TypeOf((*MockISSORepository)(nil).MarkLoginUsed), id, at)
+}
+
+// MockIAPIKeyRepository is a mock of IAPIKeyRepository interface.
+type MockIAPIKeyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAPIKeyRepositoryMockRecorder
+}
+
+// MockIAPIKeyRepositoryMockRecorder is the mock recorder for MockIAPIKeyRepository.
+type MockIAPIKeyRepositoryMockRecorder struct {
+	mock *MockIAPIKeyRepository
+}
+
+// NewMockIAPIKeyRepository creates a new mock instance.
+func NewMockIAPIKeyRepository(ctrl *gomock.Controller) *MockIAPIKeyRepository {
+	mock := &MockIAPIKeyRepository{ctrl: ctrl}
+	mock.recorder = &MockIAPIKeyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAPIKeyRepository) EXPECT() *MockIAPIKeyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockIAPIKeyRepository) Create(key *models.APIKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockIAPIKeyRepositoryMockRecorder) Create(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIAPIKeyRepository)(nil).Create), key)
+}
+
+// GetAll mocks base method.
+func (m *MockIAPIKeyRepository) GetAll() ([]models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll")
+	ret0, _ := ret[0].([]models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockIAPIKeyRepositoryMockRecorder) GetAll() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockIAPIKeyRepository)(nil).GetAll))
+}
+
+// GetByHash mocks base method.
+func (m *MockIAPIKeyRepository) GetByHash(hash string) (*models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByHash", hash)
+	ret0, _ := ret[0].(*models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByHash indicates an expected call of GetByHash.
+func (mr *MockIAPIKeyRepositoryMockRecorder) GetByHash(hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByHash", reflect.TypeOf((*MockIAPIKeyRepository)(nil).GetByHash), hash)
+}
+
+// GetByID mocks base method.
+func (m *MockIAPIKeyRepository) GetByID(id uuid.UUID) (*models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockIAPIKeyRepositoryMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockIAPIKeyRepository)(nil).GetByID), id)
+}
+
+// MarkUsed mocks base method.
+func (m *MockIAPIKeyRepository) MarkUsed(id uuid.UUID, ipAddress string, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkUsed", id, ipAddress, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkUsed indicates an expected call of MarkUsed.
+func (mr *MockIAPIKeyRepositoryMockRecorder) MarkUsed(id, ipAddress, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkUsed", reflect.TypeOf((*MockIAPIKeyRepository)(nil).MarkUsed), id, ipAddress, at)
+}
+
+// Revoke mocks base method.
+func (m *MockIAPIKeyRepository) Revoke(id, revokedBy uuid.UUID, at time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", id, revokedBy, at)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockIAPIKeyRepositoryMockRecorder) Revoke(id, revokedBy, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockIAPIKeyRepository)(nil).Revoke), id, revokedBy, at)
+}
+
+// MockILoginThrottleRepository is a mock of ILoginThrottleRepository interface.
+type MockILoginThrottleRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockILoginThrottleRepositoryMockRecorder
+}
+
+// MockILoginThrottleRepositoryMockRecorder is the mock recorder for MockILoginThrottleRepository.
+type MockILoginThrottleRepositoryMockRecorder struct {
+	mock *MockILoginThrottleRepository
+}
+
+// NewMockILoginThrottleRepository creates a new mock instance.
+func NewMockILoginThrottleRepository(ctrl *gomock.Controller) *MockILoginThrottleRepository {
+	mock := &MockILoginThrottleRepository{ctrl: ctrl}
+	mock.recorder = &MockILoginThrottleRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockILoginThrottleRepository) EXPECT() *MockILoginThrottleRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockILoginThrottleRepository) Delete(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockILoginThrottleRepositoryMockRecorder) Delete(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockILoginThrottleRepository)(nil).Delete), id)
+}
+
+// DeleteByKey mocks base method.
+func (m *MockILoginThrottleRepository) DeleteByKey(scope, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteByKey", scope, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteByKey indicates an expected call of DeleteByKey.
+func (mr *MockILoginThrottleRepositoryMockRecorder) DeleteByKey(scope, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteByKey", reflect.TypeOf((*MockILoginThrottleRepository)(nil).DeleteByKey), scope, key)
+}
+
+// GetByID mocks base method.
+func (m *MockILoginThrottleRepository) GetByID(id uuid.UUID) (*models.LoginThrottle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*models.LoginThrottle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockILoginThrottleRepositoryMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockILoginThrottleRepository)(nil).GetByID), id)
+}
+
+// GetByKey mocks base method.
+func (m *MockILoginThrottleRepository) GetByKey(scope, key string) (*models.LoginThrottle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByKey", scope, key)
+	ret0, _ := ret[0].(*models.LoginThrottle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByKey indicates an expected call of GetByKey.
+func (mr *MockILoginThrottleRepositoryMockRecorder) GetByKey(scope, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByKey", reflect.TypeOf((*MockILoginThrottleRepository)(nil).GetByKey), scope, key)
+}
+
+// GetLocked mocks base method.
+func (m *MockILoginThrottleRepository) GetLocked(at time.Time) ([]models.LoginThrottle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLocked", at)
+	ret0, _ := ret[0].([]models.LoginThrottle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLocked indicates an expected call of GetLocked.
+func (mr *MockILoginThrottleRepositoryMockRecorder) GetLocked(at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLocked", reflect.TypeOf((*MockILoginThrottleRepository)(nil).GetLocked), at)
+}
+
+// Lock mocks base method.
+func (m *MockILoginThrottleRepository) Lock(id uuid.UUID, until time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lock", id, until)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Lock indicates an expected call of Lock.
+func (mr *MockILoginThrottleRepositoryMockRecorder) Lock(id, until interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lock", reflect.TypeOf((*MockILoginThrottleRepository)(nil).Lock), id, until)
+}
+
+// RecordFailure mocks base method.
+func (m *MockILoginThrottleRepository) RecordFailure(scope, key string, at time.Time, window time.Duration) (*models.LoginThrottle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordFailure", scope, key, at, window)
+	ret0, _ := ret[0].(*models.LoginThrottle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordFailure indicates an expected call of RecordFailure.
+func (mr *MockILoginThrottleRepositoryMockRecorder) RecordFailure(scope, key, at, window interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordFailure", reflect.TypeOf((*MockILoginThrottleRepository)(nil).RecordFailure), scope, key, at, window)
+}
+
+// MockIPasswordRepository is a mock of IPasswordRepository interface.
+type MockIPasswordRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIPasswordRepositoryMockRecorder
+}
+
+// MockIPasswordRepositoryMockRecorder is the mock recorder for MockIPasswordRepository.
+type MockIPasswordRepositoryMockRecorder struct {
+	mock *MockIPasswordRepository
+}
+
+// NewMockIPasswordRepository creates a new mock instance.
+func NewMockIPasswordRepository(ctrl *gomock.Controller) *MockIPasswordRepository {
+	mock := &MockIPasswordRepository{ctrl: ctrl}
+	mock.recorder = &MockIPasswordRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIPasswordRepository) EXPECT() *MockIPasswordRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateResetToken mocks base method.
+func (m *MockIPasswordRepository) CreateResetToken(token *models.PasswordResetToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateResetToken", token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateResetToken indicates an expected call of CreateResetToken.
+func (mr *MockIPasswordRepositoryMockRecorder) CreateResetToken(token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateResetToken", reflect.TypeOf((*MockIPasswordRepository)(nil).CreateResetToken), token)
+}
+
+// GetHistory mocks base method.
+func (m *MockIPasswordRepository) GetHistory(userID uuid.UUID, limit int) ([]models.PasswordHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistory", userID, limit)
+	ret0, _ := ret[0].([]models.PasswordHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory.
+func (mr *MockIPasswordRepositoryMockRecorder) GetHistory(userID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockIPasswordRepository)(nil).GetHistory), userID, limit)
+}
+
+// GetResetTokenByHash mocks base method.
+func (m *MockIPasswordRepository) GetResetTokenByHash(hash string) (*models.PasswordResetToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetResetTokenByHash", hash)
+	ret0, _ := ret[0].(*models.PasswordResetToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetResetTokenByHash indicates an expected call of GetResetTokenByHash.
+func (mr *MockIPasswordRepositoryMockRecorder) GetResetTokenByHash(hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResetTokenByHash", reflect.TypeOf((*MockIPasswordRepository)(nil).GetResetTokenByHash), hash)
+}
+
+// InvalidateResetTokens mocks base method.
+func (m *MockIPasswordRepository) InvalidateResetTokens(userID uuid.UUID, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvalidateResetTokens", userID, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InvalidateResetTokens indicates an expected call of InvalidateResetTokens.
+func (mr *MockIPasswordRepositoryMockRecorder) InvalidateResetTokens(userID, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateResetTokens", reflect.TypeOf((*MockIPasswordRepository)(nil).InvalidateResetTokens), userID, at)
+}
+
+// MarkResetTokenUsed mocks base method.
+func (m *MockIPasswordRepository) MarkResetTokenUsed(id uuid.UUID, at time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkResetTokenUsed", id, at)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkResetTokenUsed indicates an expected call of MarkResetTokenUsed.
+func (mr *MockIPasswordRepositoryMockRecorder) MarkResetTokenUsed(id, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkResetTokenUsed", reflect.TypeOf((*MockIPasswordRepository)(nil).MarkResetTokenUsed), id, at)
+}
+
+// SetPassword mocks base method.
+func (m *MockIPasswordRepository) SetPassword(user *models.User, previous *models.PasswordHistory, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPassword", user, previous, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPassword indicates an expected call of SetPassword.
+func (mr *MockIPasswordRepositoryMockRecorder) SetPassword(user, previous, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPassword", reflect.TypeOf((*MockIPasswordRepository)(nil).SetPassword), user, previous, at)
+}
+
+// MockIRoleRepository is a mock of IRoleRepository interface.
+type MockIRoleRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIRoleRepositoryMockRecorder
+}
+
+// MockIRoleRepositoryMockRecorder is the mock recorder for MockIRoleRepository.
+type MockIRoleRepositoryMockRecorder struct {
+	mock *MockIRoleRepository
+}
+
+// NewMockIRoleRepository creates a new mock instance.
+func NewMockIRoleRepository(ctrl *gomock.Controller) *MockIRoleRepository {
+	mock := &MockIRoleRepository{ctrl: ctrl}
+	mock.recorder = &MockIRoleRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIRoleRepository) EXPECT() *MockIRoleRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountHolders mocks base method.
+func (m *MockIRoleRepository) CountHolders(roleID uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountHolders", roleID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountHolders indicates an expected call of CountHolders.
+func (mr *MockIRoleRepositoryMockRecorder) CountHolders(roleID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountHolders", reflect.TypeOf((*MockIRoleRepository)(nil).CountHolders), roleID)
+}
+
+// CountUsersWithPermission mocks base method.
+func (m *MockIRoleRepository) CountUsersWithPermission(permission string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUsersWithPermission", permission)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUsersWithPermission indicates an expected call of CountUsersWithPermission.
+func (mr *MockIRoleRepositoryMockRecorder) CountUsersWithPermission(permission interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUsersWithPermission", reflect.TypeOf((*MockIRoleRepository)(nil).CountUsersWithPermission), permission)
+}
+
+// Create mocks base method.
+func (m *MockIRoleRepository) Create(role *models.Role) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockIRoleRepositoryMockRecorder) Create(role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockIRoleRepository)(nil).Create), role)
+}
+
+// Delete mocks base method.
+func (m *MockIRoleRepository) Delete(id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockIRoleRepositoryMockRecorder) Delete(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockIRoleRepository)(nil).Delete), id)
+}
+
+// GetAll mocks base method.
+func (m *MockIRoleRepository) GetAll() ([]models.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll")
+	ret0, _ := ret[0].([]models.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockIRoleRepositoryMockRecorder) GetAll() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockIRoleRepository)(nil).GetAll))
+}
+
+// GetByID mocks base method.
+func (m *MockIRoleRepository) GetByID(id uuid.UUID) (*models.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", id)
+	ret0, _ := ret[0].(*models.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockIRoleRepositoryMockRecorder) GetByID(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockIRoleRepository)(nil).GetByID), id)
+}
+
+// GetByNames mocks base method.
+func (m *MockIRoleRepository) GetByNames(names []string) ([]models.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByNames", names)
+	ret0, _ := ret[0].([]models.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByNames indicates an expected call of GetByNames.
+func (mr *MockIRoleRepositoryMockRecorder) GetByNames(names interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByNames", reflect.TypeOf((*MockIRoleRepository)(nil).GetByNames), names)
+}
+
+// GetUserPermissions mocks base method.
+func (m *MockIRoleRepository) GetUserPermissions(userID uuid.UUID) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserPermissions", userID)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserPermissions indicates an expected call of GetUserPermissions.
+func (mr *MockIRoleRepositoryMockRecorder) GetUserPermissions(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserPermissions", reflect.TypeOf((*MockIRoleRepository)(nil).GetUserPermissions), userID)
+}
+
+// GetUserRoles mocks base method.
+func (m *MockIRoleRepository) GetUserRoles(userID uuid.UUID) ([]models.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRoles", userID)
+	ret0, _ := ret[0].([]models.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserRoles indicates an expected call of GetUserRoles.
+func (mr *MockIRoleRepositoryMockRecorder) GetUserRoles(userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRoles", reflect.TypeOf((*MockIRoleRepository)(nil).GetUserRoles), userID)
+}
+
+// SetUserRoles mocks base method.
+func (m *MockIRoleRepository) SetUserRoles(userID uuid.UUID, roleIDs []uuid.UUID, grantedBy uuid.UUID, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserRoles", userID, roleIDs, grantedBy, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUserRoles indicates an expected call of SetUserRoles.
+func (mr *MockIRoleRepositoryMockRecorder) SetUserRoles(userID, roleIDs, grantedBy, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserRoles", reflect.TypeOf((*MockIRoleRepository)(nil).SetUserRoles), userID, roleIDs, grantedBy, at)
+}
+
+// Update mocks base method.
+func (m *MockIRoleRepository) Update(role *models.Role) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockIRoleRepositoryMockRecorder) Update(role interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockIRoleRepository)(nil).Update), role)
+}
+
+// MockITaxCertificateRepository is a mock of ITaxCertificateRepository interface.
+type MockITaxCertificateRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockITaxCertificateRepositoryMockRecorder
+}
+
+// MockITaxCertificateRepositoryMockRecorder is the mock recorder for MockITaxCertificateRepository.
+type MockITaxCertificateRepositoryMockRecorder struct {
+	mock *MockITaxCertificateRepository
+}
+
+// NewMockITaxCertificateRepository creates a new mock instance.
+func NewMockITaxCertificateRepository(ctrl *gomock.Controller) *MockITaxCertificateRepository {
+	mock := &MockITaxCertificateRepository{ctrl: ctrl}
+	mock.recorder = &MockITaxCertificateRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockITaxCertificateRepository) EXPECT() *MockITaxCertificateRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Issue mocks base method.
+func (m *MockITaxCertificateRepository) Issue(userID uuid.UUID, year, month int, at time.Time) (*models.TaxCertificateIssue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Issue", userID, year, month, at)
+	ret0, _ := ret[0].(*models.TaxCertificateIssue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Issue indicates an expected call of Issue.
+func (mr *MockITaxCertificateRepositoryMockRecorder) Issue(userID, year, month, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Issue", reflect.TypeOf((*MockITaxCertificateRepository)(nil).Issue), userID, year, month, at)
+}
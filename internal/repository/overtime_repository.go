@@ -35,6 +35,30 @@ func (r *overtimeRepository) GetByUserAndDate(userID uuid.UUID, date time.Time)
 	return &overtime, nil
 }
 
+func (r *overtimeRepository) GetByID(id uuid.UUID) (*models.Overtime, error) {
+	var overtime models.Overtime
+	if err := r.db.First(&overtime, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &overtime, nil
+}
+
+// GetByUsers returns the overtime of users, newest first; an empty status returns all
+func (r *overtimeRepository) GetByUsers(userIDs []uuid.UUID, status string) ([]models.Overtime, error) {
+	var overtimes []models.Overtime
+	query := r.db.Where("user_id IN ?", userIDs)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("date DESC, created_at DESC").Find(&overtimes).Error
+	return overtimes, err
+}
+
 func (r *overtimeRepository) Create(overtime *models.Overtime) error {
 	return r.db.Create(overtime).Error
 }
+
+// Decide records a decision on pending overtime; false when it has been decided already
+func (r *overtimeRepository) Decide(id uuid.UUID, approval models.Approval) (bool, error) {
+	return decide(r.db, &models.Overtime{}, id, approval)
+}
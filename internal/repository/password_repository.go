@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"time"
+
+	"payslip-system/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type passwordRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordRepository(db *gorm.DB) IPasswordRepository {
+	return &passwordRepository{db: db}
+}
+
+// SetPassword saves the user with a new password, keeps the previous one in the history and spends the user's
+// outstanding reset tokens, in one transaction
+func (r *passwordRepository) SetPassword(user *models.User, previous *models.PasswordHistory, at time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(previous).Error; err != nil {
+			return err
+		}
+		if err := tx.Save(user).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL", user.ID).
+			Update("used_at", at).Error
+	})
+}
+
+// GetHistory returns the user's latest previous passwords, newest first
+func (r *passwordRepository) GetHistory(userID uuid.UUID, limit int) ([]models.PasswordHistory, error) {
+	var history []models.PasswordHistory
+	err := r.db.Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&history).Error
+	return history, err
+}
+
+func (r *passwordRepository) CreateResetToken(token *models.PasswordResetToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *passwordRepository) GetResetTokenByHash(hash string) (*models.PasswordResetToken, error) {
+	var token models.PasswordResetToken
+	if err := r.db.Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkResetTokenUsed spends the token unless it already was, reporting whether this call spent it
+func (r *passwordRepository) MarkResetTokenUsed(id uuid.UUID, at time.Time) (bool, error) {
+	result := r.db.Model(&models.PasswordResetToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", at)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// InvalidateResetTokens spends the user's outstanding reset tokens
+func (r *passwordRepository) InvalidateResetTokens(userID uuid.UUID, at time.Time) error {
+	return r.db.Model(&models.PasswordResetToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", at).Error
+}
@@ -1,12 +1,18 @@
 package repository
 
 import (
+	"errors"
+	"time"
+
 	"payslip-system/internal/models"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+var errItemsAlreadySent = errors.New("payroll items were already sent")
+
 type payrollRepository struct {
 	db *gorm.DB
 }
@@ -17,7 +23,8 @@ func NewPayrollRepository(db *gorm.DB) IPayrollRepository {
 
 func (r *payrollRepository) GetByPeriodID(periodID uuid.UUID) (*models.Payroll, error) {
 	var payroll models.Payroll
-	if err := r.db.Where("attendance_period_id = ?", periodID).Preload("PayrollItems.User").First(&payroll).Error; err != nil {
+	if err := r.db.Where("attendance_period_id = ? AND status <> ?", periodID, models.PayrollStatusRejected).
+		Preload("PayrollItems.User").First(&payroll).Error; err != nil {
 		return nil, err
 	}
 	return &payroll, nil
@@ -26,7 +33,7 @@ func (r *payrollRepository) GetByPeriodID(periodID uuid.UUID) (*models.Payroll,
 func (r *payrollRepository) GetPayrollItemsByPeriodAndUser(periodID, userID uuid.UUID) (*models.PayrollItem, error) {
 	var item models.PayrollItem
 	if err := r.db.Joins("JOIN payrolls ON payroll_items.payroll_id = payrolls.id").
-		Where("payrolls.attendance_period_id = ? AND payroll_items.user_id = ?", periodID, userID).
+		Where("payrolls.attendance_period_id = ? AND payrolls.status <> ? AND payroll_items.user_id = ?", periodID, models.PayrollStatusRejected, userID).
 		Preload("User").First(&item).Error; err != nil {
 		return nil, err
 	}
@@ -36,13 +43,28 @@ func (r *payrollRepository) GetPayrollItemsByPeriodAndUser(periodID, userID uuid
 func (r *payrollRepository) GetAllPayrollItemsByPeriod(periodID uuid.UUID) ([]models.PayrollItem, error) {
 	var items []models.PayrollItem
 	if err := r.db.Joins("JOIN payrolls ON payroll_items.payroll_id = payrolls.id").
-		Where("payrolls.attendance_period_id = ?", periodID).
+		Where("payrolls.attendance_period_id = ? AND payrolls.status <> ?", periodID, models.PayrollStatusRejected).
 		Preload("User").Find(&items).Error; err != nil {
 		return nil, err
 	}
 	return items, nil
 }
 
+// GetYearToDateItems returns a user's items of the final runs of periods starting earlier in the year than before
+func (r *payrollRepository) GetYearToDateItems(userID uuid.UUID, before time.Time) ([]models.PayrollItem, error) {
+	yearStart := time.Date(before.Year(), time.January, 1, 0, 0, 0, 0, before.Location())
+
+	var items []models.PayrollItem
+	if err := r.db.Joins("JOIN payrolls ON payroll_items.payroll_id = payrolls.id").
+		Joins("JOIN attendance_periods ON payrolls.attendance_period_id = attendance_periods.id").
+		Where("payroll_items.user_id = ? AND payrolls.status = ? AND attendance_periods.start_date >= ? AND attendance_periods.start_date < ?",
+			userID, models.PayrollStatusApproved, yearStart, before).
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 func (r *payrollRepository) Create(payroll *models.Payroll) error {
 	return r.db.Create(payroll).Error
 }
@@ -50,3 +72,99 @@ func (r *payrollRepository) Create(payroll *models.Payroll) error {
 func (r *payrollRepository) CreatePayrollItem(item *models.PayrollItem) error {
 	return r.db.Create(item).Error
 }
+
+// GetRunByPeriodID returns the current (non-rejected) payroll run with its approvals but without items
+func (r *payrollRepository) GetRunByPeriodID(periodID uuid.UUID) (*models.Payroll, error) {
+	var payroll models.Payroll
+	if err := r.db.Where("attendance_period_id = ? AND status <> ?", periodID, models.PayrollStatusRejected).
+		Preload("Approvals.Approver").First(&payroll).Error; err != nil {
+		return nil, err
+	}
+	return &payroll, nil
+}
+
+// GetRunByPeriodIDForUpdate locks the period's run until the surrounding transaction ends
+func (r *payrollRepository) GetRunByPeriodIDForUpdate(periodID uuid.UUID) (*models.Payroll, error) {
+	var payroll models.Payroll
+	if err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("attendance_period_id = ? AND status <> ?", periodID, models.PayrollStatusRejected).
+		First(&payroll).Error; err != nil {
+		return nil, err
+	}
+	return &payroll, nil
+}
+
+func (r *payrollRepository) Update(payroll *models.Payroll) error {
+	return r.db.Omit("PayrollItems", "Approvals").Save(payroll).Error
+}
+
+func (r *payrollRepository) CreateApproval(approval *models.PayrollApproval) error {
+	return r.db.Create(approval).Error
+}
+
+func (r *payrollRepository) GetApprovals(payrollID uuid.UUID) ([]models.PayrollApproval, error) {
+	var approvals []models.PayrollApproval
+	if err := r.db.Where("payroll_id = ?", payrollID).Order("created_at ASC").Find(&approvals).Error; err != nil {
+		return nil, err
+	}
+	return approvals, nil
+}
+
+// GetByID returns a payroll run of any status with its attendance period
+func (r *payrollRepository) GetByID(id uuid.UUID) (*models.Payroll, error) {
+	var payroll models.Payroll
+	if err := r.db.Preload("AttendancePeriod").First(&payroll, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &payroll, nil
+}
+
+func (r *payrollRepository) GetPayrollItemByID(id uuid.UUID) (*models.PayrollItem, error) {
+	var item models.PayrollItem
+	if err := r.db.Preload("User").First(&item, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// SaveDisbursementFile stores a generated file and records that its items were handed to the bank in it,
+// counting another transfer of each. Only items not yet sent, or failed or returned, are marked; if any of
+// them is not, nothing is saved and false is returned.
+func (r *payrollRepository) SaveDisbursementFile(file *models.DisbursementFile, itemIDs []uuid.UUID) (bool, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.PayrollItem{}).
+			Where("id IN ? AND payment_status IN ?", itemIDs, []string{"", models.PaymentStatusPending, models.PaymentStatusFailed, models.PaymentStatusReturned}).
+			Updates(map[string]interface{}{
+				"payment_status":     models.PaymentStatusSent,
+				"payment_attempts":   gorm.Expr("payment_attempts + 1"),
+				"payment_batch":      file.Reference,
+				"payment_reason":     "",
+				"payment_updated_at": file.CreatedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != int64(len(itemIDs)) {
+			return errItemsAlreadySent
+		}
+		return tx.Create(file).Error
+	})
+	if errors.Is(err, errItemsAlreadySent) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// GetDisbursementFile returns the run's file with the batch reference, or its latest file if none is given
+func (r *payrollRepository) GetDisbursementFile(payrollID uuid.UUID, reference string) (*models.DisbursementFile, error) {
+	query := r.db.Where("payroll_id = ?", payrollID)
+	if reference != "" {
+		query = query.Where("reference = ?", reference)
+	}
+
+	var file models.DisbursementFile
+	if err := query.Order("created_at DESC").First(&file).Error; err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
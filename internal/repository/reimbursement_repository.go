@@ -23,6 +23,30 @@ func (r *reimbursementRepository) GetByUserAndPeriod(userID, periodID uuid.UUID)
 	return reimbursements, nil
 }
 
+func (r *reimbursementRepository) GetByID(id uuid.UUID) (*models.Reimbursement, error) {
+	var reimbursement models.Reimbursement
+	if err := r.db.First(&reimbursement, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &reimbursement, nil
+}
+
+// GetByUsers returns the reimbursements of users, newest first; an empty status returns all
+func (r *reimbursementRepository) GetByUsers(userIDs []uuid.UUID, status string) ([]models.Reimbursement, error) {
+	var reimbursements []models.Reimbursement
+	query := r.db.Where("user_id IN ?", userIDs)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("created_at DESC").Find(&reimbursements).Error
+	return reimbursements, err
+}
+
 func (r *reimbursementRepository) Create(reimbursement *models.Reimbursement) error {
 	return r.db.Create(reimbursement).Error
 }
+
+// Decide records a decision on pending a reimbursement; false when it has been decided already
+func (r *reimbursementRepository) Decide(id uuid.UUID, approval models.Approval) (bool, error) {
+	return decide(r.db, &models.Reimbursement{}, id, approval)
+}
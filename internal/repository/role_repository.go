@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"time"
+
+	"payslip-system/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type roleRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) IRoleRepository {
+	return &roleRepository{db: db}
+}
+
+func (r *roleRepository) GetAll() ([]models.Role, error) {
+	var roles []models.Role
+	err := r.db.Preload("Permissions").Order("name ASC").Find(&roles).Error
+	return roles, err
+}
+
+func (r *roleRepository) GetByID(id uuid.UUID) (*models.Role, error) {
+	var role models.Role
+	if err := r.db.Preload("Permissions").First(&role, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *roleRepository) GetByNames(names []string) ([]models.Role, error) {
+	var roles []models.Role
+	err := r.db.Preload("Permissions").Where("name IN ?", names).Order("name ASC").Find(&roles).Error
+	return roles, err
+}
+
+// Create stores a role with its permissions
+func (r *roleRepository) Create(role *models.Role) error {
+	return r.db.Create(role).Error
+}
+
+// Update stores a role and replaces its permissions
+func (r *roleRepository) Update(role *models.Role) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Permissions").Save(role).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("role_id = ?", role.ID).Delete(&models.RolePermission{}).Error; err != nil {
+			return err
+		}
+		if len(role.Permissions) == 0 {
+			return nil
+		}
+		return tx.Create(&role.Permissions).Error
+	})
+}
+
+func (r *roleRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.Role{}, "id = ?", id).Error
+}
+
+// CountHolders returns how many users hold a role
+func (r *roleRepository) CountHolders(roleID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.UserRole{}).Where("role_id = ?", roleID).Count(&count).Error
+	return count, err
+}
+
+func (r *roleRepository) GetUserRoles(userID uuid.UUID) ([]models.Role, error) {
+	var roles []models.Role
+	err := r.db.Preload("Permissions").
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ?", userID).
+		Order("roles.name ASC").
+		Find(&roles).Error
+	return roles, err
+}
+
+// SetUserRoles makes the user hold exactly the given roles. Roles the user already holds keep when and by
+// whom they were granted.
+func (r *roleRepository) SetUserRoles(userID uuid.UUID, roleIDs []uuid.UUID, grantedBy uuid.UUID, at time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		remove := tx.Where("user_id = ?", userID)
+		if len(roleIDs) > 0 {
+			remove = remove.Where("role_id NOT IN ?", roleIDs)
+		}
+		if err := remove.Delete(&models.UserRole{}).Error; err != nil {
+			return err
+		}
+		if len(roleIDs) == 0 {
+			return nil
+		}
+
+		grants := make([]models.UserRole, len(roleIDs))
+		for i, roleID := range roleIDs {
+			grants[i] = models.UserRole{UserID: userID, RoleID: roleID, CreatedBy: &grantedBy, CreatedAt: at}
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Omit("User", "Role").Create(&grants).Error
+	})
+}
+
+// GetUserPermissions returns the permissions a user holds through all of their roles
+func (r *roleRepository) GetUserPermissions(userID uuid.UUID) ([]string, error) {
+	var permissions []string
+	err := r.db.Model(&models.RolePermission{}).
+		Distinct("role_permissions.permission").
+		Joins("JOIN user_roles ON user_roles.role_id = role_permissions.role_id").
+		Where("user_roles.user_id = ?", userID).
+		Order("role_permissions.permission ASC").
+		Pluck("role_permissions.permission", &permissions).Error
+	return permissions, err
+}
+
+// CountUsersWithPermission returns how many active users hold a permission through any role
+func (r *roleRepository) CountUsersWithPermission(permission string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.UserRole{}).
+		Joins("JOIN role_permissions ON role_permissions.role_id = user_roles.role_id").
+		Joins("JOIN users ON users.id = user_roles.user_id").
+		Where("role_permissions.permission = ? AND users.is_active = true", permission).
+		Distinct("user_roles.user_id").
+		Count(&count).Error
+	return count, err
+}
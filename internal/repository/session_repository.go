@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"time"
+
+	"payslip-system/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) ISessionRepository {
+	return &sessionRepository{db: db}
+}
+
+func (r *sessionRepository) Create(session *models.Session) error {
+	return r.db.Create(session).Error
+}
+
+func (r *sessionRepository) GetByID(id uuid.UUID) (*models.Session, error) {
+	var session models.Session
+	if err := r.db.First(&session, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *sessionRepository) Update(session *models.Session) error {
+	return r.db.Save(session).Error
+}
+
+// RevokeAllForUser revokes the user's sessions that are still active, returning how many there were
+func (r *sessionRepository) RevokeAllForUser(userID uuid.UUID, reason string, at time.Time) (int64, error) {
+	result := r.db.Model(&models.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, at).
+		Updates(map[string]interface{}{
+			"revoked_at":     at,
+			"revoked_reason": reason,
+			"updated_at":     at,
+		})
+	return result.RowsAffected, result.Error
+}
+
+// RevokeOthersForUser revokes the user's active sessions except the one the request was made with
+func (r *sessionRepository) RevokeOthersForUser(userID, keepSessionID uuid.UUID, reason string, at time.Time) (int64, error) {
+	result := r.db.Model(&models.Session{}).
+		Where("user_id = ? AND id <> ? AND revoked_at IS NULL AND expires_at > ?", userID, keepSessionID, at).
+		Updates(map[string]interface{}{
+			"revoked_at":     at,
+			"revoked_reason": reason,
+			"updated_at":     at,
+		})
+	return result.RowsAffected, result.Error
+}
+
+func (r *sessionRepository) CreateRefreshToken(token *models.RefreshToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *sessionRepository) GetRefreshTokenByHash(hash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	if err := r.db.Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkRefreshTokenUsed marks the token rotated unless it already was, reporting whether this call marked it.
+// Of two concurrent refreshes with the same token only one succeeds.
+func (r *sessionRepository) MarkRefreshTokenUsed(id uuid.UUID, at time.Time) (bool, error) {
+	result := r.db.Model(&models.RefreshToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", at)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"time"
+
+	"payslip-system/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ssoRepository struct {
+	db *gorm.DB
+}
+
+func NewSSORepository(db *gorm.DB) ISSORepository {
+	return &ssoRepository{db: db}
+}
+
+func (r *ssoRepository) CreateLogin(login *models.SSOLogin) error {
+	return r.db.Create(login).Error
+}
+
+func (r *ssoRepository) GetLoginByStateHash(hash string) (*models.SSOLogin, error) {
+	var login models.SSOLogin
+	if err := r.db.Where("state_hash = ?", hash).First(&login).Error; err != nil {
+		return nil, err
+	}
+	return &login, nil
+}
+
+// MarkLoginUsed marks the login completed unless it already was, reporting whether this call marked it
+func (r *ssoRepository) MarkLoginUsed(id uuid.UUID, at time.Time) (bool, error) {
+	result := r.db.Model(&models.SSOLogin{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", at)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
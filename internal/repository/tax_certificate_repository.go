@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"payslip-system/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// issueAttempts bounds the retries when concurrent requests take the same number
+const issueAttempts = 3
+
+type taxCertificateRepository struct {
+	db *gorm.DB
+}
+
+func NewTaxCertificateRepository(db *gorm.DB) ITaxCertificateRepository {
+	return &taxCertificateRepository{db: db}
+}
+
+// Issue returns how a user's certificate for a tax year was issued. The first call records it under the next
+// number of the year, in month and at the time given; later calls return that record.
+func (r *taxCertificateRepository) Issue(userID uuid.UUID, year, month int, at time.Time) (*models.TaxCertificateIssue, error) {
+	for attempt := 0; attempt < issueAttempts; attempt++ {
+		var issue models.TaxCertificateIssue
+		err := r.db.Where("user_id = ? AND tax_year = ?", userID, year).First(&issue).Error
+		if err == nil {
+			return &issue, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+
+		var last int
+		if err := r.db.Model(&models.TaxCertificateIssue{}).Where("tax_year = ?", year).
+			Select("COALESCE(MAX(sequence), 0)").Scan(&last).Error; err != nil {
+			return nil, err
+		}
+
+		issue = models.TaxCertificateIssue{ID: uuid.New(), UserID: userID, TaxYear: year, Sequence: last + 1, Month: month, IssuedAt: at}
+		result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&issue)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 1 {
+			return &issue, nil
+		}
+		// Another request issued this certificate or took the number
+	}
+	return nil, errors.New("failed to number tax certificate")
+}
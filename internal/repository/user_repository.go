@@ -31,14 +31,52 @@ func (r *userRepository) GetByUsername(username string) (*models.User, error) {
 	return &user, nil
 }
 
+// GetByOIDCSubject returns the active user linked to an identity provider subject
+func (r *userRepository) GetByOIDCSubject(subject string) (*models.User, error) {
+	var user models.User
+	if err := r.db.Where("oidc_subject = ? AND is_active = true", subject).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *userRepository) GetByEmployeeID(employeeID string) (*models.User, error) {
+	var user models.User
+	if err := r.db.Where("employee_id = ? AND is_active = true", employeeID).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetByEmail returns the active users with an email address, compared case-insensitively; it is not unique
+func (r *userRepository) GetByEmail(email string) ([]models.User, error) {
+	var users []models.User
+	err := r.db.Where("LOWER(email) = LOWER(?) AND is_active = true", email).Find(&users).Error
+	return users, err
+}
+
+// GetAllEmployees returns the users on the payroll: active users with a salary, admins included
 func (r *userRepository) GetAllEmployees() ([]models.User, error) {
 	var employees []models.User
-	if err := r.db.Where("role = ? AND is_active = true", "employee").Find(&employees).Error; err != nil {
+	if err := r.db.Where("salary IS NOT NULL AND is_active = true").Find(&employees).Error; err != nil {
 		return nil, err
 	}
 	return employees, nil
 }
 
+// GetReports returns the active users reporting to a manager, directly or through other managers
+func (r *userRepository) GetReports(managerID uuid.UUID) ([]models.User, error) {
+	var reports []models.User
+	err := r.db.Where(`id IN (
+		WITH RECURSIVE reports AS (
+			SELECT id FROM users WHERE manager_id = ?
+			UNION
+			SELECT u.id FROM users u JOIN reports ON u.manager_id = reports.id
+		) SELECT id FROM reports)`, managerID).
+		Where("is_active = true").Order("username ASC").Find(&reports).Error
+	return reports, err
+}
+
 func (r *userRepository) Create(user *models.User) error {
 	return r.db.Create(user).Error
 }
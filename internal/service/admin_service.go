@@ -3,13 +3,18 @@ package service
 import (
 	"errors"
 	"fmt"
+	"payslip-system/internal/domains"
 	"payslip-system/internal/models"
 	"payslip-system/internal/repository"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+var costCenterPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,20}$`)
+
 type adminService struct {
 	repos *repository.Repositories
 }
@@ -18,14 +23,14 @@ func NewAdminService(repos *repository.Repositories) *adminService {
 	return &adminService{repos: repos}
 }
 
-func (s *adminService) CreateAttendancePeriod(startDate, endDate time.Time, adminID uuid.UUID, ipAddress, requestID string) (*models.AttendancePeriod, error) {
+func (s *adminService) CreateAttendancePeriod(startDate, endDate time.Time, actor domains.Actor, ipAddress, requestID string) (*models.AttendancePeriod, error) {
 	if endDate.Before(startDate) {
 		return nil, errors.New("end date must be after start date")
 	}
 
 	period := &models.AttendancePeriod{
 		BaseModel: models.BaseModel{
-			CreatedBy: &adminID,
+			CreatedBy: actor.UserID,
 			IPAddress: ipAddress,
 			RequestID: requestID,
 		},
@@ -39,7 +44,88 @@ func (s *adminService) CreateAttendancePeriod(startDate, endDate time.Time, admi
 	}
 
 	// Create audit log
-	createAuditLog("attendance_periods", period.ID, "INSERT", nil, period, &adminID, ipAddress, requestID, s.repos)
+	createAuditLog("attendance_periods", period.ID, "INSERT", nil, period, actor, ipAddress, requestID, s.repos)
 
 	return period, nil
 }
+
+// SetCostCenter sets the cost center an employee's salary costs are booked to; an empty one clears it
+func (s *adminService) SetCostCenter(userID uuid.UUID, costCenter string, actor domains.Actor, ipAddress, requestID string) (*models.User, error) {
+	costCenter = strings.ToUpper(strings.TrimSpace(costCenter))
+	if costCenter != "" && !costCenterPattern.MatchString(costCenter) {
+		return nil, errors.New("cost center must be up to 20 letters, digits, '-' or '_'")
+	}
+
+	user, err := s.repos.User.GetByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	oldCostCenter := user.CostCenter
+	user.CostCenter = costCenter
+	user.UpdatedBy = actor.UserID
+	if err := s.repos.User.Update(user); err != nil {
+		return nil, err
+	}
+
+	createAuditLog("users", user.ID, "UPDATE", map[string]string{"cost_center": oldCostCenter}, map[string]string{"cost_center": costCenter}, actor, ipAddress, requestID, s.repos)
+
+	return user, nil
+}
+
+// SetEmployeeID sets the HR employee number single sign-on matches users on; an empty one clears it
+func (s *adminService) SetEmployeeID(userID uuid.UUID, employeeID string, actor domains.Actor, ipAddress, requestID string) (*models.User, error) {
+	employeeID = strings.TrimSpace(employeeID)
+	if len(employeeID) > 50 {
+		return nil, errors.New("employee ID must be at most 50 characters")
+	}
+
+	user, err := s.repos.User.GetByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	var newEmployeeID *string
+	if employeeID != "" {
+		if holder, err := s.repos.User.GetByEmployeeID(employeeID); err == nil && holder.ID != user.ID {
+			return nil, errors.New("employee ID is already assigned to another user")
+		}
+		newEmployeeID = &employeeID
+	}
+
+	oldEmployeeID := user.EmployeeID
+	user.EmployeeID = newEmployeeID
+	user.UpdatedBy = actor.UserID
+	if err := s.repos.User.Update(user); err != nil {
+		return nil, err
+	}
+
+	createAuditLog("users", user.ID, "UPDATE", map[string]*string{"employee_id": oldEmployeeID}, map[string]*string{"employee_id": newEmployeeID}, actor, ipAddress, requestID, s.repos)
+
+	return user, nil
+}
+
+// SetBirthDate sets the birthdate BPJS reports carry and payslip PDFs open with when the employee has no PIN; nil clears it
+func (s *adminService) SetBirthDate(userID uuid.UUID, birthDate *time.Time, actor domains.Actor, ipAddress, requestID string) (*models.User, error) {
+	if birthDate != nil {
+		if birthDate.Year() < 1900 || !birthDate.Before(time.Now()) {
+			return nil, errors.New("birth date must be in the past and not before 1900")
+		}
+	}
+
+	user, err := s.repos.User.GetByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	oldBirthDate := user.BirthDate
+	user.BirthDate = birthDate
+	user.UpdatedBy = actor.UserID
+	if err := s.repos.User.Update(user); err != nil {
+		return nil, err
+	}
+
+	createAuditLog("users", user.ID, "UPDATE", map[string]*time.Time{"birth_date": oldBirthDate}, map[string]*time.Time{"birth_date": birthDate}, actor, ipAddress, requestID, s.repos)
+
+	return user, nil
+}
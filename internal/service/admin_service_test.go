@@ -1,6 +1,7 @@
 package service
 
 import (
+	"payslip-system/internal/domains"
 	"payslip-system/internal/models"
 	"payslip-system/internal/repository"
 	mock_repository "payslip-system/internal/repository/mocks"
@@ -10,6 +11,8 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
 )
 
 func Test_adminService_CreateAttendancePeriod(t *testing.T) {
@@ -80,7 +83,7 @@ func Test_adminService_CreateAttendancePeriod(t *testing.T) {
 			}
 
 			s := NewAdminService(repos)
-			got, err := s.CreateAttendancePeriod(tt.args.startDate, tt.args.endDate, tt.args.adminID, tt.args.ipAddress, tt.args.requestID)
+			got, err := s.CreateAttendancePeriod(tt.args.startDate, tt.args.endDate, domains.UserActor(tt.args.adminID), tt.args.ipAddress, tt.args.requestID)
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.Nil(t, got)
@@ -92,3 +95,147 @@ func Test_adminService_CreateAttendancePeriod(t *testing.T) {
 		})
 	}
 }
+
+func Test_adminService_SetCostCenter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	adminID := uuid.New()
+
+	tests := []struct {
+		name       string
+		costCenter string
+		want       string
+		wantErr    bool
+	}{
+		{name: "success", costCenter: " eng-01 ", want: "ENG-01"},
+		{name: "success - clear", costCenter: "", want: ""},
+		{name: "error - invalid characters", costCenter: "ENG/01", wantErr: true},
+		{name: "error - too long", costCenter: "ENGINEERING-PLATFORM-TEAM", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+			mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+
+			if !tt.wantErr {
+				mockUserRepo.EXPECT().GetByID(userID).Return(&models.User{BaseModel: models.BaseModel{ID: userID}, CostCenter: "OPS"}, nil)
+				mockUserRepo.EXPECT().Update(gomock.Any()).Return(nil)
+				mockAuditLogRepo.EXPECT().Create(gomock.Any()).Return(nil)
+			}
+
+			s := NewAdminService(&repository.Repositories{User: mockUserRepo, AuditLog: mockAuditLogRepo})
+			user, err := s.SetCostCenter(userID, tt.costCenter, domains.UserActor(adminID), "127.0.0.1", "req-123")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, user.CostCenter)
+		})
+	}
+}
+
+func Test_adminService_SetEmployeeID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	adminID := uuid.New()
+
+	tests := []struct {
+		name       string
+		employeeID string
+		holder     *models.User // current holder of the employee ID
+		want       string
+		wantErr    bool
+	}{
+		{name: "success", employeeID: " E-1001 ", want: "E-1001"},
+		{name: "success - already held by the user", employeeID: "E-1001", holder: &models.User{BaseModel: models.BaseModel{ID: userID}}, want: "E-1001"},
+		{name: "success - clear", employeeID: ""},
+		{name: "error - held by another user", employeeID: "E-1001", holder: &models.User{BaseModel: models.BaseModel{ID: uuid.New()}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+			mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+
+			mockUserRepo.EXPECT().GetByID(userID).Return(&models.User{BaseModel: models.BaseModel{ID: userID}}, nil)
+			if tt.employeeID != "" {
+				if tt.holder != nil {
+					mockUserRepo.EXPECT().GetByEmployeeID("E-1001").Return(tt.holder, nil)
+				} else {
+					mockUserRepo.EXPECT().GetByEmployeeID("E-1001").Return(nil, gorm.ErrRecordNotFound)
+				}
+			}
+			if !tt.wantErr {
+				mockUserRepo.EXPECT().Update(gomock.Any()).Return(nil)
+				mockAuditLogRepo.EXPECT().Create(gomock.Any()).Return(nil)
+			}
+
+			s := NewAdminService(&repository.Repositories{User: mockUserRepo, AuditLog: mockAuditLogRepo})
+			user, err := s.SetEmployeeID(userID, tt.employeeID, domains.UserActor(adminID), "127.0.0.1", "req-123")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			if tt.want == "" {
+				assert.Nil(t, user.EmployeeID)
+				return
+			}
+			require.NotNil(t, user.EmployeeID)
+			assert.Equal(t, tt.want, *user.EmployeeID)
+		})
+	}
+}
+
+func Test_adminService_SetBirthDate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	adminID := uuid.New()
+	birthDate := time.Date(1990, time.March, 7, 0, 0, 0, 0, time.UTC)
+	tomorrow := time.Now().AddDate(0, 0, 1)
+	ancient := time.Date(1899, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		birthDate *time.Time
+		wantErr   bool
+	}{
+		{name: "success", birthDate: &birthDate},
+		{name: "success - clear", birthDate: nil},
+		{name: "error - in the future", birthDate: &tomorrow, wantErr: true},
+		{name: "error - before 1900", birthDate: &ancient, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+			mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+
+			if !tt.wantErr {
+				mockUserRepo.EXPECT().GetByID(userID).Return(&models.User{BaseModel: models.BaseModel{ID: userID}, BirthDate: &ancient}, nil)
+				mockUserRepo.EXPECT().Update(gomock.Any()).Return(nil)
+				mockAuditLogRepo.EXPECT().Create(gomock.Any()).Return(nil)
+			}
+
+			s := NewAdminService(&repository.Repositories{User: mockUserRepo, AuditLog: mockAuditLogRepo})
+			user, err := s.SetBirthDate(userID, tt.birthDate, domains.UserActor(adminID), "127.0.0.1", "req-123")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.birthDate, user.BirthDate)
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"payslip-system/internal/domains"
+	"payslip-system/internal/middleware"
+	"payslip-system/internal/models"
+	"payslip-system/internal/rbac"
+	"payslip-system/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// apiKeyPrefixLength is how much of a key is kept in the clear to tell keys apart: the prefix and 8 characters
+const apiKeyPrefixLength = len(middleware.APIKeyPrefix) + 8
+
+type apiKeyService struct {
+	repos  *repository.Repositories
+	maxTTL time.Duration
+}
+
+func NewAPIKeyService(repos *repository.Repositories, maxTTLDays int) *apiKeyService {
+	return &apiKeyService{repos: repos, maxTTL: time.Duration(maxTTLDays) * 24 * time.Hour}
+}
+
+func (s *apiKeyService) ListAPIKeys() ([]domains.APIKeySummary, error) {
+	keys, err := s.repos.APIKey.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]domains.APIKeySummary, len(keys))
+	for i := range keys {
+		summaries[i] = apiKeySummary(&keys[i])
+	}
+	return summaries, nil
+}
+
+// CreateAPIKey issues a key holding the permissions, which the admin has to hold themselves. Only its hash is
+// stored, so it cannot be shown again.
+func (s *apiKeyService) CreateAPIKey(req domains.APIKeyRequest, adminID uuid.UUID, ipAddress, requestID string) (*domains.IssuedAPIKey, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > 100 {
+		return nil, errors.New("API key name must be 1 to 100 characters")
+	}
+
+	if len(uniqueSorted(req.Permissions)) == 0 {
+		return nil, errors.New("an API key needs at least one permission")
+	}
+	permissions, err := checkPermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	held, err := s.repos.Role.GetUserPermissions(adminID)
+	if err != nil {
+		return nil, err
+	}
+	heldSet := rbac.NewSet(held)
+	for _, p := range permissions {
+		if !rbac.APIKeyGrantable(p) {
+			return nil, fmt.Errorf("API keys cannot hold permission %q", p)
+		}
+		if !heldSet.Has(p) {
+			return nil, fmt.Errorf("%w: %q", domains.ErrNotHeld, p)
+		}
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.maxTTL)
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(now) {
+			return nil, errors.New("expiry must be in the future")
+		}
+		if req.ExpiresAt.After(expiresAt) {
+			return nil, fmt.Errorf("API keys are valid for at most %d days", int(s.maxTTL.Hours()/24))
+		}
+		expiresAt = *req.ExpiresAt
+	}
+
+	random, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	apiKey := middleware.APIKeyPrefix + random
+
+	key := &models.APIKey{
+		BaseModel: models.BaseModel{
+			ID:        uuid.New(),
+			CreatedBy: &adminID,
+			IPAddress: ipAddress,
+			RequestID: requestID,
+		},
+		Name:      name,
+		Prefix:    apiKey[:apiKeyPrefixLength],
+		KeyHash:   middleware.HashAPIKey(apiKey),
+		ExpiresAt: &expiresAt,
+	}
+	for _, p := range permissions {
+		key.Permissions = append(key.Permissions, models.APIKeyPermission{APIKeyID: key.ID, Permission: p})
+	}
+	if err := s.repos.APIKey.Create(key); err != nil {
+		return nil, fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	summary := apiKeySummary(key)
+	createAuditLog("api_keys", key.ID, "INSERT", nil, summary, domains.UserActor(adminID), ipAddress, requestID, s.repos)
+
+	return &domains.IssuedAPIKey{APIKeySummary: summary, Key: apiKey}, nil
+}
+
+// RevokeAPIKey stops a key from working right away
+func (s *apiKeyService) RevokeAPIKey(keyID, adminID uuid.UUID, ipAddress, requestID string) error {
+	key, err := s.repos.APIKey.GetByID(keyID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domains.ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if key.RevokedAt != nil {
+		return domains.ErrAPIKeyRevoked
+	}
+
+	old := apiKeySummary(key)
+	now := time.Now()
+	revoked, err := s.repos.APIKey.Revoke(key.ID, adminID, now)
+	if err != nil {
+		return fmt.Errorf("failed to revoke API key: %w", err)
+	}
+	if !revoked {
+		return domains.ErrAPIKeyRevoked
+	}
+
+	key.RevokedAt = &now
+	createAuditLog("api_keys", key.ID, "UPDATE", old, apiKeySummary(key), domains.UserActor(adminID), ipAddress, requestID, s.repos)
+
+	return nil
+}
+
+func apiKeySummary(key *models.APIKey) domains.APIKeySummary {
+	summary := domains.APIKeySummary{
+		ID:          key.ID,
+		Name:        key.Name,
+		Prefix:      key.Prefix,
+		Permissions: []string{},
+		ExpiresAt:   key.ExpiresAt,
+		LastUsedAt:  key.LastUsedAt,
+		LastUsedIP:  key.LastUsedIP,
+		RevokedAt:   key.RevokedAt,
+		CreatedBy:   key.CreatedBy,
+		CreatedAt:   key.CreatedAt,
+	}
+	for _, p := range key.Permissions {
+		summary.Permissions = append(summary.Permissions, p.Permission)
+	}
+	sort.Strings(summary.Permissions)
+	return summary
+}
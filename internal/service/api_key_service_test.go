@@ -0,0 +1,139 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"payslip-system/internal/domains"
+	"payslip-system/internal/middleware"
+	"payslip-system/internal/models"
+	"payslip-system/internal/rbac"
+	"payslip-system/internal/repository"
+	mock_repository "payslip-system/internal/repository/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func Test_apiKeyService_CreateAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	inMonth := time.Now().AddDate(0, 1, 0)
+	past := time.Now().Add(-time.Hour)
+	inTwoYears := time.Now().AddDate(2, 0, 0)
+
+	tests := []struct {
+		name          string
+		req           domains.APIKeyRequest
+		wantErr       string
+		wantExpiresAt time.Time
+	}{
+		{name: "longest expiry by default", req: domains.APIKeyRequest{Name: " Badge readers ", Permissions: []string{rbac.AttendanceRecord}}, wantExpiresAt: time.Now().AddDate(0, 0, 365)},
+		{name: "own expiry", req: domains.APIKeyRequest{Name: "HRIS", Permissions: []string{rbac.EmployeeManage, rbac.AttendanceRecord}, ExpiresAt: &inMonth}, wantExpiresAt: inMonth},
+		{name: "no name", req: domains.APIKeyRequest{Name: " ", Permissions: []string{rbac.AttendanceRecord}}, wantErr: "name must be"},
+		{name: "no permissions", req: domains.APIKeyRequest{Name: "HRIS"}, wantErr: "at least one permission"},
+		{name: "unknown permission", req: domains.APIKeyRequest{Name: "HRIS", Permissions: []string{"payroll:everything"}}, wantErr: "unknown permission"},
+		{name: "self-service permission", req: domains.APIKeyRequest{Name: "HRIS", Permissions: []string{rbac.AttendanceSubmit}}, wantErr: "cannot hold permission"},
+		{name: "issuing keys", req: domains.APIKeyRequest{Name: "HRIS", Permissions: []string{rbac.APIKeyManage}}, wantErr: "cannot hold permission"},
+		{name: "permission not held", req: domains.APIKeyRequest{Name: "HRIS", Permissions: []string{rbac.AttendanceRecord, rbac.PayrollProcess}}, wantErr: "permissions you hold"},
+		{name: "expired", req: domains.APIKeyRequest{Name: "HRIS", Permissions: []string{rbac.EmployeeManage}, ExpiresAt: &past}, wantErr: "in the future"},
+		{name: "too long", req: domains.APIKeyRequest{Name: "HRIS", Permissions: []string{rbac.EmployeeManage}, ExpiresAt: &inTwoYears}, wantErr: "at most 365 days"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adminID := uuid.New()
+
+			mockAPIKeyRepo := mock_repository.NewMockIAPIKeyRepository(ctrl)
+			mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+			mockRoleRepo := mock_repository.NewMockIRoleRepository(ctrl)
+			mockRoleRepo.EXPECT().GetUserPermissions(adminID).
+				Return([]string{rbac.APIKeyManage, rbac.EmployeeManage, rbac.AttendanceRecord}, nil).AnyTimes()
+
+			var stored *models.APIKey
+			if tt.wantErr == "" {
+				mockAPIKeyRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(key *models.APIKey) error {
+					stored = key
+					return nil
+				})
+				mockAuditLogRepo.EXPECT().Create(gomock.Any()).Return(nil)
+			}
+
+			s := NewAPIKeyService(&repository.Repositories{APIKey: mockAPIKeyRepo, AuditLog: mockAuditLogRepo, Role: mockRoleRepo}, 365)
+
+			issued, err := s.CreateAPIKey(tt.req, adminID, "127.0.0.1", "req-123")
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			// Only the hash of the key is stored
+			assert.True(t, strings.HasPrefix(issued.Key, middleware.APIKeyPrefix))
+			assert.Equal(t, middleware.HashAPIKey(issued.Key), stored.KeyHash)
+			assert.Equal(t, issued.Key[:len(stored.Prefix)], stored.Prefix)
+			assert.Equal(t, strings.TrimSpace(tt.req.Name), issued.Name)
+			assert.Equal(t, uniqueSorted(tt.req.Permissions), issued.Permissions)
+			assert.Equal(t, &adminID, issued.CreatedBy)
+			require.NotNil(t, issued.ExpiresAt)
+			assert.WithinDuration(t, tt.wantExpiresAt, *issued.ExpiresAt, time.Minute)
+		})
+	}
+}
+
+func Test_apiKeyService_RevokeAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	revokedAt := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name     string
+		key      *models.APIKey
+		raceLost bool
+		wantErr  error
+	}{
+		{name: "success", key: &models.APIKey{BaseModel: models.BaseModel{ID: uuid.New()}, Name: "HRIS"}},
+		{name: "not found", wantErr: domains.ErrAPIKeyNotFound},
+		{name: "already revoked", key: &models.APIKey{BaseModel: models.BaseModel{ID: uuid.New()}, RevokedAt: &revokedAt}, wantErr: domains.ErrAPIKeyRevoked},
+		{name: "revoked concurrently", key: &models.APIKey{BaseModel: models.BaseModel{ID: uuid.New()}}, raceLost: true, wantErr: domains.ErrAPIKeyRevoked},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adminID := uuid.New()
+			keyID := uuid.New()
+
+			mockAPIKeyRepo := mock_repository.NewMockIAPIKeyRepository(ctrl)
+			mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+
+			if tt.key == nil {
+				mockAPIKeyRepo.EXPECT().GetByID(keyID).Return(nil, gorm.ErrRecordNotFound)
+			} else {
+				keyID = tt.key.ID
+				mockAPIKeyRepo.EXPECT().GetByID(keyID).Return(tt.key, nil)
+			}
+			if tt.key != nil && tt.key.RevokedAt == nil {
+				mockAPIKeyRepo.EXPECT().Revoke(keyID, adminID, gomock.Any()).Return(!tt.raceLost, nil)
+			}
+			if tt.wantErr == nil {
+				mockAuditLogRepo.EXPECT().Create(gomock.Any()).Return(nil)
+			}
+
+			s := NewAPIKeyService(&repository.Repositories{APIKey: mockAPIKeyRepo, AuditLog: mockAuditLogRepo}, 365)
+
+			err := s.RevokeAPIKey(keyID, adminID, "127.0.0.1", "req-123")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
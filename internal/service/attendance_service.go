@@ -3,11 +3,14 @@ package service
 import (
 	"errors"
 	"fmt"
+	"payslip-system/internal/domains"
 	"payslip-system/internal/models"
 	"payslip-system/internal/repository"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type attendanceService struct {
@@ -19,6 +22,24 @@ func NewAttendanceService(repos *repository.Repositories) *attendanceService {
 }
 
 func (s *attendanceService) SubmitAttendance(userID uuid.UUID, date time.Time, checkInTime time.Time, ipAddress, requestID string) error {
+	return s.submit(userID, date, checkInTime, domains.UserActor(userID), ipAddress, requestID)
+}
+
+// RecordAttendance records the attendance of an employee, identified by employee ID, on their behalf, as
+// badge readers do. The same rules apply as to employees submitting their own.
+func (s *attendanceService) RecordAttendance(employeeID string, date time.Time, checkInTime time.Time, actor domains.Actor, ipAddress, requestID string) error {
+	user, err := s.repos.User.GetByEmployeeID(strings.TrimSpace(employeeID))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.New("no active employee with this employee ID")
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.submit(user.ID, date, checkInTime, actor, ipAddress, requestID)
+}
+
+func (s *attendanceService) submit(userID uuid.UUID, date time.Time, checkInTime time.Time, actor domains.Actor, ipAddress, requestID string) error {
 	// Check if it's weekend
 	if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
 		return errors.New("cannot submit attendance on weekends")
@@ -29,36 +50,37 @@ func (s *attendanceService) SubmitAttendance(userID uuid.UUID, date time.Time, c
 		return errors.New("attendance already submitted for this date")
 	}
 
-	// Get active attendance period
-	period, err := s.repos.AttendancePeriod.GetActive()
-	if err != nil {
-		return errors.New("no active attendance period found")
-	}
+	var attendance *models.Attendance
+	err := withSubmissionPeriod(s.repos, func(txRepos *repository.Repositories, period *models.AttendancePeriod) error {
+		// Check if date is within period
+		if date.Before(period.StartDate) || date.After(period.EndDate) {
+			return errors.New("date is not within active attendance period")
+		}
 
-	// Check if date is within period
-	if date.Before(period.StartDate) || date.After(period.EndDate) {
-		return errors.New("date is not within active attendance period")
-	}
+		// Create attendance record
+		attendance = &models.Attendance{
+			BaseModel: models.BaseModel{
+				CreatedBy: actor.UserID,
+				IPAddress: ipAddress,
+				RequestID: requestID,
+			},
+			UserID:             userID,
+			AttendancePeriodID: period.ID,
+			Date:               date,
+			CheckInTime:        checkInTime,
+		}
 
-	// Create attendance record
-	attendance := &models.Attendance{
-		BaseModel: models.BaseModel{
-			CreatedBy: &userID,
-			IPAddress: ipAddress,
-			RequestID: requestID,
-		},
-		UserID:             userID,
-		AttendancePeriodID: period.ID,
-		Date:               date,
-		CheckInTime:        checkInTime,
-	}
-
-	if err := s.repos.Attendance.Create(attendance); err != nil {
-		return fmt.Errorf("failed to create attendance record: %w", err)
+		if err := txRepos.Attendance.Create(attendance); err != nil {
+			return fmt.Errorf("failed to create attendance record: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	// Create audit log
-	createAuditLog("attendances", attendance.ID, "INSERT", nil, attendance, &userID, ipAddress, requestID, s.repos)
+	createAuditLog("attendances", attendance.ID, "INSERT", nil, attendance, actor, ipAddress, requestID, s.repos)
 
 	return nil
 }
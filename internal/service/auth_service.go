@@ -1,36 +1,652 @@
 package service
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"payslip-system/internal/config"
+	"payslip-system/internal/domains"
+	"payslip-system/internal/middleware"
 	"payslip-system/internal/models"
+	"payslip-system/internal/rbac"
 	"payslip-system/internal/repository"
+	"payslip-system/internal/token"
 
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
 	"golang.org/x/crypto/bcrypt"
 )
 
+// activityLimit is how many entries of their activity users see
+const activityLimit = 100
+
 type authService struct {
-	repos *repository.Repositories
+	repos      *repository.Repositories
+	keys       *token.KeySet
+	mfa        *mfaService
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+
+	impersonationTTL time.Duration
+
+	challengeTTL         time.Duration
+	maxChallengeAttempts int
+
+	lockout config.LockoutConfig
+
+	passwordLoginDisabled bool
 }
 
-func NewAuthService(repos *repository.Repositories) *authService {
-	return &authService{repos: repos}
+func NewAuthService(repos *repository.Repositories, cfg config.AuthConfig, keys *token.KeySet, mfa *mfaService) *authService {
+	return &authService{
+		repos:                 repos,
+		keys:                  keys,
+		mfa:                   mfa,
+		accessTTL:             time.Duration(cfg.AccessTokenTTL) * time.Minute,
+		refreshTTL:            time.Duration(cfg.RefreshTokenTTL) * time.Hour,
+		impersonationTTL:      time.Duration(cfg.ImpersonationTTL) * time.Minute,
+		challengeTTL:          time.Duration(cfg.MFA.ChallengeTTL) * time.Minute,
+		maxChallengeAttempts:  cfg.MFA.MaxAttempts,
+		lockout:               cfg.Lockout,
+		passwordLoginDisabled: cfg.PasswordLoginDisabled,
+	}
 }
 
-func (s *authService) Login(username, password string) (*models.User, string, error) {
+var (
+	dummyHashOnce sync.Once
+	dummyHash     []byte
+)
+
+// dummyPasswordHash is compared against for unknown usernames, so they take as long as a wrong password
+func dummyPasswordHash() []byte {
+	dummyHashOnce.Do(func() {
+		dummyHash, _ = bcrypt.GenerateFromPassword([]byte("not a password"), bcrypt.DefaultCost)
+	})
+	return dummyHash
+}
+
+// Login checks the password. Users who need a second factor get an MFA challenge to answer with
+// CompleteMFAChallenge; the others go on to StartSession. Failed logins are throttled per username and per
+// IP address.
+func (s *authService) Login(username, password, ipAddress, requestID string) (*models.User, *domains.MFAChallenge, error) {
+	if s.passwordLoginDisabled {
+		return nil, nil, domains.ErrPasswordLoginDisabled
+	}
+
+	now := time.Now()
+	usernameKey := throttleKey(username)
+	if err := s.checkThrottle(usernameKey, ipAddress, now); err != nil {
+		return nil, nil, err
+	}
+
 	user, err := s.repos.User.GetByUsername(username)
 	if err != nil {
-		return nil, "", errors.New("invalid credentials")
+		// Unknown usernames cost a bcrypt compare and count as failures too, so neither the response time
+		// nor a lockout tells them apart from existing ones
+		bcrypt.CompareHashAndPassword(dummyPasswordHash(), []byte(password))
+		s.recordFailure(usernameKey, ipAddress, requestID, now)
+		return nil, nil, domains.ErrInvalidCredentials
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		return nil, "", errors.New("invalid credentials")
+		s.recordFailure(usernameKey, ipAddress, requestID, now)
+		return nil, nil, domains.ErrInvalidCredentials
+	}
+
+	challenge, err := s.loginChallenge(user, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	// With a second factor to come the login has not succeeded yet; CompleteMFAChallenge resets the throttle
+	if challenge == nil {
+		s.resetThrottle(usernameKey)
+	}
+	return user, challenge, nil
+}
+
+// loginChallenge starts the MFA step of a login, of a password or single sign-on login alike. Users who do not
+// need a second factor get none.
+func (s *authService) loginChallenge(user *models.User, now time.Time) (*domains.MFAChallenge, error) {
+	required, err := s.mfa.required(user)
+	if err != nil {
+		return nil, err
+	}
+	if !required {
+		return nil, nil
+	}
+
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return nil, fmt.Errorf("failed to generate mfa challenge: %w", err)
+	}
+	challengeToken := base64.RawURLEncoding.EncodeToString(random)
+
+	challenge := &models.MFAChallenge{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		TokenHash: hashRefreshToken(challengeToken),
+		ExpiresAt: now.Add(s.challengeTTL),
+		CreatedAt: now,
+	}
+	if err := s.repos.MFA.CreateChallenge(challenge); err != nil {
+		return nil, fmt.Errorf("failed to create mfa challenge: %w", err)
+	}
+
+	return &domains.MFAChallenge{
+		Token:              challengeToken,
+		ExpiresAt:          challenge.ExpiresAt,
+		EnrollmentRequired: user.MFAEnabledAt == nil,
+	}, nil
+}
+
+// EnrollMFAChallenge starts MFA enrollment for a user who has to set it up before logging in
+func (s *authService) EnrollMFAChallenge(mfaToken string) (*domains.MFAEnrollment, error) {
+	_, user, err := s.openChallenge(mfaToken)
+	if err != nil {
+		return nil, err
+	}
+	return s.mfa.Enroll(user.ID)
+}
+
+// CompleteMFAChallenge answers a login challenge with a TOTP or recovery code. For a user who enrolled with
+// the challenge, the code confirms the enrollment and the new recovery codes are returned. Wrong codes are
+// throttled like wrong passwords, so new challenges from logins with the password give no further guesses.
+func (s *authService) CompleteMFAChallenge(mfaToken, code, ipAddress, requestID string) (*models.User, []string, error) {
+	challenge, user, err := s.openChallenge(mfaToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	usernameKey := throttleKey(user.Username)
+	if err := s.checkThrottle(usernameKey, ipAddress, now); err != nil {
+		return nil, nil, err
+	}
+
+	var recoveryCodes []string
+	if user.MFAEnabledAt == nil {
+		recoveryCodes, err = s.mfa.Confirm(user.ID, code, ipAddress, requestID)
+	} else {
+		err = s.mfa.verify(user, code)
+	}
+	if errors.Is(err, domains.ErrInvalidMFACode) {
+		if err := s.repos.MFA.RecordFailedAttempt(challenge.ID); err != nil {
+			return nil, nil, fmt.Errorf("failed to record attempt: %w", err)
+		}
+		s.recordFailure(usernameKey, ipAddress, requestID, now)
+		return nil, nil, err
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	answered, err := s.repos.MFA.MarkChallengeUsed(challenge.ID, now)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to answer mfa challenge: %w", err)
+	}
+	if !answered {
+		return nil, nil, domains.ErrInvalidMFAChallenge
 	}
+	s.resetThrottle(usernameKey)
 
-	// Generate token would be imported from middleware
-	return user, "", nil
+	// Confirm changed the user
+	if recoveryCodes != nil {
+		if user, err = s.repos.User.GetByID(user.ID); err != nil {
+			return nil, nil, errors.New("user not found")
+		}
+	}
+	return user, recoveryCodes, nil
 }
 
+// openChallenge returns an unanswered login challenge that has attempts left, and its user
+func (s *authService) openChallenge(mfaToken string) (*models.MFAChallenge, *models.User, error) {
+	challenge, err := s.repos.MFA.GetChallengeByHash(hashRefreshToken(mfaToken))
+	if err != nil {
+		return nil, nil, domains.ErrInvalidMFAChallenge
+	}
+	if challenge.UsedAt != nil || time.Now().After(challenge.ExpiresAt) || challenge.Attempts >= s.maxChallengeAttempts {
+		return nil, nil, domains.ErrInvalidMFAChallenge
+	}
+
+	user, err := s.repos.User.GetByID(challenge.UserID)
+	if err != nil || !user.IsActive {
+		return nil, nil, domains.ErrInvalidMFAChallenge
+	}
+	return challenge, user, nil
+}
+
+// ValidateToken returns the active user an access token of a live session was issued to
 func (s *authService) ValidateToken(tokenString string) (*models.User, error) {
-	// Token validation logic would be here
-	return nil, nil
+	claims, err := middleware.ParseToken(s.keys, tokenString)
+	if err != nil {
+		return nil, errors.New("invalid token")
+	}
+
+	session, err := s.repos.Session.GetByID(claims.SessionID)
+	if err != nil || session.UserID != claims.UserID || session.RevokedAt != nil {
+		return nil, domains.ErrSessionRevoked
+	}
+
+	user, err := s.repos.User.GetByID(claims.UserID)
+	if err != nil || !user.IsActive {
+		return nil, errors.New("user not found or inactive")
+	}
+	return user, nil
+}
+
+// StartSession opens a session for a user who just logged in
+func (s *authService) StartSession(user *models.User, userAgent, ipAddress, requestID string) (*domains.TokenPair, error) {
+	now := time.Now()
+	session := &models.Session{
+		BaseModel:  models.BaseModel{ID: uuid.New(), CreatedBy: &user.ID, IPAddress: ipAddress, RequestID: requestID},
+		UserID:     user.ID,
+		UserAgent:  userAgent,
+		ExpiresAt:  now.Add(s.refreshTTL),
+		LastUsedAt: now,
+	}
+	if err := s.repos.Session.Create(session); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return s.issueTokens(user, session, now)
+}
+
+// Refresh rotates a refresh token: it is spent and a new access and refresh token are issued. A spent token
+// presented again means two parties hold it, so the session is revoked.
+func (s *authService) Refresh(refreshToken, userAgent, ipAddress, requestID string) (*domains.TokenPair, error) {
+	token, err := s.repos.Session.GetRefreshTokenByHash(hashRefreshToken(refreshToken))
+	if err != nil {
+		return nil, domains.ErrInvalidRefreshToken
+	}
+
+	session, err := s.repos.Session.GetByID(token.SessionID)
+	if err != nil {
+		return nil, domains.ErrInvalidRefreshToken
+	}
+	if session.RevokedAt != nil {
+		return nil, domains.ErrSessionRevoked
+	}
+
+	now := time.Now()
+	if token.UsedAt != nil {
+		s.revoke(session, models.SessionRevokedReuse, nil, ipAddress, requestID)
+		return nil, domains.ErrRefreshTokenReused
+	}
+	if now.After(token.ExpiresAt) {
+		return nil, domains.ErrInvalidRefreshToken
+	}
+
+	// Of two refreshes racing with the same token, the loser is treated as a reuse
+	marked, err := s.repos.Session.MarkRefreshTokenUsed(token.ID, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+	if !marked {
+		s.revoke(session, models.SessionRevokedReuse, nil, ipAddress, requestID)
+		return nil, domains.ErrRefreshTokenReused
+	}
+
+	user, err := s.repos.User.GetByID(session.UserID)
+	if err != nil || !user.IsActive {
+		return nil, errors.New("user not found or inactive")
+	}
+
+	session.ExpiresAt = now.Add(s.refreshTTL)
+	session.LastUsedAt = now
+	session.UserAgent = userAgent
+	session.IPAddress = ipAddress
+	session.RequestID = requestID
+	if err := s.repos.Session.Update(session); err != nil {
+		return nil, fmt.Errorf("failed to update session: %w", err)
+	}
+
+	return s.issueTokens(user, session, now)
+}
+
+// Logout revokes the session the request was made with
+func (s *authService) Logout(sessionID, userID uuid.UUID, ipAddress, requestID string) error {
+	session, err := s.repos.Session.GetByID(sessionID)
+	if err != nil || session.UserID != userID {
+		return errors.New("session not found")
+	}
+	if session.RevokedAt != nil {
+		return nil
+	}
+
+	s.revoke(session, models.SessionRevokedLogout, &userID, ipAddress, requestID)
+	return nil
+}
+
+// RevokeSessions signs a user out everywhere, returning how many sessions were active. Access tokens
+// already issued stop working on their next request.
+func (s *authService) RevokeSessions(userID, adminID uuid.UUID, ipAddress, requestID string) (int64, error) {
+	user, err := s.repos.User.GetByID(userID)
+	if err != nil {
+		return 0, errors.New("user not found")
+	}
+
+	revoked, err := s.repos.Session.RevokeAllForUser(user.ID, models.SessionRevokedByAdmin, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	createAuditLog("sessions", user.ID, "UPDATE", nil, map[string]interface{}{
+		"revoked_reason": models.SessionRevokedByAdmin,
+		"revoked":        revoked,
+	}, domains.UserActor(adminID), ipAddress, requestID, s.repos)
+
+	return revoked, nil
+}
+
+// ListLockouts returns the usernames and IP addresses currently locked out
+func (s *authService) ListLockouts() ([]models.LoginThrottle, error) {
+	return s.repos.LoginThrottle.GetLocked(time.Now())
+}
+
+// UnlockUser lifts the lockout of a user's username and forgets its failed logins
+func (s *authService) UnlockUser(userID, adminID uuid.UUID, ipAddress, requestID string) error {
+	user, err := s.repos.User.GetByID(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	throttle, err := s.repos.LoginThrottle.GetByKey(models.LoginThrottleUsername, strings.ToLower(user.Username))
+	if err != nil {
+		return errors.New("user has no failed logins")
+	}
+	return s.unlock(throttle, adminID, ipAddress, requestID)
+}
+
+// Unlock lifts a lockout, of a username or an IP address
+func (s *authService) Unlock(lockoutID, adminID uuid.UUID, ipAddress, requestID string) error {
+	throttle, err := s.repos.LoginThrottle.GetByID(lockoutID)
+	if err != nil {
+		return errors.New("lockout not found")
+	}
+	return s.unlock(throttle, adminID, ipAddress, requestID)
+}
+
+func (s *authService) unlock(throttle *models.LoginThrottle, adminID uuid.UUID, ipAddress, requestID string) error {
+	if err := s.repos.LoginThrottle.Delete(throttle.ID); err != nil {
+		return fmt.Errorf("failed to unlock: %w", err)
+	}
+
+	createAuditLog("login_throttles", throttle.ID, "DELETE", throttle, nil, domains.UserActor(adminID), ipAddress, requestID, s.repos)
+	return nil
+}
+
+// resetThrottle forgives the failures of a username once a login fully succeeded. The IP address is not
+// forgiven, or one valid account would reset a password spraying attack.
+func (s *authService) resetThrottle(usernameKey string) {
+	if err := s.repos.LoginThrottle.DeleteByKey(models.LoginThrottleUsername, usernameKey); err != nil {
+		logrus.WithError(err).Warn("Failed to reset login throttle")
+	}
+}
+
+// throttleKey is the username throttle key of a login name, the same however it is typed
+func throttleKey(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+// checkThrottle refuses a login while the username or IP address is locked out, or while the username's
+// delay after its last failure has not passed
+func (s *authService) checkThrottle(usernameKey, ipAddress string, now time.Time) error {
+	var throttled *domains.LoginThrottledError
+	for _, key := range []struct{ scope, key string }{
+		{models.LoginThrottleUsername, usernameKey},
+		{models.LoginThrottleIP, ipAddress},
+	} {
+		throttle, err := s.repos.LoginThrottle.GetByKey(key.scope, key.key)
+		if err != nil {
+			continue
+		}
+
+		wait, locked := s.throttleWait(throttle, now)
+		if wait > 0 && (throttled == nil || wait > throttled.RetryAfter) {
+			throttled = &domains.LoginThrottledError{RetryAfter: wait, Locked: locked}
+		}
+	}
+	if throttled != nil {
+		return throttled
+	}
+	return nil
+}
+
+// throttleWait is how long a throttled key has to wait for its next login attempt
+func (s *authService) throttleWait(throttle *models.LoginThrottle, now time.Time) (time.Duration, bool) {
+	if throttle.LockedUntil != nil && now.Before(*throttle.LockedUntil) {
+		return throttle.LockedUntil.Sub(now), true
+	}
+	if throttle.Scope != models.LoginThrottleUsername || throttle.Failures >= s.lockout.MaxFailures {
+		return 0, false
+	}
+	if throttle.LastFailureAt.Before(now.Add(-time.Duration(s.lockout.Window) * time.Minute)) {
+		return 0, false
+	}
+
+	// Progressive delay: the base delay, doubled with every further failure
+	delay := time.Duration(s.lockout.BaseDelay) * time.Second
+	for i := 1; i < throttle.Failures && delay < time.Duration(s.lockout.MaxDelay)*time.Second; i++ {
+		delay *= 2
+	}
+	if max := time.Duration(s.lockout.MaxDelay) * time.Second; delay > max {
+		delay = max
+	}
+	if wait := throttle.LastFailureAt.Add(delay).Sub(now); wait > 0 {
+		return wait, false
+	}
+	return 0, false
+}
+
+// recordFailure counts a failed login for the username and the IP address and locks out whichever reached
+// its limit
+func (s *authService) recordFailure(usernameKey, ipAddress, requestID string, now time.Time) {
+	window := time.Duration(s.lockout.Window) * time.Minute
+	for _, key := range []struct {
+		scope, key  string
+		maxFailures int
+	}{
+		{models.LoginThrottleUsername, usernameKey, s.lockout.MaxFailures},
+		{models.LoginThrottleIP, ipAddress, s.lockout.IPMaxFailures},
+	} {
+		throttle, err := s.repos.LoginThrottle.RecordFailure(key.scope, key.key, now, window)
+		if err != nil {
+			logrus.WithError(err).Warn("Failed to record failed login")
+			continue
+		}
+		if throttle.Failures < key.maxFailures || (throttle.LockedUntil != nil && now.Before(*throttle.LockedUntil)) {
+			continue
+		}
+
+		until := now.Add(time.Duration(s.lockout.Duration) * time.Minute)
+		if err := s.repos.LoginThrottle.Lock(throttle.ID, until); err != nil {
+			logrus.WithError(err).Warn("Failed to lock out login")
+			continue
+		}
+		throttle.LockedUntil = &until
+
+		logrus.WithFields(logrus.Fields{"scope": throttle.Scope, "key": throttle.Key, "failures": throttle.Failures}).Warn("Login locked out")
+		createAuditLog("login_throttles", throttle.ID, "UPDATE", nil, throttle, domains.Actor{}, ipAddress, requestID, s.repos)
+	}
+}
+
+// Impersonate issues a token for viewing the API as a user, to see what they see when they report a problem.
+// The token is read-only, belongs to the admin's session and cannot be refreshed; the user sees it was issued,
+// and every request made with it, in their activity.
+func (s *authService) Impersonate(userID, adminID, sessionID uuid.UUID, reason, ipAddress, requestID string) (*domains.ImpersonationToken, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" || len(reason) > 500 {
+		return nil, errors.New("reason must be 1 to 500 characters")
+	}
+
+	user, err := s.repos.User.GetByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if user.ID == adminID || !user.IsActive {
+		return nil, domains.ErrImpersonationNotAllowed
+	}
+	admin, err := s.repos.User.GetByID(adminID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	// Viewing as an admin would reach further than the permission to view as users does
+	permissions, err := s.repos.Role.GetUserPermissions(user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get permissions: %w", err)
+	}
+	if rbac.Privileged(permissions) {
+		return nil, domains.ErrImpersonationNotAllowed
+	}
+
+	roles, err := s.roleNames(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	accessToken, err := middleware.GenerateImpersonationToken(s.keys, user, roles, admin, sessionID, s.impersonationTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+	expiresAt := time.Now().Add(s.impersonationTTL)
+
+	// Nobody gets to view as a user without it being on record
+	details := map[string]interface{}{"reason": reason, "expires_at": expiresAt}
+	actor := domains.Actor{UserID: &user.ID, ImpersonatorID: &adminID}
+	if err := createAuditLog("users", user.ID, "IMPERSONATE", nil, details, actor, ipAddress, requestID, s.repos); err != nil {
+		return nil, fmt.Errorf("failed to record viewing as user: %w", err)
+	}
+
+	return &domains.ImpersonationToken{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(s.impersonationTTL.Seconds()),
+		ExpiresAt:   expiresAt,
+		UserID:      user.ID,
+		Username:    user.Username,
+	}, nil
+}
+
+// ListActivity returns the latest of what a user did, and of what admins did viewing as them
+func (s *authService) ListActivity(userID uuid.UUID) ([]domains.ActivityEntry, error) {
+	logs, err := s.repos.AuditLog.GetActivity(userID, activityLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	admins := map[uuid.UUID]*domains.ActivityActor{}
+	entries := make([]domains.ActivityEntry, len(logs))
+	for i, log := range logs {
+		entries[i] = domains.ActivityEntry{
+			ID:        log.ID,
+			Action:    log.Action,
+			TableName: log.TableName,
+			RecordID:  log.RecordID,
+			IPAddress: log.IPAddress,
+			RequestID: log.RequestID,
+			CreatedAt: log.CreatedAt,
+		}
+		if log.ImpersonatedUserID == nil {
+			continue
+		}
+
+		// Only what viewing as a user recorded is shown in full; other values may hold more than users see
+		if log.NewValues != "" {
+			if err := json.Unmarshal([]byte(log.NewValues), &entries[i].Details); err != nil {
+				return nil, fmt.Errorf("failed to read audit entry %s: %w", log.ID, err)
+			}
+		}
+		if *log.ImpersonatedUserID != userID || log.UserID == nil {
+			continue
+		}
+		admin, ok := admins[*log.UserID]
+		if !ok {
+			admin = &domains.ActivityActor{UserID: *log.UserID}
+			if user, err := s.repos.User.GetByID(*log.UserID); err == nil {
+				admin.Username = user.Username
+			}
+			admins[*log.UserID] = admin
+		}
+		entries[i].ViewedAsBy = admin
+	}
+	return entries, nil
+}
+
+func (s *authService) revoke(session *models.Session, reason string, actorID *uuid.UUID, ipAddress, requestID string) {
+	old := *session
+
+	now := time.Now()
+	session.RevokedAt = &now
+	session.RevokedReason = reason
+	session.UpdatedBy = actorID
+	if err := s.repos.Session.Update(session); err != nil {
+		return
+	}
+
+	createAuditLog("sessions", session.ID, "UPDATE", old, session, domains.Actor{UserID: actorID}, ipAddress, requestID, s.repos)
+}
+
+// issueTokens creates the next refresh token of the session and an access token
+func (s *authService) issueTokens(user *models.User, session *models.Session, now time.Time) (*domains.TokenPair, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	refreshToken := base64.RawURLEncoding.EncodeToString(random)
+
+	if err := s.repos.Session.CreateRefreshToken(&models.RefreshToken{
+		ID:        uuid.New(),
+		SessionID: session.ID,
+		TokenHash: hashRefreshToken(refreshToken),
+		ExpiresAt: session.ExpiresAt,
+		CreatedAt: now,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to store refresh token: %w", err)
+	}
+
+	names, err := s.roleNames(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := middleware.GenerateToken(s.keys, user, names, session.ID, s.accessTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	return &domains.TokenPair{
+		AccessToken:      accessToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int(s.accessTTL.Seconds()),
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: session.ExpiresAt,
+	}, nil
+}
+
+// roleNames returns the names of the roles a user holds, carried in access tokens
+func (s *authService) roleNames(userID uuid.UUID) ([]string, error) {
+	roles, err := s.repos.Role.GetUserRoles(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get roles: %w", err)
+	}
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = role.Name
+	}
+	return names, nil
+}
+
+// hashRefreshToken is how refresh tokens and MFA challenges are stored; they are random, so an unsalted hash
+// suffices
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
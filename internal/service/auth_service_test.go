@@ -0,0 +1,797 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"payslip-system/internal/config"
+	"payslip-system/internal/domains"
+	"payslip-system/internal/middleware"
+	"payslip-system/internal/models"
+	"payslip-system/internal/rbac"
+	"payslip-system/internal/repository"
+	mock_repository "payslip-system/internal/repository/mocks"
+	"payslip-system/internal/token"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+func newTestKeySet(t *testing.T) *token.KeySet {
+	keys, err := token.NewEphemeralKeySet()
+	require.NoError(t, err)
+	return keys
+}
+
+func Test_authService_StartSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "employee1", Role: "employee", IsActive: true}
+
+	mockSessionRepo := mock_repository.NewMockISessionRepository(ctrl)
+	var session *models.Session
+	mockSessionRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(s *models.Session) error {
+		session = s
+		return nil
+	})
+	var stored *models.RefreshToken
+	mockSessionRepo.EXPECT().CreateRefreshToken(gomock.Any()).DoAndReturn(func(token *models.RefreshToken) error {
+		stored = token
+		return nil
+	})
+
+	mockRoleRepo := mock_repository.NewMockIRoleRepository(ctrl)
+	mockRoleRepo.EXPECT().GetUserRoles(user.ID).Return([]models.Role{{Name: "employee"}, {Name: "hr_viewer"}}, nil)
+
+	repos := &repository.Repositories{Session: mockSessionRepo, Role: mockRoleRepo}
+	keys := newTestKeySet(t)
+	s := NewAuthService(repos, config.AuthConfig{AccessTokenTTL: 15, RefreshTokenTTL: 168}, keys, nil)
+
+	tokens, err := s.StartSession(user, "curl/8.0", "127.0.0.1", "req-123")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Bearer", tokens.TokenType)
+	assert.Equal(t, 900, tokens.ExpiresIn)
+	assert.Equal(t, user.ID, session.UserID)
+	assert.WithinDuration(t, time.Now().Add(168*time.Hour), session.ExpiresAt, time.Minute)
+
+	// Only the hash of the refresh token is stored
+	assert.Equal(t, session.ID, stored.SessionID)
+	assert.Equal(t, hashRefreshToken(tokens.RefreshToken), stored.TokenHash)
+	assert.NotContains(t, stored.TokenHash, tokens.RefreshToken)
+
+	claims, err := middleware.ParseToken(keys, tokens.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, claims.UserID)
+	assert.Equal(t, session.ID, claims.SessionID)
+	assert.Equal(t, []string{"employee", "hr_viewer"}, claims.Roles)
+	assert.NotEmpty(t, claims.ID)
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), claims.ExpiresAt.Time, time.Minute)
+}
+
+func Test_authService_Refresh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	sessionID := uuid.New()
+	used := time.Now().Add(-time.Minute)
+	revoked := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name       string
+		token      *models.RefreshToken
+		revokedAt  *time.Time
+		lostRace   bool
+		inactive   bool
+		wantRevoke bool
+		wantErr    error
+	}{
+		{name: "success", token: &models.RefreshToken{ExpiresAt: time.Now().Add(time.Hour)}},
+		{name: "error - unknown token", wantErr: domains.ErrInvalidRefreshToken},
+		{name: "error - expired", token: &models.RefreshToken{ExpiresAt: time.Now().Add(-time.Second)}, wantErr: domains.ErrInvalidRefreshToken},
+		{name: "error - session revoked", token: &models.RefreshToken{ExpiresAt: time.Now().Add(time.Hour)}, revokedAt: &revoked, wantErr: domains.ErrSessionRevoked},
+		{name: "error - reused token revokes the session", token: &models.RefreshToken{ExpiresAt: time.Now().Add(time.Hour), UsedAt: &used}, wantRevoke: true, wantErr: domains.ErrRefreshTokenReused},
+		{name: "error - concurrent refresh revokes the session", token: &models.RefreshToken{ExpiresAt: time.Now().Add(time.Hour)}, lostRace: true, wantRevoke: true, wantErr: domains.ErrRefreshTokenReused},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSessionRepo := mock_repository.NewMockISessionRepository(ctrl)
+			mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+			mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+			mockRoleRepo := mock_repository.NewMockIRoleRepository(ctrl)
+
+			if tt.token == nil {
+				mockSessionRepo.EXPECT().GetRefreshTokenByHash(hashRefreshToken("refresh-token")).Return(nil, assert.AnError)
+			} else {
+				tt.token.ID = uuid.New()
+				tt.token.SessionID = sessionID
+				mockSessionRepo.EXPECT().GetRefreshTokenByHash(hashRefreshToken("refresh-token")).Return(tt.token, nil)
+				mockSessionRepo.EXPECT().GetByID(sessionID).Return(&models.Session{BaseModel: models.BaseModel{ID: sessionID}, UserID: userID, RevokedAt: tt.revokedAt}, nil)
+			}
+
+			if tt.wantErr == nil || tt.lostRace {
+				mockSessionRepo.EXPECT().MarkRefreshTokenUsed(gomock.Any(), gomock.Any()).Return(!tt.lostRace, nil)
+			}
+			if tt.wantRevoke {
+				mockSessionRepo.EXPECT().Update(gomock.Any()).DoAndReturn(func(session *models.Session) error {
+					assert.NotNil(t, session.RevokedAt)
+					assert.Equal(t, models.SessionRevokedReuse, session.RevokedReason)
+					return nil
+				})
+				mockAuditLogRepo.EXPECT().Create(gomock.Any()).Return(nil)
+			}
+			if tt.wantErr == nil {
+				mockUserRepo.EXPECT().GetByID(userID).Return(&models.User{BaseModel: models.BaseModel{ID: userID}, Username: "employee1", IsActive: true}, nil)
+				mockSessionRepo.EXPECT().Update(gomock.Any()).Return(nil)
+				mockRoleRepo.EXPECT().GetUserRoles(userID).Return([]models.Role{{Name: "employee"}}, nil)
+				mockSessionRepo.EXPECT().CreateRefreshToken(gomock.Any()).DoAndReturn(func(token *models.RefreshToken) error {
+					assert.Equal(t, sessionID, token.SessionID)
+					assert.NotEqual(t, hashRefreshToken("refresh-token"), token.TokenHash)
+					return nil
+				})
+			}
+
+			repos := &repository.Repositories{Session: mockSessionRepo, User: mockUserRepo, AuditLog: mockAuditLogRepo, Role: mockRoleRepo}
+			keys := newTestKeySet(t)
+			s := NewAuthService(repos, config.AuthConfig{AccessTokenTTL: 15, RefreshTokenTTL: 168}, keys, nil)
+
+			tokens, err := s.Refresh("refresh-token", "curl/8.0", "127.0.0.1", "req-123")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEqual(t, "refresh-token", tokens.RefreshToken)
+
+			claims, err := middleware.ParseToken(keys, tokens.AccessToken)
+			require.NoError(t, err)
+			assert.Equal(t, sessionID, claims.SessionID)
+		})
+	}
+}
+
+func Test_authService_Logout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	sessionID := uuid.New()
+
+	mockSessionRepo := mock_repository.NewMockISessionRepository(ctrl)
+	mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+
+	mockSessionRepo.EXPECT().GetByID(sessionID).Return(&models.Session{BaseModel: models.BaseModel{ID: sessionID}, UserID: userID}, nil).Times(2)
+	mockSessionRepo.EXPECT().Update(gomock.Any()).DoAndReturn(func(session *models.Session) error {
+		assert.Equal(t, models.SessionRevokedLogout, session.RevokedReason)
+		assert.Equal(t, &userID, session.UpdatedBy)
+		return nil
+	})
+	mockAuditLogRepo.EXPECT().Create(gomock.Any()).Return(nil)
+
+	repos := &repository.Repositories{Session: mockSessionRepo, AuditLog: mockAuditLogRepo}
+	s := NewAuthService(repos, config.AuthConfig{}, newTestKeySet(t), nil)
+
+	require.NoError(t, s.Logout(sessionID, userID, "127.0.0.1", "req-123"))
+
+	// Another user's session cannot be ended
+	assert.Error(t, s.Logout(sessionID, uuid.New(), "127.0.0.1", "req-123"))
+}
+
+func Test_authService_RevokeSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	adminID := uuid.New()
+
+	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+	mockSessionRepo := mock_repository.NewMockISessionRepository(ctrl)
+	mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+
+	mockUserRepo.EXPECT().GetByID(userID).Return(&models.User{BaseModel: models.BaseModel{ID: userID}}, nil)
+	mockUserRepo.EXPECT().GetByID(gomock.Any()).Return(nil, assert.AnError)
+	mockSessionRepo.EXPECT().RevokeAllForUser(userID, models.SessionRevokedByAdmin, gomock.Any()).Return(int64(3), nil)
+	mockAuditLogRepo.EXPECT().Create(gomock.Any()).Return(nil)
+
+	repos := &repository.Repositories{User: mockUserRepo, Session: mockSessionRepo, AuditLog: mockAuditLogRepo}
+	s := NewAuthService(repos, config.AuthConfig{}, newTestKeySet(t), nil)
+
+	revoked, err := s.RevokeSessions(userID, adminID, "127.0.0.1", "req-123")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), revoked)
+
+	_, err = s.RevokeSessions(uuid.New(), adminID, "127.0.0.1", "req-123")
+	assert.Error(t, err)
+}
+
+func Test_authService_Login(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		role           string
+		mfaEnabled     bool
+		password       string
+		wantChallenge  bool
+		wantEnrollment bool
+		wantErr        bool
+	}{
+		{name: "employee without MFA gets no challenge", role: "employee", password: "password123"},
+		{name: "employee with MFA is challenged", role: "employee", mfaEnabled: true, password: "password123", wantChallenge: true},
+		{name: "admin with MFA is challenged", role: "admin", mfaEnabled: true, password: "password123", wantChallenge: true},
+		{name: "admin without MFA has to enroll", role: "admin", password: "password123", wantChallenge: true, wantEnrollment: true},
+		{name: "finance without MFA has to enroll", role: "finance", password: "password123", wantChallenge: true, wantEnrollment: true},
+		{name: "error - wrong password", role: "admin", password: "wrong", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, _ := newMFAUser(t, tt.role, tt.mfaEnabled)
+			user.Password = string(hashed)
+
+			mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+			mockMFARepo := mock_repository.NewMockIMFARepository(ctrl)
+			mockThrottleRepo := mock_repository.NewMockILoginThrottleRepository(ctrl)
+			mockRoleRepo := mock_repository.NewMockIRoleRepository(ctrl)
+			mockUserRepo.EXPECT().GetByUsername(user.Username).Return(user, nil)
+			mockThrottleRepo.EXPECT().GetByKey(gomock.Any(), gomock.Any()).Return(nil, assert.AnError).Times(2)
+			if tt.wantErr {
+				mockThrottleRepo.EXPECT().RecordFailure(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.LoginThrottle{Failures: 1}, nil).Times(2)
+			} else if !tt.wantChallenge {
+				// Failures are only forgiven once the second factor is answered too
+				mockThrottleRepo.EXPECT().DeleteByKey(models.LoginThrottleUsername, user.Username).Return(nil)
+			}
+			if !tt.wantErr && !tt.mfaEnabled {
+				mockRoleRepo.EXPECT().GetUserPermissions(user.ID).Return(builtinPermissions(tt.role), nil)
+			}
+
+			var stored *models.MFAChallenge
+			if tt.wantChallenge {
+				mockMFARepo.EXPECT().CreateChallenge(gomock.Any()).DoAndReturn(func(challenge *models.MFAChallenge) error {
+					stored = challenge
+					return nil
+				})
+			}
+
+			repos := &repository.Repositories{User: mockUserRepo, MFA: mockMFARepo, LoginThrottle: mockThrottleRepo, Role: mockRoleRepo}
+			s := NewAuthService(repos, config.AuthConfig{MFA: testMFAConfig, Lockout: testLockoutConfig}, newTestKeySet(t), NewMFAService(repos, testMFAConfig))
+
+			loggedIn, challenge, err := s.Login(user.Username, tt.password, "127.0.0.1", "req-123")
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, loggedIn)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, user.ID, loggedIn.ID)
+
+			if !tt.wantChallenge {
+				assert.Nil(t, challenge)
+				return
+			}
+			require.NotNil(t, challenge)
+			assert.Equal(t, tt.wantEnrollment, challenge.EnrollmentRequired)
+			assert.Equal(t, hashRefreshToken(challenge.Token), stored.TokenHash)
+			assert.WithinDuration(t, time.Now().Add(5*time.Minute), challenge.ExpiresAt, time.Minute)
+		})
+	}
+}
+
+func Test_authService_CompleteMFAChallenge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	used := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name        string
+		challenge   *models.MFAChallenge
+		enrolling   bool
+		wrongCode   bool
+		wantCodes   bool
+		wantAttempt bool
+		locked      bool
+		wantErr     error
+	}{
+		{name: "success", challenge: &models.MFAChallenge{ExpiresAt: time.Now().Add(time.Minute)}},
+		{name: "success - enrollment is confirmed", challenge: &models.MFAChallenge{ExpiresAt: time.Now().Add(time.Minute)}, enrolling: true, wantCodes: true},
+		{name: "error - wrong code counts an attempt and a login failure", challenge: &models.MFAChallenge{ExpiresAt: time.Now().Add(time.Minute)}, wrongCode: true, wantAttempt: true, wantErr: domains.ErrInvalidMFACode},
+		{name: "error - locked username", challenge: &models.MFAChallenge{ExpiresAt: time.Now().Add(time.Minute)}, locked: true},
+		{name: "error - unknown challenge", wantErr: domains.ErrInvalidMFAChallenge},
+		{name: "error - expired", challenge: &models.MFAChallenge{ExpiresAt: time.Now().Add(-time.Second)}, wantErr: domains.ErrInvalidMFAChallenge},
+		{name: "error - already answered", challenge: &models.MFAChallenge{ExpiresAt: time.Now().Add(time.Minute), UsedAt: &used}, wantErr: domains.ErrInvalidMFAChallenge},
+		{name: "error - out of attempts", challenge: &models.MFAChallenge{ExpiresAt: time.Now().Add(time.Minute), Attempts: 3}, wantErr: domains.ErrInvalidMFAChallenge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, secret := newMFAUser(t, "admin", !tt.enrolling)
+			code := currentCode(t, secret)
+			if tt.wrongCode {
+				code = "000000"
+				if currentCode(t, secret) == code {
+					code = "111111"
+				}
+			}
+
+			mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+			mockMFARepo := mock_repository.NewMockIMFARepository(ctrl)
+			mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+			mockThrottleRepo := mock_repository.NewMockILoginThrottleRepository(ctrl)
+
+			if tt.challenge == nil {
+				mockMFARepo.EXPECT().GetChallengeByHash(hashRefreshToken("mfa-token")).Return(nil, assert.AnError)
+			} else {
+				tt.challenge.ID = uuid.New()
+				tt.challenge.UserID = user.ID
+				mockMFARepo.EXPECT().GetChallengeByHash(hashRefreshToken("mfa-token")).Return(tt.challenge, nil)
+			}
+			if tt.challenge != nil && tt.challenge.UsedAt == nil && tt.challenge.Attempts == 0 && tt.challenge.ExpiresAt.After(time.Now()) {
+				mockUserRepo.EXPECT().GetByID(user.ID).Return(user, nil).AnyTimes()
+				if tt.locked {
+					lockedUntil := time.Now().Add(10 * time.Minute)
+					mockThrottleRepo.EXPECT().GetByKey(models.LoginThrottleUsername, user.Username).
+						Return(&models.LoginThrottle{Scope: models.LoginThrottleUsername, Key: user.Username, Failures: 5, LastFailureAt: time.Now(), LockedUntil: &lockedUntil}, nil)
+				} else {
+					mockThrottleRepo.EXPECT().GetByKey(models.LoginThrottleUsername, user.Username).Return(nil, assert.AnError)
+				}
+				mockThrottleRepo.EXPECT().GetByKey(models.LoginThrottleIP, "127.0.0.1").Return(nil, assert.AnError)
+			}
+			if tt.wantAttempt {
+				mockMFARepo.EXPECT().RecordFailedAttempt(tt.challenge.ID).Return(nil)
+				mockThrottleRepo.EXPECT().RecordFailure(models.LoginThrottleUsername, user.Username, gomock.Any(), 15*time.Minute).Return(&models.LoginThrottle{Failures: 1}, nil)
+				mockThrottleRepo.EXPECT().RecordFailure(models.LoginThrottleIP, "127.0.0.1", gomock.Any(), 15*time.Minute).Return(&models.LoginThrottle{Failures: 1}, nil)
+			}
+			if tt.wantErr == nil && !tt.locked {
+				mockMFARepo.EXPECT().MarkTOTPStepUsed(user.ID, gomock.Any()).Return(true, nil)
+				mockMFARepo.EXPECT().MarkChallengeUsed(tt.challenge.ID, gomock.Any()).Return(true, nil)
+				mockThrottleRepo.EXPECT().DeleteByKey(models.LoginThrottleUsername, user.Username).Return(nil)
+			}
+			if tt.wantCodes {
+				mockUserRepo.EXPECT().Update(user).Return(nil)
+				mockMFARepo.EXPECT().ReplaceRecoveryCodes(user.ID, gomock.Any()).Return(nil)
+				mockAuditLogRepo.EXPECT().Create(gomock.Any()).Return(nil)
+			}
+
+			repos := &repository.Repositories{User: mockUserRepo, MFA: mockMFARepo, AuditLog: mockAuditLogRepo, LoginThrottle: mockThrottleRepo}
+			s := NewAuthService(repos, config.AuthConfig{MFA: testMFAConfig, Lockout: testLockoutConfig}, newTestKeySet(t), NewMFAService(repos, testMFAConfig))
+
+			loggedIn, codes, err := s.CompleteMFAChallenge("mfa-token", code, "127.0.0.1", "req-123")
+			if tt.locked {
+				var throttled *domains.LoginThrottledError
+				require.ErrorAs(t, err, &throttled)
+				assert.True(t, throttled.Locked)
+				return
+			}
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, user.ID, loggedIn.ID)
+			if tt.wantCodes {
+				assert.Len(t, codes, recoveryCodeCount)
+				assert.NotNil(t, loggedIn.MFAEnabledAt)
+			} else {
+				assert.Nil(t, codes)
+			}
+		})
+	}
+}
+
+// Knowing the password is not enough to keep guessing MFA codes: the wrong codes lock the username out, and
+// the right password does not forgive them
+func Test_authService_Login_WrongMFACodes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	require.NoError(t, err)
+	user, secret := newMFAUser(t, "employee", true)
+	user.Password = string(hashed)
+	wrong := "000000"
+	if currentCode(t, secret) == wrong {
+		wrong = "111111"
+	}
+
+	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+	mockMFARepo := mock_repository.NewMockIMFARepository(ctrl)
+	mockThrottleRepo := mock_repository.NewMockILoginThrottleRepository(ctrl)
+	mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+	mockRoleRepo := mock_repository.NewMockIRoleRepository(ctrl)
+
+	mockUserRepo.EXPECT().GetByUsername(user.Username).Return(user, nil).AnyTimes()
+	mockUserRepo.EXPECT().GetByID(user.ID).Return(user, nil).AnyTimes()
+	mockRoleRepo.EXPECT().GetUserPermissions(user.ID).Return(builtinPermissions("employee"), nil).AnyTimes()
+	mockAuditLogRepo.EXPECT().Create(gomock.Any()).Return(nil).AnyTimes()
+
+	challenges := map[string]*models.MFAChallenge{}
+	mockMFARepo.EXPECT().CreateChallenge(gomock.Any()).DoAndReturn(func(challenge *models.MFAChallenge) error {
+		challenges[challenge.TokenHash] = challenge
+		return nil
+	}).AnyTimes()
+	mockMFARepo.EXPECT().GetChallengeByHash(gomock.Any()).DoAndReturn(func(hash string) (*models.MFAChallenge, error) {
+		if challenge, ok := challenges[hash]; ok {
+			return challenge, nil
+		}
+		return nil, gorm.ErrRecordNotFound
+	}).AnyTimes()
+	mockMFARepo.EXPECT().RecordFailedAttempt(gomock.Any()).DoAndReturn(func(id uuid.UUID) error {
+		for _, challenge := range challenges {
+			if challenge.ID == id {
+				challenge.Attempts++
+			}
+		}
+		return nil
+	}).AnyTimes()
+
+	// No DeleteByKey: a login waiting for its second factor must not reset the throttle
+	throttles := map[string]*models.LoginThrottle{}
+	mockThrottleRepo.EXPECT().GetByKey(gomock.Any(), gomock.Any()).DoAndReturn(func(scope, key string) (*models.LoginThrottle, error) {
+		if throttle, ok := throttles[scope+"/"+key]; ok {
+			return throttle, nil
+		}
+		return nil, gorm.ErrRecordNotFound
+	}).AnyTimes()
+	mockThrottleRepo.EXPECT().RecordFailure(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(func(scope, key string, at time.Time, window time.Duration) (*models.LoginThrottle, error) {
+		throttle, ok := throttles[scope+"/"+key]
+		if !ok {
+			throttle = &models.LoginThrottle{ID: uuid.New(), Scope: scope, Key: key}
+			throttles[scope+"/"+key] = throttle
+		}
+		throttle.Failures++
+		throttle.LastFailureAt = at
+		return throttle, nil
+	}).AnyTimes()
+	mockThrottleRepo.EXPECT().Lock(gomock.Any(), gomock.Any()).DoAndReturn(func(id uuid.UUID, until time.Time) error {
+		for _, throttle := range throttles {
+			if throttle.ID == id {
+				throttle.LockedUntil = &until
+			}
+		}
+		return nil
+	}).AnyTimes()
+
+	// Without delays between attempts, so only the lockout stops the guessing
+	lockout := config.LockoutConfig{MaxFailures: 5, IPMaxFailures: 20, Window: 15, Duration: 15}
+	repos := &repository.Repositories{User: mockUserRepo, MFA: mockMFARepo, LoginThrottle: mockThrottleRepo, AuditLog: mockAuditLogRepo, Role: mockRoleRepo}
+	s := NewAuthService(repos, config.AuthConfig{MFA: testMFAConfig, Lockout: lockout}, newTestKeySet(t), NewMFAService(repos, testMFAConfig))
+
+	for i := 0; i < lockout.MaxFailures; i++ {
+		_, challenge, err := s.Login(user.Username, "password123", "10.0.0.1", "req-123")
+		require.NoError(t, err)
+		require.NotNil(t, challenge)
+
+		_, _, err = s.CompleteMFAChallenge(challenge.Token, wrong, "10.0.0.1", "req-123")
+		require.ErrorIs(t, err, domains.ErrInvalidMFACode)
+		assert.Equal(t, i+1, throttles[models.LoginThrottleUsername+"/"+user.Username].Failures)
+	}
+
+	_, _, err = s.Login(user.Username, "password123", "10.0.0.1", "req-123")
+	var throttled *domains.LoginThrottledError
+	require.ErrorAs(t, err, &throttled)
+	assert.True(t, throttled.Locked)
+}
+
+var testLockoutConfig = config.LockoutConfig{MaxFailures: 5, IPMaxFailures: 20, Window: 15, Duration: 15, BaseDelay: 1, MaxDelay: 30}
+
+func Test_authService_Login_Throttle(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	require.NoError(t, err)
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "employee1", Password: string(hashed), Role: "employee", IsActive: true}
+
+	now := time.Now()
+	lockedUntil := now.Add(10 * time.Minute)
+	expired := now.Add(-time.Minute)
+
+	tests := []struct {
+		name          string
+		username      string
+		password      string
+		userThrottle  *models.LoginThrottle
+		ipThrottle    *models.LoginThrottle
+		failures      int // username failures after this attempt
+		wantThrottled bool
+		wantLocked    bool
+		wantWait      time.Duration
+		wantLockout   bool
+		wantErr       error
+	}{
+		{name: "locked username is refused without checking the password", username: "employee1", password: "password123", userThrottle: &models.LoginThrottle{Failures: 5, LastFailureAt: now, LockedUntil: &lockedUntil}, wantThrottled: true, wantLocked: true, wantWait: 10 * time.Minute},
+		{name: "locked IP address is refused", username: "employee1", password: "password123", ipThrottle: &models.LoginThrottle{Scope: models.LoginThrottleIP, Failures: 20, LastFailureAt: now, LockedUntil: &lockedUntil}, wantThrottled: true, wantLocked: true, wantWait: 10 * time.Minute},
+		{name: "retry within the progressive delay is refused", username: "employee1", password: "password123", userThrottle: &models.LoginThrottle{Failures: 3, LastFailureAt: now.Add(-time.Second)}, wantThrottled: true, wantWait: 3 * time.Second},
+		{name: "retry after the delay is checked", username: "employee1", password: "password123", userThrottle: &models.LoginThrottle{Failures: 3, LastFailureAt: now.Add(-5 * time.Second)}},
+		{name: "expired lockout is checked", username: "employee1", password: "password123", userThrottle: &models.LoginThrottle{Failures: 5, LastFailureAt: now.Add(-20 * time.Minute), LockedUntil: &expired}},
+		{name: "wrong password counts a failure", username: "employee1", password: "wrong", failures: 2, wantErr: domains.ErrInvalidCredentials},
+		{name: "fifth failure locks the username", username: "Employee1", password: "wrong", failures: 5, wantLockout: true, wantErr: domains.ErrInvalidCredentials},
+		{name: "unknown username counts a failure like a wrong password", username: "nobody", password: "password123", failures: 5, wantLockout: true, wantErr: domains.ErrInvalidCredentials},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+			mockThrottleRepo := mock_repository.NewMockILoginThrottleRepository(ctrl)
+			mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+			mockRoleRepo := mock_repository.NewMockIRoleRepository(ctrl)
+
+			key := strings.ToLower(tt.username)
+			if tt.userThrottle != nil {
+				tt.userThrottle.Scope = models.LoginThrottleUsername
+				tt.userThrottle.Key = key
+				mockThrottleRepo.EXPECT().GetByKey(models.LoginThrottleUsername, key).Return(tt.userThrottle, nil)
+			} else {
+				mockThrottleRepo.EXPECT().GetByKey(models.LoginThrottleUsername, key).Return(nil, assert.AnError)
+			}
+			if tt.ipThrottle != nil {
+				mockThrottleRepo.EXPECT().GetByKey(models.LoginThrottleIP, "10.0.0.1").Return(tt.ipThrottle, nil)
+			} else {
+				mockThrottleRepo.EXPECT().GetByKey(models.LoginThrottleIP, "10.0.0.1").Return(nil, assert.AnError)
+			}
+
+			if !tt.wantThrottled {
+				if tt.username == "nobody" {
+					mockUserRepo.EXPECT().GetByUsername(tt.username).Return(nil, assert.AnError)
+				} else {
+					mockUserRepo.EXPECT().GetByUsername(tt.username).Return(user, nil)
+				}
+			}
+			if tt.wantErr == nil && !tt.wantThrottled {
+				mockThrottleRepo.EXPECT().DeleteByKey(models.LoginThrottleUsername, key).Return(nil)
+				mockRoleRepo.EXPECT().GetUserPermissions(user.ID).Return(builtinPermissions("employee"), nil)
+			}
+			if tt.failures > 0 {
+				throttleID := uuid.New()
+				mockThrottleRepo.EXPECT().RecordFailure(models.LoginThrottleUsername, key, gomock.Any(), 15*time.Minute).
+					Return(&models.LoginThrottle{ID: throttleID, Scope: models.LoginThrottleUsername, Key: key, Failures: tt.failures}, nil)
+				mockThrottleRepo.EXPECT().RecordFailure(models.LoginThrottleIP, "10.0.0.1", gomock.Any(), 15*time.Minute).
+					Return(&models.LoginThrottle{ID: uuid.New(), Scope: models.LoginThrottleIP, Key: "10.0.0.1", Failures: tt.failures}, nil)
+				if tt.wantLockout {
+					mockThrottleRepo.EXPECT().Lock(throttleID, gomock.Any()).DoAndReturn(func(id uuid.UUID, until time.Time) error {
+						assert.WithinDuration(t, time.Now().Add(15*time.Minute), until, time.Minute)
+						return nil
+					})
+					mockAuditLogRepo.EXPECT().Create(gomock.Any()).Return(nil)
+				}
+			}
+
+			repos := &repository.Repositories{User: mockUserRepo, LoginThrottle: mockThrottleRepo, AuditLog: mockAuditLogRepo, Role: mockRoleRepo}
+			s := NewAuthService(repos, config.AuthConfig{Lockout: testLockoutConfig}, newTestKeySet(t), NewMFAService(repos, testMFAConfig))
+
+			loggedIn, _, err := s.Login(tt.username, tt.password, "10.0.0.1", "req-123")
+			if tt.wantThrottled {
+				var throttled *domains.LoginThrottledError
+				require.ErrorAs(t, err, &throttled)
+				assert.Equal(t, tt.wantLocked, throttled.Locked)
+				assert.InDelta(t, tt.wantWait.Seconds(), throttled.RetryAfter.Seconds(), 1)
+				return
+			}
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, user.ID, loggedIn.ID)
+		})
+	}
+}
+
+// An unknown username takes about as long to reject as a wrong password
+func Test_authService_Login_PasswordLoginDisabled(t *testing.T) {
+	// No repository is touched, so nothing about the user is revealed or throttled
+	s := NewAuthService(&repository.Repositories{}, config.AuthConfig{PasswordLoginDisabled: true}, newTestKeySet(t), nil)
+
+	user, challenge, err := s.Login("employee1", "password123", "127.0.0.1", "req-123")
+	assert.ErrorIs(t, err, domains.ErrPasswordLoginDisabled)
+	assert.Nil(t, user)
+	assert.Nil(t, challenge)
+}
+
+func Test_authService_Login_UnknownUserTiming(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	require.NoError(t, err)
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "employee1", Password: string(hashed), IsActive: true}
+
+	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+	mockThrottleRepo := mock_repository.NewMockILoginThrottleRepository(ctrl)
+	mockUserRepo.EXPECT().GetByUsername("employee1").Return(user, nil).AnyTimes()
+	mockUserRepo.EXPECT().GetByUsername("nobody").Return(nil, assert.AnError).AnyTimes()
+	mockThrottleRepo.EXPECT().GetByKey(gomock.Any(), gomock.Any()).Return(nil, assert.AnError).AnyTimes()
+	mockThrottleRepo.EXPECT().RecordFailure(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.LoginThrottle{Failures: 1}, nil).AnyTimes()
+
+	repos := &repository.Repositories{User: mockUserRepo, LoginThrottle: mockThrottleRepo}
+	s := NewAuthService(repos, config.AuthConfig{Lockout: testLockoutConfig}, newTestKeySet(t), nil)
+	dummyPasswordHash()
+
+	measure := func(username string) time.Duration {
+		start := time.Now()
+		_, _, err := s.Login(username, "wrong", "10.0.0.1", "req-123")
+		assert.ErrorIs(t, err, domains.ErrInvalidCredentials)
+		return time.Since(start)
+	}
+
+	known := measure("employee1")
+	unknown := measure("nobody")
+	assert.Greater(t, unknown, known/2, "unknown %s, known %s", unknown, known)
+}
+
+func Test_authService_UnlockUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	adminID := uuid.New()
+	throttle := &models.LoginThrottle{ID: uuid.New(), Scope: models.LoginThrottleUsername, Key: "employee1", Failures: 5}
+
+	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+	mockThrottleRepo := mock_repository.NewMockILoginThrottleRepository(ctrl)
+	mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+
+	mockUserRepo.EXPECT().GetByID(userID).Return(&models.User{BaseModel: models.BaseModel{ID: userID}, Username: "Employee1"}, nil)
+	mockThrottleRepo.EXPECT().GetByKey(models.LoginThrottleUsername, "employee1").Return(throttle, nil)
+	mockThrottleRepo.EXPECT().Delete(throttle.ID).Return(nil)
+	mockAuditLogRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(log *models.AuditLog) error {
+		assert.Equal(t, "DELETE", log.Action)
+		assert.Equal(t, &adminID, log.UserID)
+		return nil
+	})
+
+	repos := &repository.Repositories{User: mockUserRepo, LoginThrottle: mockThrottleRepo, AuditLog: mockAuditLogRepo}
+	s := NewAuthService(repos, config.AuthConfig{Lockout: testLockoutConfig}, newTestKeySet(t), nil)
+
+	require.NoError(t, s.UnlockUser(userID, adminID, "127.0.0.1", "req-123"))
+}
+
+func Test_authService_Impersonate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	adminID := uuid.New()
+	sessionID := uuid.New()
+
+	tests := []struct {
+		name        string
+		reason      string
+		self        bool
+		inactive    bool
+		permissions []string
+		auditErr    error
+		wantErr     string
+	}{
+		{name: "success", reason: " Ticket #4711: wrong overtime pay ", permissions: builtinPermissions(rbac.RoleEmployee)},
+		{name: "not recorded", reason: "Ticket #4711: wrong overtime pay", permissions: builtinPermissions(rbac.RoleEmployee), auditErr: errors.New("connection reset"), wantErr: "failed to record"},
+		{name: "no reason", reason: " ", wantErr: "reason must be"},
+		{name: "oneself", reason: "testing", self: true, wantErr: domains.ErrImpersonationNotAllowed.Error()},
+		{name: "inactive user", reason: "testing", inactive: true, wantErr: domains.ErrImpersonationNotAllowed.Error()},
+		{name: "admin", reason: "testing", permissions: builtinPermissions(rbac.RoleHRViewer), wantErr: domains.ErrImpersonationNotAllowed.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "employee1", IsActive: !tt.inactive}
+			if tt.self {
+				user.ID = adminID
+			}
+			admin := &models.User{BaseModel: models.BaseModel{ID: adminID}, Username: "support1", IsActive: true}
+
+			mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+			mockRoleRepo := mock_repository.NewMockIRoleRepository(ctrl)
+			mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+
+			if strings.TrimSpace(tt.reason) != "" {
+				mockUserRepo.EXPECT().GetByID(user.ID).Return(user, nil)
+			}
+			if tt.permissions != nil {
+				mockUserRepo.EXPECT().GetByID(adminID).Return(admin, nil)
+				mockRoleRepo.EXPECT().GetUserPermissions(user.ID).Return(tt.permissions, nil)
+			}
+			if tt.wantErr == "" || tt.auditErr != nil {
+				mockRoleRepo.EXPECT().GetUserRoles(user.ID).Return([]models.Role{{Name: rbac.RoleEmployee}}, nil)
+				mockAuditLogRepo.EXPECT().Create(gomock.Any()).DoAndReturn(func(log *models.AuditLog) error {
+					assert.Equal(t, "IMPERSONATE", log.Action)
+					assert.Equal(t, &adminID, log.UserID)
+					assert.Equal(t, &user.ID, log.ImpersonatedUserID)
+					assert.Contains(t, log.NewValues, `"reason":"Ticket #4711: wrong overtime pay"`)
+					return tt.auditErr
+				})
+			}
+
+			repos := &repository.Repositories{User: mockUserRepo, Role: mockRoleRepo, AuditLog: mockAuditLogRepo}
+			keys := newTestKeySet(t)
+			s := NewAuthService(repos, config.AuthConfig{AccessTokenTTL: 15, ImpersonationTTL: 10}, keys, nil)
+
+			token, err := s.Impersonate(user.ID, adminID, sessionID, tt.reason, "127.0.0.1", "req-123")
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				assert.Nil(t, token)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, 600, token.ExpiresIn)
+			assert.Equal(t, user.ID, token.UserID)
+
+			// The token is the user's, acted through by the admin in their own session
+			claims, err := middleware.ParseToken(keys, token.AccessToken)
+			require.NoError(t, err)
+			assert.Equal(t, user.ID, claims.UserID)
+			assert.Equal(t, sessionID, claims.SessionID)
+			require.NotNil(t, claims.Act)
+			assert.Equal(t, adminID, claims.Act.UserID)
+			assert.Equal(t, "support1", claims.Act.Username)
+			assert.WithinDuration(t, time.Now().Add(10*time.Minute), claims.ExpiresAt.Time, time.Minute)
+		})
+	}
+}
+
+func Test_authService_ListActivity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	adminID := uuid.New()
+
+	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+	mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+
+	mockAuditLogRepo.EXPECT().GetActivity(userID, activityLimit).Return([]models.AuditLog{
+		{ID: uuid.New(), TableName: "users", RecordID: userID, Action: "VIEW", NewValues: `{"method":"GET","path":"/api/v1/employee/payslip/1","status":200}`, UserID: &adminID, ImpersonatedUserID: &userID},
+		{ID: uuid.New(), TableName: "users", RecordID: userID, Action: "IMPERSONATE", NewValues: `{"reason":"Ticket #4711"}`, UserID: &adminID, ImpersonatedUserID: &userID},
+		{ID: uuid.New(), TableName: "attendances", RecordID: uuid.New(), Action: "INSERT", NewValues: `{"check_in_time":"2024-02-15T08:03:00Z"}`, UserID: &userID},
+	}, nil)
+	// Admins are looked up once
+	mockUserRepo.EXPECT().GetByID(adminID).Return(&models.User{BaseModel: models.BaseModel{ID: adminID}, Username: "support1"}, nil)
+
+	s := NewAuthService(&repository.Repositories{User: mockUserRepo, AuditLog: mockAuditLogRepo}, config.AuthConfig{}, newTestKeySet(t), nil)
+
+	entries, err := s.ListActivity(userID)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	require.NotNil(t, entries[0].ViewedAsBy)
+	assert.Equal(t, "support1", entries[0].ViewedAsBy.Username)
+	assert.Equal(t, "/api/v1/employee/payslip/1", entries[0].Details["path"])
+	assert.Equal(t, "Ticket #4711", entries[1].Details["reason"])
+
+	// Values of other entries are not shown
+	assert.Nil(t, entries[2].ViewedAsBy)
+	assert.Nil(t, entries[2].Details)
+}
+
+func Test_authService_ListActivity_UnreadableEntry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	adminID := uuid.New()
+
+	mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+	mockAuditLogRepo.EXPECT().GetActivity(userID, activityLimit).Return([]models.AuditLog{
+		{ID: uuid.New(), TableName: "users", RecordID: userID, Action: "VIEW", NewValues: `{"method":`, UserID: &adminID, ImpersonatedUserID: &userID},
+	}, nil)
+
+	s := NewAuthService(&repository.Repositories{AuditLog: mockAuditLogRepo}, config.AuthConfig{}, newTestKeySet(t), nil)
+
+	entries, err := s.ListActivity(userID)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read audit entry")
+	assert.Nil(t, entries)
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"payslip-system/internal/bankfile"
+	"payslip-system/internal/config"
+	"payslip-system/internal/domains"
+	"payslip-system/internal/models"
+	"payslip-system/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var accountNumberPattern = regexp.MustCompile(`^[0-9]{6,20}$`)
+
+type disbursementService struct {
+	repos    *repository.Repositories
+	cfg      config.DisbursementConfig
+	currency string
+}
+
+func NewDisbursementService(repos *repository.Repositories, cfg config.DisbursementConfig, currency string) *disbursementService {
+	return &disbursementService{
+		repos:    repos,
+		cfg:      cfg,
+		currency: currency,
+	}
+}
+
+// GenerateFile writes the salary transfers of a final payroll run in a bank upload format, stores the file
+// and marks the transfers sent. Every employee with a positive take-home pay is paid; the file is refused while any of them has no
+// bank account. Transfers are generated once; after that only a reissue, of the failed and returned transfers,
+// is possible, and only a reissue gives a transfer a new reference.
+func (s *disbursementService) GenerateFile(periodID uuid.UUID, format string, executionDate time.Time, reissue bool, actor domains.Actor, ipAddress, requestID string) (*bankfile.File, error) {
+	if format == "" {
+		format = s.cfg.DefaultFormat
+	}
+
+	period, err := s.repos.AttendancePeriod.GetByID(periodID)
+	if err != nil {
+		return nil, errors.New("attendance period not found")
+	}
+
+	payroll, err := s.repos.Payroll.GetRunByPeriodID(periodID)
+	if err != nil {
+		return nil, fmt.Errorf("payroll not found: %w", err)
+	}
+
+	if !payroll.IsFinal() {
+		return nil, errors.New("bank transfers can only be generated once the payroll is final")
+	}
+
+	items, err := s.repos.Payroll.GetAllPayrollItemsByPeriod(periodID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payroll items: %w", err)
+	}
+
+	var transfers []bankfile.Transfer
+	var itemIDs []uuid.UUID
+	var missing []string
+	for _, item := range items {
+		if item.TotalAmount <= 0 {
+			continue
+		}
+
+		needsReissue := item.PaymentStatus == models.PaymentStatusFailed || item.PaymentStatus == models.PaymentStatusReturned
+		if reissue && !needsReissue {
+			continue
+		}
+		// A second file for transfers already handed to the bank could pay them twice, and its new
+		// references would leave the statement of the uploaded file unmatched
+		if !reissue && item.PaymentStatus == models.PaymentStatusSent {
+			return nil, fmt.Errorf("the transfers were already generated in batch %s, download that file instead", item.PaymentBatch)
+		}
+		if !reissue && item.PaymentStatus != "" && item.PaymentStatus != models.PaymentStatusPending {
+			return nil, errors.New("the bank already reported on these transfers, reissue the failed and returned ones instead")
+		}
+
+		if item.User.BankAccountNumber == "" {
+			missing = append(missing, item.User.Username)
+			continue
+		}
+
+		transfers = append(transfers, bankfile.Transfer{
+			Reference:  paymentReference(item.ID, item.PaymentAttempts+1),
+			EmployeeID: item.User.Username,
+			Creditor: bankfile.Account{
+				Name:     item.User.BankAccountName,
+				BankCode: item.User.BankCode,
+				Number:   item.User.BankAccountNumber,
+			},
+			Amount: paymentAmount(item),
+		})
+		itemIDs = append(itemIDs, item.ID)
+	}
+
+	if reissue && len(transfers) == 0 && len(missing) == 0 {
+		return nil, errors.New("no failed or returned transfers to reissue")
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, &domains.MissingBankAccountsError{Employees: missing}
+	}
+
+	sort.Slice(transfers, func(i, j int) bool {
+		return transfers[i].EmployeeID < transfers[j].EmployeeID
+	})
+
+	if executionDate.IsZero() {
+		executionDate = time.Now()
+	}
+
+	now := time.Now()
+	// Unique per run, so a rerun of the same period gets a new reference at the bank
+	reference := fmt.Sprintf("SAL%s%s", period.StartDate.Format("200601"), strings.ToUpper(payroll.ID.String()[:8]))
+	if reissue {
+		// A run can be reissued more than once; 20 characters still fit every bank layout
+		reference = fmt.Sprintf("RIS%s%s", now.Format("060102150405"), strings.ToUpper(payroll.ID.String()[:4]))
+	}
+
+	month := period.StartDate.Format("Jan 2006")
+	batch := &bankfile.Batch{
+		Reference:     reference,
+		CreatedAt:     now,
+		ExecutionDate: executionDate,
+		Currency:      s.currency,
+		Description:   strings.ReplaceAll(s.cfg.Description, "{period}", month),
+		CompanyCode:   s.cfg.CompanyCode,
+		Debtor: bankfile.Account{
+			Name:     s.cfg.AccountName,
+			BankCode: s.cfg.AccountBank,
+			Number:   s.cfg.AccountNumber,
+		},
+		Transfers: transfers,
+	}
+
+	file, err := bankfile.Write(format, batch)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := &models.DisbursementFile{
+		BaseModel:   models.BaseModel{CreatedAt: now, CreatedBy: actor.UserID, IPAddress: ipAddress, RequestID: requestID},
+		PayrollID:   payroll.ID,
+		Reference:   batch.Reference,
+		Reissue:     reissue,
+		Format:      file.Format,
+		Filename:    file.Filename,
+		ContentType: file.ContentType,
+		Data:        file.Data,
+		Count:       file.Totals.Count,
+		Amount:      file.Totals.Amount,
+		AccountHash: file.Totals.AccountHash,
+		Checksum:    file.Checksum,
+	}
+	saved, err := s.repos.Payroll.SaveDisbursementFile(stored, itemIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark transfers sent: %w", err)
+	}
+	if !saved {
+		return nil, errors.New("the transfers were generated by another request, download that file instead")
+	}
+
+	createAuditLog("disbursement_files", payroll.ID, "INSERT", nil, map[string]interface{}{
+		"format":       file.Format,
+		"reissue":      reissue,
+		"reference":    batch.Reference,
+		"count":        file.Totals.Count,
+		"total_amount": bankfile.FormatAmount(file.Totals.Amount),
+		"account_hash": file.Totals.AccountHash,
+		"sha256":       file.Checksum,
+	}, actor, ipAddress, requestID, s.repos)
+
+	return file, nil
+}
+
+// GetFile returns a disbursement file generated before, byte for byte, by its batch reference or the
+// latest file of the run if none is given
+func (s *disbursementService) GetFile(periodID uuid.UUID, reference string) (*bankfile.File, error) {
+	payroll, err := s.repos.Payroll.GetRunByPeriodID(periodID)
+	if err != nil {
+		return nil, fmt.Errorf("payroll not found: %w", err)
+	}
+
+	stored, err := s.repos.Payroll.GetDisbursementFile(payroll.ID, reference)
+	if err != nil {
+		return nil, errors.New("disbursement file not found")
+	}
+
+	return &bankfile.File{
+		Format:      stored.Format,
+		Filename:    stored.Filename,
+		ContentType: stored.ContentType,
+		Data:        stored.Data,
+		Totals:      bankfile.Totals{Count: stored.Count, Amount: stored.Amount, AccountHash: stored.AccountHash},
+		Checksum:    stored.Checksum,
+	}, nil
+}
+
+// SetBankAccount sets the account an employee's salary is transferred to
+func (s *disbursementService) SetBankAccount(userID uuid.UUID, account domains.BankAccount, actor domains.Actor, ipAddress, requestID string) (*models.User, error) {
+	bank, ok := bankfile.LookupBank(account.BankCode)
+	if !ok {
+		return nil, fmt.Errorf("unknown bank code %q", account.BankCode)
+	}
+
+	if !accountNumberPattern.MatchString(account.AccountNumber) {
+		return nil, errors.New("account number must be 6 to 20 digits")
+	}
+
+	name := strings.TrimSpace(account.AccountName)
+	if name == "" || len(name) > 70 {
+		return nil, errors.New("account name must be 1 to 70 characters")
+	}
+
+	user, err := s.repos.User.GetByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	old := domains.BankAccount{BankCode: user.BankCode, AccountNumber: user.BankAccountNumber, AccountName: user.BankAccountName}
+
+	user.BankCode = bank.Code
+	user.BankAccountNumber = account.AccountNumber
+	user.BankAccountName = name
+	user.UpdatedBy = actor.UserID
+	if err := s.repos.User.Update(user); err != nil {
+		return nil, err
+	}
+
+	updated := domains.BankAccount{BankCode: user.BankCode, AccountNumber: user.BankAccountNumber, AccountName: user.BankAccountName}
+	createAuditLog("users", user.ID, "UPDATE", old, updated, actor, ipAddress, requestID, s.repos)
+
+	return user, nil
+}
@@ -0,0 +1,288 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"payslip-system/internal/bankfile"
+	"payslip-system/internal/config"
+	"payslip-system/internal/domains"
+	"payslip-system/internal/models"
+	"payslip-system/internal/repository"
+	mock_repository "payslip-system/internal/repository/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func Test_disbursementService_GenerateFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	periodID := uuid.New()
+	adminID := uuid.New()
+	executionDate := time.Date(2024, 9, 2, 0, 0, 0, 0, time.UTC)
+
+	cfg := config.DisbursementConfig{
+		DefaultFormat: "csv",
+		AccountName:   "PT Mini Payroll",
+		AccountBank:   "BCA",
+		AccountNumber: "1234567890",
+		Description:   "Salary {period}",
+	}
+
+	budi := models.User{Username: "employee2", BankCode: "BCA", BankAccountNumber: "0987654321", BankAccountName: "Budi Santoso"}
+	siti := models.User{Username: "employee1", BankCode: "MANDIRI", BankAccountNumber: "1230004567890", BankAccountName: "Siti Rahayu"}
+
+	tests := []struct {
+		name        string
+		status      string
+		reissue     bool
+		items       []models.PayrollItem
+		wantRows    [][]string
+		wantMissing []string
+		wantErr     bool
+	}{
+		{
+			name:   "success",
+			status: models.PayrollStatusApproved,
+			items: []models.PayrollItem{
+				{BaseModel: models.BaseModel{ID: uuid.New()}, User: budi, TotalAmount: 5000000.5},
+				{BaseModel: models.BaseModel{ID: uuid.New()}, User: siti, TotalAmount: 3750000},
+				{BaseModel: models.BaseModel{ID: uuid.New()}, User: models.User{Username: "employee3"}, TotalAmount: 0},
+			},
+			wantRows: [][]string{
+				{"employee1", "1230004567890", "3750000.00", "Salary Aug 2024"},
+				{"employee2", "0987654321", "5000000.50", "Salary Aug 2024"},
+			},
+		},
+		{
+			name:    "success - reissue failed and returned transfers",
+			status:  models.PayrollStatusApproved,
+			reissue: true,
+			items: []models.PayrollItem{
+				{BaseModel: models.BaseModel{ID: uuid.New()}, User: budi, TotalAmount: 5000000.5, PaymentStatus: models.PaymentStatusReturned, PaymentAttempts: 1},
+				{BaseModel: models.BaseModel{ID: uuid.New()}, User: siti, TotalAmount: 3750000, PaymentStatus: models.PaymentStatusFailed, PaymentAttempts: 1},
+				{BaseModel: models.BaseModel{ID: uuid.New()}, User: models.User{Username: "employee3"}, TotalAmount: 4000000, PaymentStatus: models.PaymentStatusPaid},
+			},
+			wantRows: [][]string{
+				{"employee1", "1230004567890", "3750000.00", "Salary Aug 2024"},
+				{"employee2", "0987654321", "5000000.50", "Salary Aug 2024"},
+			},
+		},
+		{
+			name:   "error - bank already reported",
+			status: models.PayrollStatusApproved,
+			items: []models.PayrollItem{
+				{BaseModel: models.BaseModel{ID: uuid.New()}, User: budi, TotalAmount: 5000000, PaymentStatus: models.PaymentStatusPaid},
+			},
+			wantErr: true,
+		},
+		{
+			name:   "error - transfers already generated",
+			status: models.PayrollStatusApproved,
+			items: []models.PayrollItem{
+				{BaseModel: models.BaseModel{ID: uuid.New()}, User: budi, TotalAmount: 5000000, PaymentStatus: models.PaymentStatusSent, PaymentAttempts: 1, PaymentBatch: "SAL202408ABCDEF12"},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "error - nothing to reissue",
+			status:  models.PayrollStatusApproved,
+			reissue: true,
+			items: []models.PayrollItem{
+				{BaseModel: models.BaseModel{ID: uuid.New()}, User: budi, TotalAmount: 5000000, PaymentStatus: models.PaymentStatusPaid},
+			},
+			wantErr: true,
+		},
+		{
+			name:   "error - employees without bank account",
+			status: models.PayrollStatusApproved,
+			items: []models.PayrollItem{
+				{BaseModel: models.BaseModel{ID: uuid.New()}, User: budi, TotalAmount: 5000000},
+				{BaseModel: models.BaseModel{ID: uuid.New()}, User: models.User{Username: "employee4"}, TotalAmount: 4000000},
+				{BaseModel: models.BaseModel{ID: uuid.New()}, User: models.User{Username: "employee3"}, TotalAmount: 4000000},
+			},
+			wantMissing: []string{"employee3", "employee4"},
+			wantErr:     true,
+		},
+		{
+			name:    "error - payroll awaiting approval",
+			status:  models.PayrollStatusPendingApproval,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPeriodRepo := mock_repository.NewMockIAttendancePeriodRepository(ctrl)
+			mockPayrollRepo := mock_repository.NewMockIPayrollRepository(ctrl)
+			mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+
+			period := &models.AttendancePeriod{BaseModel: models.BaseModel{ID: periodID}, StartDate: time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)}
+			mockPeriodRepo.EXPECT().GetByID(periodID).Return(period, nil)
+
+			payroll := &models.Payroll{BaseModel: models.BaseModel{ID: uuid.New()}, AttendancePeriodID: periodID, Status: tt.status}
+			mockPayrollRepo.EXPECT().GetRunByPeriodID(periodID).Return(payroll, nil)
+
+			if tt.items != nil {
+				mockPayrollRepo.EXPECT().GetAllPayrollItemsByPeriod(periodID).Return(tt.items, nil)
+			}
+			if !tt.wantErr {
+				mockPayrollRepo.EXPECT().SaveDisbursementFile(gomock.Any(), gomock.Any()).DoAndReturn(func(file *models.DisbursementFile, itemIDs []uuid.UUID) (bool, error) {
+					assert.Len(t, itemIDs, len(tt.wantRows))
+					assert.Equal(t, tt.reissue, strings.HasPrefix(file.Reference, "RIS"))
+					assert.Equal(t, tt.reissue, file.Reissue)
+					assert.Equal(t, len(tt.wantRows), file.Count)
+					assert.NotEmpty(t, file.Data)
+					return true, nil
+				})
+				mockAuditLogRepo.EXPECT().Create(gomock.Any()).Return(nil)
+			}
+
+			repos := &repository.Repositories{AttendancePeriod: mockPeriodRepo, Payroll: mockPayrollRepo, AuditLog: mockAuditLogRepo}
+			s := NewDisbursementService(repos, cfg, "IDR")
+
+			file, err := s.GenerateFile(periodID, "", executionDate, tt.reissue, domains.UserActor(adminID), "127.0.0.1", "req-123")
+			if tt.wantErr {
+				assert.Error(t, err)
+				var missing *domains.MissingBankAccountsError
+				if tt.wantMissing != nil {
+					require.True(t, errors.As(err, &missing))
+					assert.Equal(t, tt.wantMissing, missing.Employees)
+				}
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, "csv", file.Format)
+			assert.Equal(t, len(tt.wantRows), file.Totals.Count)
+			assert.Equal(t, int64(875000050), file.Totals.Amount)
+			if !tt.reissue {
+				assert.True(t, strings.HasPrefix(file.Filename, "sal202408"+strings.ToLower(payroll.ID.String()[:8])))
+			}
+
+			rows, err := csv.NewReader(strings.NewReader(string(file.Data))).ReadAll()
+			require.NoError(t, err)
+			require.Len(t, rows, len(tt.wantRows)+2)
+			attempt := "001"
+			if tt.reissue {
+				attempt = "002"
+			}
+			for i, want := range tt.wantRows {
+				row := rows[i+1]
+				assert.Equal(t, want, []string{row[3], row[7], row[8], row[10]})
+				assert.Len(t, row[2], 35)
+				assert.True(t, strings.HasSuffix(row[2], attempt))
+			}
+		})
+	}
+}
+
+func Test_disbursementService_GetFile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	periodID := uuid.New()
+	payroll := &models.Payroll{BaseModel: models.BaseModel{ID: uuid.New()}, AttendancePeriodID: periodID, Status: models.PayrollStatusApproved}
+	stored := &models.DisbursementFile{
+		PayrollID:   payroll.ID,
+		Reference:   "SAL202408ABCDEF12",
+		Format:      "csv",
+		Filename:    "sal202408abcdef12.csv",
+		ContentType: "text/csv",
+		Data:        []byte("D,1"),
+		Count:       1,
+		Amount:      500000000,
+		AccountHash: 987654321,
+		Checksum:    "abc",
+	}
+
+	tests := []struct {
+		name      string
+		reference string
+		wantErr   bool
+	}{
+		{name: "success - latest file", reference: ""},
+		{name: "success - file of a batch", reference: "SAL202408ABCDEF12"},
+		{name: "error - unknown batch", reference: "RIS240901120000ABCD", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPayrollRepo := mock_repository.NewMockIPayrollRepository(ctrl)
+			mockPayrollRepo.EXPECT().GetRunByPeriodID(periodID).Return(payroll, nil)
+			if tt.wantErr {
+				mockPayrollRepo.EXPECT().GetDisbursementFile(payroll.ID, tt.reference).Return(nil, gorm.ErrRecordNotFound)
+			} else {
+				mockPayrollRepo.EXPECT().GetDisbursementFile(payroll.ID, tt.reference).Return(stored, nil)
+			}
+
+			s := NewDisbursementService(&repository.Repositories{Payroll: mockPayrollRepo}, config.DisbursementConfig{}, "IDR")
+
+			file, err := s.GetFile(periodID, tt.reference)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, stored.Data, file.Data)
+			assert.Equal(t, stored.Filename, file.Filename)
+			assert.Equal(t, stored.Checksum, file.Checksum)
+			assert.Equal(t, bankfile.Totals{Count: 1, Amount: 500000000, AccountHash: 987654321}, file.Totals)
+		})
+	}
+}
+
+func Test_disbursementService_SetBankAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	adminID := uuid.New()
+
+	tests := []struct {
+		name    string
+		account domains.BankAccount
+		wantErr bool
+	}{
+		{name: "success", account: domains.BankAccount{BankCode: "bca", AccountNumber: "0987654321", AccountName: " Budi Santoso "}},
+		{name: "error - unknown bank", account: domains.BankAccount{BankCode: "XYZ", AccountNumber: "0987654321", AccountName: "Budi Santoso"}, wantErr: true},
+		{name: "error - account number not numeric", account: domains.BankAccount{BankCode: "BCA", AccountNumber: "0987-654321", AccountName: "Budi Santoso"}, wantErr: true},
+		{name: "error - account number too short", account: domains.BankAccount{BankCode: "BCA", AccountNumber: "12345", AccountName: "Budi Santoso"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+			mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+
+			if !tt.wantErr {
+				mockUserRepo.EXPECT().GetByID(userID).Return(&models.User{BaseModel: models.BaseModel{ID: userID}, Username: "employee1"}, nil)
+				mockUserRepo.EXPECT().Update(gomock.Any()).Return(nil)
+				mockAuditLogRepo.EXPECT().Create(gomock.Any()).Return(nil)
+			}
+
+			repos := &repository.Repositories{User: mockUserRepo, AuditLog: mockAuditLogRepo}
+			s := NewDisbursementService(repos, config.DisbursementConfig{}, "IDR")
+
+			user, err := s.SetBankAccount(userID, tt.account, domains.UserActor(adminID), "127.0.0.1", "req-123")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, "BCA", user.BankCode)
+			assert.Equal(t, "0987654321", user.BankAccountNumber)
+			assert.Equal(t, "Budi Santoso", user.BankAccountName)
+			assert.Equal(t, &adminID, user.UpdatedBy)
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"payslip-system/internal/config"
+	"payslip-system/internal/domains"
+	"payslip-system/internal/filing"
+	"payslip-system/internal/models"
+	"payslip-system/internal/repository"
+	"payslip-system/internal/tax"
+
+	"github.com/google/uuid"
+)
+
+type filingService struct {
+	repos *repository.Repositories
+	cfg   config.TaxConfig
+}
+
+func NewFilingService(repos *repository.Repositories, cfg config.TaxConfig) *filingService {
+	return &filingService{
+		repos: repos,
+		cfg:   cfg,
+	}
+}
+
+// ValidateFiling checks the tax profiles of the employees paid in a final payroll run before its monthly
+// returns are exported. The checks use the profiles as they are now, which is what an export would report.
+func (s *filingService) ValidateFiling(periodID uuid.UUID) (*domains.FilingValidation, error) {
+	_, items, err := s.filedItems(periodID)
+	if err != nil {
+		return nil, err
+	}
+
+	validation := &domains.FilingValidation{
+		PeriodID:      periodID,
+		Reports:       filing.Reports(),
+		EmployeeCount: len(items),
+		Issues:        validateTaxProfiles(items),
+		Valid:         true,
+	}
+	for _, issue := range validation.Issues {
+		if issue.Severity == domains.SeverityError {
+			validation.Valid = false
+		}
+	}
+
+	return validation, nil
+}
+
+// ExportFiling writes the PPh 21 return or a BPJS contribution report of a final payroll run in the import
+// layout of its portal. The export is refused while the validation reports errors.
+func (s *filingService) ExportFiling(periodID uuid.UUID, report string, actor domains.Actor, ipAddress, requestID string) (*filing.File, error) {
+	period, items, err := s.filedItems(periodID)
+	if err != nil {
+		return nil, err
+	}
+
+	var blocking []domains.PayrollValidationIssue
+	for _, issue := range validateTaxProfiles(items) {
+		if issue.Severity == domains.SeverityError {
+			blocking = append(blocking, issue)
+		}
+	}
+	if len(blocking) > 0 {
+		return nil, &domains.FilingValidationError{Issues: blocking}
+	}
+
+	monthly := &filing.Filing{
+		Year:            period.StartDate.Year(),
+		Month:           int(period.StartDate.Month()),
+		WithholdingDate: period.EndDate,
+		Employer: filing.Employer{
+			Name:       s.cfg.EmployerName,
+			NPWP:       s.cfg.EmployerNPWP,
+			NITKU:      s.cfg.EmployerNITKU,
+			BPJSNumber: s.cfg.BPJSNumber,
+			HealthCode: s.cfg.BPJSHealthCode,
+		},
+	}
+	for _, item := range items {
+		monthly.Employees = append(monthly.Employees, filingEmployee(item))
+	}
+
+	file, err := filing.Write(report, monthly)
+	if err != nil {
+		return nil, err
+	}
+
+	createAuditLog("tax_filings", periodID, "INSERT", nil, map[string]interface{}{
+		"report": file.Report,
+		"count":  file.Count,
+		"amount": file.Amount,
+		"sha256": file.Checksum,
+	}, actor, ipAddress, requestID, s.repos)
+
+	return file, nil
+}
+
+// filedItems returns the current payroll items of a final payroll run, in username order
+func (s *filingService) filedItems(periodID uuid.UUID) (*models.AttendancePeriod, []models.PayrollItem, error) {
+	period, err := s.repos.AttendancePeriod.GetByID(periodID)
+	if err != nil {
+		return nil, nil, errors.New("attendance period not found")
+	}
+
+	payroll, err := s.repos.Payroll.GetRunByPeriodID(periodID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("payroll not found: %w", err)
+	}
+
+	if !payroll.IsFinal() {
+		return nil, nil, errors.New("returns can only be filed once the payroll is final")
+	}
+
+	items, err := s.repos.Payroll.GetAllPayrollItemsByPeriod(periodID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get payroll items: %w", err)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].User.Username < items[j].User.Username
+	})
+
+	return period, items, nil
+}
+
+// validateTaxProfiles flags the employees the portals would reject. BPJS matches participants by NIK and the
+// tax office takes the NIK as the taxpayer ID of residents, so it is required; the NPWP is not.
+func validateTaxProfiles(items []models.PayrollItem) []domains.PayrollValidationIssue {
+	issues := []domains.PayrollValidationIssue{}
+	for i := range items {
+		employee := &items[i].User
+
+		switch {
+		case employee.NIK == "":
+			issues = append(issues, newValidationIssue(employee, domains.IssueMissingNIK, domains.SeverityError,
+				"no NIK recorded, required by e-Bupot and BPJS"))
+		case !tax.ValidNIK(employee.NIK):
+			issues = append(issues, newValidationIssue(employee, domains.IssueInvalidTaxID, domains.SeverityError,
+				fmt.Sprintf("NIK %q is not 16 digits", employee.NIK)))
+		}
+
+		if employee.NPWP == "" {
+			issues = append(issues, newValidationIssue(employee, domains.IssueMissingNPWP, domains.SeverityWarning,
+				"no NPWP recorded, the NIK is reported as taxpayer ID"))
+		} else if !tax.ValidNPWP(employee.NPWP) {
+			issues = append(issues, newValidationIssue(employee, domains.IssueInvalidTaxID, domains.SeverityError,
+				fmt.Sprintf("NPWP %q is not 15 or 16 digits", employee.NPWP)))
+		}
+
+		if employee.PTKPStatus == "" {
+			issues = append(issues, newValidationIssue(employee, domains.IssueMissingPTKPStatus, domains.SeverityWarning,
+				"no PTKP status recorded, reported as "+tax.DefaultPTKPStatus))
+		}
+	}
+	return issues
+}
+
+// filingEmployee reports a payroll item in whole rupiah. Reimbursements refund expenses and are not income.
+func filingEmployee(item models.PayrollItem) filing.Employee {
+	status := item.User.PTKPStatus
+	if status == "" {
+		status = tax.DefaultPTKPStatus
+	}
+
+	name := item.User.BankAccountName // the legal name, as registered with the bank
+	if name == "" {
+		name = item.User.Username
+	}
+
+	rupiah := func(amount float64) int64 {
+		return int64(math.Round(amount))
+	}
+
+	bpjs := item.BPJS
+	return filing.Employee{
+		EmployeeID:     item.User.Username,
+		Name:           name,
+		NIK:            item.User.NIK,
+		NPWP:           item.User.NPWP,
+		PTKPStatus:     status,
+		BirthDate:      item.User.BirthDate,
+		Gross:          rupiah(item.AttendanceAmount + item.OvertimeAmount + bpjs.TaxableBenefits()),
+		Tax:            rupiah(item.TaxAmount),
+		Wage:           rupiah(item.BaseSalary),
+		JHTEmployee:    rupiah(bpjs.JHTEmployee),
+		JHTEmployer:    rupiah(bpjs.JHTEmployer),
+		JPEmployee:     rupiah(bpjs.JPEmployee),
+		JPEmployer:     rupiah(bpjs.JPEmployer),
+		JKKEmployer:    rupiah(bpjs.JKKEmployer),
+		JKMEmployer:    rupiah(bpjs.JKMEmployer),
+		HealthEmployee: rupiah(bpjs.HealthEmployee),
+		HealthEmployer: rupiah(bpjs.HealthEmployer),
+	}
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"payslip-system/internal/config"
+	"payslip-system/internal/domains"
+	"payslip-system/internal/models"
+	"payslip-system/internal/repository"
+	mock_repository "payslip-system/internal/repository/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_filingService_ExportFiling(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	periodID := uuid.New()
+	adminID := uuid.New()
+
+	cfg := config.TaxConfig{EmployerName: "PT Mini Payroll", EmployerNPWP: "0123456789012000", BPJSNumber: "12345678", BPJSHealthCode: "01234567"}
+
+	budi := models.User{Username: "employee2", BankAccountName: "Budi Santoso", NIK: "3174011503900001", NPWP: "012345678901000", PTKPStatus: "K/1"}
+	siti := models.User{Username: "employee1", NIK: "3174015507920002"}
+	bpjs := models.BPJSContributions{JHTEmployee: 160000, JPEmployee: 80000, HealthEmployee: 80000, JHTEmployer: 296000, JPEmployer: 160000, JKKEmployer: 19200, JKMEmployer: 24000, HealthEmployer: 320000}
+
+	tests := []struct {
+		name       string
+		status     string
+		report     string
+		items      []models.PayrollItem
+		wantRows   [][]string
+		wantAmount int64
+		wantIssues []string
+		wantErr    bool
+	}{
+		{
+			name:   "success - e-Bupot",
+			status: models.PayrollStatusApproved,
+			report: "ebupot-csv",
+			items: []models.PayrollItem{
+				{User: budi, BaseSalary: 8000000, AttendanceAmount: 8000000, OvertimeAmount: 200000.4, ReimbursementAmount: 500000, TaxAmount: 126104, BPJS: bpjs},
+				{User: siti, BaseSalary: 5000000, AttendanceAmount: 5000000},
+			},
+			wantRows: [][]string{
+				{"3174015507920002", "employee1", "TK/0", "5000000", "0"},
+				{"3174011503900001", "Budi Santoso", "K/1", "8563200", "126104"},
+			},
+			wantAmount: 126104,
+		},
+		{
+			name:   "success - BPJS Ketenagakerjaan",
+			status: models.PayrollStatusApproved,
+			report: "bpjs-ketenagakerjaan",
+			items: []models.PayrollItem{
+				{User: budi, BaseSalary: 8000000, AttendanceAmount: 8000000, TaxAmount: 126104, BPJS: bpjs},
+			},
+			wantRows:   [][]string{{"12345678", "082024", "3174011503900001", "Budi Santoso"}},
+			wantAmount: 739200,
+		},
+		{
+			name:   "error - employees without NIK",
+			status: models.PayrollStatusApproved,
+			report: "ebupot-xml",
+			items: []models.PayrollItem{
+				{User: budi, BaseSalary: 8000000, AttendanceAmount: 8000000},
+				{User: models.User{Username: "employee3", NPWP: "098765432109000"}, BaseSalary: 5000000, AttendanceAmount: 5000000},
+				{User: models.User{Username: "employee4", NIK: "31740155079200"}, BaseSalary: 5000000, AttendanceAmount: 5000000},
+			},
+			wantIssues: []string{domains.IssueMissingNIK, domains.IssueInvalidTaxID},
+			wantErr:    true,
+		},
+		{
+			name:   "error - unsupported report",
+			status: models.PayrollStatusApproved,
+			report: "spt-masa",
+			items: []models.PayrollItem{
+				{User: budi, BaseSalary: 8000000, AttendanceAmount: 8000000},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "error - payroll awaiting approval",
+			status:  models.PayrollStatusPendingApproval,
+			report:  "ebupot-csv",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPeriodRepo := mock_repository.NewMockIAttendancePeriodRepository(ctrl)
+			mockPayrollRepo := mock_repository.NewMockIPayrollRepository(ctrl)
+			mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+
+			period := &models.AttendancePeriod{
+				BaseModel: models.BaseModel{ID: periodID},
+				StartDate: time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC),
+				EndDate:   time.Date(2024, 8, 31, 0, 0, 0, 0, time.UTC),
+			}
+			mockPeriodRepo.EXPECT().GetByID(periodID).Return(period, nil)
+			mockPayrollRepo.EXPECT().GetRunByPeriodID(periodID).Return(&models.Payroll{Status: tt.status}, nil)
+
+			if tt.items != nil {
+				mockPayrollRepo.EXPECT().GetAllPayrollItemsByPeriod(periodID).Return(tt.items, nil)
+			}
+			if !tt.wantErr {
+				mockAuditLogRepo.EXPECT().Create(gomock.Any()).Return(nil)
+			}
+
+			repos := &repository.Repositories{AttendancePeriod: mockPeriodRepo, Payroll: mockPayrollRepo, AuditLog: mockAuditLogRepo}
+			s := NewFilingService(repos, cfg)
+
+			file, err := s.ExportFiling(periodID, tt.report, domains.UserActor(adminID), "127.0.0.1", "req-123")
+			if tt.wantErr {
+				assert.Error(t, err)
+				if tt.wantIssues != nil {
+					var invalid *domains.FilingValidationError
+					require.True(t, errors.As(err, &invalid))
+					var codes []string
+					for _, issue := range invalid.Issues {
+						codes = append(codes, issue.Code)
+					}
+					assert.Equal(t, tt.wantIssues, codes)
+				}
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.report+"-202408.csv", file.Filename)
+			assert.Equal(t, len(tt.wantRows), file.Count)
+			assert.Equal(t, tt.wantAmount, file.Amount)
+
+			rows, err := csv.NewReader(strings.NewReader(string(file.Data))).ReadAll()
+			require.NoError(t, err)
+			require.Len(t, rows, len(tt.wantRows)+1)
+			for i, want := range tt.wantRows {
+				row := rows[i+1]
+				if tt.report == "ebupot-csv" {
+					assert.Equal(t, want, []string{row[4], row[5], row[6], row[8], row[9]})
+					assert.Equal(t, "31/08/2024", row[10])
+				} else {
+					assert.Equal(t, want, row[:4])
+				}
+			}
+		})
+	}
+}
+
+func Test_filingService_ValidateFiling(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	periodID := uuid.New()
+
+	mockPeriodRepo := mock_repository.NewMockIAttendancePeriodRepository(ctrl)
+	mockPayrollRepo := mock_repository.NewMockIPayrollRepository(ctrl)
+
+	mockPeriodRepo.EXPECT().GetByID(periodID).Return(&models.AttendancePeriod{BaseModel: models.BaseModel{ID: periodID}}, nil).Times(2)
+	mockPayrollRepo.EXPECT().GetRunByPeriodID(periodID).Return(&models.Payroll{Status: models.PayrollStatusApproved}, nil).Times(2)
+	mockPayrollRepo.EXPECT().GetAllPayrollItemsByPeriod(periodID).Return([]models.PayrollItem{
+		{User: models.User{Username: "employee1", NIK: "3174011503900001", NPWP: "012345678901000", PTKPStatus: "K/1"}},
+		{User: models.User{Username: "employee2", NIK: "3174015507920002"}},
+	}, nil)
+	mockPayrollRepo.EXPECT().GetAllPayrollItemsByPeriod(periodID).Return([]models.PayrollItem{
+		{User: models.User{Username: "employee1", NPWP: "0123", PTKPStatus: "K/1"}},
+	}, nil)
+
+	repos := &repository.Repositories{AttendancePeriod: mockPeriodRepo, Payroll: mockPayrollRepo}
+	s := NewFilingService(repos, config.TaxConfig{})
+
+	t.Run("success - warnings only", func(t *testing.T) {
+		validation, err := s.ValidateFiling(periodID)
+		require.NoError(t, err)
+
+		assert.True(t, validation.Valid)
+		assert.Equal(t, 2, validation.EmployeeCount)
+		assert.Contains(t, validation.Reports, "ebupot-xml")
+		require.Len(t, validation.Issues, 2)
+		assert.Equal(t, "employee2", validation.Issues[0].Username)
+		assert.Equal(t, domains.IssueMissingNPWP, validation.Issues[0].Code)
+		assert.Equal(t, domains.IssueMissingPTKPStatus, validation.Issues[1].Code)
+	})
+
+	t.Run("success - errors", func(t *testing.T) {
+		validation, err := s.ValidateFiling(periodID)
+		require.NoError(t, err)
+
+		assert.False(t, validation.Valid)
+		require.Len(t, validation.Issues, 2)
+		assert.Equal(t, domains.IssueMissingNIK, validation.Issues[0].Code)
+		assert.Equal(t, domains.IssueInvalidTaxID, validation.Issues[1].Code)
+		assert.Equal(t, domains.SeverityError, validation.Issues[1].Severity)
+	})
+}
@@ -2,16 +2,23 @@ package service
 
 import (
 	"encoding/json"
+	"errors"
+	"math"
 	"time"
 
+	"payslip-system/internal/domains"
 	"payslip-system/internal/models"
 	"payslip-system/internal/repository"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // Helper methods for other services
-func createAuditLog(tableName string, recordID uuid.UUID, action string, oldValues, newValues interface{}, userID *uuid.UUID, ipAddress, requestID string, repos *repository.Repositories) {
+
+// createAuditLog records a change made by the actor. The entry names the user, or the API key; for an admin
+// viewing the API as a user it names the admin, next to the user.
+func createAuditLog(tableName string, recordID uuid.UUID, action string, oldValues, newValues interface{}, actor domains.Actor, ipAddress, requestID string, repos *repository.Repositories) error {
 	var oldJSON, newJSON string
 
 	if oldValues != nil {
@@ -33,11 +40,71 @@ func createAuditLog(tableName string, recordID uuid.UUID, action string, oldValu
 		Action:    action,
 		OldValues: oldJSON,
 		NewValues: newJSON,
-		UserID:    userID,
+		UserID:    actor.UserID,
+		APIKeyID:  actor.APIKeyID,
 		IPAddress: ipAddress,
 		RequestID: requestID,
 		CreatedAt: time.Now(),
 	}
+	if actor.ImpersonatorID != nil {
+		log.UserID = actor.ImpersonatorID
+		log.ImpersonatedUserID = actor.UserID
+	}
+
+	return repos.AuditLog.Create(log)
+}
+
+// withSubmissionPeriod runs fn in a transaction holding a share lock on the active period,
+// so payroll processing cannot start until the submission has committed. Submissions are
+// rejected outright while payroll processing holds the period.
+func withSubmissionPeriod(repos *repository.Repositories, fn func(txRepos *repository.Repositories, period *models.AttendancePeriod) error) error {
+	return repos.DB.Transaction(func(tx *gorm.DB) error {
+		txRepos := repository.NewRepositories(tx)
+
+		period, err := txRepos.AttendancePeriod.GetActiveForSubmission()
+		if errors.Is(err, repository.ErrPeriodLocked) {
+			return errors.New("payroll is being processed for the active period, try again later")
+		}
+		if err != nil {
+			return errors.New("no active attendance period found")
+		}
+
+		return fn(txRepos, period)
+	})
+}
+
+// withOpenPeriod runs fn in a transaction locking a period payroll has not been processed for, so decisions
+// on the period's overtime and reimbursements cannot interleave with processing its payroll
+func withOpenPeriod(repos *repository.Repositories, periodID uuid.UUID, fn func(txRepos *repository.Repositories) error) error {
+	return repos.DB.Transaction(func(tx *gorm.DB) error {
+		txRepos := repository.NewRepositories(tx)
+
+		period, err := txRepos.AttendancePeriod.GetByIDForUpdate(periodID)
+		if err != nil {
+			return errors.New("attendance period not found")
+		}
+		if period.IsProcessed {
+			return domains.ErrPeriodProcessed
+		}
+
+		return fn(txRepos)
+	})
+}
+
+// submissionApproval is the approval of a new request: pending while the user has a line manager to decide
+// on it, approved otherwise
+func submissionApproval(user *models.User) models.Approval {
+	if user.ManagerID != nil {
+		return models.Approval{Status: models.ApprovalStatusPending}
+	}
+	return models.Approval{Status: models.ApprovalStatusApproved}
+}
+
+// toCents rounds an amount to whole cents, for sums that have to balance exactly
+func toCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
 
-	repos.AuditLog.Create(log)
+func fromCents(cents int64) float64 {
+	return float64(cents) / 100
 }
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"errors"
+	"payslip-system/internal/config"
+	"payslip-system/internal/domains"
+	"payslip-system/internal/i18n"
+	"payslip-system/internal/models"
+	"payslip-system/internal/repository"
+	mock_repository "payslip-system/internal/repository/mocks"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_languageService_SaveLanguage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	adminID := uuid.New()
+	malay := i18n.Catalog{
+		Code:               "ms",
+		Name:               "Bahasa Melayu",
+		DecimalSeparator:   ".",
+		ThousandsSeparator: ",",
+		CurrencySymbols:    map[string]string{"IDR": "Rp"},
+		DateFormat:         "02 January 2006",
+		Messages:           map[string]string{"payslip.net_pay": "Gaji bersih"},
+	}
+
+	tests := []struct {
+		name     string
+		catalog  i18n.Catalog
+		saveErr  error
+		wantSave bool
+		wantErr  bool
+	}{
+		{name: "success - new language", catalog: malay, wantSave: true},
+		{name: "error - invalid catalog", catalog: i18n.Catalog{Code: "ms"}, wantErr: true},
+		{name: "error - repository failure", catalog: malay, saveErr: errors.New("db down"), wantSave: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockLanguageRepo := mock_repository.NewMockILanguageRepository(ctrl)
+			mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+
+			mockLanguageRepo.EXPECT().GetAll().Return(nil, nil).AnyTimes()
+			mockAuditLogRepo.EXPECT().Create(gomock.Any()).Return(nil).AnyTimes()
+			if tt.wantSave {
+				mockLanguageRepo.EXPECT().Upsert(gomock.Any()).DoAndReturn(func(language *models.Language) error {
+					assert.Equal(t, tt.catalog.Code, language.Code)
+					assert.Equal(t, &adminID, language.UpdatedBy)
+					return tt.saveErr
+				})
+			}
+
+			repos := &repository.Repositories{Language: mockLanguageRepo, AuditLog: mockAuditLogRepo}
+			s := NewLanguageService(repos, config.I18nConfig{DefaultLanguage: "en"})
+
+			catalog := tt.catalog
+			got, err := s.SaveLanguage(&catalog, domains.UserActor(adminID), "127.0.0.1", "req-123")
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Equal(t, "en", s.Translator(tt.catalog.Code).Language())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotEmpty(t, got.MissingKeys)
+			tr := s.Translator(tt.catalog.Code)
+			assert.Equal(t, tt.catalog.Code, tr.Language())
+			assert.Equal(t, "Gaji bersih", tr.T("payslip.net_pay"))
+			assert.Equal(t, "Rp 5,000,000.00", tr.Money("IDR", 5000000))
+		})
+	}
+}
+
+func Test_languageService_SetPreferredLanguage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name     string
+		language string
+		wantErr  bool
+	}{
+		{name: "success", language: "id"},
+		{name: "error - unsupported language", language: "xx", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+			mockLanguageRepo := mock_repository.NewMockILanguageRepository(ctrl)
+			mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+
+			user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "employee1"}
+			mockLanguageRepo.EXPECT().GetAll().Return(nil, nil).AnyTimes()
+			if !tt.wantErr {
+				mockUserRepo.EXPECT().GetByID(user.ID).Return(user, nil)
+				mockUserRepo.EXPECT().Update(user).Return(nil)
+				mockAuditLogRepo.EXPECT().Create(gomock.Any()).Return(nil)
+			}
+
+			repos := &repository.Repositories{User: mockUserRepo, Language: mockLanguageRepo, AuditLog: mockAuditLogRepo}
+			s := NewLanguageService(repos, config.I18nConfig{DefaultLanguage: "en"})
+
+			err := s.SetPreferredLanguage(user.ID, tt.language, "127.0.0.1", "req-123")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.language, user.PreferredLanguage)
+		})
+	}
+}
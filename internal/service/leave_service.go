@@ -0,0 +1,98 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"payslip-system/internal/domains"
+	"payslip-system/internal/models"
+	"payslip-system/internal/rbac"
+	"payslip-system/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+type leaveService struct {
+	repos *repository.Repositories
+}
+
+func NewLeaveService(repos *repository.Repositories) *leaveService {
+	return &leaveService{repos: repos}
+}
+
+func (s *leaveService) SubmitLeave(userID uuid.UUID, req domains.LeaveRequest, ipAddress, requestID string) (*models.Leave, error) {
+	switch req.Type {
+	case models.LeaveTypeAnnual, models.LeaveTypeSick, models.LeaveTypeUnpaid:
+	default:
+		return nil, errors.New("leave type must be 'annual', 'sick' or 'unpaid'")
+	}
+	if req.EndDate.Before(req.StartDate) {
+		return nil, errors.New("end date must not be before start date")
+	}
+
+	user, err := s.repos.User.GetByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	overlap, err := s.repos.Leave.HasOverlap(userID, req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	if overlap {
+		return nil, errors.New("leave already requested for some of these days")
+	}
+
+	if req.DelegateID != nil {
+		if err := s.checkDelegate(userID, *req.DelegateID); err != nil {
+			return nil, err
+		}
+	}
+
+	leave := &models.Leave{
+		BaseModel: models.BaseModel{
+			CreatedBy: &userID,
+			IPAddress: ipAddress,
+			RequestID: requestID,
+		},
+		UserID:     userID,
+		Type:       req.Type,
+		StartDate:  req.StartDate,
+		EndDate:    req.EndDate,
+		Reason:     strings.TrimSpace(req.Reason),
+		DelegateID: req.DelegateID,
+		Approval:   submissionApproval(user),
+	}
+	if err := s.repos.Leave.Create(leave); err != nil {
+		return nil, fmt.Errorf("failed to create leave request: %w", err)
+	}
+
+	createAuditLog("leaves", leave.ID, "INSERT", nil, leave, domains.UserActor(userID), ipAddress, requestID, s.repos)
+
+	return leave, nil
+}
+
+func (s *leaveService) ListLeave(userID uuid.UUID) ([]models.Leave, error) {
+	return s.repos.Leave.GetByUser(userID)
+}
+
+// checkDelegate makes sure the delegate is another active user able to decide on at least one kind of request
+func (s *leaveService) checkDelegate(userID, delegateID uuid.UUID) error {
+	if delegateID == userID {
+		return errors.New("you cannot delegate to yourself")
+	}
+	if _, err := s.repos.User.GetByID(delegateID); err != nil {
+		return errors.New("delegate not found")
+	}
+
+	permissions, err := s.repos.Role.GetUserPermissions(delegateID)
+	if err != nil {
+		return err
+	}
+	held := rbac.NewSet(permissions)
+	if !held.Has(rbac.OvertimeApprove) && !held.Has(rbac.ReimbursementApprove) && !held.Has(rbac.LeaveApprove) {
+		return errors.New("delegate cannot approve requests")
+	}
+	return nil
+}
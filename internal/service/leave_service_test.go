@@ -0,0 +1,79 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"payslip-system/internal/domains"
+	"payslip-system/internal/models"
+	"payslip-system/internal/rbac"
+	"payslip-system/internal/repository"
+	mock_repository "payslip-system/internal/repository/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_leaveService_SubmitLeave(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	managerID := uuid.New()
+	start := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 4)
+
+	tests := []struct {
+		name       string
+		req        domains.LeaveRequest
+		overlap    bool
+		delegate   []string // permissions of the delegate
+		wantErr    string
+		checksUser bool
+	}{
+		{name: "success", req: domains.LeaveRequest{Type: models.LeaveTypeAnnual, StartDate: start, EndDate: end}, checksUser: true},
+		{name: "success with delegate", req: domains.LeaveRequest{Type: models.LeaveTypeAnnual, StartDate: start, EndDate: end, DelegateID: &managerID}, delegate: builtinPermissions(rbac.RoleManager), checksUser: true},
+		{name: "unknown type", req: domains.LeaveRequest{Type: "sabbatical", StartDate: start, EndDate: end}, wantErr: "leave type must be"},
+		{name: "end before start", req: domains.LeaveRequest{Type: models.LeaveTypeSick, StartDate: end, EndDate: start}, wantErr: "end date must not be before"},
+		{name: "overlapping leave", req: domains.LeaveRequest{Type: models.LeaveTypeSick, StartDate: start, EndDate: start}, overlap: true, checksUser: true, wantErr: "already requested"},
+		{name: "delegate cannot approve", req: domains.LeaveRequest{Type: models.LeaveTypeAnnual, StartDate: start, EndDate: end, DelegateID: &managerID}, delegate: builtinPermissions(rbac.RoleEmployee), checksUser: true, wantErr: "delegate cannot approve"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, ManagerID: &managerID, IsActive: true}
+
+			mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+			mockLeaveRepo := mock_repository.NewMockILeaveRepository(ctrl)
+			mockRoleRepo := mock_repository.NewMockIRoleRepository(ctrl)
+			mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+
+			if tt.checksUser {
+				mockUserRepo.EXPECT().GetByID(user.ID).Return(user, nil)
+				mockLeaveRepo.EXPECT().HasOverlap(user.ID, tt.req.StartDate, tt.req.EndDate).Return(tt.overlap, nil)
+			}
+			if tt.delegate != nil {
+				mockUserRepo.EXPECT().GetByID(managerID).Return(&models.User{BaseModel: models.BaseModel{ID: managerID}, IsActive: true}, nil)
+				mockRoleRepo.EXPECT().GetUserPermissions(managerID).Return(tt.delegate, nil)
+			}
+			if tt.wantErr == "" {
+				mockLeaveRepo.EXPECT().Create(gomock.Any()).Return(nil)
+				mockAuditLogRepo.EXPECT().Create(gomock.Any()).Return(nil)
+			}
+
+			repos := &repository.Repositories{User: mockUserRepo, Leave: mockLeaveRepo, Role: mockRoleRepo, AuditLog: mockAuditLogRepo}
+			s := NewLeaveService(repos)
+
+			leave, err := s.SubmitLeave(user.ID, tt.req, "127.0.0.1", "req-123")
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, models.ApprovalStatusPending, leave.Status)
+			assert.Equal(t, tt.req.DelegateID, leave.DelegateID)
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"payslip-system/internal/config"
+	"payslip-system/internal/domains"
+	"payslip-system/internal/models"
+	"payslip-system/internal/rbac"
+	"payslip-system/internal/repository"
+	"payslip-system/internal/totp"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// mfaSkew is how many time steps a device clock may be off
+	mfaSkew = 1
+	// recoveryCodeCount is how many recovery codes a user gets at a time
+	recoveryCodeCount = 10
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+type mfaService struct {
+	repos *repository.Repositories
+	cfg   config.MFAConfig
+}
+
+func NewMFAService(repos *repository.Repositories, cfg config.MFAConfig) *mfaService {
+	return &mfaService{
+		repos: repos,
+		cfg:   cfg,
+	}
+}
+
+// Enroll generates a new TOTP secret for the user. MFA is enabled once a code of it is confirmed; until then
+// enrolling again replaces the secret.
+func (s *mfaService) Enroll(userID uuid.UUID) (*domains.MFAEnrollment, error) {
+	user, err := s.repos.User.GetByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if user.MFAEnabledAt != nil {
+		return nil, domains.ErrMFAAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate secret: %w", err)
+	}
+	sealed, err := sealSecret(s.cfg.Secret, secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to seal secret: %w", err)
+	}
+
+	user.MFASecret = sealed
+	user.MFALastStep = 0
+	if err := s.repos.User.Update(user); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	uri := totp.ProvisioningURI(s.cfg.Issuer, user.Username, secret)
+	qrCode, err := totp.QRCode(uri)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render QR code: %w", err)
+	}
+
+	return &domains.MFAEnrollment{Secret: secret, URI: uri, QRCode: qrCode}, nil
+}
+
+// Confirm enables MFA with a code of the enrolled secret and returns the recovery codes, which are shown once
+func (s *mfaService) Confirm(userID uuid.UUID, code, ipAddress, requestID string) ([]string, error) {
+	user, err := s.repos.User.GetByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if user.MFAEnabledAt != nil {
+		return nil, domains.ErrMFAAlreadyEnabled
+	}
+	if user.MFASecret == "" {
+		return nil, domains.ErrMFANotEnrolled
+	}
+
+	// Recovery codes do not exist yet, only a TOTP code proves the app is set up
+	now := time.Now()
+	if err := s.verifyTOTP(user, code, now); err != nil {
+		return nil, err
+	}
+
+	user.MFAEnabledAt = &now
+	user.UpdatedBy = &userID
+	if err := s.repos.User.Update(user); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	codes, err := s.replaceRecoveryCodes(user.ID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	createAuditLog("users", user.ID, "UPDATE", nil, map[string]interface{}{
+		"mfa_enabled": true,
+	}, domains.UserActor(userID), ipAddress, requestID, s.repos)
+
+	return codes, nil
+}
+
+// Disable turns MFA off after checking a code. Users with administrative permissions cannot turn it off.
+func (s *mfaService) Disable(userID uuid.UUID, code, ipAddress, requestID string) error {
+	user, err := s.repos.User.GetByID(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+	mandatory, err := s.mandatory(user.ID)
+	if err != nil {
+		return err
+	}
+	if mandatory {
+		return domains.ErrMFAMandatory
+	}
+	if user.MFAEnabledAt == nil {
+		return domains.ErrMFANotEnabled
+	}
+	if err := s.verify(user, code); err != nil {
+		return err
+	}
+
+	user.MFASecret = ""
+	user.MFAEnabledAt = nil
+	user.MFALastStep = 0
+	user.UpdatedBy = &userID
+	if err := s.repos.User.Update(user); err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	if err := s.repos.MFA.DeleteRecoveryCodes(user.ID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	createAuditLog("users", user.ID, "UPDATE", nil, map[string]interface{}{
+		"mfa_enabled": false,
+	}, domains.UserActor(userID), ipAddress, requestID, s.repos)
+
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes, for when they run out or may have leaked
+func (s *mfaService) RegenerateRecoveryCodes(userID uuid.UUID, code, ipAddress, requestID string) ([]string, error) {
+	user, err := s.repos.User.GetByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if user.MFAEnabledAt == nil {
+		return nil, domains.ErrMFANotEnabled
+	}
+	if err := s.verify(user, code); err != nil {
+		return nil, err
+	}
+
+	codes, err := s.replaceRecoveryCodes(user.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	createAuditLog("mfa_recovery_codes", user.ID, "INSERT", nil, map[string]interface{}{
+		"count": len(codes),
+	}, domains.UserActor(userID), ipAddress, requestID, s.repos)
+
+	return codes, nil
+}
+
+// verify accepts a TOTP code or an unused recovery code of a user with MFA enabled
+func (s *mfaService) verify(user *models.User, code string) error {
+	code = strings.ReplaceAll(code, " ", "")
+	if len(code) == totp.Digits {
+		return s.verifyTOTP(user, code, time.Now())
+	}
+	return s.useRecoveryCode(user.ID, code)
+}
+
+// verifyTOTP checks a code of the user's secret. A code is accepted once; the step it matched is stored.
+func (s *mfaService) verifyTOTP(user *models.User, code string, now time.Time) error {
+	secret, err := openSecret(s.cfg.Secret, user.MFASecret)
+	if err != nil {
+		return fmt.Errorf("failed to open mfa secret: %w", err)
+	}
+
+	step, ok := totp.Validate(secret, code, now, mfaSkew)
+	if !ok || step <= user.MFALastStep {
+		return domains.ErrInvalidMFACode
+	}
+
+	// The step is only stored if it is later than the stored one, so two requests with the same code
+	// cannot both be accepted
+	stored, err := s.repos.MFA.MarkTOTPStepUsed(user.ID, step)
+	if err != nil {
+		return fmt.Errorf("failed to use mfa code: %w", err)
+	}
+	if !stored {
+		return domains.ErrInvalidMFACode
+	}
+	user.MFALastStep = step
+	return nil
+}
+
+func (s *mfaService) useRecoveryCode(userID uuid.UUID, code string) error {
+	codes, err := s.repos.MFA.GetUnusedRecoveryCodes(userID)
+	if err != nil {
+		return fmt.Errorf("failed to get recovery codes: %w", err)
+	}
+
+	hash := hashRecoveryCode(code)
+	for _, recovery := range codes {
+		if subtle.ConstantTimeCompare([]byte(recovery.CodeHash), []byte(hash)) != 1 {
+			continue
+		}
+		used, err := s.repos.MFA.MarkRecoveryCodeUsed(recovery.ID, time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to use recovery code: %w", err)
+		}
+		if !used {
+			break
+		}
+		return nil
+	}
+	return domains.ErrInvalidMFACode
+}
+
+func (s *mfaService) replaceRecoveryCodes(userID uuid.UUID, now time.Time) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	stored := make([]models.MFARecoveryCode, recoveryCodeCount)
+	for i := range codes {
+		random := make([]byte, 5)
+		if _, err := rand.Read(random); err != nil {
+			return nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+		code := strings.ToLower(recoveryCodeEncoding.EncodeToString(random))
+		codes[i] = code[:4] + "-" + code[4:]
+		stored[i] = models.MFARecoveryCode{
+			ID:        uuid.New(),
+			UserID:    userID,
+			CodeHash:  hashRecoveryCode(codes[i]),
+			CreatedAt: now,
+		}
+	}
+
+	if err := s.repos.MFA.ReplaceRecoveryCodes(userID, stored); err != nil {
+		return nil, fmt.Errorf("failed to store recovery codes: %w", err)
+	}
+	return codes, nil
+}
+
+// hashRecoveryCode is how recovery codes are stored, ignoring case and the dash they are shown with
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// required reports whether logins of the user need a second factor
+func (s *mfaService) required(user *models.User) (bool, error) {
+	if user.MFAEnabledAt != nil {
+		return true, nil
+	}
+	return s.mandatory(user.ID)
+}
+
+// mandatory reports whether the user holds permissions beyond self-service, which call for MFA
+func (s *mfaService) mandatory(userID uuid.UUID) (bool, error) {
+	permissions, err := s.repos.Role.GetUserPermissions(userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get permissions: %w", err)
+	}
+	return rbac.Privileged(permissions), nil
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"payslip-system/internal/config"
+	"payslip-system/internal/domains"
+	"payslip-system/internal/models"
+	"payslip-system/internal/rbac"
+	"payslip-system/internal/repository"
+	mock_repository "payslip-system/internal/repository/mocks"
+	"payslip-system/internal/totp"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testMFAConfig = config.MFAConfig{Issuer: "PT Test", Secret: "mfa-secret", ChallengeTTL: 5, MaxAttempts: 3}
+
+// newMFAUser returns a user enrolled with a fresh TOTP secret, and the secret
+func newMFAUser(t *testing.T, role string, enabled bool) (*models.User, string) {
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	sealed, err := sealSecret(testMFAConfig.Secret, secret)
+	require.NoError(t, err)
+
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: role + "1", Role: role, IsActive: true, MFASecret: sealed}
+	if enabled {
+		enabledAt := time.Now().Add(-24 * time.Hour)
+		user.MFAEnabledAt = &enabledAt
+	}
+	return user, secret
+}
+
+// builtinPermissions returns the permissions of a builtin role
+func builtinPermissions(role string) []string {
+	for _, builtin := range rbac.BuiltinRoles {
+		if builtin.Name == role {
+			return builtin.Permissions
+		}
+	}
+	return nil
+}
+
+func currentCode(t *testing.T, secret string) string {
+	code, err := totp.Code(secret, totp.Step(time.Now()))
+	require.NoError(t, err)
+	return code
+}
+
+func Test_mfaService_Enroll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "admin", Role: "admin", IsActive: true}
+
+	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+	mockUserRepo.EXPECT().GetByID(user.ID).Return(user, nil)
+	mockUserRepo.EXPECT().Update(user).Return(nil)
+
+	s := NewMFAService(&repository.Repositories{User: mockUserRepo}, testMFAConfig)
+
+	enrollment, err := s.Enroll(user.ID)
+	require.NoError(t, err)
+	assert.Contains(t, enrollment.URI, "otpauth://totp/PT%20Test:admin?")
+	assert.Contains(t, enrollment.URI, "secret="+enrollment.Secret)
+	assert.NotEmpty(t, enrollment.QRCode)
+
+	// The secret is sealed at rest
+	assert.NotContains(t, user.MFASecret, enrollment.Secret)
+	opened, err := openSecret(testMFAConfig.Secret, user.MFASecret)
+	require.NoError(t, err)
+	assert.Equal(t, enrollment.Secret, opened)
+	assert.Nil(t, user.MFAEnabledAt)
+}
+
+func Test_mfaService_Confirm(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name     string
+		enrolled bool
+		enabled  bool
+		wrong    bool
+		wantErr  error
+	}{
+		{name: "success", enrolled: true},
+		{name: "error - wrong code", enrolled: true, wrong: true, wantErr: domains.ErrInvalidMFACode},
+		{name: "error - not enrolled", wantErr: domains.ErrMFANotEnrolled},
+		{name: "error - already enabled", enrolled: true, enabled: true, wantErr: domains.ErrMFAAlreadyEnabled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, secret := newMFAUser(t, "employee", tt.enabled)
+			if !tt.enrolled {
+				user.MFASecret = ""
+			}
+			code := currentCode(t, secret)
+			if tt.wrong {
+				code = "000000"
+				if currentCode(t, secret) == code {
+					code = "111111"
+				}
+			}
+
+			mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+			mockMFARepo := mock_repository.NewMockIMFARepository(ctrl)
+			mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+
+			mockUserRepo.EXPECT().GetByID(user.ID).Return(user, nil)
+			var stored []models.MFARecoveryCode
+			if tt.wantErr == nil {
+				mockMFARepo.EXPECT().MarkTOTPStepUsed(user.ID, gomock.Any()).Return(true, nil)
+				mockUserRepo.EXPECT().Update(user).Return(nil)
+				mockMFARepo.EXPECT().ReplaceRecoveryCodes(user.ID, gomock.Any()).DoAndReturn(func(userID uuid.UUID, codes []models.MFARecoveryCode) error {
+					stored = codes
+					return nil
+				})
+				mockAuditLogRepo.EXPECT().Create(gomock.Any()).Return(nil)
+			}
+
+			repos := &repository.Repositories{User: mockUserRepo, MFA: mockMFARepo, AuditLog: mockAuditLogRepo}
+			s := NewMFAService(repos, testMFAConfig)
+
+			codes, err := s.Confirm(user.ID, code, "127.0.0.1", "req-123")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.NotNil(t, user.MFAEnabledAt)
+			assert.Equal(t, totp.Step(time.Now()), user.MFALastStep)
+			require.Len(t, codes, recoveryCodeCount)
+			require.Len(t, stored, recoveryCodeCount)
+			assert.Regexp(t, `^[a-z2-7]{4}-[a-z2-7]{4}$`, codes[0])
+			assert.Equal(t, hashRecoveryCode(codes[0]), stored[0].CodeHash)
+			assert.NotContains(t, stored[0].CodeHash, codes[0])
+		})
+	}
+}
+
+func Test_mfaService_verify(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user, secret := newMFAUser(t, "employee", true)
+	recovery := models.MFARecoveryCode{ID: uuid.New(), UserID: user.ID, CodeHash: hashRecoveryCode("abcd-efgh")}
+
+	mockMFARepo := mock_repository.NewMockIMFARepository(ctrl)
+	mockMFARepo.EXPECT().MarkTOTPStepUsed(user.ID, gomock.Any()).Return(true, nil)
+	mockMFARepo.EXPECT().MarkTOTPStepUsed(user.ID, gomock.Any()).Return(false, nil)
+	mockMFARepo.EXPECT().GetUnusedRecoveryCodes(user.ID).Return([]models.MFARecoveryCode{recovery}, nil).Times(3)
+	mockMFARepo.EXPECT().MarkRecoveryCodeUsed(recovery.ID, gomock.Any()).Return(true, nil)
+	mockMFARepo.EXPECT().MarkRecoveryCodeUsed(recovery.ID, gomock.Any()).Return(false, nil)
+
+	s := NewMFAService(&repository.Repositories{MFA: mockMFARepo}, testMFAConfig)
+
+	code := currentCode(t, secret)
+	stale := *user
+	require.NoError(t, s.verify(user, code))
+
+	// A TOTP code is accepted once, also by a request that loaded the user before it was used
+	assert.ErrorIs(t, s.verify(user, code), domains.ErrInvalidMFACode)
+	assert.ErrorIs(t, s.verify(&stale, code), domains.ErrInvalidMFACode)
+
+	// Recovery codes ignore case and the dash, and are spent on use
+	require.NoError(t, s.verify(user, "ABCDEFGH"))
+	assert.ErrorIs(t, s.verify(user, "abcd-efgh"), domains.ErrInvalidMFACode)
+	assert.ErrorIs(t, s.verify(user, "zzzz-zzzz"), domains.ErrInvalidMFACode)
+}
+
+func Test_mfaService_Disable(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	admin, adminSecret := newMFAUser(t, "admin", true)
+	employee, employeeSecret := newMFAUser(t, "employee", true)
+
+	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+	mockMFARepo := mock_repository.NewMockIMFARepository(ctrl)
+	mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+	mockRoleRepo := mock_repository.NewMockIRoleRepository(ctrl)
+
+	mockUserRepo.EXPECT().GetByID(admin.ID).Return(admin, nil)
+	mockUserRepo.EXPECT().GetByID(employee.ID).Return(employee, nil)
+	mockRoleRepo.EXPECT().GetUserPermissions(admin.ID).Return(builtinPermissions("admin"), nil)
+	mockRoleRepo.EXPECT().GetUserPermissions(employee.ID).Return(builtinPermissions("employee"), nil)
+	mockMFARepo.EXPECT().MarkTOTPStepUsed(employee.ID, gomock.Any()).Return(true, nil)
+	mockUserRepo.EXPECT().Update(employee).Return(nil)
+	mockMFARepo.EXPECT().DeleteRecoveryCodes(employee.ID).Return(nil)
+	mockAuditLogRepo.EXPECT().Create(gomock.Any()).Return(nil)
+
+	repos := &repository.Repositories{User: mockUserRepo, MFA: mockMFARepo, AuditLog: mockAuditLogRepo, Role: mockRoleRepo}
+	s := NewMFAService(repos, testMFAConfig)
+
+	assert.ErrorIs(t, s.Disable(admin.ID, currentCode(t, adminSecret), "127.0.0.1", "req-123"), domains.ErrMFAMandatory)
+
+	require.NoError(t, s.Disable(employee.ID, currentCode(t, employeeSecret), "127.0.0.1", "req-123"))
+	assert.Nil(t, employee.MFAEnabledAt)
+	assert.Empty(t, employee.MFASecret)
+}
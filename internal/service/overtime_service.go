@@ -3,6 +3,7 @@ package service
 import (
 	"errors"
 	"fmt"
+	"payslip-system/internal/domains"
 	"payslip-system/internal/models"
 	"payslip-system/internal/repository"
 	"time"
@@ -29,31 +30,38 @@ func (s *overtimeService) SubmitOvertime(userID uuid.UUID, date time.Time, hours
 		return errors.New("overtime already submitted for this date")
 	}
 
-	// Get active attendance period
-	period, err := s.repos.AttendancePeriod.GetActive()
+	user, err := s.repos.User.GetByID(userID)
 	if err != nil {
-		return errors.New("no active attendance period found")
+		return errors.New("user not found")
 	}
 
-	// Create overtime record
-	overtime := &models.Overtime{
-		BaseModel: models.BaseModel{
-			CreatedBy: &userID,
-			IPAddress: ipAddress,
-			RequestID: requestID,
-		},
-		UserID:             userID,
-		AttendancePeriodID: period.ID,
-		Date:               date,
-		Hours:              hours,
-	}
+	var overtime *models.Overtime
+	err = withSubmissionPeriod(s.repos, func(txRepos *repository.Repositories, period *models.AttendancePeriod) error {
+		// Create overtime record
+		overtime = &models.Overtime{
+			BaseModel: models.BaseModel{
+				CreatedBy: &userID,
+				IPAddress: ipAddress,
+				RequestID: requestID,
+			},
+			UserID:             userID,
+			AttendancePeriodID: period.ID,
+			Date:               date,
+			Hours:              hours,
+			Approval:           submissionApproval(user),
+		}
 
-	if err := s.repos.Overtime.Create(overtime); err != nil {
-		return fmt.Errorf("failed to create overtime record: %w", err)
+		if err := txRepos.Overtime.Create(overtime); err != nil {
+			return fmt.Errorf("failed to create overtime record: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	// Create audit log
-	createAuditLog("overtimes", overtime.ID, "INSERT", nil, overtime, &userID, ipAddress, requestID, s.repos)
+	createAuditLog("overtimes", overtime.ID, "INSERT", nil, overtime, domains.UserActor(userID), ipAddress, requestID, s.repos)
 
 	return nil
 }
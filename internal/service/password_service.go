@@ -0,0 +1,373 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	netmail "net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"payslip-system/internal/config"
+	"payslip-system/internal/domains"
+	"payslip-system/internal/mail"
+	"payslip-system/internal/models"
+	"payslip-system/internal/password"
+	"payslip-system/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// resetResponseTime is the least a reset request takes, so the response time does not tell whether an email
+// was queued
+var resetResponseTime = 200 * time.Millisecond
+
+type passwordService struct {
+	repos     *repository.Repositories
+	cfg       config.PasswordConfig
+	policy    *password.Policy
+	mailCfg   config.MailConfig
+	company   string
+	languages domains.ILanguageService
+	sender    mail.Sender
+
+	// Passwords are neither changed nor reset when only single sign-on is allowed
+	disabled bool
+}
+
+func NewPasswordService(repos *repository.Repositories, cfg config.PasswordConfig, policy *password.Policy, mailCfg config.MailConfig, company string, languages domains.ILanguageService, sender mail.Sender, passwordLoginDisabled bool) *passwordService {
+	return &passwordService{
+		repos:     repos,
+		cfg:       cfg,
+		policy:    policy,
+		mailCfg:   mailCfg,
+		company:   company,
+		languages: languages,
+		sender:    sender,
+		disabled:  passwordLoginDisabled,
+	}
+}
+
+// ChangePassword sets a new password after checking the current one. The user's other sessions are revoked;
+// the one the change was made with stays logged in.
+func (s *passwordService) ChangePassword(userID, sessionID uuid.UUID, currentPassword, newPassword, ipAddress, requestID string) error {
+	if s.disabled {
+		return domains.ErrPasswordLoginDisabled
+	}
+
+	user, err := s.repos.User.GetByID(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(currentPassword)); err != nil {
+		return domains.ErrWrongPassword
+	}
+	if err := s.checkNewPassword(user, newPassword); err != nil {
+		return err
+	}
+
+	return s.setPassword(user, newPassword, &userID, &sessionID, ipAddress, requestID)
+}
+
+// RequestReset queues an email with a reset token to the user. Requests are throttled per username and per IP
+// address. Nothing tells the caller whether the username exists or has an email address, not even the
+// response time.
+func (s *passwordService) RequestReset(username, ipAddress, requestID string) error {
+	if s.disabled {
+		return domains.ErrPasswordLoginDisabled
+	}
+
+	start := time.Now()
+	defer func() {
+		time.Sleep(time.Until(start.Add(resetResponseTime)))
+	}()
+
+	if err := s.throttleReset(strings.ToLower(strings.TrimSpace(username)), ipAddress, start); err != nil {
+		return err
+	}
+
+	user, err := s.repos.User.GetByUsername(username)
+	if err != nil || !user.IsActive || user.Email == "" {
+		return nil
+	}
+
+	// The token is issued when the email is sent, so the queue holds none
+	delivery := &models.EmailDelivery{
+		BaseModel: models.BaseModel{
+			IPAddress: ipAddress,
+			RequestID: requestID,
+		},
+		UserID:        user.ID,
+		Template:      mail.TemplatePasswordReset,
+		Recipient:     user.Email,
+		Status:        models.EmailStatusQueued,
+		NextAttemptAt: start,
+	}
+	if _, err := s.repos.EmailDelivery.CreateIfAbsent(delivery); err != nil {
+		logrus.WithError(err).WithField("user_id", user.ID).Error("Failed to queue password reset email")
+	}
+	return nil
+}
+
+// SendResetEmail issues a reset token for a queued reset email and sends it. Every attempt issues a new token,
+// superseding the one of an attempt that failed.
+func (s *passwordService) SendResetEmail(delivery *models.EmailDelivery) error {
+	user := &delivery.User
+	if !user.IsActive {
+		return errors.New("user is inactive")
+	}
+
+	token, resetToken, err := s.createResetToken(user, nil, delivery.IPAddress)
+	if err != nil {
+		return err
+	}
+	return s.sendResetEmail(user, delivery.Recipient, resetToken, token.ExpiresAt, false)
+}
+
+// ResetPassword sets a new password with a reset token. All sessions of the user are revoked and the lockout
+// of the username, if any, is lifted.
+func (s *passwordService) ResetPassword(resetToken, newPassword, ipAddress, requestID string) error {
+	if s.disabled {
+		return domains.ErrPasswordLoginDisabled
+	}
+
+	token, err := s.repos.Password.GetResetTokenByHash(hashRefreshToken(resetToken))
+	if err != nil || token.UsedAt != nil || time.Now().After(token.ExpiresAt) {
+		return domains.ErrInvalidResetToken
+	}
+
+	user, err := s.repos.User.GetByID(token.UserID)
+	if err != nil || !user.IsActive {
+		return domains.ErrInvalidResetToken
+	}
+
+	// A password the policy refuses leaves the token usable for another try
+	if err := s.checkNewPassword(user, newPassword); err != nil {
+		return err
+	}
+
+	spent, err := s.repos.Password.MarkResetTokenUsed(token.ID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to use reset token: %w", err)
+	}
+	if !spent {
+		return domains.ErrInvalidResetToken
+	}
+
+	if err := s.setPassword(user, newPassword, &user.ID, nil, ipAddress, requestID); err != nil {
+		return err
+	}
+
+	if err := s.repos.LoginThrottle.DeleteByKey(models.LoginThrottleUsername, strings.ToLower(user.Username)); err != nil {
+		logrus.WithError(err).Warn("Failed to reset login throttle")
+	}
+	return nil
+}
+
+// AdminReset signs the user out everywhere and makes them change their password: the current one only works
+// to set a new one. A reset token is emailed right away, or returned when the user has no email address or
+// the email fails.
+func (s *passwordService) AdminReset(userID, adminID uuid.UUID, ipAddress, requestID string) (*domains.PasswordResetResult, error) {
+	if s.disabled {
+		return nil, domains.ErrPasswordLoginDisabled
+	}
+
+	user, err := s.repos.User.GetByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	now := time.Now()
+	user.MustChangePassword = true
+	user.UpdatedBy = &adminID
+	user.IPAddress = ipAddress
+	user.RequestID = requestID
+	if err := s.repos.User.Update(user); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	revoked, err := s.repos.Session.RevokeAllForUser(user.ID, models.SessionRevokedPasswordChange, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	token, resetToken, err := s.createResetToken(user, &adminID, ipAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domains.PasswordResetResult{ExpiresAt: token.ExpiresAt, SessionsRevoked: revoked}
+	if user.Email != "" {
+		if err := s.sendResetEmail(user, user.Email, resetToken, token.ExpiresAt, true); err != nil {
+			logrus.WithError(err).WithField("user_id", user.ID).Error("Failed to send password reset email")
+		} else {
+			result.Emailed = true
+		}
+	}
+	if !result.Emailed {
+		result.ResetToken = resetToken
+	}
+
+	createAuditLog("users", user.ID, "UPDATE", nil, map[string]interface{}{
+		"password_reset":       true,
+		"must_change_password": true,
+		"sessions_revoked":     revoked,
+		"emailed":              result.Emailed,
+	}, domains.UserActor(adminID), ipAddress, requestID, s.repos)
+
+	return result, nil
+}
+
+// checkNewPassword applies the password policy and refuses the current and recent passwords
+func (s *passwordService) checkNewPassword(user *models.User, newPassword string) error {
+	if violations := s.policy.Check(newPassword, user.Username); len(violations) > 0 {
+		return &domains.PasswordPolicyError{Violations: violations}
+	}
+
+	previous := []string{user.Password}
+	history, err := s.repos.Password.GetHistory(user.ID, s.cfg.History)
+	if err != nil {
+		return fmt.Errorf("failed to get password history: %w", err)
+	}
+	for _, entry := range history {
+		previous = append(previous, entry.PasswordHash)
+	}
+	for _, hash := range previous {
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(newPassword)) == nil {
+			return domains.ErrPasswordReused
+		}
+	}
+	return nil
+}
+
+// setPassword stores a checked password, keeps the old one in the history and invalidates outstanding reset
+// tokens and sessions, except keepSessionID when set
+func (s *passwordService) setPassword(user *models.User, newPassword string, actorID, keepSessionID *uuid.UUID, ipAddress, requestID string) error {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	now := time.Now()
+	previous := &models.PasswordHistory{
+		ID:           uuid.New(),
+		UserID:       user.ID,
+		PasswordHash: user.Password,
+		CreatedAt:    now,
+	}
+
+	user.Password = string(hashed)
+	user.MustChangePassword = false
+	user.PasswordChangedAt = &now
+	user.UpdatedBy = actorID
+	user.IPAddress = ipAddress
+	user.RequestID = requestID
+	if err := s.repos.Password.SetPassword(user, previous, now); err != nil {
+		return fmt.Errorf("failed to set password: %w", err)
+	}
+
+	var revoked int64
+	if keepSessionID != nil {
+		revoked, err = s.repos.Session.RevokeOthersForUser(user.ID, *keepSessionID, models.SessionRevokedPasswordChange, now)
+	} else {
+		revoked, err = s.repos.Session.RevokeAllForUser(user.ID, models.SessionRevokedPasswordChange, now)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	createAuditLog("users", user.ID, "UPDATE", nil, map[string]interface{}{
+		"password_changed": true,
+		"sessions_revoked": revoked,
+	}, domains.Actor{UserID: actorID}, ipAddress, requestID, s.repos)
+
+	return nil
+}
+
+// throttleReset counts a reset request for the username and the IP address, and refuses it when either asked
+// too often within the window
+func (s *passwordService) throttleReset(usernameKey, ipAddress string, now time.Time) error {
+	window := time.Duration(s.cfg.ResetWindow) * time.Minute
+	throttled := false
+	for _, key := range []struct {
+		scope, key  string
+		maxRequests int
+	}{
+		{models.LoginThrottleResetUsername, usernameKey, s.cfg.ResetMaxRequests},
+		{models.LoginThrottleResetIP, ipAddress, s.cfg.ResetIPMaxRequests},
+	} {
+		throttle, err := s.repos.LoginThrottle.RecordFailure(key.scope, key.key, now, window)
+		if err != nil {
+			return fmt.Errorf("failed to record reset request: %w", err)
+		}
+		if throttle.Failures > key.maxRequests {
+			throttled = true
+		}
+	}
+	if throttled {
+		return &domains.ResetThrottledError{RetryAfter: window}
+	}
+	return nil
+}
+
+// createResetToken issues a reset token, superseding the user's earlier ones
+func (s *passwordService) createResetToken(user *models.User, adminID *uuid.UUID, ipAddress string) (*models.PasswordResetToken, string, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return nil, "", fmt.Errorf("failed to generate reset token: %w", err)
+	}
+	resetToken := base64.RawURLEncoding.EncodeToString(random)
+
+	now := time.Now()
+	if err := s.repos.Password.InvalidateResetTokens(user.ID, now); err != nil {
+		return nil, "", fmt.Errorf("failed to invalidate reset tokens: %w", err)
+	}
+
+	token := &models.PasswordResetToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		TokenHash: hashRefreshToken(resetToken),
+		ExpiresAt: now.Add(time.Duration(s.cfg.ResetTokenTTL) * time.Minute),
+		CreatedBy: adminID,
+		IPAddress: ipAddress,
+		CreatedAt: now,
+	}
+	if err := s.repos.Password.CreateResetToken(token); err != nil {
+		return nil, "", fmt.Errorf("failed to store reset token: %w", err)
+	}
+	return token, resetToken, nil
+}
+
+// sendResetEmail sends a reset token to the address given
+func (s *passwordService) sendResetEmail(user *models.User, address, resetToken string, expiresAt time.Time, byAdmin bool) error {
+	tr := s.languages.Translator(user.PreferredLanguage)
+	data := mail.PasswordResetData{
+		Language:  tr.Language(),
+		Name:      user.Username,
+		Company:   s.company,
+		Token:     resetToken,
+		ExpiresIn: int(time.Until(expiresAt).Round(time.Minute).Minutes()),
+		ByAdmin:   byAdmin,
+	}
+	if s.cfg.ResetURL != "" {
+		data.ResetURL = strings.ReplaceAll(s.cfg.ResetURL, "{token}", url.QueryEscape(resetToken))
+	}
+
+	content, err := mail.Render(mail.TemplatePasswordReset, tr, data)
+	if err != nil {
+		return err
+	}
+
+	return s.sender.Send(&mail.Message{
+		From:    netmail.Address{Name: s.mailCfg.FromName, Address: s.mailCfg.From},
+		To:      netmail.Address{Name: user.Username, Address: address},
+		Subject: content.Subject,
+		Text:    content.Text,
+		HTML:    content.HTML,
+	})
+}
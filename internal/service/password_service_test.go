@@ -0,0 +1,360 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"payslip-system/internal/config"
+	"payslip-system/internal/domains"
+	"payslip-system/internal/mail"
+	"payslip-system/internal/models"
+	"payslip-system/internal/password"
+	"payslip-system/internal/repository"
+	mock_repository "payslip-system/internal/repository/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var testPasswordConfig = config.PasswordConfig{MinLength: 10, History: 3, ResetTokenTTL: 60, ResetURL: "https://payroll.example.com/reset?token={token}",
+	ResetMaxRequests: 3, ResetIPMaxRequests: 20, ResetWindow: 60}
+
+func hashPassword(t *testing.T, plain string) string {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(plain), bcrypt.MinCost)
+	require.NoError(t, err)
+	return string(hashed)
+}
+
+func newPasswordService(t *testing.T, ctrl *gomock.Controller, repos *repository.Repositories, sender *fakeSender) *passwordService {
+	policy, err := password.NewPolicy(testPasswordConfig)
+	require.NoError(t, err)
+
+	mockLanguageRepo := mock_repository.NewMockILanguageRepository(ctrl)
+	mockLanguageRepo.EXPECT().GetAll().Return(nil, nil).AnyTimes()
+	languages := NewLanguageService(&repository.Repositories{Language: mockLanguageRepo}, config.I18nConfig{DefaultLanguage: "en"})
+
+	mailCfg := config.MailConfig{From: "payroll@example.com", FromName: "Payroll"}
+	return NewPasswordService(repos, testPasswordConfig, policy, mailCfg, "PT Mini Payroll", languages, sender, false)
+}
+
+func Test_passwordService_ChangePassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name            string
+		currentPassword string
+		newPassword     string
+		wantErr         error
+		wantViolations  bool
+	}{
+		{name: "success", currentPassword: "summit-ember-93", newPassword: "glacier-tuesday-41"},
+		{name: "wrong current password", currentPassword: "summit-ember-94", newPassword: "glacier-tuesday-41", wantErr: domains.ErrWrongPassword},
+		{name: "policy violation", currentPassword: "summit-ember-93", newPassword: "short", wantViolations: true},
+		{name: "current password", currentPassword: "summit-ember-93", newPassword: "summit-ember-93", wantErr: domains.ErrPasswordReused},
+		{name: "previous password", currentPassword: "summit-ember-93", newPassword: "orchid-lantern-77", wantErr: domains.ErrPasswordReused},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "employee1", Role: "employee", IsActive: true,
+				Password: hashPassword(t, "summit-ember-93"), MustChangePassword: true}
+			oldHash := user.Password
+			sessionID := uuid.New()
+
+			mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+			mockPasswordRepo := mock_repository.NewMockIPasswordRepository(ctrl)
+			mockSessionRepo := mock_repository.NewMockISessionRepository(ctrl)
+			mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+
+			mockUserRepo.EXPECT().GetByID(user.ID).Return(user, nil)
+			if tt.wantErr != domains.ErrWrongPassword && !tt.wantViolations {
+				mockPasswordRepo.EXPECT().GetHistory(user.ID, testPasswordConfig.History).Return([]models.PasswordHistory{
+					{UserID: user.ID, PasswordHash: hashPassword(t, "orchid-lantern-77")},
+				}, nil)
+			}
+			if tt.wantErr == nil && !tt.wantViolations {
+				mockPasswordRepo.EXPECT().SetPassword(user, gomock.Any(), gomock.Any()).DoAndReturn(func(_ *models.User, previous *models.PasswordHistory, _ time.Time) error {
+					assert.Equal(t, oldHash, previous.PasswordHash)
+					return nil
+				})
+				mockSessionRepo.EXPECT().RevokeOthersForUser(user.ID, sessionID, models.SessionRevokedPasswordChange, gomock.Any()).Return(int64(2), nil)
+				mockAuditLogRepo.EXPECT().Create(gomock.Any()).Return(nil)
+			}
+
+			repos := &repository.Repositories{User: mockUserRepo, Password: mockPasswordRepo, Session: mockSessionRepo, AuditLog: mockAuditLogRepo}
+			s := newPasswordService(t, ctrl, repos, &fakeSender{})
+
+			err := s.ChangePassword(user.ID, sessionID, tt.currentPassword, tt.newPassword, "127.0.0.1", "req-123")
+			if tt.wantViolations {
+				var policy *domains.PasswordPolicyError
+				require.ErrorAs(t, err, &policy)
+				assert.NotEmpty(t, policy.Violations)
+				return
+			}
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Equal(t, oldHash, user.Password)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(tt.newPassword)))
+			assert.False(t, user.MustChangePassword)
+			assert.NotNil(t, user.PasswordChangedAt)
+		})
+	}
+}
+
+func Test_passwordService_ResetPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	usedAt := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name      string
+		missing   bool
+		expired   bool
+		used      bool
+		inactive  bool
+		raceLost  bool
+		wantErr   error
+		wantReset bool
+	}{
+		{name: "success", wantReset: true},
+		{name: "unknown token", missing: true, wantErr: domains.ErrInvalidResetToken},
+		{name: "expired token", expired: true, wantErr: domains.ErrInvalidResetToken},
+		{name: "used token", used: true, wantErr: domains.ErrInvalidResetToken},
+		{name: "inactive user", inactive: true, wantErr: domains.ErrInvalidResetToken},
+		{name: "token spent concurrently", raceLost: true, wantErr: domains.ErrInvalidResetToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "Employee1", Role: "employee", IsActive: !tt.inactive,
+				Password: hashPassword(t, "employee123")}
+			resetToken := "reset-token"
+			token := &models.PasswordResetToken{ID: uuid.New(), UserID: user.ID, TokenHash: hashRefreshToken(resetToken), ExpiresAt: time.Now().Add(time.Hour)}
+			if tt.expired {
+				token.ExpiresAt = time.Now().Add(-time.Minute)
+			}
+			if tt.used {
+				token.UsedAt = &usedAt
+			}
+
+			mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+			mockPasswordRepo := mock_repository.NewMockIPasswordRepository(ctrl)
+			mockSessionRepo := mock_repository.NewMockISessionRepository(ctrl)
+			mockThrottleRepo := mock_repository.NewMockILoginThrottleRepository(ctrl)
+			mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+
+			if tt.missing {
+				mockPasswordRepo.EXPECT().GetResetTokenByHash(token.TokenHash).Return(nil, gorm.ErrRecordNotFound)
+			} else {
+				mockPasswordRepo.EXPECT().GetResetTokenByHash(token.TokenHash).Return(token, nil)
+			}
+			if !tt.missing && !tt.expired && !tt.used {
+				mockUserRepo.EXPECT().GetByID(user.ID).Return(user, nil)
+			}
+			if tt.raceLost || tt.wantReset {
+				mockPasswordRepo.EXPECT().GetHistory(user.ID, testPasswordConfig.History).Return(nil, nil)
+				mockPasswordRepo.EXPECT().MarkResetTokenUsed(token.ID, gomock.Any()).Return(!tt.raceLost, nil)
+			}
+			if tt.wantReset {
+				mockPasswordRepo.EXPECT().SetPassword(user, gomock.Any(), gomock.Any()).Return(nil)
+				mockSessionRepo.EXPECT().RevokeAllForUser(user.ID, models.SessionRevokedPasswordChange, gomock.Any()).Return(int64(1), nil)
+				mockAuditLogRepo.EXPECT().Create(gomock.Any()).Return(nil)
+				mockThrottleRepo.EXPECT().DeleteByKey(models.LoginThrottleUsername, "employee1").Return(nil)
+			}
+
+			repos := &repository.Repositories{User: mockUserRepo, Password: mockPasswordRepo, Session: mockSessionRepo,
+				LoginThrottle: mockThrottleRepo, AuditLog: mockAuditLogRepo}
+			s := newPasswordService(t, ctrl, repos, &fakeSender{})
+
+			err := s.ResetPassword(resetToken, "glacier-tuesday-41", "127.0.0.1", "req-123")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(user.Password), []byte("glacier-tuesday-41")))
+		})
+	}
+}
+
+func Test_passwordService_RequestReset(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "employee1", Email: "employee1@example.com", IsActive: true}
+
+	tests := []struct {
+		name          string
+		username      string
+		requests      int // including this one
+		ipRequests    int
+		wantQueued    bool
+		wantThrottled bool
+	}{
+		{name: "success - email queued", username: "employee1", requests: 1, ipRequests: 1, wantQueued: true},
+		{name: "success - unknown users get the same answer", username: "nobody", requests: 1, ipRequests: 1},
+		{name: "error - too many requests for the username", username: "employee1", requests: 4, ipRequests: 4, wantThrottled: true},
+		{name: "error - too many requests from the IP address", username: "nobody", requests: 1, ipRequests: 21, wantThrottled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+			mockThrottleRepo := mock_repository.NewMockILoginThrottleRepository(ctrl)
+			mockDeliveryRepo := mock_repository.NewMockIEmailDeliveryRepository(ctrl)
+
+			mockThrottleRepo.EXPECT().RecordFailure(models.LoginThrottleResetUsername, tt.username, gomock.Any(), time.Hour).
+				Return(&models.LoginThrottle{Failures: tt.requests}, nil)
+			mockThrottleRepo.EXPECT().RecordFailure(models.LoginThrottleResetIP, "127.0.0.1", gomock.Any(), time.Hour).
+				Return(&models.LoginThrottle{Failures: tt.ipRequests}, nil)
+			if !tt.wantThrottled {
+				if tt.username == "employee1" {
+					mockUserRepo.EXPECT().GetByUsername(tt.username).Return(user, nil)
+				} else {
+					mockUserRepo.EXPECT().GetByUsername(tt.username).Return(nil, gorm.ErrRecordNotFound)
+				}
+			}
+			var queued *models.EmailDelivery
+			if tt.wantQueued {
+				mockDeliveryRepo.EXPECT().CreateIfAbsent(gomock.Any()).DoAndReturn(func(delivery *models.EmailDelivery) (bool, error) {
+					queued = delivery
+					return true, nil
+				})
+			}
+
+			sender := &fakeSender{}
+			repos := &repository.Repositories{User: mockUserRepo, LoginThrottle: mockThrottleRepo, EmailDelivery: mockDeliveryRepo}
+			s := newPasswordService(t, ctrl, repos, sender)
+
+			start := time.Now()
+			err := s.RequestReset(tt.username, "127.0.0.1", "req-123")
+			assert.GreaterOrEqual(t, time.Since(start), resetResponseTime)
+			assert.Empty(t, sender.sent)
+
+			if tt.wantThrottled {
+				var throttled *domains.ResetThrottledError
+				require.ErrorAs(t, err, &throttled)
+				assert.Equal(t, time.Hour, throttled.RetryAfter)
+				return
+			}
+			require.NoError(t, err)
+			if tt.wantQueued {
+				assert.Equal(t, mail.TemplatePasswordReset, queued.Template)
+				assert.Equal(t, "employee1@example.com", queued.Recipient)
+				assert.Nil(t, queued.PayrollID)
+			}
+		})
+	}
+}
+
+func Test_passwordService_SendResetEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "employee1", Email: "employee1@example.com", IsActive: true, PreferredLanguage: "id"}
+	delivery := &models.EmailDelivery{UserID: user.ID, User: user, Template: mail.TemplatePasswordReset, Recipient: user.Email}
+
+	mockPasswordRepo := mock_repository.NewMockIPasswordRepository(ctrl)
+	mockPasswordRepo.EXPECT().InvalidateResetTokens(user.ID, gomock.Any()).Return(nil)
+	var stored *models.PasswordResetToken
+	mockPasswordRepo.EXPECT().CreateResetToken(gomock.Any()).DoAndReturn(func(token *models.PasswordResetToken) error {
+		stored = token
+		return nil
+	})
+
+	sender := &fakeSender{}
+	s := newPasswordService(t, ctrl, &repository.Repositories{Password: mockPasswordRepo}, sender)
+
+	require.NoError(t, s.SendResetEmail(delivery))
+	require.Len(t, sender.sent, 1)
+	msg := sender.sent[0]
+	assert.Equal(t, "employee1@example.com", msg.To.Address)
+	assert.Equal(t, "payroll@example.com", msg.From.Address)
+	assert.Nil(t, stored.CreatedBy)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), stored.ExpiresAt, time.Minute)
+
+	// Only the hash is stored; the token itself is in the email, in the reset link
+	assert.NotContains(t, msg.Text, stored.TokenHash)
+	assert.Contains(t, msg.Text, "https://payroll.example.com/reset?token=")
+}
+
+func Test_passwordService_AdminReset(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name        string
+		email       string
+		sendErr     error
+		wantEmailed bool
+	}{
+		{name: "emailed", email: "employee1@example.com", wantEmailed: true},
+		{name: "no email address", email: ""},
+		{name: "email fails", email: "employee1@example.com", sendErr: errors.New("connection refused")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adminID := uuid.New()
+			user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "employee1", Email: tt.email, IsActive: true}
+
+			mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+			mockPasswordRepo := mock_repository.NewMockIPasswordRepository(ctrl)
+			mockSessionRepo := mock_repository.NewMockISessionRepository(ctrl)
+			mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+
+			mockUserRepo.EXPECT().GetByID(user.ID).Return(user, nil)
+			mockUserRepo.EXPECT().Update(user).Return(nil)
+			mockSessionRepo.EXPECT().RevokeAllForUser(user.ID, models.SessionRevokedPasswordChange, gomock.Any()).Return(int64(3), nil)
+			mockPasswordRepo.EXPECT().InvalidateResetTokens(user.ID, gomock.Any()).Return(nil)
+			var stored *models.PasswordResetToken
+			mockPasswordRepo.EXPECT().CreateResetToken(gomock.Any()).DoAndReturn(func(token *models.PasswordResetToken) error {
+				stored = token
+				return nil
+			})
+			mockAuditLogRepo.EXPECT().Create(gomock.Any()).Return(nil)
+
+			sender := &fakeSender{err: tt.sendErr}
+			repos := &repository.Repositories{User: mockUserRepo, Password: mockPasswordRepo, Session: mockSessionRepo, AuditLog: mockAuditLogRepo}
+			s := newPasswordService(t, ctrl, repos, sender)
+
+			result, err := s.AdminReset(user.ID, adminID, "127.0.0.1", "req-123")
+			require.NoError(t, err)
+
+			assert.True(t, user.MustChangePassword)
+			assert.Equal(t, int64(3), result.SessionsRevoked)
+			assert.Equal(t, &adminID, stored.CreatedBy)
+			assert.Equal(t, tt.wantEmailed, result.Emailed)
+			if tt.wantEmailed {
+				assert.Empty(t, result.ResetToken)
+				require.Len(t, sender.sent, 1)
+			} else {
+				// The admin hands the token over instead
+				assert.Equal(t, stored.TokenHash, hashRefreshToken(result.ResetToken))
+			}
+		})
+	}
+}
+
+func Test_passwordService_PasswordLoginDisabled(t *testing.T) {
+	policy, err := password.NewPolicy(testPasswordConfig)
+	require.NoError(t, err)
+	s := NewPasswordService(&repository.Repositories{}, testPasswordConfig, policy, config.MailConfig{}, "PT Mini Payroll", nil, nil, true)
+
+	assert.ErrorIs(t, s.ChangePassword(uuid.New(), uuid.New(), "summit-ember-93", "glacier-tuesday-41", "127.0.0.1", "req-123"), domains.ErrPasswordLoginDisabled)
+	assert.ErrorIs(t, s.RequestReset("employee1", "127.0.0.1", "req-123"), domains.ErrPasswordLoginDisabled)
+	assert.ErrorIs(t, s.ResetPassword("token", "glacier-tuesday-41", "127.0.0.1", "req-123"), domains.ErrPasswordLoginDisabled)
+	_, err = s.AdminReset(uuid.New(), uuid.New(), "127.0.0.1", "req-123")
+	assert.ErrorIs(t, err, domains.ErrPasswordLoginDisabled)
+}
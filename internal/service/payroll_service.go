@@ -3,9 +3,11 @@ package service
 import (
 	"errors"
 	"fmt"
+	"payslip-system/internal/config"
 	"payslip-system/internal/domains"
 	"payslip-system/internal/models"
 	"payslip-system/internal/repository"
+	"payslip-system/internal/tax"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,10 +15,12 @@ import (
 
 type payrollService struct {
 	repos *repository.Repositories
+	cfg   config.PayrollConfig
+	tax   config.TaxConfig
 }
 
-func NewPayrollService(repos *repository.Repositories) *payrollService {
-	return &payrollService{repos: repos}
+func NewPayrollService(repos *repository.Repositories, cfg config.PayrollConfig, taxCfg config.TaxConfig) *payrollService {
+	return &payrollService{repos: repos, cfg: cfg, tax: taxCfg}
 }
 
 func (s *payrollService) GeneratePayslip(userID, periodID uuid.UUID) (*domains.PayslipResponse, error) {
@@ -26,7 +30,8 @@ func (s *payrollService) GeneratePayslip(userID, periodID uuid.UUID) (*domains.P
 		return nil, fmt.Errorf("user not found: %w", err)
 	}
 
-	if user.Role != "employee" || user.Salary == nil {
+	// Anyone with a salary is on the payroll, whichever roles they hold
+	if user.Salary == nil {
 		return nil, errors.New("invalid employee or salary not set")
 	}
 
@@ -38,13 +43,23 @@ func (s *payrollService) GeneratePayslip(userID, periodID uuid.UUID) (*domains.P
 
 	// If payroll is processed, get from payroll item
 	if period.IsProcessed {
+		payroll, err := s.repos.Payroll.GetRunByPeriodID(periodID)
+		if err != nil {
+			return nil, fmt.Errorf("payroll not found: %w", err)
+		}
+
+		if !payroll.IsFinal() {
+			return nil, errors.New("payroll for this period is awaiting approval")
+		}
+
 		item, err := s.repos.Payroll.GetPayrollItemsByPeriodAndUser(periodID, userID)
 		if err != nil {
 			return nil, fmt.Errorf("payroll item not found: %w", err)
 		}
 
-		// Get reimbursements
+		// Get the reimbursements that were paid
 		reimbursements, _ := s.repos.Reimbursement.GetByUserAndPeriod(userID, periodID)
+		reimbursements = approvedReimbursements(reimbursements)
 
 		return &domains.PayslipResponse{
 			Employee:            user,
@@ -58,16 +73,54 @@ func (s *payrollService) GeneratePayslip(userID, periodID uuid.UUID) (*domains.P
 			Reimbursements:      reimbursements,
 			ReimbursementAmount: item.ReimbursementAmount,
 			TotalAmount:         item.TotalAmount,
+			TaxAmount:           item.TaxAmount,
+			BPJS:                itemContributions(item),
+			PayrollItemID:       &item.ID,
 		}, nil
 	}
 
 	// Calculate live payslip
-	return s.calculatePayslip(user, period)
+	return s.calculatePayslip(s.repos, user, period)
+}
+
+// calculatePayslip computes a live payslip from the records read through repos, which
+// ProcessPayroll points at its transaction
+func (s *payrollService) calculatePayslip(repos *repository.Repositories, user *models.User, period *models.AttendancePeriod) (*domains.PayslipResponse, error) {
+	if user.Salary == nil {
+		return nil, errors.New("salary not set")
+	}
+
+	// Get attendance, overtime and reimbursement records
+	attendances, _ := repos.Attendance.GetByUserAndPeriod(user.ID, period.ID)
+	overtimes, _ := repos.Overtime.GetByUserAndPeriod(user.ID, period.ID)
+	reimbursements, _ := repos.Reimbursement.GetByUserAndPeriod(user.ID, period.ID)
+	yearToDate, err := s.yearToDate(repos, user, period)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.computePayslip(user, period, attendances, overtimes, reimbursements, yearToDate), nil
 }
 
-func (s *payrollService) calculatePayslip(user *models.User, period *models.AttendancePeriod) (*domains.PayslipResponse, error) {
-	// Get attendance records
-	attendances, _ := s.repos.Attendance.GetByUserAndPeriod(user.ID, period.ID)
+// yearToDate returns what the employee was paid earlier in the year when December's PPh 21 settles the year
+func (s *payrollService) yearToDate(repos *repository.Repositories, user *models.User, period *models.AttendancePeriod) ([]models.PayrollItem, error) {
+	if !s.tax.Withhold || period.StartDate.Month() != time.December {
+		return nil, nil
+	}
+	items, err := repos.Payroll.GetYearToDateItems(user.ID, period.StartDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get year to date payroll: %w", err)
+	}
+	return items, nil
+}
+
+// computePayslip computes the pay of a period. PPh 21 is withheld at the TER rates from January to November;
+// December withholds what is left of the tax due on the year, given the year to date items.
+func (s *payrollService) computePayslip(user *models.User, period *models.AttendancePeriod, attendances []models.Attendance, overtimes []models.Overtime, reimbursements []models.Reimbursement, yearToDate []models.PayrollItem) *domains.PayslipResponse {
+	// Only approved overtime and reimbursements are paid
+	overtimes = approvedOvertimes(overtimes)
+	reimbursements = approvedReimbursements(reimbursements)
+
 	attendanceDays := len(attendances)
 
 	// Calculate working days in period
@@ -78,8 +131,6 @@ func (s *payrollService) calculatePayslip(user *models.User, period *models.Atte
 	dailySalary := baseSalary / 30 // Assuming 30 days per month
 	attendanceAmount := dailySalary * float64(attendanceDays)
 
-	// Get overtime records
-	overtimes, _ := s.repos.Overtime.GetByUserAndPeriod(user.ID, period.ID)
 	var overtimeHours float64
 	for _, ot := range overtimes {
 		overtimeHours += ot.Hours
@@ -89,8 +140,6 @@ func (s *payrollService) calculatePayslip(user *models.User, period *models.Atte
 	hourlyRate := dailySalary / 8 // 8 working hours per day
 	overtimeAmount := overtimeHours * hourlyRate * 2
 
-	// Get reimbursements
-	reimbursements, _ := s.repos.Reimbursement.GetByUserAndPeriod(user.ID, period.ID)
 	var reimbursementAmount float64
 	for _, r := range reimbursements {
 		reimbursementAmount += r.Amount
@@ -99,6 +148,28 @@ func (s *payrollService) calculatePayslip(user *models.User, period *models.Atte
 	// Calculate total
 	totalAmount := attendanceAmount + overtimeAmount + reimbursementAmount
 
+	var taxAmount float64
+	var contributions *models.BPJSContributions
+	if s.tax.Withhold {
+		// BPJS is due on the contractual wage; reimbursements are not income
+		bpjs := tax.Contributions(baseSalary, s.tax.BPJS)
+		gross := attendanceAmount + overtimeAmount + bpjs.TaxableBenefits()
+		hasTaxID := user.NPWP != "" || user.NIK != ""
+		if period.StartDate.Month() == time.December {
+			annualGross, pension, withheld := gross, bpjs.PensionContributions(), 0.0
+			for _, item := range yearToDate {
+				annualGross += item.AttendanceAmount + item.OvertimeAmount + item.BPJS.TaxableBenefits()
+				pension += item.BPJS.PensionContributions()
+				withheld += item.TaxAmount
+			}
+			taxAmount = tax.YearEndWithholding(annualGross, pension, len(yearToDate)+1, user.PTKPStatus, hasTaxID, withheld)
+		} else {
+			taxAmount = tax.MonthlyWithholding(gross, user.PTKPStatus, hasTaxID)
+		}
+		totalAmount -= taxAmount + bpjs.EmployeeTotal()
+		contributions = &bpjs
+	}
+
 	return &domains.PayslipResponse{
 		Employee:            user,
 		Period:              period,
@@ -111,7 +182,38 @@ func (s *payrollService) calculatePayslip(user *models.User, period *models.Atte
 		Reimbursements:      reimbursements,
 		ReimbursementAmount: reimbursementAmount,
 		TotalAmount:         totalAmount,
-	}, nil
+		TaxAmount:           taxAmount,
+		BPJS:                contributions,
+	}
+}
+
+func approvedOvertimes(overtimes []models.Overtime) []models.Overtime {
+	var approved []models.Overtime
+	for _, ot := range overtimes {
+		if ot.IsApproved() {
+			approved = append(approved, ot)
+		}
+	}
+	return approved
+}
+
+func approvedReimbursements(reimbursements []models.Reimbursement) []models.Reimbursement {
+	var approved []models.Reimbursement
+	for _, r := range reimbursements {
+		if r.IsApproved() {
+			approved = append(approved, r)
+		}
+	}
+	return approved
+}
+
+// itemContributions returns the BPJS contributions of a payroll item, or nil when none were withheld
+func itemContributions(item *models.PayrollItem) *models.BPJSContributions {
+	if item.BPJS == (models.BPJSContributions{}) {
+		return nil
+	}
+	bpjs := item.BPJS
+	return &bpjs
 }
 
 func (s *payrollService) GeneratePayrollSummary(periodID uuid.UUID) (*domains.PayrollSummaryResponse, error) {
@@ -144,7 +246,7 @@ func (s *payrollService) GeneratePayrollSummary(periodID uuid.UUID) (*domains.Pa
 			totalAmount += item.TotalAmount
 		} else {
 			// Calculate live
-			payslip, err := s.calculatePayslip(&employee, period)
+			payslip, err := s.calculatePayslip(s.repos, &employee, period)
 			if err != nil {
 				continue
 			}
@@ -156,38 +258,59 @@ func (s *payrollService) GeneratePayrollSummary(periodID uuid.UUID) (*domains.Pa
 		}
 	}
 
+	// Only an approved run is actual payroll; before that the figures can still change
+	provisional := true
+	if period.IsProcessed {
+		if payroll, err := s.repos.Payroll.GetRunByPeriodID(periodID); err == nil && payroll.IsFinal() {
+			provisional = false
+		}
+	}
+
 	return &domains.PayrollSummaryResponse{
 		Period:      period,
 		Employees:   employeeSummaries,
 		TotalAmount: totalAmount,
+		Provisional: provisional,
 	}, nil
 }
 
-func (s *payrollService) ProcessPayroll(periodID, adminID uuid.UUID, ipAddress, requestID string) error {
-	// Get period
-	period, err := s.repos.AttendancePeriod.GetByID(periodID)
+func (s *payrollService) ProcessPayroll(periodID uuid.UUID, actor domains.Actor, ipAddress, requestID string) error {
+	if actor.IsAPIKey() {
+		return domains.ErrAPIKeyActor
+	}
+	adminID := *actor.UserID
+
+	// Start transaction
+	tx := s.repos.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Everything the run reads goes through the transaction, so it sees the period as locked below
+	txRepos := repository.NewRepositories(tx)
+
+	// Lock the period row for the whole run: concurrent runs wait here and then see it processed,
+	// and employee submissions into the period are rejected until we commit
+	period, err := txRepos.AttendancePeriod.GetByIDForUpdate(periodID)
 	if err != nil {
+		tx.Rollback()
 		return fmt.Errorf("period not found: %w", err)
 	}
 
 	if period.IsProcessed {
+		tx.Rollback()
 		return errors.New("payroll already processed for this period")
 	}
 
 	// Get all employees
-	employees, err := s.repos.User.GetAllEmployees()
+	employees, err := txRepos.User.GetAllEmployees()
 	if err != nil {
+		tx.Rollback()
 		return fmt.Errorf("failed to get employees: %w", err)
 	}
 
-	// Start transaction
-	tx := s.repos.DB.Begin()
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
-
 	// Create payroll record
 	now := time.Now()
 	payroll := &models.Payroll{
@@ -198,6 +321,8 @@ func (s *payrollService) ProcessPayroll(periodID, adminID uuid.UUID, ipAddress,
 		},
 		AttendancePeriodID: periodID,
 		ProcessedBy:        adminID,
+		Status:             models.PayrollStatusPendingApproval,
+		RequiredApprovals:  s.cfg.RequiredApprovals,
 	}
 
 	if err := tx.Create(payroll).Error; err != nil {
@@ -213,9 +338,11 @@ func (s *payrollService) ProcessPayroll(periodID, adminID uuid.UUID, ipAddress,
 			continue
 		}
 
-		payslip, err := s.calculatePayslip(&employee, period)
+		// A payslip that cannot be computed fails the whole run rather than leaving the employee unpaid
+		payslip, err := s.calculatePayslip(txRepos, &employee, period)
 		if err != nil {
-			continue
+			tx.Rollback()
+			return fmt.Errorf("failed to calculate payslip for %s: %w", employee.Username, err)
 		}
 
 		// Create payroll item
@@ -235,6 +362,10 @@ func (s *payrollService) ProcessPayroll(periodID, adminID uuid.UUID, ipAddress,
 			OvertimeAmount:      payslip.OvertimeAmount,
 			ReimbursementAmount: payslip.ReimbursementAmount,
 			TotalAmount:         payslip.TotalAmount,
+			TaxAmount:           payslip.TaxAmount,
+		}
+		if payslip.BPJS != nil {
+			item.BPJS = *payslip.BPJS
 		}
 
 		if err := tx.Create(item).Error; err != nil {
@@ -270,8 +401,340 @@ func (s *payrollService) ProcessPayroll(periodID, adminID uuid.UUID, ipAddress,
 	}
 
 	// Create audit logs
-	createAuditLog("payrolls", payroll.ID, "INSERT", nil, payroll, &adminID, ipAddress, requestID, s.repos)
-	createAuditLog("attendance_periods", period.ID, "UPDATE", nil, period, &adminID, ipAddress, requestID, s.repos)
+	createAuditLog("payrolls", payroll.ID, "INSERT", nil, payroll, actor, ipAddress, requestID, s.repos)
+	createAuditLog("attendance_periods", period.ID, "UPDATE", nil, period, actor, ipAddress, requestID, s.repos)
+
+	return nil
+}
+
+// GetPayrollRun returns the current payroll run for a period along with its approvals
+func (s *payrollService) GetPayrollRun(periodID uuid.UUID) (*models.Payroll, error) {
+	payroll, err := s.repos.Payroll.GetRunByPeriodID(periodID)
+	if err != nil {
+		return nil, fmt.Errorf("payroll not found: %w", err)
+	}
+	return payroll, nil
+}
+
+// ApprovePayroll records a checker's approval. The run becomes final once it has
+// collected the required number of approvals from admins other than its preparer.
+func (s *payrollService) ApprovePayroll(periodID uuid.UUID, actor domains.Actor, comment, ipAddress, requestID string) (*models.Payroll, error) {
+	if actor.IsAPIKey() {
+		return nil, domains.ErrAPIKeyActor
+	}
+	adminID := *actor.UserID
+
+	tx := s.repos.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Lock the run until we commit: concurrent approvals wait here and then see the approvals before them
+	payrolls := repository.NewPayrollRepository(tx)
+	payroll, err := payrolls.GetRunByPeriodIDForUpdate(periodID)
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("payroll not found: %w", err)
+	}
+
+	if payroll.Status != models.PayrollStatusPendingApproval {
+		tx.Rollback()
+		return nil, fmt.Errorf("payroll is %s, not awaiting approval", payroll.Status)
+	}
+
+	if payroll.ProcessedBy == adminID {
+		tx.Rollback()
+		return nil, errors.New("payroll must be approved by a different admin than the one who prepared it")
+	}
+
+	approvals, err := payrolls.GetApprovals(payroll.ID)
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to get approvals: %w", err)
+	}
+
+	for _, approval := range approvals {
+		if approval.ApproverID == adminID {
+			tx.Rollback()
+			return nil, errors.New("payroll already approved by this admin")
+		}
+	}
+
+	approval := &models.PayrollApproval{
+		BaseModel: models.BaseModel{
+			CreatedBy: &adminID,
+			IPAddress: ipAddress,
+			RequestID: requestID,
+		},
+		PayrollID:  payroll.ID,
+		ApproverID: adminID,
+		Decision:   models.ApprovalDecisionApproved,
+		Comment:    comment,
+	}
+
+	if err := payrolls.CreateApproval(approval); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to record approval: %w", err)
+	}
+
+	approvals = append(approvals, *approval)
+	payroll.Approvals = approvals
+
+	// Finalize the run once enough approvals are collected
+	oldPayroll := *payroll
+	finalized := len(approvals) >= payroll.RequiredApprovals
+	if finalized {
+		now := time.Now()
+		payroll.Status = models.PayrollStatusApproved
+		payroll.FinalizedAt = &now
+		payroll.UpdatedBy = &adminID
+		payroll.IPAddress = ipAddress
+		payroll.RequestID = requestID
+
+		if err := payrolls.Update(payroll); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to finalize payroll: %w", err)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	createAuditLog("payroll_approvals", approval.ID, "INSERT", nil, approval, actor, ipAddress, requestID, s.repos)
+	if finalized {
+		createAuditLog("payrolls", payroll.ID, "UPDATE", oldPayroll, payroll, actor, ipAddress, requestID, s.repos)
+	}
+
+	return payroll, nil
+}
+
+// RejectPayroll discards a pending payroll run and reopens its period so it can be corrected and prepared again
+func (s *payrollService) RejectPayroll(periodID uuid.UUID, actor domains.Actor, reason, ipAddress, requestID string) error {
+	if actor.IsAPIKey() {
+		return domains.ErrAPIKeyActor
+	}
+	adminID := *actor.UserID
+
+	if reason == "" {
+		return errors.New("rejection reason is required")
+	}
+
+	payroll, err := s.repos.Payroll.GetRunByPeriodID(periodID)
+	if err != nil {
+		return fmt.Errorf("payroll not found: %w", err)
+	}
+
+	if payroll.Status != models.PayrollStatusPendingApproval {
+		return fmt.Errorf("payroll is %s, not awaiting approval", payroll.Status)
+	}
+
+	approval := &models.PayrollApproval{
+		BaseModel: models.BaseModel{
+			CreatedBy: &adminID,
+			IPAddress: ipAddress,
+			RequestID: requestID,
+		},
+		PayrollID:  payroll.ID,
+		ApproverID: adminID,
+		Decision:   models.ApprovalDecisionRejected,
+		Comment:    reason,
+	}
+
+	tx := s.repos.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	period, err := repository.NewAttendancePeriodRepository(tx).GetByIDForUpdate(periodID)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("period not found: %w", err)
+	}
+
+	// Replace any earlier approval by the same admin with the rejection
+	if err := tx.Where("payroll_id = ? AND approver_id = ?", payroll.ID, adminID).Delete(&models.PayrollApproval{}).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to record rejection: %w", err)
+	}
+
+	if err := tx.Create(approval).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to record rejection: %w", err)
+	}
+
+	payroll.Status = models.PayrollStatusRejected
+	payroll.UpdatedBy = &adminID
+	payroll.IPAddress = ipAddress
+	payroll.RequestID = requestID
+	if err := tx.Omit("PayrollItems", "Approvals").Save(payroll).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to reject payroll: %w", err)
+	}
+
+	// Reopen the period
+	period.IsProcessed = false
+	period.ProcessedAt = nil
+	period.UpdatedBy = &adminID
+	period.IPAddress = ipAddress
+	period.RequestID = requestID
+	if err := tx.Save(period).Error; err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to update period: %w", err)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	createAuditLog("payroll_approvals", approval.ID, "INSERT", nil, approval, actor, ipAddress, requestID, s.repos)
+	createAuditLog("payrolls", payroll.ID, "UPDATE", nil, payroll, actor, ipAddress, requestID, s.repos)
+	createAuditLog("attendance_periods", period.ID, "UPDATE", nil, period, actor, ipAddress, requestID, s.repos)
 
 	return nil
 }
+
+// PreviewPayroll computes every payroll item for a period exactly as ProcessPayroll
+// would, without persisting anything, and reports validation issues per employee.
+func (s *payrollService) PreviewPayroll(periodID uuid.UUID) (*domains.PayrollPreviewResponse, error) {
+	// Get period
+	period, err := s.repos.AttendancePeriod.GetByID(periodID)
+	if err != nil {
+		return nil, fmt.Errorf("period not found: %w", err)
+	}
+
+	if period.IsProcessed {
+		return nil, errors.New("payroll already processed for this period")
+	}
+
+	// Get all employees
+	employees, err := s.repos.User.GetAllEmployees()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get employees: %w", err)
+	}
+
+	preview := &domains.PayrollPreviewResponse{
+		Period: period,
+		Items:  []domains.PayrollPreviewItem{},
+		Issues: []domains.PayrollValidationIssue{},
+	}
+
+	for i := range employees {
+		employee := &employees[i]
+
+		// ProcessPayroll skips these employees, so surface them instead
+		if employee.Salary == nil {
+			preview.Issues = append(preview.Issues, newValidationIssue(employee, domains.IssueMissingSalary, domains.SeverityError,
+				"salary not set, employee will be skipped"))
+			continue
+		}
+
+		attendances, _ := s.repos.Attendance.GetByUserAndPeriod(employee.ID, period.ID)
+		overtimes, _ := s.repos.Overtime.GetByUserAndPeriod(employee.ID, period.ID)
+		reimbursements, _ := s.repos.Reimbursement.GetByUserAndPeriod(employee.ID, period.ID)
+		yearToDate, err := s.yearToDate(s.repos, employee, period)
+		if err != nil {
+			return nil, err
+		}
+
+		payslip := s.computePayslip(employee, period, attendances, overtimes, reimbursements, yearToDate)
+		preview.Issues = append(preview.Issues, s.validatePayslip(employee, payslip, attendances, overtimes, reimbursements)...)
+
+		var bpjsAmount float64
+		if payslip.BPJS != nil {
+			bpjsAmount = payslip.BPJS.EmployeeTotal()
+		}
+
+		preview.Items = append(preview.Items, domains.PayrollPreviewItem{
+			Employee:            employee,
+			BaseSalary:          payslip.BaseSalary,
+			AttendanceDays:      payslip.AttendanceDays,
+			WorkingDays:         payslip.WorkingDays,
+			AttendanceAmount:    payslip.AttendanceAmount,
+			OvertimeHours:       payslip.OvertimeHours,
+			OvertimeAmount:      payslip.OvertimeAmount,
+			ReimbursementAmount: payslip.ReimbursementAmount,
+			TaxAmount:           payslip.TaxAmount,
+			BPJSAmount:          bpjsAmount,
+			TotalAmount:         payslip.TotalAmount,
+		})
+		preview.TotalAmount += payslip.TotalAmount
+	}
+
+	preview.EmployeeCount = len(preview.Items)
+	preview.Valid = true
+	for _, issue := range preview.Issues {
+		if issue.Severity == domains.SeverityError {
+			preview.Valid = false
+			break
+		}
+	}
+
+	return preview, nil
+}
+
+func (s *payrollService) validatePayslip(employee *models.User, payslip *domains.PayslipResponse, attendances []models.Attendance, overtimes []models.Overtime, reimbursements []models.Reimbursement) []domains.PayrollValidationIssue {
+	var issues []domains.PayrollValidationIssue
+
+	if payslip.AttendanceDays == 0 {
+		issues = append(issues, newValidationIssue(employee, domains.IssueZeroAttendance, domains.SeverityWarning,
+			"no attendance recorded in period"))
+	}
+
+	attended := make(map[string]bool, len(attendances))
+	for _, a := range attendances {
+		attended[a.Date.Format("2006-01-02")] = true
+	}
+	for _, ot := range overtimes {
+		date := ot.Date.Format("2006-01-02")
+		if ot.Status == models.ApprovalStatusPending {
+			issues = append(issues, newValidationIssue(employee, domains.IssuePendingApproval, domains.SeverityWarning,
+				fmt.Sprintf("%.1f overtime hours on %s await the line manager's approval and will not be paid", ot.Hours, date)))
+			continue
+		}
+		if ot.IsApproved() && !attended[date] {
+			issues = append(issues, newValidationIssue(employee, domains.IssueOvertimeWithoutAttendance, domains.SeverityWarning,
+				fmt.Sprintf("%.1f overtime hours on %s without attendance", ot.Hours, date)))
+		}
+	}
+
+	for _, r := range reimbursements {
+		if r.Status == models.ApprovalStatusPending {
+			issues = append(issues, newValidationIssue(employee, domains.IssuePendingApproval, domains.SeverityWarning,
+				fmt.Sprintf("reimbursement %q of %.2f awaits the line manager's approval and will not be paid", r.Description, r.Amount)))
+		}
+	}
+
+	for _, r := range payslip.Reimbursements {
+		if s.cfg.LargeReimbursementThreshold > 0 && r.Amount > s.cfg.LargeReimbursementThreshold {
+			issues = append(issues, newValidationIssue(employee, domains.IssueLargeReimbursement, domains.SeverityWarning,
+				fmt.Sprintf("reimbursement %q of %.2f exceeds threshold %.2f", r.Description, r.Amount, s.cfg.LargeReimbursementThreshold)))
+		}
+	}
+
+	if s.tax.Withhold && employee.NPWP == "" && employee.NIK == "" {
+		issues = append(issues, newValidationIssue(employee, domains.IssueMissingTaxID, domains.SeverityWarning,
+			"no NPWP or NIK recorded, PPh 21 is withheld with the 20% surcharge"))
+	}
+
+	if payslip.TotalAmount < 0 {
+		issues = append(issues, newValidationIssue(employee, domains.IssueNegativeNetPay, domains.SeverityError,
+			fmt.Sprintf("net pay is negative: %.2f", payslip.TotalAmount)))
+	}
+
+	return issues
+}
+
+func newValidationIssue(employee *models.User, code, severity, message string) domains.PayrollValidationIssue {
+	return domains.PayrollValidationIssue{
+		EmployeeID: employee.ID,
+		Username:   employee.Username,
+		Code:       code,
+		Severity:   severity,
+		Message:    message,
+	}
+}
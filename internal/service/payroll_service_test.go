@@ -0,0 +1,231 @@
+package service
+
+import (
+	"payslip-system/internal/config"
+	"payslip-system/internal/domains"
+	"payslip-system/internal/models"
+	"payslip-system/internal/repository"
+	mock_repository "payslip-system/internal/repository/mocks"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_payrollService_PreviewPayroll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	salary := 6000000.0
+	periodID := uuid.New()
+	day := time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)
+	approved := models.Approval{Status: models.ApprovalStatusApproved}
+	pending := models.Approval{Status: models.ApprovalStatusPending}
+
+	tests := []struct {
+		name           string
+		period         *models.AttendancePeriod
+		employee       models.User
+		attendances    []models.Attendance
+		overtimes      []models.Overtime
+		reimbursements []models.Reimbursement
+		wantItems      int
+		wantCodes      []string
+		wantValid      bool
+		wantErr        bool
+	}{
+		{
+			name:        "clean employee",
+			period:      &models.AttendancePeriod{BaseModel: models.BaseModel{ID: periodID}},
+			employee:    models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "employee1", Salary: &salary},
+			attendances: []models.Attendance{{Date: day}},
+			overtimes:   []models.Overtime{{Date: day, Hours: 2, Approval: approved}},
+			wantItems:   1,
+			wantCodes:   nil,
+			wantValid:   true,
+		},
+		{
+			name:      "missing salary is reported and skipped",
+			period:    &models.AttendancePeriod{BaseModel: models.BaseModel{ID: periodID}},
+			employee:  models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "employee2"},
+			wantItems: 0,
+			wantCodes: []string{domains.IssueMissingSalary},
+			wantValid: false,
+		},
+		{
+			name:           "warnings for attendance, overtime and reimbursement",
+			period:         &models.AttendancePeriod{BaseModel: models.BaseModel{ID: periodID}},
+			employee:       models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "employee3", Salary: &salary},
+			overtimes:      []models.Overtime{{Date: day, Hours: 1, Approval: approved}},
+			reimbursements: []models.Reimbursement{{Amount: 20000000, Description: "Laptop", Approval: approved}},
+			wantItems:      1,
+			wantCodes: []string{
+				domains.IssueZeroAttendance,
+				domains.IssueOvertimeWithoutAttendance,
+				domains.IssueLargeReimbursement,
+			},
+			wantValid: true,
+		},
+		{
+			name:           "pending requests are reported and not paid",
+			period:         &models.AttendancePeriod{BaseModel: models.BaseModel{ID: periodID}},
+			employee:       models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "employee4", Salary: &salary},
+			attendances:    []models.Attendance{{Date: day}},
+			overtimes:      []models.Overtime{{Date: day.AddDate(0, 0, 1), Hours: 3, Approval: pending}},
+			reimbursements: []models.Reimbursement{{Amount: 20000000, Description: "Laptop", Approval: pending}},
+			wantItems:      1,
+			wantCodes:      []string{domains.IssuePendingApproval, domains.IssuePendingApproval},
+			wantValid:      true,
+		},
+		{
+			name:    "error - period already processed",
+			period:  &models.AttendancePeriod{BaseModel: models.BaseModel{ID: periodID}, IsProcessed: true},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+			mockAttendancePeriodRepo := mock_repository.NewMockIAttendancePeriodRepository(ctrl)
+			mockAttendanceRepo := mock_repository.NewMockIAttendanceRepository(ctrl)
+			mockOvertimeRepo := mock_repository.NewMockIOvertimeRepository(ctrl)
+			mockReimbursementRepo := mock_repository.NewMockIReimbursementRepository(ctrl)
+
+			mockAttendancePeriodRepo.EXPECT().GetByID(periodID).Return(tt.period, nil)
+			mockUserRepo.EXPECT().GetAllEmployees().Return([]models.User{tt.employee}, nil).AnyTimes()
+			mockAttendanceRepo.EXPECT().GetByUserAndPeriod(tt.employee.ID, periodID).Return(tt.attendances, nil).AnyTimes()
+			mockAttendanceRepo.EXPECT().CountWorkingDaysInPeriod(gomock.Any(), gomock.Any()).Return(20).AnyTimes()
+			mockOvertimeRepo.EXPECT().GetByUserAndPeriod(tt.employee.ID, periodID).Return(tt.overtimes, nil).AnyTimes()
+			mockReimbursementRepo.EXPECT().GetByUserAndPeriod(tt.employee.ID, periodID).Return(tt.reimbursements, nil).AnyTimes()
+
+			repos := &repository.Repositories{
+				User:             mockUserRepo,
+				AttendancePeriod: mockAttendancePeriodRepo,
+				Attendance:       mockAttendanceRepo,
+				Overtime:         mockOvertimeRepo,
+				Reimbursement:    mockReimbursementRepo,
+			}
+
+			s := NewPayrollService(repos, config.PayrollConfig{LargeReimbursementThreshold: 10000000}, config.TaxConfig{})
+			got, err := s.PreviewPayroll(periodID)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, got)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Len(t, got.Items, tt.wantItems)
+			assert.Equal(t, tt.wantItems, got.EmployeeCount)
+			assert.Equal(t, tt.wantValid, got.Valid)
+
+			var codes []string
+			for _, issue := range got.Issues {
+				codes = append(codes, issue.Code)
+			}
+			assert.Equal(t, tt.wantCodes, codes)
+		})
+	}
+}
+
+func Test_payrollService_computePayslip(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	salary := 12000000.0
+	period := &models.AttendancePeriod{BaseModel: models.BaseModel{ID: uuid.New()}, StartDate: time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC)}
+	december := &models.AttendancePeriod{BaseModel: models.BaseModel{ID: uuid.New()}, StartDate: time.Date(2024, time.December, 1, 0, 0, 0, 0, time.UTC)}
+	attendances := make([]models.Attendance, 20)
+
+	taxCfg := config.TaxConfig{
+		Withhold: true,
+		BPJS: config.BPJSConfig{
+			JHTEmployee: 2, JHTEmployer: 3.7, JPEmployee: 1, JPEmployer: 2, JKKEmployer: 0.24, JKMEmployer: 0.3,
+			HealthEmployee: 1, HealthEmployer: 4, JPWageCap: 10547400, HealthWageCap: 12000000,
+		},
+	}
+
+	// January to November, each paid as August
+	var yearToDate []models.PayrollItem
+	for i := 0; i < 11; i++ {
+		yearToDate = append(yearToDate, models.PayrollItem{
+			AttendanceAmount: 8000000,
+			TaxAmount:        85448,
+			BPJS:             models.BPJSContributions{JHTEmployee: 240000, JPEmployee: 105474, JKKEmployer: 28800, JKMEmployer: 36000, HealthEmployer: 480000},
+		})
+	}
+
+	tests := []struct {
+		name       string
+		taxCfg     config.TaxConfig
+		period     *models.AttendancePeriod
+		yearToDate []models.PayrollItem
+		employee   *models.User
+		wantTax    float64
+		wantBPJS   float64
+		wantTotal  float64
+	}{
+		{
+			name:      "success - no withholding",
+			employee:  &models.User{Salary: &salary, NPWP: "012345678901000", PTKPStatus: "K/1"},
+			wantTotal: 8500000,
+		},
+		{
+			name:      "success - PPh 21 at the TER rate and BPJS withheld",
+			taxCfg:    taxCfg,
+			employee:  &models.User{Salary: &salary, NPWP: "012345678901000", PTKPStatus: "K/1"},
+			wantTax:   85448, // 1% of 8,544,800 in category B
+			wantBPJS:  465474,
+			wantTotal: 7949078,
+		},
+		{
+			name:      "success - surcharge without tax ID",
+			taxCfg:    taxCfg,
+			employee:  &models.User{Salary: &salary, PTKPStatus: "K/1"},
+			wantTax:   102538,
+			wantBPJS:  465474,
+			wantTotal: 7931988,
+		},
+		{
+			name:       "success - December settles the year",
+			taxCfg:     taxCfg,
+			period:     december,
+			yearToDate: yearToDate,
+			employee:   &models.User{Salary: &salary, NPWP: "012345678901000", PTKPStatus: "K/1"},
+			wantTax:    573322, // 1,513,250 due on the year less 939,928 withheld
+			wantBPJS:   465474,
+			wantTotal:  7461204,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAttendanceRepo := mock_repository.NewMockIAttendanceRepository(ctrl)
+			mockAttendanceRepo.EXPECT().CountWorkingDaysInPeriod(gomock.Any(), gomock.Any()).Return(20)
+
+			repos := &repository.Repositories{Attendance: mockAttendanceRepo}
+			s := NewPayrollService(repos, config.PayrollConfig{}, tt.taxCfg)
+
+			approved := models.Approval{Status: models.ApprovalStatusApproved}
+			reimbursements := []models.Reimbursement{
+				{Amount: 500000, Description: "Taxi", Approval: approved},
+				{Amount: 750000, Description: "Hotel", Approval: models.Approval{Status: models.ApprovalStatusRejected}},
+			}
+			if tt.period == nil {
+				tt.period = period
+			}
+			got := s.computePayslip(tt.employee, tt.period, attendances, nil, reimbursements, tt.yearToDate)
+
+			assert.Equal(t, tt.wantTax, got.TaxAmount)
+			if tt.wantBPJS == 0 {
+				assert.Nil(t, got.BPJS)
+			} else {
+				assert.Equal(t, tt.wantBPJS, got.BPJS.EmployeeTotal())
+			}
+			assert.InDelta(t, tt.wantTotal, got.TotalAmount, 0.001)
+		})
+	}
+}
@@ -0,0 +1,350 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	netmail "net/mail"
+	"time"
+
+	"payslip-system/internal/config"
+	"payslip-system/internal/domains"
+	"payslip-system/internal/mail"
+	"payslip-system/internal/models"
+	"payslip-system/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// maxRetryDoublings caps the exponential retry backoff
+const maxRetryDoublings = 10
+
+var errNoEmailAddress = errors.New("employee has no email address")
+
+type payslipMailService struct {
+	repos     *repository.Repositories
+	cfg       config.MailConfig
+	company   string
+	payroll   domains.IPayrollService
+	payslips  domains.IPayslipService
+	passwords domains.IPasswordService
+	languages domains.ILanguageService
+	sender    mail.Sender
+}
+
+func NewPayslipMailService(repos *repository.Repositories, cfg config.MailConfig, company string, payroll domains.IPayrollService, payslips domains.IPayslipService, passwords domains.IPasswordService, languages domains.ILanguageService, sender mail.Sender) *payslipMailService {
+	return &payslipMailService{
+		repos:     repos,
+		cfg:       cfg,
+		company:   company,
+		payroll:   payroll,
+		payslips:  payslips,
+		passwords: passwords,
+		languages: languages,
+		sender:    sender,
+	}
+}
+
+// DistributePayslips queues a payslip email to every employee of a final payroll run. Employees who
+// already have a delivery for the run are skipped, so distributing again only picks up the missing ones.
+func (s *payslipMailService) DistributePayslips(periodID uuid.UUID, actor domains.Actor, ipAddress, requestID string) (*domains.PayslipDistributionReport, error) {
+	payroll, err := s.repos.Payroll.GetRunByPeriodID(periodID)
+	if err != nil {
+		return nil, fmt.Errorf("payroll not found: %w", err)
+	}
+
+	if !payroll.IsFinal() {
+		return nil, errors.New("payslips can only be emailed once the payroll is final")
+	}
+
+	items, err := s.repos.Payroll.GetAllPayrollItemsByPeriod(periodID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payroll items: %w", err)
+	}
+
+	queued := 0
+	now := time.Now()
+	for _, item := range items {
+		delivery := &models.EmailDelivery{
+			BaseModel: models.BaseModel{
+				CreatedBy: actor.UserID,
+				IPAddress: ipAddress,
+				RequestID: requestID,
+			},
+			PayrollID:          &payroll.ID,
+			AttendancePeriodID: &periodID,
+			UserID:             item.UserID,
+			Template:           mail.TemplatePayslip,
+			Recipient:          item.User.Email,
+			Status:             models.EmailStatusQueued,
+			NextAttemptAt:      now,
+		}
+		// Recorded as failed so admins see who is missing; retrying picks up an address added later
+		if delivery.Recipient == "" {
+			delivery.Status = models.EmailStatusFailed
+			delivery.LastError = errNoEmailAddress.Error()
+		}
+
+		created, err := s.repos.EmailDelivery.CreateIfAbsent(delivery)
+		if err != nil {
+			return nil, fmt.Errorf("failed to queue payslip emails: %w", err)
+		}
+		if created && delivery.Status == models.EmailStatusQueued {
+			queued++
+		}
+	}
+
+	if queued > 0 {
+		createAuditLog("email_deliveries", payroll.ID, "INSERT", nil, map[string]interface{}{"payroll_id": payroll.ID, "queued": queued}, actor, ipAddress, requestID, s.repos)
+	}
+
+	report, err := s.distributionReport(payroll)
+	if err != nil {
+		return nil, err
+	}
+	report.Queued = queued
+	return report, nil
+}
+
+// PayrollFinalized queues the payslip emails of a newly finalized run when auto distribution is on.
+// Failures are logged rather than returned; admins can still distribute manually.
+func (s *payslipMailService) PayrollFinalized(periodID uuid.UUID, actor domains.Actor, ipAddress, requestID string) {
+	if !s.cfg.AutoDistribute {
+		return
+	}
+
+	if _, err := s.DistributePayslips(periodID, actor, ipAddress, requestID); err != nil {
+		logrus.WithError(err).WithField("period_id", periodID).Error("Failed to queue payslip emails")
+	}
+}
+
+// GetDistribution returns the delivery status of every payslip email of the period's payroll run
+func (s *payslipMailService) GetDistribution(periodID uuid.UUID) (*domains.PayslipDistributionReport, error) {
+	payroll, err := s.repos.Payroll.GetRunByPeriodID(periodID)
+	if err != nil {
+		return nil, fmt.Errorf("payroll not found: %w", err)
+	}
+	return s.distributionReport(payroll)
+}
+
+// RetryDelivery queues a failed delivery again with a fresh set of attempts, sending to the employee's current address
+func (s *payslipMailService) RetryDelivery(deliveryID uuid.UUID, actor domains.Actor, ipAddress, requestID string) (*models.EmailDelivery, error) {
+	delivery, err := s.repos.EmailDelivery.GetByID(deliveryID)
+	if err != nil {
+		return nil, errors.New("delivery not found")
+	}
+
+	if delivery.Status != models.EmailStatusFailed {
+		return nil, fmt.Errorf("delivery is %s, only failed deliveries can be retried", delivery.Status)
+	}
+
+	if delivery.User.Email == "" {
+		return nil, errNoEmailAddress
+	}
+
+	oldDelivery := *delivery
+	delivery.Recipient = delivery.User.Email
+	delivery.Status = models.EmailStatusQueued
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Now()
+	delivery.LastError = ""
+	delivery.UpdatedBy = actor.UserID
+	delivery.IPAddress = ipAddress
+	delivery.RequestID = requestID
+
+	if err := s.repos.EmailDelivery.Update(delivery); err != nil {
+		return nil, fmt.Errorf("failed to queue delivery: %w", err)
+	}
+
+	createAuditLog("email_deliveries", delivery.ID, "UPDATE", oldDelivery, delivery, actor, ipAddress, requestID, s.repos)
+
+	return delivery, nil
+}
+
+// SetEmail sets the address an employee's payslips are emailed to
+func (s *payslipMailService) SetEmail(userID uuid.UUID, email, ipAddress, requestID string) error {
+	address, err := netmail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return errors.New("invalid email address")
+	}
+
+	user, err := s.repos.User.GetByID(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	oldEmail := user.Email
+	user.Email = email
+	if err := s.repos.User.Update(user); err != nil {
+		return err
+	}
+
+	createAuditLog("users", user.ID, "UPDATE", map[string]string{"email": oldEmail}, map[string]string{"email": email}, domains.UserActor(userID), ipAddress, requestID, s.repos)
+
+	return nil
+}
+
+// ProcessQueue sends one batch of due deliveries and returns how many it attempted. Temporary failures are
+// retried with exponential backoff until max_attempts; rejections by the mail server fail right away.
+func (s *payslipMailService) ProcessQueue() (int, error) {
+	// The claim has to outlast sending the whole batch, or another worker would pick it up again
+	lease := time.Duration(s.cfg.BatchSize*s.cfg.Timeout)*time.Second + time.Minute
+
+	deliveries, err := s.repos.EmailDelivery.ClaimDue(time.Now(), s.cfg.BatchSize, lease)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim deliveries: %w", err)
+	}
+
+	for i := range deliveries {
+		s.deliver(&deliveries[i])
+	}
+	return len(deliveries), nil
+}
+
+// Run processes the queue every poll interval until ctx is done
+func (s *payslipMailService) Run(ctx context.Context) {
+	if !s.cfg.Enabled {
+		logrus.Info("Mail delivery is disabled, payslip emails stay queued")
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(s.cfg.PollInterval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.ProcessQueue(); err != nil {
+			logrus.WithError(err).Error("Failed to process mail queue")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliver sends a claimed delivery and records the outcome
+func (s *payslipMailService) deliver(delivery *models.EmailDelivery) {
+	err := s.send(delivery)
+
+	now := time.Now()
+	log := logrus.WithFields(logrus.Fields{"delivery_id": delivery.ID, "attempt": delivery.Attempts})
+	switch {
+	case err == nil:
+		delivery.Status = models.EmailStatusSent
+		delivery.SentAt = &now
+		delivery.LastError = ""
+	case errors.Is(err, errNoEmailAddress) || mail.IsPermanent(err) || delivery.Attempts >= s.cfg.MaxAttempts:
+		delivery.Status = models.EmailStatusFailed
+		delivery.LastError = err.Error()
+		log.WithError(err).Warn("Payslip email failed")
+	default:
+		delivery.Status = models.EmailStatusQueued
+		delivery.NextAttemptAt = now.Add(s.retryDelay(delivery.Attempts))
+		delivery.LastError = err.Error()
+		log.WithError(err).Info("Payslip email will be retried")
+	}
+
+	if err := s.repos.EmailDelivery.Update(delivery); err != nil {
+		log.WithError(err).Error("Failed to record payslip email delivery")
+	}
+}
+
+// send renders the payslip email in the employee's language and hands it to the mail server. Password reset
+// emails are sent by the password service.
+func (s *payslipMailService) send(delivery *models.EmailDelivery) error {
+	if delivery.Recipient == "" {
+		return errNoEmailAddress
+	}
+
+	if delivery.Template == mail.TemplatePasswordReset {
+		return s.passwords.SendResetEmail(delivery)
+	}
+
+	payslip, err := s.payroll.GeneratePayslip(delivery.UserID, *delivery.AttendancePeriodID)
+	if err != nil {
+		return err
+	}
+
+	s.payslips.Localize(payslip, "")
+	localized := payslip.Localized
+	data := mail.PayslipData{
+		Language:     localized.Language,
+		EmployeeName: payslip.Employee.Username,
+		Period:       localized.Period,
+		NetPay:       localized.Amounts["total_amount"],
+		Company:      s.company,
+		PortalURL:    s.cfg.PortalURL,
+	}
+
+	var attachments []mail.Attachment
+	if s.cfg.AttachPDF {
+		document, err := s.payslips.RenderPDF(payslip, "")
+		switch {
+		case errors.Is(err, domains.ErrPayslipPasswordUnavailable):
+			// Without a password the PDF cannot be protected, so the email only points to the portal
+		case err != nil:
+			return err
+		default:
+			attachments = append(attachments, mail.Attachment{
+				Filename:    fmt.Sprintf("payslip-%s-%s.pdf", payslip.Period.StartDate.Format("2006-01"), payslip.Employee.Username),
+				ContentType: "application/pdf",
+				Data:        document,
+			})
+			data.Attached = true
+		}
+	}
+	delivery.Attached = data.Attached
+
+	content, err := mail.Render(delivery.Template, s.languages.Translator(localized.Language), data)
+	if err != nil {
+		return err
+	}
+
+	return s.sender.Send(&mail.Message{
+		From:        netmail.Address{Name: s.cfg.FromName, Address: s.cfg.From},
+		To:          netmail.Address{Name: payslip.Employee.Username, Address: delivery.Recipient},
+		Subject:     content.Subject,
+		Text:        content.Text,
+		HTML:        content.HTML,
+		Attachments: attachments,
+	})
+}
+
+// retryDelay doubles the configured backoff with every failed attempt
+func (s *payslipMailService) retryDelay(attempts int) time.Duration {
+	doublings := attempts - 1
+	if doublings < 0 {
+		doublings = 0
+	}
+	if doublings > maxRetryDoublings {
+		doublings = maxRetryDoublings
+	}
+	return time.Duration(s.cfg.RetryBackoff) * time.Second << doublings
+}
+
+func (s *payslipMailService) distributionReport(payroll *models.Payroll) (*domains.PayslipDistributionReport, error) {
+	deliveries, err := s.repos.EmailDelivery.GetByPayrollID(payroll.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deliveries: %w", err)
+	}
+
+	counts := map[string]int{
+		models.EmailStatusQueued:  0,
+		models.EmailStatusSending: 0,
+		models.EmailStatusSent:    0,
+		models.EmailStatusFailed:  0,
+	}
+	for _, delivery := range deliveries {
+		counts[delivery.Status]++
+	}
+
+	return &domains.PayslipDistributionReport{
+		PayrollID:  payroll.ID,
+		PeriodID:   payroll.AttendancePeriodID,
+		Counts:     counts,
+		Deliveries: deliveries,
+	}, nil
+}
@@ -0,0 +1,292 @@
+package service
+
+import (
+	"errors"
+	"net/textproto"
+	"testing"
+	"time"
+
+	"payslip-system/internal/config"
+	"payslip-system/internal/domains"
+	mock_domains "payslip-system/internal/domains/mocks"
+	"payslip-system/internal/mail"
+	"payslip-system/internal/models"
+	"payslip-system/internal/repository"
+	mock_repository "payslip-system/internal/repository/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeSender struct {
+	err  error
+	sent []*mail.Message
+}
+
+func (f *fakeSender) Send(msg *mail.Message) error {
+	f.sent = append(f.sent, msg)
+	return f.err
+}
+
+func Test_payslipMailService_DistributePayslips(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	periodID := uuid.New()
+	adminID := uuid.New()
+
+	tests := []struct {
+		name          string
+		status        string
+		alreadyQueued bool
+		wantQueued    int
+		wantErr       bool
+	}{
+		{name: "success", status: models.PayrollStatusApproved, wantQueued: 1},
+		{name: "success - already distributed", status: models.PayrollStatusApproved, alreadyQueued: true, wantQueued: 0},
+		{name: "error - payroll awaiting approval", status: models.PayrollStatusPendingApproval, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPayrollRepo := mock_repository.NewMockIPayrollRepository(ctrl)
+			mockDeliveryRepo := mock_repository.NewMockIEmailDeliveryRepository(ctrl)
+			mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+
+			payroll := &models.Payroll{BaseModel: models.BaseModel{ID: uuid.New()}, AttendancePeriodID: periodID, Status: tt.status}
+			mockPayrollRepo.EXPECT().GetRunByPeriodID(periodID).Return(payroll, nil)
+
+			if !tt.wantErr {
+				items := []models.PayrollItem{
+					{UserID: uuid.New(), User: models.User{Username: "employee1", Email: "employee1@example.com"}},
+					{UserID: uuid.New(), User: models.User{Username: "employee2"}},
+				}
+				mockPayrollRepo.EXPECT().GetAllPayrollItemsByPeriod(periodID).Return(items, nil)
+
+				var queued []*models.EmailDelivery
+				mockDeliveryRepo.EXPECT().CreateIfAbsent(gomock.Any()).DoAndReturn(func(delivery *models.EmailDelivery) (bool, error) {
+					queued = append(queued, delivery)
+					return !tt.alreadyQueued, nil
+				}).Times(2)
+				mockDeliveryRepo.EXPECT().GetByPayrollID(payroll.ID).DoAndReturn(func(uuid.UUID) ([]models.EmailDelivery, error) {
+					var deliveries []models.EmailDelivery
+					for _, delivery := range queued {
+						deliveries = append(deliveries, *delivery)
+					}
+					return deliveries, nil
+				})
+				if tt.wantQueued > 0 {
+					mockAuditLogRepo.EXPECT().Create(gomock.Any()).Return(nil)
+				}
+			}
+
+			repos := &repository.Repositories{Payroll: mockPayrollRepo, EmailDelivery: mockDeliveryRepo, AuditLog: mockAuditLogRepo}
+			s := NewPayslipMailService(repos, config.MailConfig{}, "PT Mini Payroll", nil, nil, nil, nil, &fakeSender{})
+
+			report, err := s.DistributePayslips(periodID, domains.UserActor(adminID), "127.0.0.1", "req-123")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantQueued, report.Queued)
+			assert.Equal(t, 1, report.Counts[models.EmailStatusQueued])
+			assert.Equal(t, 1, report.Counts[models.EmailStatusFailed])
+			assert.Equal(t, errNoEmailAddress.Error(), report.Deliveries[1].LastError)
+		})
+	}
+}
+
+func Test_payslipMailService_ProcessQueue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLanguageRepo := mock_repository.NewMockILanguageRepository(ctrl)
+	mockLanguageRepo.EXPECT().GetAll().Return(nil, nil).AnyTimes()
+	languages := NewLanguageService(&repository.Repositories{Language: mockLanguageRepo}, config.I18nConfig{DefaultLanguage: "en"})
+
+	tests := []struct {
+		name          string
+		sendErr       error
+		attempts      int
+		passwordErr   error
+		wantStatus    string
+		wantAttached  bool
+		wantNextRetry time.Duration
+	}{
+		{name: "success", wantStatus: models.EmailStatusSent, wantAttached: true},
+		{name: "success - sent without PDF when no password is available", passwordErr: domains.ErrPayslipPasswordUnavailable, wantStatus: models.EmailStatusSent},
+		{name: "temporary failure is retried with backoff", sendErr: &textproto.Error{Code: 451, Msg: "try again later"}, attempts: 2, wantStatus: models.EmailStatusQueued, wantAttached: true, wantNextRetry: 2 * time.Minute},
+		{name: "rejected by the mail server", sendErr: &textproto.Error{Code: 550, Msg: "no such user"}, attempts: 1, wantStatus: models.EmailStatusFailed, wantAttached: true},
+		{name: "attempts exhausted", sendErr: errors.New("connection refused"), attempts: 3, wantStatus: models.EmailStatusFailed, wantAttached: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDeliveryRepo := mock_repository.NewMockIEmailDeliveryRepository(ctrl)
+			mockPayrollService := mock_domains.NewMockIPayrollService(ctrl)
+			mockPayslipService := mock_domains.NewMockIPayslipService(ctrl)
+
+			attempts := tt.attempts
+			if attempts == 0 {
+				attempts = 1
+			}
+			periodID := uuid.New()
+			delivery := models.EmailDelivery{
+				BaseModel:          models.BaseModel{ID: uuid.New()},
+				AttendancePeriodID: &periodID,
+				UserID:             uuid.New(),
+				Template:           mail.TemplatePayslip,
+				Recipient:          "employee1@example.com",
+				Status:             models.EmailStatusSending,
+				Attempts:           attempts,
+			}
+			mockDeliveryRepo.EXPECT().ClaimDue(gomock.Any(), 20, gomock.Any()).Return([]models.EmailDelivery{delivery}, nil)
+
+			payslip := &domains.PayslipResponse{
+				Employee:    &models.User{Username: "employee1", PreferredLanguage: "id"},
+				Period:      &models.AttendancePeriod{StartDate: time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2024, 8, 31, 0, 0, 0, 0, time.UTC)},
+				TotalAmount: 5000000,
+			}
+			mockPayrollService.EXPECT().GeneratePayslip(delivery.UserID, periodID).Return(payslip, nil)
+			mockPayslipService.EXPECT().Localize(payslip, "").Do(func(payslip *domains.PayslipResponse, _ string) {
+				payslip.Localized = &domains.LocalizedPayslip{
+					Language: "id",
+					Period:   "01 Agustus 2024 - 31 Agustus 2024",
+					Amounts:  map[string]string{"total_amount": "Rp 5.000.000,00"},
+				}
+			})
+			if tt.passwordErr != nil {
+				mockPayslipService.EXPECT().RenderPDF(payslip, "").Return(nil, tt.passwordErr)
+			} else {
+				mockPayslipService.EXPECT().RenderPDF(payslip, "").Return([]byte("%PDF-1.4"), nil)
+			}
+
+			var updated *models.EmailDelivery
+			mockDeliveryRepo.EXPECT().Update(gomock.Any()).DoAndReturn(func(delivery *models.EmailDelivery) error {
+				updated = delivery
+				return nil
+			})
+
+			sender := &fakeSender{err: tt.sendErr}
+			cfg := config.MailConfig{From: "payroll@example.com", AttachPDF: true, MaxAttempts: 3, RetryBackoff: 60, BatchSize: 20, Timeout: 10}
+			repos := &repository.Repositories{EmailDelivery: mockDeliveryRepo}
+			s := NewPayslipMailService(repos, cfg, "PT Mini Payroll", mockPayrollService, mockPayslipService, nil, languages, sender)
+
+			before := time.Now()
+			processed, err := s.ProcessQueue()
+			assert.NoError(t, err)
+			assert.Equal(t, 1, processed)
+
+			assert.Equal(t, tt.wantStatus, updated.Status)
+			assert.Equal(t, tt.wantAttached, updated.Attached)
+			if tt.wantStatus == models.EmailStatusSent {
+				assert.NotNil(t, updated.SentAt)
+				assert.Empty(t, updated.LastError)
+			} else {
+				assert.NotEmpty(t, updated.LastError)
+			}
+			if tt.wantNextRetry > 0 {
+				assert.WithinDuration(t, before.Add(tt.wantNextRetry), updated.NextAttemptAt, time.Second)
+			}
+
+			assert.Len(t, sender.sent, 1)
+			msg := sender.sent[0]
+			assert.Equal(t, "employee1@example.com", msg.To.Address)
+			assert.Equal(t, "Slip gaji Anda untuk 01 Agustus 2024 - 31 Agustus 2024", msg.Subject)
+			assert.Contains(t, msg.Text, "Rp 5.000.000,00")
+			if tt.wantAttached {
+				assert.Len(t, msg.Attachments, 1)
+			} else {
+				assert.Empty(t, msg.Attachments)
+			}
+		})
+	}
+}
+
+func Test_payslipMailService_ProcessQueue_PasswordReset(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockDeliveryRepo := mock_repository.NewMockIEmailDeliveryRepository(ctrl)
+	mockPasswordService := mock_domains.NewMockIPasswordService(ctrl)
+
+	delivery := models.EmailDelivery{
+		BaseModel: models.BaseModel{ID: uuid.New()},
+		UserID:    uuid.New(),
+		Template:  mail.TemplatePasswordReset,
+		Recipient: "employee1@example.com",
+		Status:    models.EmailStatusSending,
+		Attempts:  1,
+	}
+	mockDeliveryRepo.EXPECT().ClaimDue(gomock.Any(), 20, gomock.Any()).Return([]models.EmailDelivery{delivery}, nil)
+	mockPasswordService.EXPECT().SendResetEmail(gomock.Any()).Return(nil)
+	var updated *models.EmailDelivery
+	mockDeliveryRepo.EXPECT().Update(gomock.Any()).DoAndReturn(func(delivery *models.EmailDelivery) error {
+		updated = delivery
+		return nil
+	})
+
+	cfg := config.MailConfig{MaxAttempts: 3, RetryBackoff: 60, BatchSize: 20, Timeout: 10}
+	repos := &repository.Repositories{EmailDelivery: mockDeliveryRepo}
+	s := NewPayslipMailService(repos, cfg, "PT Mini Payroll", nil, nil, mockPasswordService, nil, &fakeSender{})
+
+	processed, err := s.ProcessQueue()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, processed)
+	assert.Equal(t, models.EmailStatusSent, updated.Status)
+}
+
+func Test_payslipMailService_RetryDelivery(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name    string
+		status  string
+		email   string
+		wantErr bool
+	}{
+		{name: "success", status: models.EmailStatusFailed, email: "new@example.com"},
+		{name: "error - not failed", status: models.EmailStatusSent, email: "new@example.com", wantErr: true},
+		{name: "error - still no email address", status: models.EmailStatusFailed, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDeliveryRepo := mock_repository.NewMockIEmailDeliveryRepository(ctrl)
+			mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+
+			delivery := &models.EmailDelivery{
+				BaseModel: models.BaseModel{ID: uuid.New()},
+				Recipient: "old@example.com",
+				Status:    tt.status,
+				Attempts:  5,
+				LastError: "550 no such user",
+				User:      models.User{Email: tt.email},
+			}
+			mockDeliveryRepo.EXPECT().GetByID(delivery.ID).Return(delivery, nil)
+			if !tt.wantErr {
+				mockDeliveryRepo.EXPECT().Update(delivery).Return(nil)
+				mockAuditLogRepo.EXPECT().Create(gomock.Any()).Return(nil)
+			}
+
+			repos := &repository.Repositories{EmailDelivery: mockDeliveryRepo, AuditLog: mockAuditLogRepo}
+			s := NewPayslipMailService(repos, config.MailConfig{}, "PT Mini Payroll", nil, nil, nil, nil, &fakeSender{})
+
+			got, err := s.RetryDelivery(delivery.ID, domains.UserActor(uuid.New()), "127.0.0.1", "req-123")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, models.EmailStatusQueued, got.Status)
+			assert.Equal(t, "new@example.com", got.Recipient)
+			assert.Zero(t, got.Attempts)
+			assert.Empty(t, got.LastError)
+		})
+	}
+}
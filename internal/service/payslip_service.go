@@ -0,0 +1,299 @@
+package service
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+
+	"payslip-system/internal/config"
+	"payslip-system/internal/domains"
+	"payslip-system/internal/i18n"
+	"payslip-system/internal/pdf"
+	"payslip-system/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+var payslipPINPattern = regexp.MustCompile(`^[0-9]{6,12}$`)
+
+// verificationTagSize is the length of the truncated HMAC in verification tokens
+const verificationTagSize = 16
+
+type payslipService struct {
+	repos     *repository.Repositories
+	cfg       config.PayslipConfig
+	languages domains.ILanguageService
+	signer    *pdf.Signer
+	renderer  *pdf.PayslipRenderer
+}
+
+func NewPayslipService(repos *repository.Repositories, cfg config.PayslipConfig, languages domains.ILanguageService) *payslipService {
+	signer := loadPayslipSigner(cfg)
+	return &payslipService{
+		repos:     repos,
+		cfg:       cfg,
+		languages: languages,
+		signer:    signer,
+		renderer:  pdf.NewPayslipRenderer(cfg, signer),
+	}
+}
+
+// RenderPDF renders a payslip with the configured template in the requested language, or the
+// employee's preferred one, protected with the employee's payslip password and signed with the company key
+func (s *payslipService) RenderPDF(payslip *domains.PayslipResponse, language string) ([]byte, error) {
+	password, err := s.documentPassword(payslip)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := pdf.RenderOptions{Translator: s.translator(payslip, language), Password: password}
+	if payslip.PayrollItemID != nil {
+		token, err := s.verificationToken(*payslip.PayrollItemID)
+		if err != nil {
+			return nil, err
+		}
+		opts.VerificationURL = strings.TrimRight(s.cfg.VerificationBaseURL, "/") + "/api/v1/verify/" + token
+	}
+
+	return s.renderer.Render(payslip, opts)
+}
+
+// Localize attaches labels and formatted amounts in the requested language, or the employee's preferred one
+func (s *payslipService) Localize(payslip *domains.PayslipResponse, language string) {
+	tr := s.translator(payslip, language)
+
+	labels := map[string]string{}
+	for key, message := range tr.Messages("payslip.") {
+		labels[strings.TrimPrefix(key, "payslip.")] = message
+	}
+	if s.cfg.Title != "" {
+		labels["title"] = s.cfg.Title
+	}
+
+	payslip.Localized = &domains.LocalizedPayslip{
+		Language: tr.Language(),
+		Labels:   labels,
+		Amounts: map[string]string{
+			"base_salary":          tr.Money(s.cfg.Currency, payslip.BaseSalary),
+			"attendance_amount":    tr.Money(s.cfg.Currency, payslip.AttendanceAmount),
+			"overtime_hours":       tr.Number(payslip.OvertimeHours, 1),
+			"overtime_amount":      tr.Money(s.cfg.Currency, payslip.OvertimeAmount),
+			"reimbursement_amount": tr.Money(s.cfg.Currency, payslip.ReimbursementAmount),
+			"total_amount":         tr.Money(s.cfg.Currency, payslip.TotalAmount),
+		},
+		AmountInWords: tr.AmountInWords(payslip.TotalAmount, s.cfg.Currency),
+	}
+	if payslip.TaxAmount != 0 {
+		payslip.Localized.Amounts["tax_amount"] = tr.Money(s.cfg.Currency, payslip.TaxAmount)
+	}
+	if payslip.BPJS != nil {
+		payslip.Localized.Amounts["bpjs_amount"] = tr.Money(s.cfg.Currency, payslip.BPJS.EmployeeTotal())
+	}
+	if payslip.Period != nil {
+		payslip.Localized.Period = tr.Date(payslip.Period.StartDate) + " - " + tr.Date(payslip.Period.EndDate)
+	}
+}
+
+// Verify resolves a QR verification token to the payslip it was issued for. Only payslips
+// of finalized payroll runs verify.
+func (s *payslipService) Verify(token string) (*domains.PayslipVerification, error) {
+	itemID, err := s.parseVerificationToken(token)
+	if err != nil {
+		return nil, domains.ErrPayslipNotVerifiable
+	}
+
+	item, err := s.repos.Payroll.GetPayrollItemByID(itemID)
+	if err != nil {
+		return nil, domains.ErrPayslipNotVerifiable
+	}
+
+	payroll, err := s.repos.Payroll.GetByID(item.PayrollID)
+	if err != nil || !payroll.IsFinal() {
+		return nil, domains.ErrPayslipNotVerifiable
+	}
+
+	return &domains.PayslipVerification{
+		Valid:        true,
+		EmployeeName: item.User.Username,
+		PeriodStart:  payroll.AttendancePeriod.StartDate,
+		PeriodEnd:    payroll.AttendancePeriod.EndDate,
+		NetPay:       item.TotalAmount,
+		Currency:     s.cfg.Currency,
+	}, nil
+}
+
+// SetPIN stores the PIN an employee wants their payslips protected with
+func (s *payslipService) SetPIN(userID uuid.UUID, pin, ipAddress, requestID string) error {
+	if !payslipPINPattern.MatchString(pin) {
+		return errors.New("PIN must be 6 to 12 digits")
+	}
+
+	user, err := s.repos.User.GetByID(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	sealed, err := sealSecret(s.cfg.PINSecret, pin)
+	if err != nil {
+		return err
+	}
+
+	hadPIN := user.PayslipPIN != ""
+	user.PayslipPIN = sealed
+	if err := s.repos.User.Update(user); err != nil {
+		return err
+	}
+
+	// The PIN itself never goes into the audit trail
+	createAuditLog("users", user.ID, "UPDATE", map[string]bool{"payslip_pin_set": hadPIN}, map[string]bool{"payslip_pin_set": true}, domains.UserActor(userID), ipAddress, requestID, s.repos)
+
+	return nil
+}
+
+// SigningCertificate returns the PEM certificate chain payslips are signed with
+func (s *payslipService) SigningCertificate() []byte {
+	return s.signer.CertificatePEM()
+}
+
+// translator picks the requested language, then the employee's preferred one, then the default
+func (s *payslipService) translator(payslip *domains.PayslipResponse, language string) *i18n.Translator {
+	var preferred string
+	if payslip != nil && payslip.Employee != nil {
+		preferred = payslip.Employee.PreferredLanguage
+	}
+	return s.languages.Translator(language, preferred)
+}
+
+// documentPassword is the employee's PIN, falling back to their birthdate as DDMMYYYY
+func (s *payslipService) documentPassword(payslip *domains.PayslipResponse) (string, error) {
+	if !s.cfg.Encrypt {
+		return "", nil
+	}
+	if payslip == nil || payslip.Employee == nil {
+		return "", errors.New("incomplete payslip")
+	}
+
+	employee := payslip.Employee
+	if employee.PayslipPIN != "" {
+		return openSecret(s.cfg.PINSecret, employee.PayslipPIN)
+	}
+	if employee.BirthDate != nil {
+		return employee.BirthDate.Format("02012006"), nil
+	}
+	return "", domains.ErrPayslipPasswordUnavailable
+}
+
+// verificationToken encrypts the payroll item ID so the token reveals nothing, and
+// authenticates it so tokens cannot be forged: base64url(AES(id) || HMAC(AES(id))[:16])
+func (s *payslipService) verificationToken(itemID uuid.UUID) (string, error) {
+	block, err := aes.NewCipher(s.verificationKey("encrypt"))
+	if err != nil {
+		return "", err
+	}
+
+	token := make([]byte, aes.BlockSize, aes.BlockSize+verificationTagSize)
+	block.Encrypt(token, itemID[:])
+	token = append(token, s.verificationTag(token)...)
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+func (s *payslipService) parseVerificationToken(token string) (uuid.UUID, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(data) != aes.BlockSize+verificationTagSize {
+		return uuid.Nil, errors.New("malformed token")
+	}
+
+	encrypted, tag := data[:aes.BlockSize], data[aes.BlockSize:]
+	if !hmac.Equal(tag, s.verificationTag(encrypted)) {
+		return uuid.Nil, errors.New("invalid token signature")
+	}
+
+	block, err := aes.NewCipher(s.verificationKey("encrypt"))
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	var itemID uuid.UUID
+	block.Decrypt(itemID[:], encrypted)
+	return itemID, nil
+}
+
+func (s *payslipService) verificationTag(data []byte) []byte {
+	mac := hmac.New(sha256.New, s.verificationKey("sign"))
+	mac.Write(data)
+	return mac.Sum(nil)[:verificationTagSize]
+}
+
+// verificationKey derives separate keys for each use of the verification secret
+func (s *payslipService) verificationKey(purpose string) []byte {
+	key := sha256.Sum256([]byte("payslip-verification-" + purpose + ":" + s.cfg.VerificationSecret))
+	return key[:]
+}
+
+func loadPayslipSigner(cfg config.PayslipConfig) *pdf.Signer {
+	if cfg.SigningKeyPath != "" || cfg.SigningCertPath != "" {
+		signer, err := pdf.LoadSigner(cfg.SigningKeyPath, cfg.SigningCertPath)
+		if err != nil {
+			logrus.WithError(err).Fatal("Failed to load payslip signing key")
+		}
+		return signer
+	}
+
+	logrus.Warn("No payslip signing key configured, signing payslips with a self-signed certificate")
+	signer, err := pdf.NewSelfSignedSigner(cfg.CompanyName)
+	if err != nil {
+		logrus.WithError(err).Fatal("Failed to create payslip signing key")
+	}
+	return signer
+}
+
+// sealSecret encrypts a value with AES-GCM under a key derived from secret
+func sealSecret(secret, value string) (string, error) {
+	gcm, err := secretCipher(secret)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(value), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func openSecret(secret, sealed string) (string, error) {
+	gcm, err := secretCipher(secret)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil || len(data) < gcm.NonceSize() {
+		return "", errors.New("invalid sealed secret")
+	}
+
+	value, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", errors.New("invalid sealed secret")
+	}
+	return string(value), nil
+}
+
+func secretCipher(secret string) (cipher.AEAD, error) {
+	key := sha256.Sum256([]byte(secret))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
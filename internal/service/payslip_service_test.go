@@ -0,0 +1,200 @@
+package service
+
+import (
+	"payslip-system/internal/config"
+	"payslip-system/internal/domains"
+	"payslip-system/internal/models"
+	"payslip-system/internal/repository"
+	mock_repository "payslip-system/internal/repository/mocks"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_payslipService_SetPIN(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name    string
+		pin     string
+		wantErr bool
+	}{
+		{name: "success", pin: "123456"},
+		{name: "error - too short", pin: "1234", wantErr: true},
+		{name: "error - not numeric", pin: "12ab56", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+			mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+
+			user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "employee1"}
+			if !tt.wantErr {
+				mockUserRepo.EXPECT().GetByID(user.ID).Return(user, nil)
+				mockUserRepo.EXPECT().Update(user).Return(nil)
+				mockAuditLogRepo.EXPECT().Create(gomock.Any()).Return(nil)
+			}
+
+			repos := &repository.Repositories{User: mockUserRepo, AuditLog: mockAuditLogRepo}
+			s := NewPayslipService(repos, config.PayslipConfig{PINSecret: "secret"}, nil)
+
+			err := s.SetPIN(user.ID, tt.pin, "127.0.0.1", "req-123")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotEqual(t, tt.pin, user.PayslipPIN)
+			pin, err := openSecret("secret", user.PayslipPIN)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.pin, pin)
+		})
+	}
+}
+
+func Test_payslipService_documentPassword(t *testing.T) {
+	sealed, err := sealSecret("secret", "654321")
+	assert.NoError(t, err)
+	birthDate := time.Date(1990, 3, 7, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		encrypt  bool
+		employee *models.User
+		want     string
+		wantErr  error
+	}{
+		{name: "encryption disabled", encrypt: false, employee: &models.User{}, want: ""},
+		{name: "PIN takes precedence", encrypt: true, employee: &models.User{PayslipPIN: sealed, BirthDate: &birthDate}, want: "654321"},
+		{name: "birthdate fallback", encrypt: true, employee: &models.User{BirthDate: &birthDate}, want: "07031990"},
+		{name: "no password available", encrypt: true, employee: &models.User{}, wantErr: domains.ErrPayslipPasswordUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &payslipService{cfg: config.PayslipConfig{Encrypt: tt.encrypt, PINSecret: "secret"}}
+			got, err := s.documentPassword(&domains.PayslipResponse{Employee: tt.employee})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_payslipService_Verify(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	period := models.AttendancePeriod{
+		StartDate: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := []struct {
+		name          string
+		tamper        bool
+		payrollStatus string
+		wantErr       bool
+	}{
+		{name: "success", payrollStatus: models.PayrollStatusApproved},
+		{name: "error - tampered token", tamper: true, payrollStatus: models.PayrollStatusApproved, wantErr: true},
+		{name: "error - payroll rejected", payrollStatus: models.PayrollStatusRejected, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPayrollRepo := mock_repository.NewMockIPayrollRepository(ctrl)
+
+			payroll := &models.Payroll{BaseModel: models.BaseModel{ID: uuid.New()}, Status: tt.payrollStatus, AttendancePeriod: period}
+			item := &models.PayrollItem{
+				BaseModel:   models.BaseModel{ID: uuid.New()},
+				PayrollID:   payroll.ID,
+				TotalAmount: 4500000,
+				User:        models.User{Username: "employee1"},
+			}
+			mockPayrollRepo.EXPECT().GetPayrollItemByID(item.ID).Return(item, nil).AnyTimes()
+			mockPayrollRepo.EXPECT().GetByID(payroll.ID).Return(payroll, nil).AnyTimes()
+
+			s := &payslipService{
+				repos: &repository.Repositories{Payroll: mockPayrollRepo},
+				cfg:   config.PayslipConfig{Currency: "IDR", VerificationSecret: "secret"},
+			}
+
+			token, err := s.verificationToken(item.ID)
+			assert.NoError(t, err)
+			assert.NotContains(t, token, item.ID.String())
+			if tt.tamper {
+				replacement := "A"
+				if token[0] == 'A' {
+					replacement = "B"
+				}
+				token = replacement + token[1:]
+			}
+
+			got, err := s.Verify(token)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, domains.ErrPayslipNotVerifiable)
+				assert.Nil(t, got)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.True(t, got.Valid)
+			assert.Equal(t, "employee1", got.EmployeeName)
+			assert.Equal(t, period.StartDate, got.PeriodStart)
+			assert.Equal(t, 4500000.0, got.NetPay)
+		})
+	}
+}
+
+func Test_payslipService_Localize(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockLanguageRepo := mock_repository.NewMockILanguageRepository(ctrl)
+	mockLanguageRepo.EXPECT().GetAll().Return(nil, nil).AnyTimes()
+	repos := &repository.Repositories{Language: mockLanguageRepo}
+	languages := NewLanguageService(repos, config.I18nConfig{DefaultLanguage: "en"})
+
+	tests := []struct {
+		name         string
+		requested    string
+		preferred    string
+		wantLanguage string
+		wantNetPay   string
+		wantLabel    string
+	}{
+		{name: "default language", wantLanguage: "en", wantNetPay: "IDR 5,000,000.00", wantLabel: "Net pay"},
+		{name: "employee preference", preferred: "id", wantLanguage: "id", wantNetPay: "Rp 5.000.000,00", wantLabel: "Gaji bersih"},
+		{name: "request overrides preference", requested: "en", preferred: "id", wantLanguage: "en", wantNetPay: "IDR 5,000,000.00", wantLabel: "Net pay"},
+		{name: "unknown language falls back", requested: "xx", preferred: "id", wantLanguage: "id", wantNetPay: "Rp 5.000.000,00", wantLabel: "Gaji bersih"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &payslipService{repos: repos, cfg: config.PayslipConfig{Currency: "IDR"}, languages: languages}
+			payslip := &domains.PayslipResponse{
+				Employee:    &models.User{Username: "employee1", PreferredLanguage: tt.preferred},
+				Period:      &models.AttendancePeriod{StartDate: time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2024, 8, 31, 0, 0, 0, 0, time.UTC)},
+				TotalAmount: 5000000,
+			}
+
+			s.Localize(payslip, tt.requested)
+
+			assert.Equal(t, tt.wantLanguage, payslip.Localized.Language)
+			assert.Equal(t, tt.wantNetPay, payslip.Localized.Amounts["total_amount"])
+			assert.Equal(t, tt.wantLabel, payslip.Localized.Labels["net_pay"])
+			assert.NotEmpty(t, payslip.Localized.AmountInWords)
+		})
+	}
+}
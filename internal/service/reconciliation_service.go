@@ -0,0 +1,322 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"payslip-system/internal/bankfile"
+	"payslip-system/internal/domains"
+	"payslip-system/internal/models"
+	"payslip-system/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+type reconciliationService struct {
+	repos *repository.Repositories
+}
+
+func NewReconciliationService(repos *repository.Repositories) *reconciliationService {
+	return &reconciliationService{
+		repos: repos,
+	}
+}
+
+// ImportStatement applies a bank statement or upload confirmation to the payments of a final payroll run.
+// Entries are matched to payroll items by the end-to-end reference of the disbursement file and their
+// amount; an entry without a known reference matches a sent item only when that item is the single one
+// with its amount. Entries about an earlier transfer of a reissued payment are recorded but not applied.
+// Entries imported before are skipped, so importing overlapping statements is safe.
+func (s *reconciliationService) ImportStatement(periodID uuid.UUID, format string, data []byte, actor domains.Actor, ipAddress, requestID string) (*domains.StatementImportResult, error) {
+	entries, err := bankfile.ParseStatement(format, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("statement has no transfers")
+	}
+	if format == "" {
+		format = bankfile.DetectStatementFormat(data)
+	}
+
+	payroll, err := s.repos.Payroll.GetRunByPeriodID(periodID)
+	if err != nil {
+		return nil, fmt.Errorf("payroll not found: %w", err)
+	}
+
+	if !payroll.IsFinal() {
+		return nil, errors.New("payments can only be reconciled once the payroll is final")
+	}
+
+	items, err := s.repos.Payroll.GetAllPayrollItemsByPeriod(periodID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payroll items: %w", err)
+	}
+
+	byID := map[string]*models.PayrollItem{}
+	for i := range items {
+		byID[paymentItemID(items[i].ID)] = &items[i]
+	}
+
+	result := &domains.StatementImportResult{PeriodID: periodID, Entries: len(entries), Transactions: []models.BankTransaction{}}
+	matchedByAmount := map[uuid.UUID]bool{}
+	now := time.Now()
+
+	for _, entry := range entries {
+		transaction := models.BankTransaction{
+			BaseModel: models.BaseModel{
+				CreatedBy: actor.UserID,
+				IPAddress: ipAddress,
+				RequestID: requestID,
+			},
+			AttendancePeriodID: periodID,
+			Fingerprint:        statementFingerprint(entry),
+			Source:             format,
+			TransactionID:      entry.TransactionID,
+			Reference:          entry.Reference,
+			Account:            entry.Account,
+			Amount:             float64(entry.Amount) / 100,
+			Status:             entry.Status,
+			BookingDate:        entry.BookingDate,
+			Reason:             entry.Reason,
+		}
+
+		item, attempt, note := matchStatementEntry(entry, byID, matchedByAmount)
+		var payment *models.PayrollItem
+		if item != nil {
+			transaction.PayrollItemID = &item.ID
+			transaction.Matched = true
+
+			switch {
+			case attempt != item.PaymentAttempts:
+				note = fmt.Sprintf("not applied, reports transfer %d of the payment, which was reissued as transfer %d", attempt, item.PaymentAttempts)
+			case !paymentTransitionAllowed(paymentStatusOf(*item), entry.Status):
+				note = fmt.Sprintf("not applied, payment is already %s", paymentStatusOf(*item))
+			default:
+				updated := *item
+				updated.PaymentStatus = entry.Status
+				updated.PaymentReason = entry.Reason
+				updated.PaymentUpdatedAt = &now
+				payment = &updated
+			}
+		}
+		transaction.Note = note
+
+		// The entry and the payment it applies are stored together, so a failed import can simply be repeated
+		created, err := s.repos.BankTransaction.Import(&transaction, payment)
+		if err != nil {
+			return nil, fmt.Errorf("failed to store bank transaction: %w", err)
+		}
+		if !created {
+			result.Duplicates++
+			continue
+		}
+
+		if item == nil {
+			result.Unmatched++
+			result.Transactions = append(result.Transactions, transaction)
+			continue
+		}
+		result.Matched++
+		result.Transactions = append(result.Transactions, transaction)
+		if payment == nil {
+			continue
+		}
+
+		status := paymentStatusOf(*item)
+		*item = *payment
+		result.Updated++
+
+		createAuditLog("payroll_items", item.ID, "UPDATE",
+			map[string]string{"payment_status": status},
+			map[string]string{"payment_status": item.PaymentStatus, "payment_reason": item.PaymentReason, "transaction_id": entry.TransactionID},
+			actor, ipAddress, requestID, s.repos)
+	}
+
+	return result, nil
+}
+
+// GetReconciliation lists the payments of a payroll run that still need attention: failed and returned
+// ones to reissue, ones the bank has not reported on, and statement entries that matched no payroll item
+func (s *reconciliationService) GetReconciliation(periodID uuid.UUID) (*domains.ReconciliationReport, error) {
+	payroll, err := s.repos.Payroll.GetRunByPeriodID(periodID)
+	if err != nil {
+		return nil, fmt.Errorf("payroll not found: %w", err)
+	}
+
+	items, err := s.repos.Payroll.GetAllPayrollItemsByPeriod(periodID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payroll items: %w", err)
+	}
+
+	transactions, err := s.repos.BankTransaction.GetByPeriodID(periodID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bank transactions: %w", err)
+	}
+
+	report := &domains.ReconciliationReport{
+		PayrollID: payroll.ID,
+		PeriodID:  periodID,
+		Counts: map[string]int{
+			models.PaymentStatusPending:  0,
+			models.PaymentStatusSent:     0,
+			models.PaymentStatusPaid:     0,
+			models.PaymentStatusFailed:   0,
+			models.PaymentStatusReturned: 0,
+		},
+		Reissue:     []domains.ReconciliationItem{},
+		Outstanding: []domains.ReconciliationItem{},
+		Unmatched:   []models.BankTransaction{},
+	}
+
+	for _, item := range items {
+		if item.TotalAmount <= 0 {
+			continue
+		}
+
+		status := paymentStatusOf(item)
+		report.Counts[status]++
+		report.TotalAmount += item.TotalAmount
+
+		entry := domains.ReconciliationItem{
+			PayrollItemID: item.ID,
+			UserID:        item.UserID,
+			Username:      item.User.Username,
+			Amount:        item.TotalAmount,
+			PaymentStatus: status,
+			PaymentBatch:  item.PaymentBatch,
+			Reason:        item.PaymentReason,
+		}
+
+		switch status {
+		case models.PaymentStatusPaid:
+			report.PaidAmount += item.TotalAmount
+		case models.PaymentStatusFailed, models.PaymentStatusReturned:
+			report.Reissue = append(report.Reissue, entry)
+		default:
+			report.Outstanding = append(report.Outstanding, entry)
+		}
+	}
+
+	for _, transaction := range transactions {
+		if !transaction.Matched {
+			report.Unmatched = append(report.Unmatched, transaction)
+		}
+	}
+
+	sortReconciliationItems(report.Reissue)
+	sortReconciliationItems(report.Outstanding)
+	report.TotalAmount = math.Round(report.TotalAmount*100) / 100
+	report.PaidAmount = math.Round(report.PaidAmount*100) / 100
+
+	return report, nil
+}
+
+// matchStatementEntry finds the payroll item a statement entry pays and the transfer of it the entry is about,
+// or explains why there is none
+func matchStatementEntry(entry bankfile.StatementEntry, byID map[string]*models.PayrollItem, matchedByAmount map[uuid.UUID]bool) (*models.PayrollItem, int, string) {
+	if id, attempt, ok := parsePaymentReference(entry.Reference); ok {
+		if item, ok := byID[id]; ok {
+			if paymentAmount(*item) != entry.Amount {
+				return nil, 0, fmt.Sprintf("amount differs from the payroll item (%s)", bankfile.FormatAmount(paymentAmount(*item)))
+			}
+			return item, attempt, ""
+		}
+	}
+
+	var candidates []*models.PayrollItem
+	for _, item := range byID {
+		if paymentStatusOf(*item) != models.PaymentStatusSent || matchedByAmount[item.ID] || paymentAmount(*item) != entry.Amount {
+			continue
+		}
+		if entry.Account != "" && entry.Account != item.User.BankAccountNumber {
+			continue
+		}
+		candidates = append(candidates, item)
+	}
+
+	switch len(candidates) {
+	case 0:
+		return nil, 0, "no payroll item with this reference or amount"
+	case 1:
+		matchedByAmount[candidates[0].ID] = true
+		return candidates[0], candidates[0].PaymentAttempts, "matched by amount"
+	default:
+		return nil, 0, fmt.Sprintf("%d payroll items have this amount", len(candidates))
+	}
+}
+
+// paymentTransitionAllowed reports whether the bank may move a payment to a status. Only payments awaiting
+// the bank are updated, except that a paid transfer can still be returned.
+func paymentTransitionAllowed(from, to string) bool {
+	switch from {
+	case models.PaymentStatusPending, models.PaymentStatusSent:
+		return true
+	case models.PaymentStatusPaid:
+		return to == models.PaymentStatusReturned
+	default:
+		return false
+	}
+}
+
+func paymentStatusOf(item models.PayrollItem) string {
+	if item.PaymentStatus == "" {
+		return models.PaymentStatusPending
+	}
+	return item.PaymentStatus
+}
+
+// paymentReference is the end-to-end ID of a transfer of a payroll item: the item and which transfer of it,
+// as a reissue is a new transfer. 35 characters, the most bank layouts allow.
+func paymentReference(itemID uuid.UUID, attempt int) string {
+	return fmt.Sprintf("%s%03d", paymentItemID(itemID), attempt)
+}
+
+func paymentItemID(itemID uuid.UUID) string {
+	return strings.ReplaceAll(itemID.String(), "-", "")
+}
+
+// parsePaymentReference splits an end-to-end ID into the payroll item and the transfer of it. References
+// without a transfer are from files written before reissues were numbered, and count as transfer 0.
+func parsePaymentReference(reference string) (string, int, bool) {
+	reference = strings.ToLower(strings.ReplaceAll(reference, "-", ""))
+	switch len(reference) {
+	case 32:
+		return reference, 0, true
+	case 35:
+		attempt, err := strconv.Atoi(reference[32:])
+		if err != nil {
+			return "", 0, false
+		}
+		return reference[:32], attempt, true
+	default:
+		return "", 0, false
+	}
+}
+
+func paymentAmount(item models.PayrollItem) int64 {
+	return toCents(item.TotalAmount)
+}
+
+// statementFingerprint identifies a statement entry across imports of the same or overlapping statements
+func statementFingerprint(entry bankfile.StatementEntry) string {
+	var date string
+	if entry.BookingDate != nil {
+		date = entry.BookingDate.Format("2006-01-02")
+	}
+	sum := sha256.Sum256([]byte(strings.Join([]string{entry.TransactionID, entry.Reference, entry.Account, entry.Status, date, bankfile.FormatAmount(entry.Amount)}, "|")))
+	return hex.EncodeToString(sum[:])
+}
+
+func sortReconciliationItems(items []domains.ReconciliationItem) {
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Username < items[j].Username
+	})
+}
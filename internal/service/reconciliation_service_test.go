@@ -0,0 +1,206 @@
+package service
+
+import (
+	"payslip-system/internal/domains"
+	"testing"
+
+	"payslip-system/internal/models"
+	"payslip-system/internal/repository"
+	mock_repository "payslip-system/internal/repository/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_reconciliationService_ImportStatement(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	periodID := uuid.New()
+	adminID := uuid.New()
+
+	paidItem := models.PayrollItem{BaseModel: models.BaseModel{ID: uuid.New()}, User: models.User{Username: "employee1", BankAccountNumber: "0987654321"}, TotalAmount: 5000000.5, PaymentStatus: models.PaymentStatusSent, PaymentAttempts: 1}
+	returnedItem := models.PayrollItem{BaseModel: models.BaseModel{ID: uuid.New()}, User: models.User{Username: "employee2", BankAccountNumber: "1230004567890"}, TotalAmount: 3750000, PaymentStatus: models.PaymentStatusPaid, PaymentAttempts: 1}
+	amountItem := models.PayrollItem{BaseModel: models.BaseModel{ID: uuid.New()}, User: models.User{Username: "employee3", BankAccountNumber: "5550001111"}, TotalAmount: 4200000, PaymentStatus: models.PaymentStatusSent, PaymentAttempts: 1}
+	legacyItem := models.PayrollItem{BaseModel: models.BaseModel{ID: uuid.New()}, User: models.User{Username: "employee5", BankAccountNumber: "8880003333"}, TotalAmount: 1900000, PaymentStatus: models.PaymentStatusSent}
+	reissuedItem := models.PayrollItem{BaseModel: models.BaseModel{ID: uuid.New()}, User: models.User{Username: "employee4", BankAccountNumber: "7770002222"}, TotalAmount: 2800000, PaymentStatus: models.PaymentStatusSent, PaymentAttempts: 2}
+
+	tests := []struct {
+		name        string
+		status      string
+		statement   string
+		duplicates  bool
+		wantUpdates map[uuid.UUID]string
+		wantResult  [4]int // matched, unmatched, duplicates, updated
+		wantNotes   []string
+		wantErr     bool
+	}{
+		{
+			name:   "success",
+			status: models.PayrollStatusApproved,
+			statement: "reference,amount,status,account_number,reason\n" +
+				paymentReference(paidItem.ID, 1) + ",5000000.50,paid,,\n" +
+				paymentReference(returnedItem.ID, 1) + ",3750000.00,returned,,AC04\n" +
+				",4200000,paid,5550001111,\n" +
+				"unknown,100000,paid,,\n" +
+				paymentReference(amountItem.ID, 1) + ",1,paid,,\n",
+			wantUpdates: map[uuid.UUID]string{
+				paidItem.ID:     models.PaymentStatusPaid,
+				returnedItem.ID: models.PaymentStatusReturned,
+				amountItem.ID:   models.PaymentStatusPaid,
+			},
+			wantResult: [4]int{3, 2, 0, 3},
+			wantNotes:  []string{"", "", "matched by amount", "no payroll item with this reference or amount", "amount differs from the payroll item (4200000.00)"},
+		},
+		{
+			name:   "success - paid item cannot fail",
+			status: models.PayrollStatusApproved,
+			statement: "reference,amount,status\n" +
+				paymentReference(returnedItem.ID, 1) + ",3750000.00,failed\n",
+			wantResult: [4]int{1, 0, 0, 0},
+			wantNotes:  []string{"not applied, payment is already paid"},
+		},
+		{
+			name:   "success - earlier transfer of a reissued payment",
+			status: models.PayrollStatusApproved,
+			statement: "reference,amount,status,account_number,reason\n" +
+				paymentReference(reissuedItem.ID, 1) + ",2800000,returned,,AC04\n" +
+				paymentReference(reissuedItem.ID, 2) + ",2800000,paid,,\n",
+			wantUpdates: map[uuid.UUID]string{
+				reissuedItem.ID: models.PaymentStatusPaid,
+			},
+			wantResult: [4]int{2, 0, 0, 1},
+			wantNotes:  []string{"not applied, reports transfer 1 of the payment, which was reissued as transfer 2", ""},
+		},
+		{
+			name:   "success - references without a transfer number",
+			status: models.PayrollStatusApproved,
+			statement: "reference,amount,status\n" +
+				paymentItemID(legacyItem.ID) + ",1900000,paid\n",
+			wantUpdates: map[uuid.UUID]string{
+				legacyItem.ID: models.PaymentStatusPaid,
+			},
+			wantResult: [4]int{1, 0, 0, 1},
+			wantNotes:  []string{""},
+		},
+		{
+			name:   "success - imported before",
+			status: models.PayrollStatusApproved,
+			statement: "reference,amount,status\n" +
+				paymentReference(paidItem.ID, 1) + ",5000000.50,paid\n",
+			duplicates: true,
+			wantResult: [4]int{0, 0, 1, 0},
+		},
+		{
+			name:      "error - payroll awaiting approval",
+			status:    models.PayrollStatusPendingApproval,
+			statement: "reference,amount\nabc,100\n",
+			wantErr:   true,
+		},
+		{
+			name:      "error - invalid statement",
+			statement: "amount\n100\n",
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPayrollRepo := mock_repository.NewMockIPayrollRepository(ctrl)
+			mockTransactionRepo := mock_repository.NewMockIBankTransactionRepository(ctrl)
+			mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+
+			if tt.status != "" {
+				payroll := &models.Payroll{BaseModel: models.BaseModel{ID: uuid.New()}, AttendancePeriodID: periodID, Status: tt.status}
+				mockPayrollRepo.EXPECT().GetRunByPeriodID(periodID).Return(payroll, nil)
+			}
+
+			if !tt.wantErr {
+				items := []models.PayrollItem{paidItem, returnedItem, amountItem, reissuedItem, legacyItem}
+				mockPayrollRepo.EXPECT().GetAllPayrollItemsByPeriod(periodID).Return(items, nil)
+
+				updates := map[uuid.UUID]string{}
+				mockTransactionRepo.EXPECT().Import(gomock.Any(), gomock.Any()).DoAndReturn(func(transaction *models.BankTransaction, payment *models.PayrollItem) (bool, error) {
+					assert.Equal(t, "csv", transaction.Source)
+					assert.NotEmpty(t, transaction.Fingerprint)
+					if payment != nil && !tt.duplicates {
+						updates[payment.ID] = payment.PaymentStatus
+					}
+					return !tt.duplicates, nil
+				}).AnyTimes()
+				mockAuditLogRepo.EXPECT().Create(gomock.Any()).Return(nil).Times(len(tt.wantUpdates))
+				defer func() {
+					if len(tt.wantUpdates) > 0 {
+						assert.Equal(t, tt.wantUpdates, updates)
+					}
+				}()
+			}
+
+			repos := &repository.Repositories{Payroll: mockPayrollRepo, BankTransaction: mockTransactionRepo, AuditLog: mockAuditLogRepo}
+			s := NewReconciliationService(repos)
+
+			result, err := s.ImportStatement(periodID, "", []byte(tt.statement), domains.UserActor(adminID), "127.0.0.1", "req-123")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantResult, [4]int{result.Matched, result.Unmatched, result.Duplicates, result.Updated})
+
+			var notes []string
+			for _, transaction := range result.Transactions {
+				notes = append(notes, transaction.Note)
+			}
+			assert.Equal(t, tt.wantNotes, notes)
+		})
+	}
+}
+
+func Test_reconciliationService_GetReconciliation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	periodID := uuid.New()
+
+	mockPayrollRepo := mock_repository.NewMockIPayrollRepository(ctrl)
+	mockTransactionRepo := mock_repository.NewMockIBankTransactionRepository(ctrl)
+
+	payroll := &models.Payroll{BaseModel: models.BaseModel{ID: uuid.New()}, AttendancePeriodID: periodID, Status: models.PayrollStatusApproved}
+	mockPayrollRepo.EXPECT().GetRunByPeriodID(periodID).Return(payroll, nil)
+	mockPayrollRepo.EXPECT().GetAllPayrollItemsByPeriod(periodID).Return([]models.PayrollItem{
+		{BaseModel: models.BaseModel{ID: uuid.New()}, User: models.User{Username: "employee1"}, TotalAmount: 5000000.5, PaymentStatus: models.PaymentStatusPaid},
+		{BaseModel: models.BaseModel{ID: uuid.New()}, User: models.User{Username: "employee4"}, TotalAmount: 3750000, PaymentStatus: models.PaymentStatusReturned, PaymentReason: "AC04"},
+		{BaseModel: models.BaseModel{ID: uuid.New()}, User: models.User{Username: "employee2"}, TotalAmount: 4000000, PaymentStatus: models.PaymentStatusFailed},
+		{BaseModel: models.BaseModel{ID: uuid.New()}, User: models.User{Username: "employee3"}, TotalAmount: 4200000, PaymentStatus: models.PaymentStatusSent},
+		{BaseModel: models.BaseModel{ID: uuid.New()}, User: models.User{Username: "employee5"}, TotalAmount: 3100000},
+	}, nil)
+	mockTransactionRepo.EXPECT().GetByPeriodID(periodID).Return([]models.BankTransaction{
+		{TransactionID: "TRX001", Matched: true},
+		{TransactionID: "TRX002", Note: "no payroll item with this reference or amount"},
+	}, nil)
+
+	repos := &repository.Repositories{Payroll: mockPayrollRepo, BankTransaction: mockTransactionRepo}
+	s := NewReconciliationService(repos)
+
+	report, err := s.GetReconciliation(periodID)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]int{"pending": 1, "sent": 1, "paid": 1, "failed": 1, "returned": 1}, report.Counts)
+	assert.Equal(t, 20050000.5, report.TotalAmount)
+	assert.Equal(t, 5000000.5, report.PaidAmount)
+
+	require.Len(t, report.Reissue, 2)
+	assert.Equal(t, "employee2", report.Reissue[0].Username)
+	assert.Equal(t, "employee4", report.Reissue[1].Username)
+	assert.Equal(t, "AC04", report.Reissue[1].Reason)
+
+	require.Len(t, report.Outstanding, 2)
+	assert.Equal(t, "employee3", report.Outstanding[0].Username)
+	assert.Equal(t, "pending", report.Outstanding[1].PaymentStatus)
+
+	require.Len(t, report.Unmatched, 1)
+	assert.Equal(t, "TRX002", report.Unmatched[0].TransactionID)
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"errors"
 	"fmt"
+	"payslip-system/internal/domains"
 	"payslip-system/internal/models"
 	"payslip-system/internal/repository"
 
@@ -26,31 +27,38 @@ func (s *reimbursementService) SubmitReimbursement(userID uuid.UUID, amount floa
 		return errors.New("reimbursement description is required")
 	}
 
-	// Get active attendance period
-	period, err := s.repos.AttendancePeriod.GetActive()
+	user, err := s.repos.User.GetByID(userID)
 	if err != nil {
-		return errors.New("no active attendance period found")
+		return errors.New("user not found")
 	}
 
-	// Create reimbursement record
-	reimbursement := &models.Reimbursement{
-		BaseModel: models.BaseModel{
-			CreatedBy: &userID,
-			IPAddress: ipAddress,
-			RequestID: requestID,
-		},
-		UserID:             userID,
-		AttendancePeriodID: period.ID,
-		Amount:             amount,
-		Description:        description,
-	}
+	var reimbursement *models.Reimbursement
+	err = withSubmissionPeriod(s.repos, func(txRepos *repository.Repositories, period *models.AttendancePeriod) error {
+		// Create reimbursement record
+		reimbursement = &models.Reimbursement{
+			BaseModel: models.BaseModel{
+				CreatedBy: &userID,
+				IPAddress: ipAddress,
+				RequestID: requestID,
+			},
+			UserID:             userID,
+			AttendancePeriodID: period.ID,
+			Amount:             amount,
+			Description:        description,
+			Approval:           submissionApproval(user),
+		}
 
-	if err := s.repos.Reimbursement.Create(reimbursement); err != nil {
-		return fmt.Errorf("failed to create reimbursement record: %w", err)
+		if err := txRepos.Reimbursement.Create(reimbursement); err != nil {
+			return fmt.Errorf("failed to create reimbursement record: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	// Create audit log
-	createAuditLog("reimbursements", reimbursement.ID, "INSERT", nil, reimbursement, &userID, ipAddress, requestID, s.repos)
+	createAuditLog("reimbursements", reimbursement.ID, "INSERT", nil, reimbursement, domains.UserActor(userID), ipAddress, requestID, s.repos)
 
 	return nil
 }
@@ -0,0 +1,457 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"payslip-system/internal/config"
+	"payslip-system/internal/domains"
+	"payslip-system/internal/models"
+	"payslip-system/internal/repository"
+	"sort"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+type reportService struct {
+	repos    *repository.Repositories
+	cfg      config.PayrollConfig
+	ledger   config.LedgerConfig
+	currency string
+}
+
+func NewReportService(repos *repository.Repositories, cfg config.PayrollConfig, ledger config.LedgerConfig, currency string) *reportService {
+	return &reportService{repos: repos, cfg: cfg, ledger: ledger, currency: currency}
+}
+
+// GetPayrollVariance compares two periods with approved runs per employee and per component.
+// A thresholdPercent of zero or less falls back to the configured threshold.
+func (s *reportService) GetPayrollVariance(fromPeriodID, toPeriodID uuid.UUID, thresholdPercent float64) (*domains.PayrollVarianceReport, error) {
+	if fromPeriodID == toPeriodID {
+		return nil, errors.New("cannot compare a period with itself")
+	}
+
+	if thresholdPercent <= 0 {
+		thresholdPercent = s.cfg.VarianceThresholdPercent
+	}
+
+	fromPeriod, _, fromItems, err := s.getFinalItems(fromPeriodID)
+	if err != nil {
+		return nil, err
+	}
+
+	toPeriod, _, toItems, err := s.getFinalItems(toPeriodID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domains.PayrollVarianceReport{
+		FromPeriod:       fromPeriod,
+		ToPeriod:         toPeriod,
+		ThresholdPercent: thresholdPercent,
+		Employees:        []domains.EmployeeVariance{},
+		Joiners:          []domains.EmployeeSummary{},
+		Leavers:          []domains.EmployeeSummary{},
+	}
+
+	previousByUser := make(map[uuid.UUID]models.PayrollItem, len(fromItems))
+	for _, item := range fromItems {
+		previousByUser[item.UserID] = item
+	}
+
+	var previousTotal, currentTotal models.PayrollItem
+	for i := range toItems {
+		current := toItems[i]
+		addPayrollItem(&currentTotal, current)
+
+		previous, ok := previousByUser[current.UserID]
+		if !ok {
+			report.Joiners = append(report.Joiners, domains.EmployeeSummary{
+				Employee:    &toItems[i].User,
+				TotalAmount: current.TotalAmount,
+			})
+			continue
+		}
+		delete(previousByUser, current.UserID)
+		addPayrollItem(&previousTotal, previous)
+
+		variance := domains.EmployeeVariance{
+			Employee:   &toItems[i].User,
+			Components: compareComponents(previous, current, thresholdPercent),
+		}
+		for _, component := range variance.Components {
+			if component.Flagged {
+				variance.Flagged = true
+				report.FlaggedCount++
+				break
+			}
+		}
+		report.Employees = append(report.Employees, variance)
+	}
+
+	for i := range fromItems {
+		if _, left := previousByUser[fromItems[i].UserID]; left {
+			report.Leavers = append(report.Leavers, domains.EmployeeSummary{
+				Employee:    &fromItems[i].User,
+				TotalAmount: fromItems[i].TotalAmount,
+			})
+		}
+	}
+
+	// Totals only cover employees present in both periods so joiners and leavers don't skew them
+	report.Totals = compareComponents(previousTotal, currentTotal, thresholdPercent)
+
+	sort.Slice(report.Employees, func(i, j int) bool {
+		return report.Employees[i].Employee.Username < report.Employees[j].Employee.Username
+	})
+	sortSummaries(report.Joiners)
+	sortSummaries(report.Leavers)
+
+	return report, nil
+}
+
+// ExportPayrollVarianceCSV renders one row per employee and component
+func (s *reportService) ExportPayrollVarianceCSV(report *domains.PayrollVarianceReport) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	header := []string{"status", "employee_id", "username", "component", "previous", "current", "change", "change_percent", "flagged"}
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	for _, employee := range report.Employees {
+		for _, component := range employee.Components {
+			if err := w.Write(varianceRow("changed", employee.Employee, component)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, joiner := range report.Joiners {
+		row := varianceRow("joiner", joiner.Employee, domains.ComponentVariance{
+			Component: domains.ComponentNetPay,
+			Current:   joiner.TotalAmount,
+			Change:    joiner.TotalAmount,
+		})
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, leaver := range report.Leavers {
+		row := varianceRow("leaver", leaver.Employee, domains.ComponentVariance{
+			Component: domains.ComponentNetPay,
+			Previous:  leaver.TotalAmount,
+			Change:    -leaver.TotalAmount,
+		})
+		if err := w.Write(row); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, component := range report.Totals {
+		if err := w.Write(varianceRow("total", nil, component)); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GetPayrollJournal books a final payroll run: the earned components and the employer BPJS contributions are
+// debited to their expense accounts, PPh 21 and BPJS are credited to their payables and net pay to salaries
+// payable. Amounts are rounded to cents per employee before they are
+// summed, so every entry balances. byCostCenter posts one entry per cost center instead of one for the run.
+func (s *reportService) GetPayrollJournal(periodID uuid.UUID, byCostCenter bool) (*domains.PayrollJournal, error) {
+	period, payroll, items, err := s.getFinalItems(periodID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cents per component, per cost center
+	postings := map[string]map[string]int64{}
+	for _, item := range items {
+		// PPh 21 and the employee BPJS shares are the only deductions an account is known for
+		taxAmount := toCents(item.TaxAmount)
+		bpjsEmployee := toCents(item.BPJS.EmployeeTotal())
+		if deductions := toCents(payrollItemDeductions(item)); deductions != taxAmount+bpjsEmployee {
+			return nil, fmt.Errorf("payroll item of %s has deductions the journal cannot post", item.User.Username)
+		}
+
+		costCenter := ""
+		if byCostCenter {
+			costCenter = item.User.CostCenter
+			if costCenter == "" {
+				costCenter = s.ledger.DefaultCostCenter
+			}
+		}
+
+		amounts, ok := postings[costCenter]
+		if !ok {
+			amounts = map[string]int64{}
+			postings[costCenter] = amounts
+		}
+
+		salary := toCents(item.AttendanceAmount)
+		overtime := toCents(item.OvertimeAmount)
+		reimbursement := toCents(item.ReimbursementAmount)
+		bpjsEmployer := toCents(item.BPJS.EmployerTotal())
+		amounts[domains.LedgerSalaryExpense] += salary
+		amounts[domains.LedgerOvertimeExpense] += overtime
+		amounts[domains.LedgerReimbursementExpense] += reimbursement
+		amounts[domains.LedgerBPJSExpense] += bpjsEmployer
+		amounts[domains.LedgerTaxPayable] += taxAmount
+		amounts[domains.LedgerBPJSPayable] += bpjsEmployee + bpjsEmployer
+		amounts[domains.LedgerNetSalaryPayable] += salary + overtime + reimbursement - taxAmount - bpjsEmployee
+	}
+
+	if len(postings) == 0 {
+		return nil, errors.New("payroll has no items to post")
+	}
+
+	costCenters := make([]string, 0, len(postings))
+	for costCenter := range postings {
+		costCenters = append(costCenters, costCenter)
+	}
+	sort.Strings(costCenters)
+
+	month := period.StartDate.Format("Jan 2006")
+	reference := fmt.Sprintf("%s-%s", s.ledger.Journal, period.StartDate.Format("200601"))
+
+	journal := &domains.PayrollJournal{
+		Journal:   s.ledger.Journal,
+		PayrollID: payroll.ID,
+		PeriodID:  periodID,
+		Currency:  s.currency,
+		Entries:   []domains.JournalEntry{},
+	}
+
+	for _, costCenter := range costCenters {
+		entry := domains.JournalEntry{
+			Reference:   reference,
+			Date:        period.EndDate.Format("2006-01-02"),
+			Description: "Payroll " + month,
+			CostCenter:  costCenter,
+			Lines:       []domains.JournalLine{},
+		}
+		if costCenter != "" {
+			entry.Reference += "-" + costCenter
+		}
+
+		var debit, credit int64
+		for _, component := range s.journalComponents() {
+			amount := postings[costCenter][component.name]
+			if amount == 0 {
+				continue
+			}
+
+			// A negative amount, e.g. from a correction, is posted on the other side
+			debitSide := component.debit != (amount < 0)
+			if amount < 0 {
+				amount = -amount
+			}
+
+			line := domains.JournalLine{
+				Account:     component.account,
+				Component:   component.name,
+				Description: fmt.Sprintf("%s %s", component.description, month),
+				CostCenter:  costCenter,
+			}
+			if debitSide {
+				line.Debit = fromCents(amount)
+				debit += amount
+			} else {
+				line.Credit = fromCents(amount)
+				credit += amount
+			}
+			entry.Lines = append(entry.Lines, line)
+		}
+
+		if debit != credit {
+			return nil, fmt.Errorf("journal entry %s does not balance", entry.Reference)
+		}
+		entry.TotalDebit = fromCents(debit)
+		entry.TotalCredit = fromCents(credit)
+		journal.Entries = append(journal.Entries, entry)
+	}
+
+	return journal, nil
+}
+
+// ExportPayrollJournalCSV renders one row per journal line, in the layout of a generic ERP journal import
+func (s *reportService) ExportPayrollJournalCSV(journal *domains.PayrollJournal) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	header := []string{"journal", "reference", "date", "account", "component", "description", "cost_center", "debit", "credit", "currency"}
+	if err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	for _, entry := range journal.Entries {
+		for _, line := range entry.Lines {
+			row := []string{
+				journal.Journal,
+				entry.Reference,
+				entry.Date,
+				line.Account,
+				line.Component,
+				line.Description,
+				line.CostCenter,
+				strconv.FormatFloat(line.Debit, 'f', 2, 64),
+				strconv.FormatFloat(line.Credit, 'f', 2, 64),
+				journal.Currency,
+			}
+			if err := w.Write(row); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+type journalComponent struct {
+	name        string
+	account     string
+	description string
+	debit       bool
+}
+
+// journalComponents lists the journal lines in posting order, debits first
+func (s *reportService) journalComponents() []journalComponent {
+	accounts := s.ledger.Accounts
+	return []journalComponent{
+		{name: domains.LedgerSalaryExpense, account: accounts.SalaryExpense, description: "Salary expense", debit: true},
+		{name: domains.LedgerOvertimeExpense, account: accounts.OvertimeExpense, description: "Overtime expense", debit: true},
+		{name: domains.LedgerReimbursementExpense, account: accounts.ReimbursementExpense, description: "Reimbursement expense", debit: true},
+		{name: domains.LedgerBPJSExpense, account: accounts.BPJSExpense, description: "BPJS employer contributions", debit: true},
+		{name: domains.LedgerTaxPayable, account: accounts.TaxPayable, description: "PPh 21 payable"},
+		{name: domains.LedgerBPJSPayable, account: accounts.BPJSPayable, description: "BPJS payable"},
+		{name: domains.LedgerNetSalaryPayable, account: accounts.NetSalaryPayable, description: "Net salary payable"},
+	}
+}
+
+// getFinalItems returns the payroll items of a period whose run has been approved. Runs awaiting approval
+// may still be rejected, so their figures are not reported as actual payroll.
+func (s *reportService) getFinalItems(periodID uuid.UUID) (*models.AttendancePeriod, *models.Payroll, []models.PayrollItem, error) {
+	period, err := s.repos.AttendancePeriod.GetByID(periodID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("period not found: %w", err)
+	}
+
+	if !period.IsProcessed {
+		return nil, nil, nil, fmt.Errorf("payroll not processed for period %s", periodID)
+	}
+
+	payroll, err := s.repos.Payroll.GetRunByPeriodID(periodID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("payroll not found: %w", err)
+	}
+
+	if !payroll.IsFinal() {
+		return nil, nil, nil, fmt.Errorf("payroll for period %s is not final yet", periodID)
+	}
+
+	items, err := s.repos.Payroll.GetAllPayrollItemsByPeriod(periodID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to get payroll items: %w", err)
+	}
+
+	return period, payroll, items, nil
+}
+
+// payrollItemDeductions derives deductions as gross earnings minus net pay
+func payrollItemDeductions(item models.PayrollItem) float64 {
+	return item.AttendanceAmount + item.OvertimeAmount + item.ReimbursementAmount - item.TotalAmount
+}
+
+func addPayrollItem(total *models.PayrollItem, item models.PayrollItem) {
+	total.BaseSalary += item.BaseSalary
+	total.AttendanceAmount += item.AttendanceAmount
+	total.OvertimeAmount += item.OvertimeAmount
+	total.ReimbursementAmount += item.ReimbursementAmount
+	total.TotalAmount += item.TotalAmount
+}
+
+func compareComponents(previous, current models.PayrollItem, thresholdPercent float64) []domains.ComponentVariance {
+	return []domains.ComponentVariance{
+		compareComponent(domains.ComponentBaseSalary, previous.BaseSalary, current.BaseSalary, thresholdPercent),
+		compareComponent(domains.ComponentAttendance, previous.AttendanceAmount, current.AttendanceAmount, thresholdPercent),
+		compareComponent(domains.ComponentOvertime, previous.OvertimeAmount, current.OvertimeAmount, thresholdPercent),
+		compareComponent(domains.ComponentReimbursement, previous.ReimbursementAmount, current.ReimbursementAmount, thresholdPercent),
+		compareComponent(domains.ComponentDeductions, payrollItemDeductions(previous), payrollItemDeductions(current), thresholdPercent),
+		compareComponent(domains.ComponentNetPay, previous.TotalAmount, current.TotalAmount, thresholdPercent),
+	}
+}
+
+func compareComponent(component string, previous, current, thresholdPercent float64) domains.ComponentVariance {
+	variance := domains.ComponentVariance{
+		Component: component,
+		Previous:  previous,
+		Current:   current,
+		Change:    current - previous,
+	}
+
+	// Ignore sub-cent floating point noise
+	if math.Abs(variance.Change) < 0.01 {
+		variance.Change = 0
+		zero := 0.0
+		variance.ChangePercent = &zero
+		return variance
+	}
+
+	if previous == 0 {
+		// Any amount appearing from nothing is worth a look
+		variance.Flagged = true
+		return variance
+	}
+
+	percent := variance.Change / math.Abs(previous) * 100
+	variance.ChangePercent = &percent
+	variance.Flagged = math.Abs(percent) > thresholdPercent
+	return variance
+}
+
+func varianceRow(status string, employee *models.User, component domains.ComponentVariance) []string {
+	var employeeID, username string
+	if employee != nil {
+		employeeID = employee.ID.String()
+		username = employee.Username
+	}
+
+	var changePercent string
+	if component.ChangePercent != nil {
+		changePercent = strconv.FormatFloat(*component.ChangePercent, 'f', 2, 64)
+	}
+
+	return []string{
+		status,
+		employeeID,
+		username,
+		component.Component,
+		strconv.FormatFloat(component.Previous, 'f', 2, 64),
+		strconv.FormatFloat(component.Current, 'f', 2, 64),
+		strconv.FormatFloat(component.Change, 'f', 2, 64),
+		changePercent,
+		strconv.FormatBool(component.Flagged),
+	}
+}
+
+func sortSummaries(summaries []domains.EmployeeSummary) {
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Employee.Username < summaries[j].Employee.Username
+	})
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"payslip-system/internal/config"
+	"payslip-system/internal/domains"
+	"payslip-system/internal/models"
+	"payslip-system/internal/repository"
+	mock_repository "payslip-system/internal/repository/mocks"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_reportService_GetPayrollVariance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fromID, toID := uuid.New(), uuid.New()
+	stayer := models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "stayer"}
+	leaver := models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "leaver"}
+	joiner := models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "joiner"}
+
+	fromItems := []models.PayrollItem{
+		{UserID: stayer.ID, User: stayer, BaseSalary: 6000000, AttendanceAmount: 4000000, OvertimeAmount: 100000, TotalAmount: 4100000},
+		{UserID: leaver.ID, User: leaver, BaseSalary: 5000000, AttendanceAmount: 3000000, TotalAmount: 3000000},
+	}
+	toItems := []models.PayrollItem{
+		{UserID: stayer.ID, User: stayer, BaseSalary: 6000000, AttendanceAmount: 4000000, OvertimeAmount: 300000, ReimbursementAmount: 50000, TotalAmount: 4350000},
+		{UserID: joiner.ID, User: joiner, BaseSalary: 4000000, AttendanceAmount: 2000000, TotalAmount: 2000000},
+	}
+
+	mockAttendancePeriodRepo := mock_repository.NewMockIAttendancePeriodRepository(ctrl)
+	mockPayrollRepo := mock_repository.NewMockIPayrollRepository(ctrl)
+	mockAttendancePeriodRepo.EXPECT().GetByID(fromID).Return(&models.AttendancePeriod{BaseModel: models.BaseModel{ID: fromID}, IsProcessed: true}, nil)
+	mockAttendancePeriodRepo.EXPECT().GetByID(toID).Return(&models.AttendancePeriod{BaseModel: models.BaseModel{ID: toID}, IsProcessed: true}, nil)
+	mockPayrollRepo.EXPECT().GetRunByPeriodID(fromID).Return(&models.Payroll{Status: models.PayrollStatusApproved}, nil)
+	mockPayrollRepo.EXPECT().GetRunByPeriodID(toID).Return(&models.Payroll{Status: models.PayrollStatusApproved}, nil)
+	mockPayrollRepo.EXPECT().GetAllPayrollItemsByPeriod(fromID).Return(fromItems, nil)
+	mockPayrollRepo.EXPECT().GetAllPayrollItemsByPeriod(toID).Return(toItems, nil)
+
+	repos := &repository.Repositories{
+		AttendancePeriod: mockAttendancePeriodRepo,
+		Payroll:          mockPayrollRepo,
+	}
+
+	s := NewReportService(repos, config.PayrollConfig{VarianceThresholdPercent: 10}, config.LedgerConfig{}, "IDR")
+	got, err := s.GetPayrollVariance(fromID, toID, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, 10.0, got.ThresholdPercent)
+	require.Len(t, got.Employees, 1)
+	require.Len(t, got.Joiners, 1)
+	require.Len(t, got.Leavers, 1)
+	assert.Equal(t, "joiner", got.Joiners[0].Employee.Username)
+	assert.Equal(t, "leaver", got.Leavers[0].Employee.Username)
+	assert.Equal(t, 1, got.FlaggedCount)
+
+	flagged := map[string]bool{}
+	for _, component := range got.Employees[0].Components {
+		flagged[component.Component] = component.Flagged
+	}
+	assert.Equal(t, map[string]bool{
+		domains.ComponentBaseSalary:    false,
+		domains.ComponentAttendance:    false,
+		domains.ComponentOvertime:      true,
+		domains.ComponentReimbursement: true,
+		domains.ComponentDeductions:    false,
+		domains.ComponentNetPay:        false,
+	}, flagged)
+
+	csv, err := s.ExportPayrollVarianceCSV(got)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(csv)), "\n")
+	// header + 6 components + joiner + leaver + 6 totals
+	assert.Len(t, lines, 15)
+}
+
+func Test_reportService_GetPayrollVariance_UnprocessedPeriod(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fromID, toID := uuid.New(), uuid.New()
+	mockAttendancePeriodRepo := mock_repository.NewMockIAttendancePeriodRepository(ctrl)
+	mockAttendancePeriodRepo.EXPECT().GetByID(fromID).Return(&models.AttendancePeriod{BaseModel: models.BaseModel{ID: fromID}}, nil)
+
+	s := NewReportService(&repository.Repositories{AttendancePeriod: mockAttendancePeriodRepo}, config.PayrollConfig{}, config.LedgerConfig{}, "IDR")
+	got, err := s.GetPayrollVariance(fromID, toID, 5)
+	assert.Error(t, err)
+	assert.Nil(t, got)
+}
+
+// A run awaiting approval may still be rejected, so it is not compared as actual payroll
+func Test_reportService_GetPayrollVariance_RunNotFinal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fromID, toID := uuid.New(), uuid.New()
+	mockAttendancePeriodRepo := mock_repository.NewMockIAttendancePeriodRepository(ctrl)
+	mockPayrollRepo := mock_repository.NewMockIPayrollRepository(ctrl)
+	mockAttendancePeriodRepo.EXPECT().GetByID(fromID).Return(&models.AttendancePeriod{BaseModel: models.BaseModel{ID: fromID}, IsProcessed: true}, nil)
+	mockAttendancePeriodRepo.EXPECT().GetByID(toID).Return(&models.AttendancePeriod{BaseModel: models.BaseModel{ID: toID}, IsProcessed: true}, nil)
+	mockPayrollRepo.EXPECT().GetRunByPeriodID(fromID).Return(&models.Payroll{Status: models.PayrollStatusApproved}, nil)
+	mockPayrollRepo.EXPECT().GetRunByPeriodID(toID).Return(&models.Payroll{Status: models.PayrollStatusPendingApproval}, nil)
+	mockPayrollRepo.EXPECT().GetAllPayrollItemsByPeriod(fromID).Return(nil, nil)
+
+	repos := &repository.Repositories{AttendancePeriod: mockAttendancePeriodRepo, Payroll: mockPayrollRepo}
+	s := NewReportService(repos, config.PayrollConfig{}, config.LedgerConfig{}, "IDR")
+	got, err := s.GetPayrollVariance(fromID, toID, 5)
+	assert.ErrorContains(t, err, "not final")
+	assert.Nil(t, got)
+}
+
+func Test_reportService_GetPayrollJournal(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	periodID := uuid.New()
+	period := &models.AttendancePeriod{
+		BaseModel:   models.BaseModel{ID: periodID},
+		StartDate:   time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:     time.Date(2024, 8, 31, 0, 0, 0, 0, time.UTC),
+		IsProcessed: true,
+	}
+
+	ledger := config.LedgerConfig{
+		Journal:           "PAY",
+		DefaultCostCenter: "UNASSIGNED",
+		Accounts: config.LedgerAccounts{
+			SalaryExpense:        "6100",
+			OvertimeExpense:      "6110",
+			ReimbursementExpense: "6120",
+			BPJSExpense:          "6130",
+			TaxPayable:           "2120",
+			BPJSPayable:          "2130",
+			NetSalaryPayable:     "2110",
+		},
+	}
+
+	items := []models.PayrollItem{
+		{User: models.User{Username: "employee1", CostCenter: "ENG"}, AttendanceAmount: 4000000.004, OvertimeAmount: 150000.004, TotalAmount: 4150000.008},
+		{User: models.User{Username: "employee2", CostCenter: "ENG"}, AttendanceAmount: 3000000, ReimbursementAmount: 250000, TotalAmount: 3250000},
+		{User: models.User{Username: "employee3"}, AttendanceAmount: 2000000, TotalAmount: 2000000},
+	}
+
+	tests := []struct {
+		name         string
+		status       string
+		items        []models.PayrollItem
+		byCostCenter bool
+		wantEntries  []domains.JournalEntry
+		wantCSVLine  string
+		wantErr      bool
+	}{
+		{
+			name:   "success",
+			status: models.PayrollStatusApproved,
+			items:  items,
+			wantEntries: []domains.JournalEntry{
+				{
+					Reference: "PAY-202408", Date: "2024-08-31", Description: "Payroll Aug 2024",
+					Lines: []domains.JournalLine{
+						{Account: "6100", Component: domains.LedgerSalaryExpense, Description: "Salary expense Aug 2024", Debit: 9000000},
+						{Account: "6110", Component: domains.LedgerOvertimeExpense, Description: "Overtime expense Aug 2024", Debit: 150000},
+						{Account: "6120", Component: domains.LedgerReimbursementExpense, Description: "Reimbursement expense Aug 2024", Debit: 250000},
+						{Account: "2110", Component: domains.LedgerNetSalaryPayable, Description: "Net salary payable Aug 2024", Credit: 9400000},
+					},
+					TotalDebit: 9400000, TotalCredit: 9400000,
+				},
+			},
+			wantCSVLine: "PAY,PAY-202408,2024-08-31,6110,overtime_expense,Overtime expense Aug 2024,,150000.00,0.00,IDR",
+		},
+		{
+			name:         "success - split by cost center",
+			status:       models.PayrollStatusApproved,
+			items:        items,
+			byCostCenter: true,
+			wantEntries: []domains.JournalEntry{
+				{
+					Reference: "PAY-202408-ENG", Date: "2024-08-31", Description: "Payroll Aug 2024", CostCenter: "ENG",
+					Lines: []domains.JournalLine{
+						{Account: "6100", Component: domains.LedgerSalaryExpense, Description: "Salary expense Aug 2024", CostCenter: "ENG", Debit: 7000000},
+						{Account: "6110", Component: domains.LedgerOvertimeExpense, Description: "Overtime expense Aug 2024", CostCenter: "ENG", Debit: 150000},
+						{Account: "6120", Component: domains.LedgerReimbursementExpense, Description: "Reimbursement expense Aug 2024", CostCenter: "ENG", Debit: 250000},
+						{Account: "2110", Component: domains.LedgerNetSalaryPayable, Description: "Net salary payable Aug 2024", CostCenter: "ENG", Credit: 7400000},
+					},
+					TotalDebit: 7400000, TotalCredit: 7400000,
+				},
+				{
+					Reference: "PAY-202408-UNASSIGNED", Date: "2024-08-31", Description: "Payroll Aug 2024", CostCenter: "UNASSIGNED",
+					Lines: []domains.JournalLine{
+						{Account: "6100", Component: domains.LedgerSalaryExpense, Description: "Salary expense Aug 2024", CostCenter: "UNASSIGNED", Debit: 2000000},
+						{Account: "2110", Component: domains.LedgerNetSalaryPayable, Description: "Net salary payable Aug 2024", CostCenter: "UNASSIGNED", Credit: 2000000},
+					},
+					TotalDebit: 2000000, TotalCredit: 2000000,
+				},
+			},
+			wantCSVLine: "PAY,PAY-202408-ENG,2024-08-31,6110,overtime_expense,Overtime expense Aug 2024,ENG,150000.00,0.00,IDR",
+		},
+		{
+			name:   "success - PPh 21 and BPJS withheld",
+			status: models.PayrollStatusApproved,
+			items: []models.PayrollItem{
+				{
+					User: models.User{Username: "employee1"}, AttendanceAmount: 8000000, ReimbursementAmount: 500000, TotalAmount: 7908422, TaxAmount: 126104,
+					BPJS: models.BPJSContributions{JHTEmployee: 240000, JPEmployee: 105474, HealthEmployee: 120000, JHTEmployer: 444000, JPEmployer: 210948, JKKEmployer: 28800, JKMEmployer: 36000, HealthEmployer: 480000},
+				},
+			},
+			wantEntries: []domains.JournalEntry{
+				{
+					Reference: "PAY-202408", Date: "2024-08-31", Description: "Payroll Aug 2024",
+					Lines: []domains.JournalLine{
+						{Account: "6100", Component: domains.LedgerSalaryExpense, Description: "Salary expense Aug 2024", Debit: 8000000},
+						{Account: "6120", Component: domains.LedgerReimbursementExpense, Description: "Reimbursement expense Aug 2024", Debit: 500000},
+						{Account: "6130", Component: domains.LedgerBPJSExpense, Description: "BPJS employer contributions Aug 2024", Debit: 1199748},
+						{Account: "2120", Component: domains.LedgerTaxPayable, Description: "PPh 21 payable Aug 2024", Credit: 126104},
+						{Account: "2130", Component: domains.LedgerBPJSPayable, Description: "BPJS payable Aug 2024", Credit: 1665222},
+						{Account: "2110", Component: domains.LedgerNetSalaryPayable, Description: "Net salary payable Aug 2024", Credit: 7908422},
+					},
+					TotalDebit: 9699748, TotalCredit: 9699748,
+				},
+			},
+			wantCSVLine: "PAY,PAY-202408,2024-08-31,2120,tax_payable,PPh 21 payable Aug 2024,,0.00,126104.00,IDR",
+		},
+		{
+			name:   "error - deductions without an account split",
+			status: models.PayrollStatusApproved,
+			items: []models.PayrollItem{
+				{User: models.User{Username: "employee1"}, AttendanceAmount: 4000000, TotalAmount: 3800000},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "error - payroll awaiting approval",
+			status:  models.PayrollStatusPendingApproval,
+			items:   items,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAttendancePeriodRepo := mock_repository.NewMockIAttendancePeriodRepository(ctrl)
+			mockPayrollRepo := mock_repository.NewMockIPayrollRepository(ctrl)
+
+			mockAttendancePeriodRepo.EXPECT().GetByID(periodID).Return(period, nil)
+			mockPayrollRepo.EXPECT().GetRunByPeriodID(periodID).Return(&models.Payroll{BaseModel: models.BaseModel{ID: uuid.New()}, Status: tt.status}, nil)
+			if tt.status == models.PayrollStatusApproved {
+				mockPayrollRepo.EXPECT().GetAllPayrollItemsByPeriod(periodID).Return(tt.items, nil)
+			}
+
+			repos := &repository.Repositories{AttendancePeriod: mockAttendancePeriodRepo, Payroll: mockPayrollRepo}
+			s := NewReportService(repos, config.PayrollConfig{}, ledger, "IDR")
+
+			journal, err := s.GetPayrollJournal(periodID, tt.byCostCenter)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, "PAY", journal.Journal)
+			assert.Equal(t, "IDR", journal.Currency)
+			assert.Equal(t, tt.wantEntries, journal.Entries)
+
+			csv, err := s.ExportPayrollJournalCSV(journal)
+			require.NoError(t, err)
+			lines := strings.Split(strings.TrimSpace(string(csv)), "\n")
+			assert.Equal(t, "journal,reference,date,account,component,description,cost_center,debit,credit,currency", lines[0])
+			assert.Contains(t, lines, tt.wantCSVLine)
+		})
+	}
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"payslip-system/internal/domains"
+	"payslip-system/internal/models"
+	"payslip-system/internal/rbac"
+	"payslip-system/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,31}$`)
+
+type roleService struct {
+	repos *repository.Repositories
+}
+
+func NewRoleService(repos *repository.Repositories) *roleService {
+	return &roleService{repos: repos}
+}
+
+func (s *roleService) ListPermissions() []rbac.Permission {
+	return rbac.Permissions
+}
+
+func (s *roleService) ListRoles() ([]domains.RoleSummary, error) {
+	roles, err := s.repos.Role.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]domains.RoleSummary, len(roles))
+	for i := range roles {
+		summaries[i] = roleSummary(&roles[i])
+	}
+	return summaries, nil
+}
+
+func (s *roleService) CreateRole(req domains.RoleRequest, adminID uuid.UUID, ipAddress, requestID string) (*domains.RoleSummary, error) {
+	name := strings.TrimSpace(req.Name)
+	if !roleNamePattern.MatchString(name) {
+		return nil, errors.New("role name must be 2 to 32 lowercase letters, digits or '_', starting with a letter")
+	}
+	if rbac.IsBuiltin(name) {
+		return nil, domains.ErrRoleExists
+	}
+	if existing, err := s.repos.Role.GetByNames([]string{name}); err != nil {
+		return nil, err
+	} else if len(existing) > 0 {
+		return nil, domains.ErrRoleExists
+	}
+
+	permissions, err := checkPermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &models.Role{
+		BaseModel: models.BaseModel{
+			ID:        uuid.New(),
+			CreatedBy: &adminID,
+			IPAddress: ipAddress,
+			RequestID: requestID,
+		},
+		Name:        name,
+		Description: strings.TrimSpace(req.Description),
+	}
+	role.Permissions = rolePermissions(role.ID, permissions)
+	if err := s.repos.Role.Create(role); err != nil {
+		return nil, fmt.Errorf("failed to create role: %w", err)
+	}
+
+	summary := roleSummary(role)
+	createAuditLog("roles", role.ID, "INSERT", nil, summary, domains.UserActor(adminID), ipAddress, requestID, s.repos)
+
+	return &summary, nil
+}
+
+// UpdateRole changes the description and permissions of a role; the name stays
+func (s *roleService) UpdateRole(roleID uuid.UUID, req domains.RoleRequest, adminID uuid.UUID, ipAddress, requestID string) (*domains.RoleSummary, error) {
+	role, err := s.getRole(roleID)
+	if err != nil {
+		return nil, err
+	}
+	if role.Builtin {
+		return nil, domains.ErrBuiltinRole
+	}
+
+	permissions, err := checkPermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	old := roleSummary(role)
+	role.Description = strings.TrimSpace(req.Description)
+	role.Permissions = rolePermissions(role.ID, permissions)
+	role.UpdatedBy = &adminID
+	role.IPAddress = ipAddress
+	role.RequestID = requestID
+	if err := s.repos.Role.Update(role); err != nil {
+		return nil, fmt.Errorf("failed to update role: %w", err)
+	}
+
+	summary := roleSummary(role)
+	createAuditLog("roles", role.ID, "UPDATE", old, summary, domains.UserActor(adminID), ipAddress, requestID, s.repos)
+
+	return &summary, nil
+}
+
+// DeleteRole deletes a role nobody holds
+func (s *roleService) DeleteRole(roleID, adminID uuid.UUID, ipAddress, requestID string) error {
+	role, err := s.getRole(roleID)
+	if err != nil {
+		return err
+	}
+	if role.Builtin {
+		return domains.ErrBuiltinRole
+	}
+
+	holders, err := s.repos.Role.CountHolders(role.ID)
+	if err != nil {
+		return err
+	}
+	if holders > 0 {
+		return domains.ErrRoleInUse
+	}
+
+	if err := s.repos.Role.Delete(role.ID); err != nil {
+		return fmt.Errorf("failed to delete role: %w", err)
+	}
+
+	createAuditLog("roles", role.ID, "DELETE", roleSummary(role), nil, domains.UserActor(adminID), ipAddress, requestID, s.repos)
+
+	return nil
+}
+
+func (s *roleService) GetUserRoles(userID uuid.UUID) (*domains.UserRoles, error) {
+	if _, err := s.repos.User.GetByID(userID); err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	roles, err := s.repos.Role.GetUserRoles(userID)
+	if err != nil {
+		return nil, err
+	}
+	return userRoles(userID, roles), nil
+}
+
+// SetUserRoles replaces the roles a user holds. Changes apply to the user's next request.
+func (s *roleService) SetUserRoles(userID uuid.UUID, names []string, adminID uuid.UUID, ipAddress, requestID string) (*domains.UserRoles, error) {
+	if _, err := s.repos.User.GetByID(userID); err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	names = uniqueSorted(names)
+	roles, err := s.repos.Role.GetByNames(names)
+	if err != nil {
+		return nil, err
+	}
+	if len(roles) != len(names) {
+		found := map[string]bool{}
+		for _, role := range roles {
+			found[role.Name] = true
+		}
+		for _, name := range names {
+			if !found[name] {
+				return nil, fmt.Errorf("unknown role %q", name)
+			}
+		}
+	}
+
+	current, err := s.repos.Role.GetUserRoles(userID)
+	if err != nil {
+		return nil, err
+	}
+	old := userRoles(userID, current)
+	updated := userRoles(userID, roles)
+
+	// Taking role management away from its last holder would leave nobody to give it back
+	if contains(old.Permissions, rbac.RoleManage) && !contains(updated.Permissions, rbac.RoleManage) {
+		managers, err := s.repos.Role.CountUsersWithPermission(rbac.RoleManage)
+		if err != nil {
+			return nil, err
+		}
+		if managers <= 1 {
+			return nil, domains.ErrLastRoleManager
+		}
+	}
+
+	roleIDs := make([]uuid.UUID, len(roles))
+	for i, role := range roles {
+		roleIDs[i] = role.ID
+	}
+	if err := s.repos.Role.SetUserRoles(userID, roleIDs, adminID, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to set roles: %w", err)
+	}
+
+	createAuditLog("user_roles", userID, "UPDATE", old, updated, domains.UserActor(adminID), ipAddress, requestID, s.repos)
+
+	return updated, nil
+}
+
+func (s *roleService) getRole(roleID uuid.UUID) (*models.Role, error) {
+	role, err := s.repos.Role.GetByID(roleID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domains.ErrRoleNotFound
+	}
+	return role, err
+}
+
+// checkPermissions rejects unknown permissions and returns the rest sorted, without duplicates
+func checkPermissions(permissions []string) ([]string, error) {
+	permissions = uniqueSorted(permissions)
+	if len(permissions) == 0 {
+		return nil, errors.New("a role needs at least one permission")
+	}
+	for _, p := range permissions {
+		if _, ok := rbac.Lookup(p); !ok {
+			return nil, fmt.Errorf("unknown permission %q", p)
+		}
+	}
+	return permissions, nil
+}
+
+func rolePermissions(roleID uuid.UUID, permissions []string) []models.RolePermission {
+	rows := make([]models.RolePermission, len(permissions))
+	for i, p := range permissions {
+		rows[i] = models.RolePermission{RoleID: roleID, Permission: p}
+	}
+	return rows
+}
+
+func roleSummary(role *models.Role) domains.RoleSummary {
+	summary := domains.RoleSummary{
+		ID:          role.ID,
+		Name:        role.Name,
+		Description: role.Description,
+		Builtin:     role.Builtin,
+		Permissions: []string{},
+	}
+	for _, p := range role.Permissions {
+		summary.Permissions = append(summary.Permissions, p.Permission)
+	}
+	sort.Strings(summary.Permissions)
+	return summary
+}
+
+// userRoles adds up the permissions of roles
+func userRoles(userID uuid.UUID, roles []models.Role) *domains.UserRoles {
+	var names, permissions []string
+	for _, role := range roles {
+		names = append(names, role.Name)
+		for _, p := range role.Permissions {
+			permissions = append(permissions, p.Permission)
+		}
+	}
+	return &domains.UserRoles{UserID: userID, Roles: uniqueSorted(names), Permissions: uniqueSorted(permissions)}
+}
+
+func uniqueSorted(values []string) []string {
+	seen := map[string]bool{}
+	unique := []string{}
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v != "" && !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"testing"
+
+	"payslip-system/internal/domains"
+	"payslip-system/internal/models"
+	"payslip-system/internal/rbac"
+	"payslip-system/internal/repository"
+	mock_repository "payslip-system/internal/repository/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func newRole(name string, builtin bool, permissions ...string) models.Role {
+	role := models.Role{BaseModel: models.BaseModel{ID: uuid.New()}, Name: name, Builtin: builtin}
+	role.Permissions = rolePermissions(role.ID, permissions)
+	return role
+}
+
+func Test_roleService_CreateRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name     string
+		req      domains.RoleRequest
+		existing bool
+		wantErr  string
+	}{
+		{name: "success", req: domains.RoleRequest{Name: "payroll_clerk", Permissions: []string{rbac.PayrollProcess, rbac.PayrollViewSummary, rbac.PayrollProcess}}},
+		{name: "invalid name", req: domains.RoleRequest{Name: "Payroll Clerk", Permissions: []string{rbac.PayrollProcess}}, wantErr: "role name must be"},
+		{name: "builtin name", req: domains.RoleRequest{Name: "finance", Permissions: []string{rbac.PayrollProcess}}, wantErr: domains.ErrRoleExists.Error()},
+		{name: "existing name", req: domains.RoleRequest{Name: "payroll_clerk", Permissions: []string{rbac.PayrollProcess}}, existing: true, wantErr: domains.ErrRoleExists.Error()},
+		{name: "unknown permission", req: domains.RoleRequest{Name: "payroll_clerk", Permissions: []string{"payroll:everything"}}, wantErr: `unknown permission "payroll:everything"`},
+		{name: "no permissions", req: domains.RoleRequest{Name: "payroll_clerk", Permissions: []string{}}, wantErr: "at least one permission"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adminID := uuid.New()
+
+			mockRoleRepo := mock_repository.NewMockIRoleRepository(ctrl)
+			mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+
+			if tt.name != "invalid name" && tt.name != "builtin name" {
+				var existing []models.Role
+				if tt.existing {
+					existing = []models.Role{newRole(tt.req.Name, false, rbac.ReportView)}
+				}
+				mockRoleRepo.EXPECT().GetByNames([]string{tt.req.Name}).Return(existing, nil)
+			}
+			if tt.wantErr == "" {
+				mockRoleRepo.EXPECT().Create(gomock.Any()).Return(nil)
+				mockAuditLogRepo.EXPECT().Create(gomock.Any()).Return(nil)
+			}
+
+			s := NewRoleService(&repository.Repositories{Role: mockRoleRepo, AuditLog: mockAuditLogRepo})
+
+			role, err := s.CreateRole(tt.req, adminID, "127.0.0.1", "req-123")
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "payroll_clerk", role.Name)
+			assert.False(t, role.Builtin)
+			assert.Equal(t, []string{rbac.PayrollProcess, rbac.PayrollViewSummary}, role.Permissions)
+		})
+	}
+}
+
+func Test_roleService_UpdateRole_Builtin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	finance := newRole("finance", true, rbac.PayrollApprove)
+	mockRoleRepo := mock_repository.NewMockIRoleRepository(ctrl)
+	mockRoleRepo.EXPECT().GetByID(finance.ID).Return(&finance, nil)
+	mockRoleRepo.EXPECT().GetByID(gomock.Not(finance.ID)).Return(nil, gorm.ErrRecordNotFound)
+
+	s := NewRoleService(&repository.Repositories{Role: mockRoleRepo})
+
+	req := domains.RoleRequest{Permissions: []string{rbac.RoleManage}}
+	_, err := s.UpdateRole(finance.ID, req, uuid.New(), "127.0.0.1", "req-123")
+	assert.ErrorIs(t, err, domains.ErrBuiltinRole)
+
+	_, err = s.UpdateRole(uuid.New(), req, uuid.New(), "127.0.0.1", "req-123")
+	assert.ErrorIs(t, err, domains.ErrRoleNotFound)
+}
+
+func Test_roleService_DeleteRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tests := []struct {
+		name    string
+		holders int64
+		wantErr error
+	}{
+		{name: "success"},
+		{name: "held by users", holders: 2, wantErr: domains.ErrRoleInUse},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			role := newRole("payroll_clerk", false, rbac.PayrollProcess)
+
+			mockRoleRepo := mock_repository.NewMockIRoleRepository(ctrl)
+			mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+			mockRoleRepo.EXPECT().GetByID(role.ID).Return(&role, nil)
+			mockRoleRepo.EXPECT().CountHolders(role.ID).Return(tt.holders, nil)
+			if tt.wantErr == nil {
+				mockRoleRepo.EXPECT().Delete(role.ID).Return(nil)
+				mockAuditLogRepo.EXPECT().Create(gomock.Any()).Return(nil)
+			}
+
+			s := NewRoleService(&repository.Repositories{Role: mockRoleRepo, AuditLog: mockAuditLogRepo})
+
+			err := s.DeleteRole(role.ID, uuid.New(), "127.0.0.1", "req-123")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_roleService_SetUserRoles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	admin := newRole("admin", true, builtinPermissions("admin")...)
+	employee := newRole("employee", true, builtinPermissions("employee")...)
+	finance := newRole("finance", true, builtinPermissions("finance")...)
+
+	tests := []struct {
+		name     string
+		current  []models.Role
+		roles    []string
+		found    []models.Role
+		managers int64
+		wantErr  string
+	}{
+		{name: "admin also paid as an employee", current: []models.Role{admin}, roles: []string{"employee", "admin", "admin"}, found: []models.Role{admin, employee}},
+		{name: "unknown role", current: []models.Role{employee}, roles: []string{"employee", "auditor"}, found: []models.Role{employee}, wantErr: `unknown role "auditor"`},
+		{name: "other admins remain", current: []models.Role{admin}, roles: []string{"finance"}, found: []models.Role{finance}, managers: 2},
+		{name: "last role manager", current: []models.Role{admin}, roles: []string{"finance"}, found: []models.Role{finance}, managers: 1, wantErr: domains.ErrLastRoleManager.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			adminID := uuid.New()
+
+			mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+			mockRoleRepo := mock_repository.NewMockIRoleRepository(ctrl)
+			mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+
+			mockUserRepo.EXPECT().GetByID(userID).Return(&models.User{BaseModel: models.BaseModel{ID: userID}, IsActive: true}, nil)
+			mockRoleRepo.EXPECT().GetByNames(uniqueSorted(tt.roles)).Return(tt.found, nil)
+			if len(tt.found) == len(uniqueSorted(tt.roles)) {
+				mockRoleRepo.EXPECT().GetUserRoles(userID).Return(tt.current, nil)
+			}
+			if tt.managers > 0 {
+				mockRoleRepo.EXPECT().CountUsersWithPermission(rbac.RoleManage).Return(tt.managers, nil)
+			}
+			if tt.wantErr == "" {
+				mockRoleRepo.EXPECT().SetUserRoles(userID, gomock.Len(len(tt.found)), adminID, gomock.Any()).Return(nil)
+				mockAuditLogRepo.EXPECT().Create(gomock.Any()).Return(nil)
+			}
+
+			repos := &repository.Repositories{User: mockUserRepo, Role: mockRoleRepo, AuditLog: mockAuditLogRepo}
+			s := NewRoleService(repos)
+
+			roles, err := s.SetUserRoles(userID, tt.roles, adminID, "127.0.0.1", "req-123")
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, uniqueSorted(tt.roles), roles.Roles)
+			for _, role := range tt.found {
+				for _, p := range role.Permissions {
+					assert.Contains(t, roles.Permissions, p.Permission)
+				}
+			}
+		})
+	}
+}
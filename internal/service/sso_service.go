@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"payslip-system/internal/config"
+	"payslip-system/internal/domains"
+	"payslip-system/internal/models"
+	"payslip-system/internal/oidc"
+	"payslip-system/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type ssoService struct {
+	repos    *repository.Repositories
+	cfg      config.OIDCConfig
+	provider *oidc.Provider // nil when single sign-on is not enabled
+	auth     *authService
+	stateTTL time.Duration
+
+	passwordLoginDisabled bool
+}
+
+func NewSSOService(repos *repository.Repositories, cfg config.AuthConfig, auth *authService) *ssoService {
+	s := &ssoService{
+		repos:                 repos,
+		cfg:                   cfg.OIDC,
+		auth:                  auth,
+		stateTTL:              time.Duration(cfg.OIDC.StateTTL) * time.Minute,
+		passwordLoginDisabled: cfg.PasswordLoginDisabled,
+	}
+	if cfg.OIDC.Enabled {
+		s.provider = oidc.NewProvider(cfg.OIDC)
+	}
+	return s
+}
+
+// BeginLogin starts a login at the identity provider. The PKCE verifier and nonce stay on the server with the
+// login; the state returned identifies it when the provider sends the user back.
+func (s *ssoService) BeginLogin(ipAddress string) (*domains.SSOLogin, error) {
+	if s.provider == nil {
+		return nil, domains.ErrSSODisabled
+	}
+
+	state, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+
+	authorizationURL, err := s.provider.AuthorizationURL(context.Background(), state, nonce, verifier)
+	if err != nil {
+		logrus.WithError(err).Error("Failed to start single sign-on")
+		return nil, domains.ErrSSOFailed
+	}
+
+	now := time.Now()
+	login := &models.SSOLogin{
+		ID:           uuid.New(),
+		StateHash:    hashRefreshToken(state),
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		ExpiresAt:    now.Add(s.stateTTL),
+		IPAddress:    ipAddress,
+		CreatedAt:    now,
+	}
+	if err := s.repos.SSO.CreateLogin(login); err != nil {
+		return nil, fmt.Errorf("failed to start single sign-on: %w", err)
+	}
+
+	return &domains.SSOLogin{AuthorizationURL: authorizationURL, State: state, ExpiresAt: login.ExpiresAt}, nil
+}
+
+// CompleteLogin redeems the code the identity provider returned for a login, finds the user the verified
+// identity belongs to and, with group role mapping, updates their roles. Like a password login, it ends with
+// an MFA challenge for users who need a second factor.
+func (s *ssoService) CompleteLogin(code, state, ipAddress, requestID string) (*models.User, *domains.MFAChallenge, error) {
+	if s.provider == nil {
+		return nil, nil, domains.ErrSSODisabled
+	}
+
+	login, err := s.repos.SSO.GetLoginByStateHash(hashRefreshToken(state))
+	if err != nil || login.UsedAt != nil || time.Now().After(login.ExpiresAt) {
+		return nil, nil, domains.ErrInvalidSSOLogin
+	}
+
+	// A state works once, even when the code turns out to be bad
+	now := time.Now()
+	completed, err := s.repos.SSO.MarkLoginUsed(login.ID, now)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to complete single sign-on: %w", err)
+	}
+	if !completed {
+		return nil, nil, domains.ErrInvalidSSOLogin
+	}
+
+	identity, err := s.provider.Exchange(context.Background(), code, login.CodeVerifier, login.Nonce)
+	if err != nil {
+		logrus.WithError(err).WithField("request_id", requestID).Warn("Single sign-on refused")
+		return nil, nil, domains.ErrSSOFailed
+	}
+
+	user, err := s.link(identity, ipAddress, requestID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(s.cfg.GroupRoles) > 0 {
+		if err := s.syncRoles(user, identity.Groups, ipAddress, requestID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Passwords cannot be used, so there is none to change
+	if s.passwordLoginDisabled && user.MustChangePassword {
+		user.MustChangePassword = false
+		if err := s.repos.User.Update(user); err != nil {
+			return nil, nil, fmt.Errorf("failed to update user: %w", err)
+		}
+	}
+
+	challenge, err := s.auth.loginChallenge(user, now)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, challenge, nil
+}
+
+// link finds the user of an identity: the user linked to its subject, or else the user with its employee ID
+// or verified email address, who is linked to the subject from then on. A user linked to another subject is
+// not taken over.
+func (s *ssoService) link(identity *oidc.Identity, ipAddress, requestID string) (*models.User, error) {
+	user, err := s.repos.User.GetByOIDCSubject(identity.Subject)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	matchedBy := "employee_id"
+	user = nil
+	if s.cfg.EmployeeIDClaim != "" && identity.EmployeeID != "" {
+		user, err = s.repos.User.GetByEmployeeID(identity.EmployeeID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+	if user == nil && identity.Email != "" && identity.EmailVerified {
+		users, err := s.repos.User.GetByEmail(identity.Email)
+		if err != nil {
+			return nil, err
+		}
+		// Email addresses are not unique; a shared one identifies nobody
+		if len(users) == 1 {
+			user = &users[0]
+		}
+		matchedBy = "email"
+	}
+
+	logger := logrus.WithFields(logrus.Fields{"subject": identity.Subject, "request_id": requestID})
+	if user == nil {
+		logger.Warn("Single sign-on identity matches no user")
+		return nil, domains.ErrSSONoUser
+	}
+	if user.OIDCSubject != nil {
+		logger.WithField("user_id", user.ID).Warn("Single sign-on identity matches a user linked to another subject")
+		return nil, domains.ErrSSONoUser
+	}
+
+	subject := identity.Subject
+	user.OIDCSubject = &subject
+	user.IPAddress = ipAddress
+	user.RequestID = requestID
+	if err := s.repos.User.Update(user); err != nil {
+		return nil, fmt.Errorf("failed to link user: %w", err)
+	}
+
+	createAuditLog("users", user.ID, "UPDATE", nil, map[string]string{"oidc_subject": subject, "matched_by": matchedBy}, domains.UserActor(user.ID), ipAddress, requestID, s.repos)
+
+	return user, nil
+}
+
+// syncRoles gives the user the roles mapped to their groups, refusing users in none of the mapped groups
+func (s *ssoService) syncRoles(user *models.User, groups []string, ipAddress, requestID string) error {
+	var names []string
+	for _, mapping := range s.cfg.GroupRoles {
+		if contains(groups, mapping.Group) {
+			names = append(names, mapping.Roles...)
+		}
+	}
+	names = uniqueSorted(names)
+	if len(names) == 0 {
+		return domains.ErrSSONoRole
+	}
+
+	roles, err := s.repos.Role.GetByNames(names)
+	if err != nil {
+		return err
+	}
+	if len(roles) != len(names) {
+		return fmt.Errorf("group role mapping names unknown roles: %s", strings.Join(names, ", "))
+	}
+
+	current, err := s.repos.Role.GetUserRoles(user.ID)
+	if err != nil {
+		return err
+	}
+	old := userRoles(user.ID, current)
+	updated := userRoles(user.ID, roles)
+	if reflect.DeepEqual(old.Roles, updated.Roles) {
+		return nil
+	}
+
+	roleIDs := make([]uuid.UUID, len(roles))
+	for i, role := range roles {
+		roleIDs[i] = role.ID
+	}
+	if err := s.repos.Role.SetUserRoles(user.ID, roleIDs, user.ID, time.Now()); err != nil {
+		return fmt.Errorf("failed to set roles: %w", err)
+	}
+
+	createAuditLog("user_roles", user.ID, "UPDATE", old, map[string]interface{}{
+		"roles":       updated.Roles,
+		"permissions": updated.Permissions,
+		"groups":      groups,
+	}, domains.UserActor(user.ID), ipAddress, requestID, s.repos)
+
+	return nil
+}
+
+// randomToken is 32 random bytes, base64url encoded
+func randomToken() (string, error) {
+	random := make([]byte, 32)
+	if _, err := rand.Read(random); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(random), nil
+}
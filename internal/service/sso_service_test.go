@@ -0,0 +1,237 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"payslip-system/internal/config"
+	"payslip-system/internal/domains"
+	"payslip-system/internal/models"
+	"payslip-system/internal/oidc/oidctest"
+	"payslip-system/internal/rbac"
+	"payslip-system/internal/repository"
+	mock_repository "payslip-system/internal/repository/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// newTestOIDCConfig is single sign-on against a local mock provider
+func newTestOIDCConfig(t *testing.T) (*oidctest.Server, config.AuthConfig) {
+	server, err := oidctest.NewServer("payroll", "s3cret", "https://payroll.example.com/sso/callback")
+	require.NoError(t, err)
+	t.Cleanup(server.Close)
+
+	return server, config.AuthConfig{
+		MFA: testMFAConfig,
+		OIDC: config.OIDCConfig{
+			Enabled:         true,
+			IssuerURL:       server.URL,
+			ClientID:        server.ClientID,
+			ClientSecret:    server.ClientSecret,
+			RedirectURL:     server.RedirectURL,
+			EmailClaim:      "email",
+			EmployeeIDClaim: "employee_id",
+			GroupsClaim:     "groups",
+			GroupRoles: []config.OIDCGroupRoles{
+				{Group: "staff", Roles: []string{rbac.RoleEmployee}},
+				{Group: "payroll-admins", Roles: []string{rbac.RoleAdmin}},
+			},
+			StateTTL: 10,
+			Timeout:  5,
+		},
+	}
+}
+
+func builtinRole(name string) models.Role {
+	role := models.Role{BaseModel: models.BaseModel{ID: uuid.New()}, Name: name, Builtin: true}
+	for _, permission := range builtinPermissions(name) {
+		role.Permissions = append(role.Permissions, models.RolePermission{RoleID: role.ID, Permission: permission})
+	}
+	return role
+}
+
+func Test_ssoService_CompleteLogin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	employeeRole := builtinRole(rbac.RoleEmployee)
+	adminRole := builtinRole(rbac.RoleAdmin)
+	otherSubject := "idp-other"
+
+	tests := []struct {
+		name   string
+		claims map[string]interface{}
+		// how the user is found: by subject, by employee ID or by email
+		bySubject    bool
+		byEmployeeID bool
+		byEmail      int // users sharing the email
+		linkedTo     *string
+		currentRoles []models.Role
+		wantAdmin    bool // the groups add the admin role
+		wantErr      error
+		wantLink     bool
+		wantMFA      bool
+	}{
+		{name: "linked user", claims: map[string]interface{}{"groups": []string{"staff"}}, bySubject: true, currentRoles: []models.Role{employeeRole}},
+		{name: "links by employee ID", claims: map[string]interface{}{"employee_id": "E-1001", "groups": []string{"staff"}}, byEmployeeID: true, currentRoles: []models.Role{employeeRole}, wantLink: true},
+		{name: "links by verified email", claims: map[string]interface{}{"email": "alice@example.com", "email_verified": true, "groups": []string{"staff"}}, byEmail: 1, currentRoles: []models.Role{employeeRole}, wantLink: true},
+		{name: "unverified email is not matched", claims: map[string]interface{}{"email": "alice@example.com", "email_verified": false}, wantErr: domains.ErrSSONoUser},
+		{name: "email without email_verified is not matched", claims: map[string]interface{}{"email": "alice@example.com"}, wantErr: domains.ErrSSONoUser},
+		{name: "shared email is not matched", claims: map[string]interface{}{"email": "team@example.com", "email_verified": true}, byEmail: 2, wantErr: domains.ErrSSONoUser},
+		{name: "user linked to another subject", claims: map[string]interface{}{"employee_id": "E-1001"}, byEmployeeID: true, linkedTo: &otherSubject, wantErr: domains.ErrSSONoUser},
+		{name: "no mapped group", claims: map[string]interface{}{"groups": []string{"contractors"}}, bySubject: true, wantErr: domains.ErrSSONoRole},
+		{name: "roles follow groups", claims: map[string]interface{}{"groups": []string{"staff", "payroll-admins"}}, bySubject: true, currentRoles: []models.Role{employeeRole}, wantAdmin: true, wantMFA: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, cfg := newTestOIDCConfig(t)
+
+			user := &models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "alice", IsActive: true, OIDCSubject: tt.linkedTo}
+			claims := map[string]interface{}{"sub": "idp-42"}
+			for name, value := range tt.claims {
+				claims[name] = value
+			}
+
+			mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+			mockSSORepo := mock_repository.NewMockISSORepository(ctrl)
+			mockRoleRepo := mock_repository.NewMockIRoleRepository(ctrl)
+			mockMFARepo := mock_repository.NewMockIMFARepository(ctrl)
+			mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+
+			var stored *models.SSOLogin
+			mockSSORepo.EXPECT().CreateLogin(gomock.Any()).DoAndReturn(func(login *models.SSOLogin) error {
+				stored = login
+				return nil
+			})
+			mockSSORepo.EXPECT().GetLoginByStateHash(gomock.Any()).DoAndReturn(func(hash string) (*models.SSOLogin, error) {
+				require.Equal(t, stored.StateHash, hash)
+				return stored, nil
+			})
+			mockSSORepo.EXPECT().MarkLoginUsed(gomock.Any(), gomock.Any()).Return(true, nil)
+
+			if tt.bySubject {
+				subject := "idp-42"
+				user.OIDCSubject = &subject
+				mockUserRepo.EXPECT().GetByOIDCSubject("idp-42").Return(user, nil)
+			} else {
+				mockUserRepo.EXPECT().GetByOIDCSubject("idp-42").Return(nil, gorm.ErrRecordNotFound)
+			}
+			if tt.byEmployeeID {
+				mockUserRepo.EXPECT().GetByEmployeeID("E-1001").Return(user, nil)
+			}
+			if tt.byEmail > 0 {
+				users := []models.User{*user}
+				for i := 1; i < tt.byEmail; i++ {
+					users = append(users, models.User{BaseModel: models.BaseModel{ID: uuid.New()}, IsActive: true})
+				}
+				mockUserRepo.EXPECT().GetByEmail(claims["email"]).Return(users, nil)
+			}
+			if tt.wantLink {
+				mockUserRepo.EXPECT().Update(gomock.Any()).DoAndReturn(func(updated *models.User) error {
+					require.NotNil(t, updated.OIDCSubject)
+					assert.Equal(t, "idp-42", *updated.OIDCSubject)
+					return nil
+				})
+				mockAuditLogRepo.EXPECT().Create(gomock.Any()).Return(nil)
+			}
+
+			if tt.currentRoles != nil {
+				mapped := []models.Role{employeeRole}
+				if tt.wantAdmin {
+					mapped = []models.Role{adminRole, employeeRole}
+				}
+				mockRoleRepo.EXPECT().GetByNames(gomock.Any()).Return(mapped, nil)
+				mockRoleRepo.EXPECT().GetUserRoles(user.ID).Return(tt.currentRoles, nil)
+
+				permissions := builtinPermissions(rbac.RoleEmployee)
+				if tt.wantAdmin {
+					mockRoleRepo.EXPECT().SetUserRoles(user.ID, []uuid.UUID{adminRole.ID, employeeRole.ID}, user.ID, gomock.Any()).Return(nil)
+					mockAuditLogRepo.EXPECT().Create(gomock.Any()).Return(nil)
+					permissions = builtinPermissions(rbac.RoleAdmin)
+				}
+				mockRoleRepo.EXPECT().GetUserPermissions(user.ID).Return(permissions, nil)
+			}
+			if tt.wantMFA {
+				mockMFARepo.EXPECT().CreateChallenge(gomock.Any()).Return(nil)
+			}
+
+			repos := &repository.Repositories{User: mockUserRepo, SSO: mockSSORepo, Role: mockRoleRepo, MFA: mockMFARepo, AuditLog: mockAuditLogRepo}
+			auth := NewAuthService(repos, cfg, newTestKeySet(t), NewMFAService(repos, testMFAConfig))
+			s := NewSSOService(repos, cfg, auth)
+
+			login, err := s.BeginLogin("127.0.0.1")
+			require.NoError(t, err)
+			assert.Equal(t, hashRefreshToken(login.State), stored.StateHash)
+			assert.WithinDuration(t, time.Now().Add(10*time.Minute), login.ExpiresAt, time.Minute)
+
+			code, state, err := server.Authorize(login.AuthorizationURL, claims)
+			require.NoError(t, err)
+			assert.Equal(t, login.State, state)
+
+			loggedIn, challenge, err := s.CompleteLogin(code, state, "127.0.0.1", "req-123")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, user.ID, loggedIn.ID)
+			if tt.wantMFA {
+				require.NotNil(t, challenge)
+				assert.True(t, challenge.EnrollmentRequired)
+			} else {
+				assert.Nil(t, challenge)
+			}
+		})
+	}
+}
+
+func Test_ssoService_CompleteLogin_InvalidState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	_, cfg := newTestOIDCConfig(t)
+	used := time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name     string
+		login    *models.SSOLogin
+		raceLost bool
+	}{
+		{name: "unknown state"},
+		{name: "expired", login: &models.SSOLogin{ID: uuid.New(), ExpiresAt: time.Now().Add(-time.Minute)}},
+		{name: "already completed", login: &models.SSOLogin{ID: uuid.New(), ExpiresAt: time.Now().Add(time.Minute), UsedAt: &used}},
+		{name: "completed concurrently", login: &models.SSOLogin{ID: uuid.New(), ExpiresAt: time.Now().Add(time.Minute)}, raceLost: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSSORepo := mock_repository.NewMockISSORepository(ctrl)
+			if tt.login != nil {
+				mockSSORepo.EXPECT().GetLoginByStateHash(hashRefreshToken("state")).Return(tt.login, nil)
+			} else {
+				mockSSORepo.EXPECT().GetLoginByStateHash(hashRefreshToken("state")).Return(nil, gorm.ErrRecordNotFound)
+			}
+			if tt.raceLost {
+				mockSSORepo.EXPECT().MarkLoginUsed(tt.login.ID, gomock.Any()).Return(false, nil)
+			}
+
+			s := NewSSOService(&repository.Repositories{SSO: mockSSORepo}, cfg, nil)
+			_, _, err := s.CompleteLogin("code", "state", "127.0.0.1", "req-123")
+			assert.ErrorIs(t, err, domains.ErrInvalidSSOLogin)
+		})
+	}
+}
+
+func Test_ssoService_Disabled(t *testing.T) {
+	s := NewSSOService(&repository.Repositories{}, config.AuthConfig{}, nil)
+
+	_, err := s.BeginLogin("127.0.0.1")
+	assert.ErrorIs(t, err, domains.ErrSSODisabled)
+	_, _, err = s.CompleteLogin("code", "state", "127.0.0.1", "req-123")
+	assert.ErrorIs(t, err, domains.ErrSSODisabled)
+}
@@ -0,0 +1,310 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"payslip-system/internal/config"
+	"payslip-system/internal/domains"
+	"payslip-system/internal/models"
+	"payslip-system/internal/pdf"
+	"payslip-system/internal/repository"
+	"payslip-system/internal/tax"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// The 1721-A1 is an Indonesian form, so its amounts are always formatted the Indonesian way
+const taxCertificateLanguage = "id"
+
+type taxService struct {
+	repos     *repository.Repositories
+	cfg       config.TaxConfig
+	currency  string
+	languages domains.ILanguageService
+	renderer  *pdf.TaxCertificateRenderer
+}
+
+func NewTaxService(repos *repository.Repositories, cfg config.TaxConfig, payslip config.PayslipConfig, languages domains.ILanguageService) *taxService {
+	return &taxService{
+		repos:     repos,
+		cfg:       cfg,
+		currency:  payslip.Currency,
+		languages: languages,
+		renderer:  pdf.NewTaxCertificateRenderer(payslip),
+	}
+}
+
+// SetTaxProfile records the NIK, NPWP and PTKP status an employee's PPh 21 is withheld and reported with
+func (s *taxService) SetTaxProfile(userID uuid.UUID, profile domains.TaxProfile, actor domains.Actor, ipAddress, requestID string) (*models.User, error) {
+	nik := strings.TrimSpace(profile.NIK)
+	if nik != "" && !tax.ValidNIK(nik) {
+		return nil, errors.New("NIK must be 16 digits")
+	}
+
+	// NPWPs are usually written as 01.234.567.8-901.000
+	npwp := strings.NewReplacer(".", "", "-", "", " ", "").Replace(profile.NPWP)
+	if npwp != "" && !tax.ValidNPWP(npwp) {
+		return nil, errors.New("NPWP must be 15 or 16 digits")
+	}
+
+	status := strings.ToUpper(strings.TrimSpace(profile.PTKPStatus))
+	if !tax.ValidPTKPStatus(status) {
+		return nil, errors.New("PTKP status must be TK/0 to TK/3 or K/0 to K/3")
+	}
+
+	user, err := s.repos.User.GetByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	old := domains.TaxProfile{NIK: user.NIK, NPWP: user.NPWP, PTKPStatus: user.PTKPStatus}
+
+	user.NIK = nik
+	user.NPWP = npwp
+	user.PTKPStatus = status
+	user.UpdatedBy = actor.UserID
+	if err := s.repos.User.Update(user); err != nil {
+		return nil, err
+	}
+
+	updated := domains.TaxProfile{NIK: user.NIK, NPWP: user.NPWP, PTKPStatus: user.PTKPStatus}
+	createAuditLog("users", user.ID, "UPDATE", old, updated, actor, ipAddress, requestID, s.repos)
+
+	return user, nil
+}
+
+// GetTaxCertificate returns the 1721-A1 of one employee for a tax year, or the year to date while it is running
+func (s *taxService) GetTaxCertificate(userID uuid.UUID, year int) (*domains.TaxCertificate, error) {
+	certificates, err := s.aggregateCertificates(year, &userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(certificates) == 0 {
+		return nil, domains.ErrTaxCertificateNotFound
+	}
+	return &certificates[0], nil
+}
+
+// GetTaxCertificates returns the 1721-A1 of every employee paid in a tax year, in username order
+func (s *taxService) GetTaxCertificates(year int) ([]domains.TaxCertificate, error) {
+	return s.aggregateCertificates(year, nil)
+}
+
+// aggregateCertificates aggregates the final payroll runs of a tax year into one 1721-A1 per employee paid in
+// it, or only the employee given. A period counts towards the year its start date falls in; runs awaiting
+// approval do not count.
+func (s *taxService) aggregateCertificates(year int, userID *uuid.UUID) ([]domains.TaxCertificate, error) {
+	if year < 2000 || year > time.Now().Year() {
+		return nil, errors.New("invalid tax year")
+	}
+
+	periods, err := s.repos.AttendancePeriod.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attendance periods: %w", err)
+	}
+	sort.Slice(periods, func(i, j int) bool {
+		return periods[i].StartDate.Before(periods[j].StartDate)
+	})
+
+	byUser := map[uuid.UUID]*domains.TaxCertificate{}
+	for _, period := range periods {
+		if !period.IsProcessed || period.StartDate.Year() != year {
+			continue
+		}
+
+		payroll, err := s.repos.Payroll.GetRunByPeriodID(period.ID)
+		if err != nil {
+			return nil, fmt.Errorf("payroll not found: %w", err)
+		}
+		if !payroll.IsFinal() {
+			continue
+		}
+
+		items, err := s.periodItems(period.ID, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get payroll items: %w", err)
+		}
+
+		month := int(period.StartDate.Month())
+		for _, item := range items {
+			certificate, ok := byUser[item.UserID]
+			if !ok {
+				certificate = &domains.TaxCertificate{TaxYear: year, FirstMonth: month, UserID: item.UserID, Months: []domains.TaxCertificateMonth{}}
+				byUser[item.UserID] = certificate
+			}
+
+			// The latest profile is the one reported
+			certificate.Username = item.User.Username
+			certificate.NIK = item.User.NIK
+			certificate.NPWP = item.User.NPWP
+			certificate.PTKPStatus = item.User.PTKPStatus
+			certificate.LastMonth = month
+
+			// Reimbursements refund expenses and are not income
+			gross := item.AttendanceAmount + item.OvertimeAmount + item.BPJS.TaxableBenefits()
+			certificate.Salary += item.AttendanceAmount
+			certificate.Overtime += item.OvertimeAmount
+			certificate.InsurancePremiums += item.BPJS.TaxableBenefits()
+			certificate.PensionContributions += item.BPJS.PensionContributions()
+			certificate.TaxWithheld += item.TaxAmount
+			certificate.Months = append(certificate.Months, domains.TaxCertificateMonth{
+				Month:         month,
+				PeriodID:      period.ID,
+				PayrollItemID: item.ID,
+				GrossIncome:   math.Round(gross),
+				TaxWithheld:   item.TaxAmount,
+			})
+		}
+	}
+
+	certificates := make([]domains.TaxCertificate, 0, len(byUser))
+	for _, certificate := range byUser {
+		certificates = append(certificates, *certificate)
+	}
+	sort.Slice(certificates, func(i, j int) bool {
+		return certificates[i].Username < certificates[j].Username
+	})
+
+	for i := range certificates {
+		certificate := &certificates[i]
+		if err := s.issue(certificate); err != nil {
+			return nil, err
+		}
+		certificate.Currency = s.currency
+		certificate.EmployerName = s.cfg.EmployerName
+		certificate.EmployerNPWP = s.cfg.EmployerNPWP
+		certificate.SignerName = s.cfg.SignerName
+		certificate.SignerNPWP = s.cfg.SignerNPWP
+		s.computeTax(certificate)
+	}
+
+	return certificates, nil
+}
+
+// periodItems returns the items of a period's run, or only the employee's
+func (s *taxService) periodItems(periodID uuid.UUID, userID *uuid.UUID) ([]models.PayrollItem, error) {
+	if userID == nil {
+		return s.repos.Payroll.GetAllPayrollItemsByPeriod(periodID)
+	}
+
+	item, err := s.repos.Payroll.GetPayrollItemsByPeriodAndUser(periodID, *userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []models.PayrollItem{*item}, nil
+}
+
+// issue numbers the certificate of a completed tax year, keeping the number and date it was first issued
+// under. Year to date certificates are statements, without a number, as of now.
+func (s *taxService) issue(certificate *domains.TaxCertificate) error {
+	now := time.Now()
+	if certificate.TaxYear >= now.Year() {
+		certificate.IssuedAt = now
+		return nil
+	}
+
+	issue, err := s.repos.TaxCertificate.Issue(certificate.UserID, certificate.TaxYear, certificate.LastMonth, now)
+	if err != nil {
+		return fmt.Errorf("failed to issue tax certificate: %w", err)
+	}
+	certificate.Number = fmt.Sprintf("1.1-%02d.%02d-%07d", issue.Month, issue.TaxYear%100, issue.Sequence)
+	certificate.IssuedAt = issue.IssuedAt
+	return nil
+}
+
+// computeTax fills in the deductions and the annual PPh 21 from the aggregated income. Income of a partial
+// year is not annualized, as for employees who joined or left during the year.
+func (s *taxService) computeTax(certificate *domains.TaxCertificate) {
+	if certificate.PTKPStatus == "" {
+		certificate.PTKPStatus = tax.DefaultPTKPStatus
+	}
+
+	months := map[int]bool{}
+	for _, month := range certificate.Months {
+		months[month.Month] = true
+	}
+
+	certificate.Salary = math.Round(certificate.Salary)
+	certificate.Overtime = math.Round(certificate.Overtime)
+	certificate.InsurancePremiums = math.Round(certificate.InsurancePremiums)
+	certificate.GrossIncome = certificate.Salary + certificate.Overtime + certificate.InsurancePremiums
+
+	certificate.PensionContributions = math.Round(certificate.PensionContributions)
+	certificate.OccupationalCost = tax.OccupationalCost(certificate.GrossIncome, len(months))
+	certificate.TotalDeductions = certificate.OccupationalCost + certificate.PensionContributions
+
+	certificate.NetIncome = certificate.GrossIncome - certificate.TotalDeductions
+	certificate.PTKP = tax.PTKP(certificate.PTKPStatus)
+	certificate.TaxableIncome = tax.TaxableIncome(certificate.NetIncome, certificate.PTKP)
+	certificate.TaxDue = tax.IncomeTax(certificate.TaxableIncome)
+	if certificate.NPWP == "" && certificate.NIK == "" {
+		certificate.TaxDue = math.Round(certificate.TaxDue * tax.NoTaxIDSurcharge)
+	}
+
+	certificate.TaxWithheld = math.Round(certificate.TaxWithheld)
+	certificate.TaxBalance = certificate.TaxDue - certificate.TaxWithheld
+}
+
+// RenderCertificatePDF draws a 1721-A1 certificate
+func (s *taxService) RenderCertificatePDF(certificate *domains.TaxCertificate) ([]byte, error) {
+	return s.renderer.Render(certificate, s.languages.Translator(taxCertificateLanguage))
+}
+
+// ArchiveTaxCertificates bundles the PDF of every certificate with all of them as structured data in one zip file
+func (s *taxService) ArchiveTaxCertificates(certificates []domains.TaxCertificate) ([]byte, error) {
+	if len(certificates) == 0 {
+		return nil, domains.ErrTaxCertificateNotFound
+	}
+	year := certificates[0].TaxYear
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for i := range certificates {
+		data, err := s.RenderCertificatePDF(&certificates[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to render certificate of %s: %w", certificates[i].Username, err)
+		}
+		if err := writeZipFile(archive, taxCertificateFilename(&certificates[i], "pdf"), data); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := json.MarshalIndent(certificates, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeZipFile(archive, fmt.Sprintf("1721-A1-%d.json", year), data); err != nil {
+		return nil, err
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// taxCertificateFilename names the downloaded certificate of one employee
+func taxCertificateFilename(certificate *domains.TaxCertificate, extension string) string {
+	return fmt.Sprintf("1721-A1-%d-%s.%s", certificate.TaxYear, certificate.Username, extension)
+}
+
+func writeZipFile(archive *zip.Writer, name string, data []byte) error {
+	file, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(data)
+	return err
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+	"time"
+
+	"payslip-system/internal/config"
+	"payslip-system/internal/domains"
+	mock_domains "payslip-system/internal/domains/mocks"
+	"payslip-system/internal/i18n"
+	"payslip-system/internal/models"
+	"payslip-system/internal/repository"
+	mock_repository "payslip-system/internal/repository/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func Test_taxService_GetTaxCertificates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	month := func(year int, m time.Month) models.AttendancePeriod {
+		return models.AttendancePeriod{
+			BaseModel:   models.BaseModel{ID: uuid.New()},
+			StartDate:   time.Date(year, m, 1, 0, 0, 0, 0, time.UTC),
+			EndDate:     time.Date(year, m+1, 0, 0, 0, 0, 0, time.UTC),
+			IsProcessed: true,
+		}
+	}
+	january, february, march := month(2024, time.January), month(2024, time.February), month(2024, time.March)
+	december := month(2023, time.December)
+	april := month(2024, time.April)
+	april.IsProcessed = false
+
+	budi := models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "employee1", NPWP: "012345678901000", PTKPStatus: "K/1"}
+	siti := models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "employee2"}
+	withheld := func() models.PayrollItem {
+		return models.PayrollItem{
+			BaseModel: models.BaseModel{ID: uuid.New()}, UserID: budi.ID, User: budi,
+			AttendanceAmount: 8000000, ReimbursementAmount: 500000, TotalAmount: 7908422, TaxAmount: 126104,
+			BPJS: models.BPJSContributions{JHTEmployee: 240000, JPEmployee: 105474, HealthEmployee: 120000, JHTEmployer: 444000, JPEmployer: 210948, JKKEmployer: 28800, JKMEmployer: 36000, HealthEmployer: 480000},
+		}
+	}
+
+	mockPeriodRepo := mock_repository.NewMockIAttendancePeriodRepository(ctrl)
+	mockPayrollRepo := mock_repository.NewMockIPayrollRepository(ctrl)
+	mockLanguages := mock_domains.NewMockILanguageService(ctrl)
+
+	mockPeriodRepo.EXPECT().GetAll().Return([]models.AttendancePeriod{march, december, january, april, february}, nil).AnyTimes()
+	approved := &models.Payroll{Status: models.PayrollStatusApproved}
+	mockPayrollRepo.EXPECT().GetRunByPeriodID(january.ID).Return(approved, nil).AnyTimes()
+	mockPayrollRepo.EXPECT().GetRunByPeriodID(february.ID).Return(&models.Payroll{Status: models.PayrollStatusPendingApproval}, nil).AnyTimes()
+	mockPayrollRepo.EXPECT().GetRunByPeriodID(march.ID).Return(approved, nil).AnyTimes()
+	mockPayrollRepo.EXPECT().GetAllPayrollItemsByPeriod(january.ID).Return([]models.PayrollItem{
+		withheld(),
+		{BaseModel: models.BaseModel{ID: uuid.New()}, UserID: siti.ID, User: siti, AttendanceAmount: 60000000, TotalAmount: 60000000},
+	}, nil).AnyTimes()
+	mockPayrollRepo.EXPECT().GetAllPayrollItemsByPeriod(march.ID).Return([]models.PayrollItem{withheld()}, nil).AnyTimes()
+	sitiItem := models.PayrollItem{BaseModel: models.BaseModel{ID: uuid.New()}, UserID: siti.ID, User: siti, AttendanceAmount: 60000000, TotalAmount: 60000000}
+	mockPayrollRepo.EXPECT().GetPayrollItemsByPeriodAndUser(january.ID, siti.ID).Return(&sitiItem, nil).AnyTimes()
+	mockPayrollRepo.EXPECT().GetPayrollItemsByPeriodAndUser(gomock.Any(), gomock.Any()).Return(nil, gorm.ErrRecordNotFound).AnyTimes()
+	mockLanguages.EXPECT().Translator("id").Return(i18n.NewRegistry("en", nil).Translator("id")).AnyTimes()
+
+	// Certificates keep the number and date they were first issued under
+	issued := map[uuid.UUID]*models.TaxCertificateIssue{}
+	mockTaxCertificateRepo := mock_repository.NewMockITaxCertificateRepository(ctrl)
+	mockTaxCertificateRepo.EXPECT().Issue(gomock.Any(), 2024, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(userID uuid.UUID, year, month int, at time.Time) (*models.TaxCertificateIssue, error) {
+			if issue, ok := issued[userID]; ok {
+				return issue, nil
+			}
+			issued[userID] = &models.TaxCertificateIssue{UserID: userID, TaxYear: year, Sequence: len(issued) + 1, Month: month, IssuedAt: at}
+			return issued[userID], nil
+		}).AnyTimes()
+
+	repos := &repository.Repositories{AttendancePeriod: mockPeriodRepo, Payroll: mockPayrollRepo, TaxCertificate: mockTaxCertificateRepo}
+	taxCfg := config.TaxConfig{EmployerName: "PT Mini Payroll", EmployerNPWP: "0123456789012000", SignerName: "Finance Manager"}
+	s := NewTaxService(repos, taxCfg, config.PayslipConfig{PaperSize: "A4", Currency: "IDR"}, mockLanguages)
+
+	t.Run("success", func(t *testing.T) {
+		certificates, err := s.GetTaxCertificates(2024)
+		require.NoError(t, err)
+		require.Len(t, certificates, 2)
+
+		first := certificates[0]
+		assert.Equal(t, "1.1-03.24-0000001", first.Number)
+		assert.Equal(t, "employee1", first.Username)
+		assert.Equal(t, "PT Mini Payroll", first.EmployerName)
+		assert.Equal(t, [2]int{1, 3}, [2]int{first.FirstMonth, first.LastMonth})
+		assert.Len(t, first.Months, 2)
+		assert.Equal(t, float64(16000000), first.Salary)
+		assert.Equal(t, float64(1089600), first.InsurancePremiums)
+		assert.Equal(t, float64(17089600), first.GrossIncome)
+		assert.Equal(t, float64(854480), first.OccupationalCost)
+		assert.Equal(t, float64(690948), first.PensionContributions)
+		assert.Equal(t, float64(15544172), first.NetIncome)
+		assert.Equal(t, float64(63000000), first.PTKP)
+		assert.Equal(t, float64(0), first.TaxDue)
+		assert.Equal(t, float64(252208), first.TaxWithheld)
+		assert.Equal(t, float64(-252208), first.TaxBalance)
+
+		second := certificates[1]
+		assert.Equal(t, "1.1-01.24-0000002", second.Number)
+		assert.Equal(t, "TK/0", second.PTKPStatus)
+		assert.Len(t, second.Months, 1)
+		assert.Equal(t, float64(60000000), second.GrossIncome)
+		assert.Equal(t, float64(500000), second.OccupationalCost)
+		assert.Equal(t, float64(5500000), second.TaxableIncome)
+		assert.Equal(t, float64(330000), second.TaxDue) // 20% surcharge without NPWP or NIK
+		assert.Equal(t, float64(330000), second.TaxBalance)
+	})
+
+	t.Run("success - number kept on reissue", func(t *testing.T) {
+		firstIssuedAt := issued[budi.ID].IssuedAt
+
+		certificates, err := s.GetTaxCertificates(2024)
+		require.NoError(t, err)
+		assert.Equal(t, "1.1-03.24-0000001", certificates[0].Number)
+		assert.Equal(t, firstIssuedAt, certificates[0].IssuedAt)
+	})
+
+	t.Run("success - archive", func(t *testing.T) {
+		certificates, err := s.GetTaxCertificates(2024)
+		require.NoError(t, err)
+
+		data, err := s.ArchiveTaxCertificates(certificates)
+		require.NoError(t, err)
+
+		archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		require.NoError(t, err)
+		var names []string
+		for _, file := range archive.File {
+			names = append(names, file.Name)
+		}
+		assert.Equal(t, []string{"1721-A1-2024-employee1.pdf", "1721-A1-2024-employee2.pdf", "1721-A1-2024.json"}, names)
+	})
+
+	t.Run("success - own certificate", func(t *testing.T) {
+		certificate, err := s.GetTaxCertificate(siti.ID, 2024)
+		require.NoError(t, err)
+		assert.Equal(t, "employee2", certificate.Username)
+		assert.Equal(t, "1.1-01.24-0000002", certificate.Number)
+		assert.Len(t, certificate.Months, 1)
+	})
+
+	t.Run("error - not paid in the tax year", func(t *testing.T) {
+		_, err := s.GetTaxCertificate(uuid.New(), 2024)
+		assert.ErrorIs(t, err, domains.ErrTaxCertificateNotFound)
+	})
+
+	t.Run("error - future tax year", func(t *testing.T) {
+		_, err := s.GetTaxCertificates(time.Now().Year() + 1)
+		assert.Error(t, err)
+	})
+}
+
+func Test_taxService_SetTaxProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	adminID := uuid.New()
+
+	tests := []struct {
+		name     string
+		profile  domains.TaxProfile
+		wantNPWP string
+		wantErr  bool
+	}{
+		{name: "success", profile: domains.TaxProfile{NIK: "3174011503900001", NPWP: "01.234.567.8-901.000", PTKPStatus: "k/1"}, wantNPWP: "012345678901000"},
+		{name: "success - without tax IDs", profile: domains.TaxProfile{PTKPStatus: "TK/0"}},
+		{name: "error - NIK too short", profile: domains.TaxProfile{NIK: "317401150390", PTKPStatus: "TK/0"}, wantErr: true},
+		{name: "error - invalid NPWP", profile: domains.TaxProfile{NPWP: "01234", PTKPStatus: "TK/0"}, wantErr: true},
+		{name: "error - invalid PTKP status", profile: domains.TaxProfile{PTKPStatus: "K/4"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+			mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+
+			if !tt.wantErr {
+				mockUserRepo.EXPECT().GetByID(userID).Return(&models.User{BaseModel: models.BaseModel{ID: userID}, Username: "employee1"}, nil)
+				mockUserRepo.EXPECT().Update(gomock.Any()).Return(nil)
+				mockAuditLogRepo.EXPECT().Create(gomock.Any()).Return(nil)
+			}
+
+			repos := &repository.Repositories{User: mockUserRepo, AuditLog: mockAuditLogRepo}
+			s := NewTaxService(repos, config.TaxConfig{}, config.PayslipConfig{}, nil)
+
+			user, err := s.SetTaxProfile(userID, tt.profile, domains.UserActor(adminID), "127.0.0.1", "req-123")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.profile.NIK, user.NIK)
+			assert.Equal(t, tt.wantNPWP, user.NPWP)
+			assert.Equal(t, &adminID, user.UpdatedBy)
+		})
+	}
+}
@@ -0,0 +1,321 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"payslip-system/internal/domains"
+	"payslip-system/internal/models"
+	"payslip-system/internal/repository"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type teamService struct {
+	repos *repository.Repositories
+}
+
+func NewTeamService(repos *repository.Repositories) *teamService {
+	return &teamService{repos: repos}
+}
+
+// SetManager sets the line manager of a user; nil removes it. Pending requests of the user move to the new
+// manager.
+func (s *teamService) SetManager(userID uuid.UUID, managerID *uuid.UUID, actor domains.Actor, ipAddress, requestID string) (*models.User, error) {
+	user, err := s.repos.User.GetByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+
+	if managerID != nil {
+		if *managerID == userID {
+			return nil, domains.ErrManagerCycle
+		}
+		if _, err := s.repos.User.GetByID(*managerID); err != nil {
+			return nil, errors.New("manager not found")
+		}
+
+		reports, err := s.repos.User.GetReports(userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, report := range reports {
+			if report.ID == *managerID {
+				return nil, domains.ErrManagerCycle
+			}
+		}
+	}
+
+	oldManagerID := user.ManagerID
+	user.ManagerID = managerID
+	user.UpdatedBy = actor.UserID
+	if err := s.repos.User.Update(user); err != nil {
+		return nil, err
+	}
+
+	createAuditLog("users", user.ID, "UPDATE", map[string]*uuid.UUID{"manager_id": oldManagerID}, map[string]*uuid.UUID{"manager_id": managerID}, actor, ipAddress, requestID, s.repos)
+
+	return user, nil
+}
+
+// GetTeam returns the direct and indirect reports of a manager, and those of managers on leave who delegated
+// to them
+func (s *teamService) GetTeam(managerID uuid.UUID) ([]domains.TeamMember, error) {
+	team, err := s.team(managerID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]domains.TeamMember, 0, len(team))
+	for _, member := range team {
+		members = append(members, member)
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].Username < members[j].Username })
+	return members, nil
+}
+
+// ListRequests returns the requests of a kind the approver's team submitted; an empty status returns all
+func (s *teamService) ListRequests(approverID uuid.UUID, kind, status string) ([]domains.TeamRequest, error) {
+	switch status {
+	case "", models.ApprovalStatusPending, models.ApprovalStatusApproved, models.ApprovalStatusRejected:
+	default:
+		return nil, errors.New("status must be 'pending', 'approved' or 'rejected'")
+	}
+
+	team, err := s.team(approverID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	requests := []domains.TeamRequest{}
+	if len(team) == 0 {
+		return requests, nil
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(team))
+	for id := range team {
+		userIDs = append(userIDs, id)
+	}
+
+	switch kind {
+	case domains.RequestKindOvertime:
+		overtimes, err := s.repos.Overtime.GetByUsers(userIDs, status)
+		if err != nil {
+			return nil, err
+		}
+		for i := range overtimes {
+			requests = append(requests, overtimeRequest(&overtimes[i], team[overtimes[i].UserID]))
+		}
+	case domains.RequestKindReimbursement:
+		reimbursements, err := s.repos.Reimbursement.GetByUsers(userIDs, status)
+		if err != nil {
+			return nil, err
+		}
+		for i := range reimbursements {
+			requests = append(requests, reimbursementRequest(&reimbursements[i], team[reimbursements[i].UserID]))
+		}
+	case domains.RequestKindLeave:
+		leaves, err := s.repos.Leave.GetByUsers(userIDs, status)
+		if err != nil {
+			return nil, err
+		}
+		for i := range leaves {
+			requests = append(requests, leaveRequest(&leaves[i], team[leaves[i].UserID]))
+		}
+	default:
+		return nil, fmt.Errorf("unknown request kind %q", kind)
+	}
+
+	return requests, nil
+}
+
+// Decide approves or rejects a pending request of the approver's team. Overtime and reimbursements can only
+// be decided until payroll has been processed for their period.
+func (s *teamService) Decide(approverID uuid.UUID, kind string, id uuid.UUID, approve bool, comment, ipAddress, requestID string) (*domains.TeamRequest, error) {
+	comment = strings.TrimSpace(comment)
+	if !approve && comment == "" {
+		return nil, errors.New("a reason is required to reject a request")
+	}
+
+	team, err := s.team(approverID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var request domains.TeamRequest
+	var table string
+	var periodID *uuid.UUID
+	switch kind {
+	case domains.RequestKindOvertime:
+		overtime, err := s.repos.Overtime.GetByID(id)
+		if err != nil {
+			return nil, requestLookupError(err)
+		}
+		request, table, periodID = overtimeRequest(overtime, team[overtime.UserID]), "overtimes", &overtime.AttendancePeriodID
+	case domains.RequestKindReimbursement:
+		reimbursement, err := s.repos.Reimbursement.GetByID(id)
+		if err != nil {
+			return nil, requestLookupError(err)
+		}
+		request, table, periodID = reimbursementRequest(reimbursement, team[reimbursement.UserID]), "reimbursements", &reimbursement.AttendancePeriodID
+	case domains.RequestKindLeave:
+		leave, err := s.repos.Leave.GetByID(id)
+		if err != nil {
+			return nil, requestLookupError(err)
+		}
+		request, table = leaveRequest(leave, team[leave.UserID]), "leaves"
+	default:
+		return nil, fmt.Errorf("unknown request kind %q", kind)
+	}
+
+	if _, ok := team[request.UserID]; !ok {
+		return nil, domains.ErrRequestNotFound
+	}
+	if request.Status != models.ApprovalStatusPending {
+		return nil, domains.ErrAlreadyDecided
+	}
+
+	now := time.Now()
+	approval := models.Approval{
+		Status:          models.ApprovalStatusRejected,
+		DecidedBy:       &approverID,
+		OnBehalfOf:      request.DelegatedBy,
+		DecidedAt:       &now,
+		DecisionComment: comment,
+	}
+	if approve {
+		approval.Status = models.ApprovalStatusApproved
+	}
+
+	record := func(repos *repository.Repositories) error {
+		var decided bool
+		var err error
+		switch kind {
+		case domains.RequestKindOvertime:
+			decided, err = repos.Overtime.Decide(id, approval)
+		case domains.RequestKindReimbursement:
+			decided, err = repos.Reimbursement.Decide(id, approval)
+		default:
+			decided, err = repos.Leave.Decide(id, approval)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to record decision: %w", err)
+		}
+		if !decided {
+			return domains.ErrAlreadyDecided
+		}
+		return nil
+	}
+	if periodID != nil {
+		err = withOpenPeriod(s.repos, *periodID, record)
+	} else {
+		err = record(s.repos)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	old := models.Approval{Status: request.Status}
+	createAuditLog(table, id, "UPDATE", old, approval, domains.UserActor(approverID), ipAddress, requestID, s.repos)
+
+	request.Status = approval.Status
+	request.DecidedBy = approval.DecidedBy
+	request.OnBehalfOf = approval.OnBehalfOf
+	request.DecidedAt = approval.DecidedAt
+	request.DecisionComment = approval.DecisionComment
+	return &request, nil
+}
+
+// team is who an approver decides for on day: their direct and indirect reports, and the reports of managers
+// whose approved leave lasts and who delegated to the approver. Nobody decides on their own requests.
+func (s *teamService) team(approverID uuid.UUID, day time.Time) (map[uuid.UUID]domains.TeamMember, error) {
+	team := map[uuid.UUID]domains.TeamMember{}
+
+	reports, err := s.repos.User.GetReports(approverID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range reports {
+		team[reports[i].ID] = teamMember(&reports[i], nil)
+	}
+
+	delegations, err := s.repos.Leave.GetDelegatedTo(approverID, day)
+	if err != nil {
+		return nil, err
+	}
+	for _, leave := range delegations {
+		managerID := leave.UserID
+		reports, err := s.repos.User.GetReports(managerID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range reports {
+			// The approver's own authority comes first
+			if _, ok := team[reports[i].ID]; !ok {
+				team[reports[i].ID] = teamMember(&reports[i], &managerID)
+			}
+		}
+	}
+
+	delete(team, approverID)
+	return team, nil
+}
+
+func requestLookupError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domains.ErrRequestNotFound
+	}
+	return err
+}
+
+func teamMember(user *models.User, delegatedBy *uuid.UUID) domains.TeamMember {
+	return domains.TeamMember{
+		ID:          user.ID,
+		Username:    user.Username,
+		Email:       user.Email,
+		ManagerID:   user.ManagerID,
+		DelegatedBy: delegatedBy,
+	}
+}
+
+func teamRequest(id uuid.UUID, kind string, userID uuid.UUID, member domains.TeamMember, submittedAt time.Time, approval models.Approval) domains.TeamRequest {
+	return domains.TeamRequest{
+		ID:              id,
+		Kind:            kind,
+		UserID:          userID,
+		Username:        member.Username,
+		SubmittedAt:     submittedAt,
+		DelegatedBy:     member.DelegatedBy,
+		Status:          approval.Status,
+		DecidedBy:       approval.DecidedBy,
+		OnBehalfOf:      approval.OnBehalfOf,
+		DecidedAt:       approval.DecidedAt,
+		DecisionComment: approval.DecisionComment,
+	}
+}
+
+func overtimeRequest(overtime *models.Overtime, member domains.TeamMember) domains.TeamRequest {
+	request := teamRequest(overtime.ID, domains.RequestKindOvertime, overtime.UserID, member, overtime.CreatedAt, overtime.Approval)
+	request.Date = &overtime.Date
+	request.Hours = overtime.Hours
+	return request
+}
+
+func reimbursementRequest(reimbursement *models.Reimbursement, member domains.TeamMember) domains.TeamRequest {
+	request := teamRequest(reimbursement.ID, domains.RequestKindReimbursement, reimbursement.UserID, member, reimbursement.CreatedAt, reimbursement.Approval)
+	request.Amount = reimbursement.Amount
+	request.Description = reimbursement.Description
+	return request
+}
+
+func leaveRequest(leave *models.Leave, member domains.TeamMember) domains.TeamRequest {
+	request := teamRequest(leave.ID, domains.RequestKindLeave, leave.UserID, member, leave.CreatedAt, leave.Approval)
+	request.LeaveType = leave.Type
+	request.StartDate = &leave.StartDate
+	request.EndDate = &leave.EndDate
+	request.Reason = leave.Reason
+	return request
+}
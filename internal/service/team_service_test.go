@@ -0,0 +1,215 @@
+package service
+
+import (
+	"testing"
+
+	"payslip-system/internal/domains"
+	"payslip-system/internal/models"
+	"payslip-system/internal/repository"
+	mock_repository "payslip-system/internal/repository/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func newReport(username string, managerID uuid.UUID) models.User {
+	return models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: username, ManagerID: &managerID, IsActive: true}
+}
+
+func Test_teamService_ListRequests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	managerID := uuid.New()
+	onLeaveID := uuid.New()
+	alice := newReport("alice", managerID)
+	bob := newReport("bob", alice.ID) // indirect report
+	carol := newReport("carol", onLeaveID)
+
+	mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+	mockLeaveRepo := mock_repository.NewMockILeaveRepository(ctrl)
+	mockOvertimeRepo := mock_repository.NewMockIOvertimeRepository(ctrl)
+
+	// The manager on leave manages the manager too, who stays in charge of their own reports
+	manager := newReport("manager", onLeaveID)
+	manager.ID = managerID
+	mockUserRepo.EXPECT().GetReports(managerID).Return([]models.User{alice, bob}, nil)
+	mockLeaveRepo.EXPECT().GetDelegatedTo(managerID, gomock.Any()).Return([]models.Leave{{UserID: onLeaveID}}, nil)
+	mockUserRepo.EXPECT().GetReports(onLeaveID).Return([]models.User{alice, bob, carol, manager}, nil)
+	mockOvertimeRepo.EXPECT().GetByUsers(gomock.Len(3), models.ApprovalStatusPending).DoAndReturn(func(userIDs []uuid.UUID, status string) ([]models.Overtime, error) {
+		assert.NotContains(t, userIDs, managerID)
+		return []models.Overtime{
+			{UserID: bob.ID, Hours: 2, Approval: models.Approval{Status: models.ApprovalStatusPending}},
+			{UserID: carol.ID, Hours: 1, Approval: models.Approval{Status: models.ApprovalStatusPending}},
+		}, nil
+	})
+
+	repos := &repository.Repositories{User: mockUserRepo, Leave: mockLeaveRepo, Overtime: mockOvertimeRepo}
+	s := NewTeamService(repos)
+
+	requests, err := s.ListRequests(managerID, domains.RequestKindOvertime, models.ApprovalStatusPending)
+	require.NoError(t, err)
+	require.Len(t, requests, 2)
+	assert.Equal(t, "bob", requests[0].Username)
+	assert.Nil(t, requests[0].DelegatedBy)
+	assert.Equal(t, "carol", requests[1].Username)
+	assert.Equal(t, &onLeaveID, requests[1].DelegatedBy)
+
+	_, err = s.ListRequests(managerID, domains.RequestKindOvertime, "done")
+	assert.Error(t, err)
+}
+
+func Test_teamService_Decide(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	managerID := uuid.New()
+	onLeaveID := uuid.New()
+	report := newReport("alice", managerID)
+	delegated := newReport("carol", onLeaveID)
+	outsider := newReport("dave", uuid.New())
+
+	pendingLeave := func(user models.User) *models.Leave {
+		return &models.Leave{BaseModel: models.BaseModel{ID: uuid.New()}, UserID: user.ID, Type: models.LeaveTypeAnnual,
+			Approval: models.Approval{Status: models.ApprovalStatusPending}}
+	}
+	decided := pendingLeave(report)
+	decided.Status = models.ApprovalStatusApproved
+
+	tests := []struct {
+		name           string
+		leave          *models.Leave
+		approve        bool
+		comment        string
+		decideConflict bool
+		wantOnBehalfOf *uuid.UUID
+		wantErr        string
+	}{
+		{name: "approve own report", leave: pendingLeave(report), approve: true},
+		{name: "reject as delegate", leave: pendingLeave(delegated), comment: "Team is short-staffed", wantOnBehalfOf: &onLeaveID},
+		{name: "reject without reason", leave: pendingLeave(report), wantErr: "a reason is required"},
+		{name: "outside the team", leave: pendingLeave(outsider), approve: true, wantErr: domains.ErrRequestNotFound.Error()},
+		{name: "already decided", leave: decided, approve: true, wantErr: domains.ErrAlreadyDecided.Error()},
+		{name: "decided concurrently", leave: pendingLeave(report), approve: true, decideConflict: true, wantErr: domains.ErrAlreadyDecided.Error()},
+		{name: "unknown request", approve: true, wantErr: domains.ErrRequestNotFound.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+			mockLeaveRepo := mock_repository.NewMockILeaveRepository(ctrl)
+			mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+
+			id := uuid.New()
+			if tt.leave != nil {
+				id = tt.leave.ID
+			}
+
+			if tt.approve || tt.comment != "" {
+				mockUserRepo.EXPECT().GetReports(managerID).Return([]models.User{report}, nil)
+				mockLeaveRepo.EXPECT().GetDelegatedTo(managerID, gomock.Any()).Return([]models.Leave{{UserID: onLeaveID}}, nil)
+				mockUserRepo.EXPECT().GetReports(onLeaveID).Return([]models.User{delegated}, nil)
+
+				if tt.leave != nil {
+					mockLeaveRepo.EXPECT().GetByID(id).Return(tt.leave, nil)
+				} else {
+					mockLeaveRepo.EXPECT().GetByID(id).Return(nil, gorm.ErrRecordNotFound)
+				}
+				if tt.wantErr == "" || tt.decideConflict {
+					mockLeaveRepo.EXPECT().Decide(id, gomock.Any()).DoAndReturn(func(_ uuid.UUID, approval models.Approval) (bool, error) {
+						assert.Equal(t, &managerID, approval.DecidedBy)
+						assert.Equal(t, tt.wantOnBehalfOf, approval.OnBehalfOf)
+						return !tt.decideConflict, nil
+					})
+				}
+				if tt.wantErr == "" {
+					mockAuditLogRepo.EXPECT().Create(gomock.Any()).Return(nil)
+				}
+			}
+
+			repos := &repository.Repositories{User: mockUserRepo, Leave: mockLeaveRepo, AuditLog: mockAuditLogRepo}
+			s := NewTeamService(repos)
+
+			request, err := s.Decide(managerID, domains.RequestKindLeave, id, tt.approve, tt.comment, "127.0.0.1", "req-123")
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			wantStatus := models.ApprovalStatusRejected
+			if tt.approve {
+				wantStatus = models.ApprovalStatusApproved
+			}
+			assert.Equal(t, wantStatus, request.Status)
+			assert.Equal(t, tt.comment, request.DecisionComment)
+			assert.Equal(t, tt.wantOnBehalfOf, request.OnBehalfOf)
+			assert.NotNil(t, request.DecidedAt)
+		})
+	}
+}
+
+func Test_teamService_SetManager(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "alice", IsActive: true}
+	report := newReport("bob", user.ID)
+	manager := models.User{BaseModel: models.BaseModel{ID: uuid.New()}, Username: "carol", IsActive: true}
+
+	tests := []struct {
+		name      string
+		managerID *uuid.UUID
+		wantErr   error
+	}{
+		{name: "success", managerID: &manager.ID},
+		{name: "remove manager"},
+		{name: "own manager", managerID: &user.ID, wantErr: domains.ErrManagerCycle},
+		{name: "reporting to a report", managerID: &report.ID, wantErr: domains.ErrManagerCycle},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adminID := uuid.New()
+			current := user
+
+			mockUserRepo := mock_repository.NewMockIUserRepository(ctrl)
+			mockAuditLogRepo := mock_repository.NewMockIAuditLogRepository(ctrl)
+
+			mockUserRepo.EXPECT().GetByID(user.ID).Return(&current, nil)
+			if tt.managerID != nil && *tt.managerID != user.ID {
+				target := manager
+				if *tt.managerID == report.ID {
+					target = report
+				}
+				mockUserRepo.EXPECT().GetByID(*tt.managerID).Return(&target, nil)
+				mockUserRepo.EXPECT().GetReports(user.ID).Return([]models.User{report}, nil)
+			}
+			if tt.wantErr == nil {
+				mockUserRepo.EXPECT().Update(gomock.Any()).Return(nil)
+				mockAuditLogRepo.EXPECT().Create(gomock.Any()).Return(nil)
+			}
+
+			s := NewTeamService(&repository.Repositories{User: mockUserRepo, AuditLog: mockAuditLogRepo})
+
+			updated, err := s.SetManager(user.ID, tt.managerID, domains.UserActor(adminID), "127.0.0.1", "req-123")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.managerID, updated.ManagerID)
+			assert.Equal(t, &adminID, updated.UpdatedBy)
+		})
+	}
+}
+
+func Test_submissionApproval(t *testing.T) {
+	managerID := uuid.New()
+	assert.Equal(t, models.ApprovalStatusPending, submissionApproval(&models.User{ManagerID: &managerID}).Status)
+	assert.Equal(t, models.ApprovalStatusApproved, submissionApproval(&models.User{}).Status)
+}
@@ -0,0 +1,233 @@
+// Package tax computes Indonesian employee income tax (PPh 21) and BPJS social security contributions.
+// Amounts are in rupiah and rounded to whole rupiah.
+package tax
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+
+	"payslip-system/internal/config"
+	"payslip-system/internal/models"
+)
+
+const (
+	// OccupationalCostRate is the biaya jabatan deducted from gross income, capped per month worked
+	OccupationalCostRate       = 0.05
+	OccupationalCostMonthlyCap = 500000
+
+	// NoTaxIDSurcharge applies to employees without NPWP or NIK
+	NoTaxIDSurcharge = 1.2
+
+	// DefaultPTKPStatus is assumed for employees whose status was not recorded
+	DefaultPTKPStatus = "TK/0"
+
+	ptkpBase      = 54000000
+	ptkpMarried   = 4500000
+	ptkpDependent = 4500000
+)
+
+var (
+	ptkpStatusPattern = regexp.MustCompile(`^(TK|K)/([0-3])$`)
+	nikPattern        = regexp.MustCompile(`^[0-9]{16}$`)
+	npwpPattern       = regexp.MustCompile(`^[0-9]{15,16}$`)
+)
+
+// bracket is one step of the Article 17 progressive rates, up to an annual taxable income
+type bracket struct {
+	upTo float64
+	rate float64
+}
+
+var brackets = []bracket{
+	{60000000, 0.05},
+	{250000000, 0.15},
+	{500000000, 0.25},
+	{5000000000, 0.30},
+	{math.Inf(1), 0.35},
+}
+
+// ValidPTKPStatus reports whether status is TK/0 to TK/3 or K/0 to K/3
+func ValidPTKPStatus(status string) bool {
+	return ptkpStatusPattern.MatchString(status)
+}
+
+// ValidNIK reports whether nik has the 16 digits of a national identity number
+func ValidNIK(nik string) bool {
+	return nikPattern.MatchString(nik)
+}
+
+// ValidNPWP reports whether npwp is a 15 digit taxpayer number or its 16 digit successor
+func ValidNPWP(npwp string) bool {
+	return npwpPattern.MatchString(npwp)
+}
+
+// PTKP is the annual non-taxable income for a PTKP status; unknown statuses count as TK/0
+func PTKP(status string) float64 {
+	match := ptkpStatusPattern.FindStringSubmatch(status)
+	if match == nil {
+		return ptkpBase
+	}
+
+	amount := float64(ptkpBase)
+	if match[1] == "K" {
+		amount += ptkpMarried
+	}
+	dependents, _ := strconv.Atoi(match[2])
+	return amount + float64(dependents)*ptkpDependent
+}
+
+// OccupationalCost is the biaya jabatan on gross income earned over a number of months
+func OccupationalCost(gross float64, months int) float64 {
+	return math.Round(math.Min(gross*OccupationalCostRate, float64(months)*OccupationalCostMonthlyCap))
+}
+
+// TaxableIncome is the annual net income above PTKP, rounded down to thousands
+func TaxableIncome(netIncome, ptkp float64) float64 {
+	if netIncome <= ptkp {
+		return 0
+	}
+	return math.Floor((netIncome-ptkp)/1000) * 1000
+}
+
+// IncomeTax applies the Article 17 rates to an annual taxable income
+func IncomeTax(taxable float64) float64 {
+	var tax, lower float64
+	for _, b := range brackets {
+		if taxable <= lower {
+			break
+		}
+		tax += (math.Min(taxable, b.upTo) - lower) * b.rate
+		lower = b.upTo
+	}
+	return math.Round(tax)
+}
+
+// terRate is one step of the monthly effective rates (TER) of PP 58/2023, up to a monthly gross income
+type terRate struct {
+	upTo float64
+	rate float64
+}
+
+// terRates are the TER categories: A for TK/0, TK/1 and K/0, B for TK/2, TK/3, K/1 and K/2, C for K/3
+var terRates = map[string][]terRate{
+	"A": {
+		{5400000, 0}, {5650000, 0.0025}, {5950000, 0.005}, {6300000, 0.0075}, {6750000, 0.01},
+		{7500000, 0.0125}, {8550000, 0.015}, {9650000, 0.0175}, {10050000, 0.02}, {10350000, 0.0225},
+		{10700000, 0.025}, {11050000, 0.03}, {11600000, 0.035}, {12500000, 0.04}, {13750000, 0.05},
+		{15100000, 0.06}, {16950000, 0.07}, {19750000, 0.08}, {24150000, 0.09}, {26450000, 0.10},
+		{28000000, 0.11}, {30050000, 0.12}, {32400000, 0.13}, {35400000, 0.14}, {39100000, 0.15},
+		{43850000, 0.16}, {47800000, 0.17}, {51400000, 0.18}, {56300000, 0.19}, {62200000, 0.20},
+		{68600000, 0.21}, {77500000, 0.22}, {89000000, 0.23}, {103000000, 0.24}, {125000000, 0.25},
+		{157000000, 0.26}, {206000000, 0.27}, {337000000, 0.28}, {454000000, 0.29}, {550000000, 0.30},
+		{695000000, 0.31}, {910000000, 0.32}, {1400000000, 0.33}, {math.Inf(1), 0.34},
+	},
+	"B": {
+		{6200000, 0}, {6500000, 0.0025}, {6850000, 0.005}, {7300000, 0.0075}, {9200000, 0.01},
+		{10750000, 0.015}, {11250000, 0.02}, {11600000, 0.025}, {12600000, 0.03}, {13600000, 0.04},
+		{14950000, 0.05}, {16400000, 0.06}, {18450000, 0.07}, {21850000, 0.08}, {26000000, 0.09},
+		{27700000, 0.10}, {29350000, 0.11}, {31450000, 0.12}, {33950000, 0.13}, {37100000, 0.14},
+		{41100000, 0.15}, {45800000, 0.16}, {49500000, 0.17}, {53800000, 0.18}, {58500000, 0.19},
+		{64000000, 0.20}, {71000000, 0.21}, {80000000, 0.22}, {93000000, 0.23}, {109000000, 0.24},
+		{129000000, 0.25}, {163000000, 0.26}, {211000000, 0.27}, {374000000, 0.28}, {459000000, 0.29},
+		{555000000, 0.30}, {704000000, 0.31}, {957000000, 0.32}, {1405000000, 0.33}, {math.Inf(1), 0.34},
+	},
+	"C": {
+		{6600000, 0}, {6950000, 0.0025}, {7350000, 0.005}, {7800000, 0.0075}, {8850000, 0.01},
+		{9800000, 0.0125}, {10950000, 0.015}, {11200000, 0.0175}, {12050000, 0.02}, {12950000, 0.03},
+		{14150000, 0.04}, {15550000, 0.05}, {17050000, 0.06}, {19500000, 0.07}, {22700000, 0.08},
+		{26600000, 0.09}, {28100000, 0.10}, {30100000, 0.11}, {32600000, 0.12}, {35400000, 0.13},
+		{38900000, 0.14}, {43000000, 0.15}, {47400000, 0.16}, {51200000, 0.17}, {55800000, 0.18},
+		{60400000, 0.19}, {66700000, 0.20}, {74500000, 0.21}, {83200000, 0.22}, {95600000, 0.23},
+		{110000000, 0.24}, {134000000, 0.25}, {169000000, 0.26}, {221000000, 0.27}, {390000000, 0.28},
+		{463000000, 0.29}, {561000000, 0.30}, {709000000, 0.31}, {965000000, 0.32}, {1419000000, 0.33},
+		{math.Inf(1), 0.34},
+	},
+}
+
+// TERCategory is the TER category of a PTKP status, which follows from its PTKP amount
+func TERCategory(status string) string {
+	switch ptkp := PTKP(status); {
+	case ptkp <= ptkpBase+ptkpMarried:
+		return "A"
+	case ptkp <= ptkpBase+ptkpMarried+2*ptkpDependent:
+		return "B"
+	default:
+		return "C"
+	}
+}
+
+// TERRate is the monthly effective rate on a month's gross income
+func TERRate(gross float64, status string) float64 {
+	rates := terRates[TERCategory(status)]
+	for _, r := range rates {
+		if gross <= r.upTo {
+			return r.rate
+		}
+	}
+	return rates[len(rates)-1].rate
+}
+
+// MonthlyWithholding is the PPh 21 to withhold from a month's gross income in January to November: the TER
+// rate of the employee's category applied to the gross, without deductions
+func MonthlyWithholding(gross float64, ptkpStatus string, hasTaxID bool) float64 {
+	if gross <= 0 {
+		return 0
+	}
+
+	withholding := gross * TERRate(gross, ptkpStatus)
+	if !hasTaxID {
+		withholding *= NoTaxIDSurcharge
+	}
+	return math.Round(withholding)
+}
+
+// AnnualTax is the PPh 21 due on the gross income of a tax year at the Article 17 rates, after the occupational
+// cost of the months worked, the pension contributions and PTKP
+func AnnualTax(gross, pensionContributions float64, months int, ptkpStatus string, hasTaxID bool) float64 {
+	net := gross - OccupationalCost(gross, months) - pensionContributions
+	due := IncomeTax(TaxableIncome(net, PTKP(ptkpStatus)))
+	if !hasTaxID {
+		due = math.Round(due * NoTaxIDSurcharge)
+	}
+	return due
+}
+
+// YearEndWithholding is the PPh 21 to withhold in December: the tax due on the year, including December,
+// less what was withheld in the months before. Tax overpaid by then is not withheld, nor refunded through
+// payroll; the 1721-A1 shows it in its balance.
+func YearEndWithholding(gross, pensionContributions float64, months int, ptkpStatus string, hasTaxID bool, withheld float64) float64 {
+	return math.Max(0, AnnualTax(gross, pensionContributions, months, ptkpStatus, hasTaxID)-withheld)
+}
+
+// Contributions computes the BPJS contributions on a monthly wage
+func Contributions(wage float64, rates config.BPJSConfig) models.BPJSContributions {
+	if wage <= 0 {
+		return models.BPJSContributions{}
+	}
+
+	jpWage := capped(wage, rates.JPWageCap)
+	healthWage := capped(wage, rates.HealthWageCap)
+
+	return models.BPJSContributions{
+		JHTEmployee:    percent(wage, rates.JHTEmployee),
+		JPEmployee:     percent(jpWage, rates.JPEmployee),
+		HealthEmployee: percent(healthWage, rates.HealthEmployee),
+		JHTEmployer:    percent(wage, rates.JHTEmployer),
+		JPEmployer:     percent(jpWage, rates.JPEmployer),
+		JKKEmployer:    percent(wage, rates.JKKEmployer),
+		JKMEmployer:    percent(wage, rates.JKMEmployer),
+		HealthEmployer: percent(healthWage, rates.HealthEmployer),
+	}
+}
+
+func capped(wage, limit float64) float64 {
+	if limit > 0 && wage > limit {
+		return limit
+	}
+	return wage
+}
+
+func percent(amount, rate float64) float64 {
+	return math.Round(amount * rate / 100)
+}
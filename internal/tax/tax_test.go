@@ -0,0 +1,154 @@
+package tax
+
+import (
+	"testing"
+
+	"payslip-system/internal/config"
+	"payslip-system/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPTKP(t *testing.T) {
+	tests := []struct {
+		status string
+		want   float64
+	}{
+		{"TK/0", 54000000},
+		{"TK/3", 67500000},
+		{"K/0", 58500000},
+		{"K/2", 67500000},
+		{"", 54000000},
+		{"K/4", 54000000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			assert.Equal(t, tt.want, PTKP(tt.status))
+		})
+	}
+}
+
+func TestValidProfile(t *testing.T) {
+	assert.True(t, ValidPTKPStatus("K/3"))
+	assert.False(t, ValidPTKPStatus("k/1"))
+	assert.False(t, ValidPTKPStatus("TK/4"))
+
+	assert.True(t, ValidNIK("3174011503900001"))
+	assert.False(t, ValidNIK("317401150390000"))
+
+	assert.True(t, ValidNPWP("012345678901000"))
+	assert.True(t, ValidNPWP("0012345678901000"))
+	assert.False(t, ValidNPWP("01.234.567.8-901.000"))
+}
+
+func TestIncomeTax(t *testing.T) {
+	tests := []struct {
+		name    string
+		taxable float64
+		want    float64
+	}{
+		{"nothing taxable", 0, 0},
+		{"first bracket", 60000000, 3000000},
+		{"second bracket", 100000000, 9000000},
+		{"third bracket", 300000000, 44000000},
+		{"top bracket", 6000000000, 1794000000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IncomeTax(tt.taxable))
+		})
+	}
+}
+
+func TestTaxableIncome(t *testing.T) {
+	assert.Equal(t, float64(56400000), TaxableIncome(110400999, 54000000))
+	assert.Equal(t, float64(0), TaxableIncome(50000000, 54000000))
+}
+
+func TestOccupationalCost(t *testing.T) {
+	assert.Equal(t, float64(250000), OccupationalCost(5000000, 1))
+	assert.Equal(t, float64(500000), OccupationalCost(20000000, 1))
+	assert.Equal(t, float64(6000000), OccupationalCost(150000000, 12))
+	assert.Equal(t, float64(1500000), OccupationalCost(150000000, 3))
+}
+
+func TestTERCategory(t *testing.T) {
+	for status, want := range map[string]string{
+		"TK/0": "A", "TK/1": "A", "K/0": "A",
+		"TK/2": "B", "TK/3": "B", "K/1": "B", "K/2": "B",
+		"K/3": "C", "": "A",
+	} {
+		assert.Equal(t, want, TERCategory(status), status)
+	}
+}
+
+func TestMonthlyWithholding(t *testing.T) {
+	tests := []struct {
+		name     string
+		gross    float64
+		status   string
+		hasTaxID bool
+		want     float64
+	}{
+		{name: "category A", gross: 10000000, status: "TK/0", hasTaxID: true, want: 200000},
+		{name: "category B", gross: 10000000, status: "K/1", hasTaxID: true, want: 150000},
+		{name: "category C", gross: 10000000, status: "K/3", hasTaxID: true, want: 150000},
+		{name: "upper bound of a step", gross: 5400000, status: "TK/0", hasTaxID: true, want: 0},
+		{name: "without tax ID", gross: 10000000, status: "TK/0", want: 240000},
+		{name: "top rate", gross: 2000000000, status: "TK/0", hasTaxID: true, want: 680000000},
+		{name: "no income", gross: 0, status: "TK/0", hasTaxID: true, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MonthlyWithholding(tt.gross, tt.status, tt.hasTaxID))
+		})
+	}
+}
+
+func TestYearEndWithholding(t *testing.T) {
+	// 10,000,000 a month as TK/0: 6,000,000 occupational cost and 3,600,000 pension contributions leave
+	// 110,400,000, of which 56,400,000 is taxable at 5%: 2,820,000
+	tests := []struct {
+		name     string
+		withheld float64
+		hasTaxID bool
+		want     float64
+	}{
+		{name: "underpaid", withheld: 11 * 200000, hasTaxID: true, want: 620000},
+		{name: "overpaid", withheld: 4000000, hasTaxID: true, want: 0},
+		{name: "without tax ID", withheld: 11 * 240000, want: 744000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, YearEndWithholding(120000000, 3600000, 12, "TK/0", tt.hasTaxID, tt.withheld))
+		})
+	}
+}
+
+func TestContributions(t *testing.T) {
+	rates := config.BPJSConfig{
+		JHTEmployee: 2, JHTEmployer: 3.7, JPEmployee: 1, JPEmployer: 2, JKKEmployer: 0.24, JKMEmployer: 0.3,
+		HealthEmployee: 1, HealthEmployer: 4, JPWageCap: 10547400, HealthWageCap: 12000000,
+	}
+
+	got := Contributions(15000000, rates)
+	assert.Equal(t, models.BPJSContributions{
+		JHTEmployee:    300000,
+		JPEmployee:     105474,
+		HealthEmployee: 120000,
+		JHTEmployer:    555000,
+		JPEmployer:     210948,
+		JKKEmployer:    36000,
+		JKMEmployer:    45000,
+		HealthEmployer: 480000,
+	}, got)
+	assert.Equal(t, float64(525474), got.EmployeeTotal())
+	assert.Equal(t, float64(405474), got.PensionContributions())
+	assert.Equal(t, float64(561000), got.TaxableBenefits())
+
+	assert.Equal(t, models.BPJSContributions{}, Contributions(0, rates))
+}
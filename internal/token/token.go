@@ -0,0 +1,257 @@
+// Package token signs access tokens with asymmetric keys and verifies them by key ID, so keys can be rotated
+// and other services can verify tokens with the published public keys alone
+package token
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"payslip-system/internal/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minRSABits is the smallest RSA key accepted for RS256
+const minRSABits = 2048
+
+type key struct {
+	id      string
+	method  jwt.SigningMethod
+	private crypto.Signer // nil for retired keys, which only verify
+	public  crypto.PublicKey
+}
+
+// KeySet is the key new tokens are signed with and every key tokens are still verified with
+type KeySet struct {
+	signing *key
+	keys    map[string]*key
+	order   []string // kids in configuration order, for the JWKS
+}
+
+// LoadKeySet reads the configured PEM keys. The key with signingKeyID signs new tokens and needs a private key;
+// the others are retired keys and only need a public key.
+func LoadKeySet(signingKeyID string, keys []config.SigningKeyConfig) (*KeySet, error) {
+	ks := &KeySet{keys: map[string]*key{}}
+	for _, cfg := range keys {
+		if cfg.ID == "" {
+			return nil, errors.New("token key without id")
+		}
+		if _, ok := ks.keys[cfg.ID]; ok {
+			return nil, fmt.Errorf("duplicate token key id %q", cfg.ID)
+		}
+
+		k, err := loadKey(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("token key %q: %w", cfg.ID, err)
+		}
+		ks.add(k)
+	}
+
+	signing, ok := ks.keys[signingKeyID]
+	if !ok {
+		return nil, fmt.Errorf("signing key %q is not configured", signingKeyID)
+	}
+	if signing.private == nil {
+		return nil, fmt.Errorf("signing key %q has no private key", signingKeyID)
+	}
+	ks.signing = signing
+
+	return ks, nil
+}
+
+// NewEphemeralKeySet creates a throwaway Ed25519 key. Tokens signed with it stop verifying when the process
+// restarts, and other instances cannot verify them.
+func NewEphemeralKeySet() (*KeySet, error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	k := &key{method: jwt.SigningMethodEdDSA, private: private, public: public}
+	k.id = thumbprint(k)
+
+	ks := &KeySet{keys: map[string]*key{}}
+	ks.add(k)
+	ks.signing = k
+	return ks, nil
+}
+
+func (ks *KeySet) add(k *key) {
+	ks.keys[k.id] = k
+	ks.order = append(ks.order, k.id)
+}
+
+// SigningKeyID is the kid of the key new tokens are signed with
+func (ks *KeySet) SigningKeyID() string {
+	return ks.signing.id
+}
+
+// Sign signs the claims with the signing key, naming it in the kid header
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.signing.method, claims)
+	token.Header["kid"] = ks.signing.id
+	return token.SignedString(ks.signing.private)
+}
+
+// Parse verifies a token with the key its kid header names and decodes its claims. A key only verifies
+// tokens of its own algorithm.
+func (ks *KeySet) Parse(tokenString string, claims jwt.Claims) error {
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		k, ok := ks.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown key id %q", kid)
+		}
+		if token.Method.Alg() != k.method.Alg() {
+			return nil, fmt.Errorf("key %q does not sign with %s", kid, token.Method.Alg())
+		}
+		return k.public, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}), jwt.WithExpirationRequired())
+	return err
+}
+
+// JWKS is a JSON Web Key Set (RFC 7517)
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWK is the public part of an RSA or Ed25519 key
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`   // RSA modulus
+	E         string `json:"e,omitempty"`   // RSA exponent
+	Curve     string `json:"crv,omitempty"` // OKP curve
+	X         string `json:"x,omitempty"`   // OKP public key
+}
+
+// JWKS publishes the public keys, the signing key first
+func (ks *KeySet) JWKS() JWKS {
+	set := JWKS{Keys: []JWK{ks.signing.jwk()}}
+	for _, kid := range ks.order {
+		if kid != ks.signing.id {
+			set.Keys = append(set.Keys, ks.keys[kid].jwk())
+		}
+	}
+	return set
+}
+
+func (k *key) jwk() JWK {
+	jwk := JWK{KeyID: k.id, Use: "sig", Algorithm: k.method.Alg()}
+	switch public := k.public.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	}
+	return jwk
+}
+
+// thumbprint is the RFC 7638 thumbprint of the key, used as the kid of generated keys
+func thumbprint(k *key) string {
+	jwk := k.jwk()
+	var canonical string
+	switch jwk.KeyType {
+	case "RSA":
+		canonical = fmt.Sprintf(`{"e":%q,"kty":"RSA","n":%q}`, jwk.E, jwk.N)
+	default:
+		canonical = fmt.Sprintf(`{"crv":%q,"kty":"OKP","x":%q}`, jwk.Curve, jwk.X)
+	}
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func loadKey(cfg config.SigningKeyConfig) (*key, error) {
+	k := &key{id: cfg.ID}
+
+	switch {
+	case cfg.PrivateKeyPath != "":
+		data, err := os.ReadFile(cfg.PrivateKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read private key: %w", err)
+		}
+		private, err := parsePrivateKey(data)
+		if err != nil {
+			return nil, err
+		}
+		k.private = private
+		k.public = private.Public()
+	case cfg.PublicKeyPath != "":
+		data, err := os.ReadFile(cfg.PublicKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read public key: %w", err)
+		}
+		public, err := parsePublicKey(data)
+		if err != nil {
+			return nil, err
+		}
+		k.public = public
+	default:
+		return nil, errors.New("neither private_key_path nor public_key_path is set")
+	}
+
+	switch public := k.public.(type) {
+	case *rsa.PublicKey:
+		if public.N.BitLen() < minRSABits {
+			return nil, fmt.Errorf("RSA key has %d bits, at least %d are required", public.N.BitLen(), minRSABits)
+		}
+		k.method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		k.method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("unsupported key type %T, use RSA or Ed25519", k.public)
+	}
+	return k, nil
+}
+
+func parsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found in private key file")
+	}
+
+	if block.Type == "RSA PRIVATE KEY" {
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	signer, ok := parsed.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", parsed)
+	}
+	return signer, nil
+}
+
+func parsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found in public key file")
+	}
+
+	if block.Type == "RSA PUBLIC KEY" {
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+	public, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+	return public, nil
+}
@@ -0,0 +1,184 @@
+package token
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"payslip-system/internal/config"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePEM(t *testing.T, name, blockType string, der []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600))
+	return path
+}
+
+func rsaKeyFiles(t *testing.T, bits int) (privatePath, publicPath string) {
+	private, err := rsa.GenerateKey(rand.Reader, bits)
+	require.NoError(t, err)
+	public, err := x509.MarshalPKIXPublicKey(&private.PublicKey)
+	require.NoError(t, err)
+	return writePEM(t, "rsa.pem", "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(private)),
+		writePEM(t, "rsa.pub.pem", "PUBLIC KEY", public)
+}
+
+func ed25519KeyFiles(t *testing.T) (privatePath, publicPath string) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	require.NoError(t, err)
+	return writePEM(t, "ed25519.pem", "PRIVATE KEY", privateDER),
+		writePEM(t, "ed25519.pub.pem", "PUBLIC KEY", publicDER)
+}
+
+func testClaims() *jwt.RegisteredClaims {
+	return &jwt.RegisteredClaims{Subject: "user", ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))}
+}
+
+func TestKeySet_SignAndParse(t *testing.T) {
+	rsaPrivate, _ := rsaKeyFiles(t, 2048)
+	edPrivate, _ := ed25519KeyFiles(t)
+
+	tests := []struct {
+		name string
+		key  config.SigningKeyConfig
+		alg  string
+	}{
+		{name: "RS256", key: config.SigningKeyConfig{ID: "rsa-1", PrivateKeyPath: rsaPrivate}, alg: "RS256"},
+		{name: "EdDSA", key: config.SigningKeyConfig{ID: "ed-1", PrivateKeyPath: edPrivate}, alg: "EdDSA"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := LoadKeySet(tt.key.ID, []config.SigningKeyConfig{tt.key})
+			require.NoError(t, err)
+
+			signed, err := keys.Sign(testClaims())
+			require.NoError(t, err)
+
+			parsed, _, err := jwt.NewParser().ParseUnverified(signed, &jwt.RegisteredClaims{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.key.ID, parsed.Header["kid"])
+			assert.Equal(t, tt.alg, parsed.Header["alg"])
+
+			claims := &jwt.RegisteredClaims{}
+			require.NoError(t, keys.Parse(signed, claims))
+			assert.Equal(t, "user", claims.Subject)
+		})
+	}
+}
+
+func TestKeySet_Rotation(t *testing.T) {
+	oldPrivate, oldPublic := rsaKeyFiles(t, 2048)
+	newPrivate, _ := ed25519KeyFiles(t)
+
+	before, err := LoadKeySet("2024-01", []config.SigningKeyConfig{{ID: "2024-01", PrivateKeyPath: oldPrivate}})
+	require.NoError(t, err)
+	oldToken, err := before.Sign(testClaims())
+	require.NoError(t, err)
+
+	// The old key is retired: only its public key is kept, to verify tokens it signed
+	after, err := LoadKeySet("2024-07", []config.SigningKeyConfig{
+		{ID: "2024-01", PublicKeyPath: oldPublic},
+		{ID: "2024-07", PrivateKeyPath: newPrivate},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "2024-07", after.SigningKeyID())
+
+	require.NoError(t, after.Parse(oldToken, &jwt.RegisteredClaims{}))
+
+	newToken, err := after.Sign(testClaims())
+	require.NoError(t, err)
+	require.NoError(t, after.Parse(newToken, &jwt.RegisteredClaims{}))
+
+	// Tokens of the new key are unknown to instances still on the old configuration
+	assert.Error(t, before.Parse(newToken, &jwt.RegisteredClaims{}))
+
+	jwks := after.JWKS()
+	require.Len(t, jwks.Keys, 2)
+	assert.Equal(t, JWK{KeyType: "OKP", KeyID: "2024-07", Use: "sig", Algorithm: "EdDSA", Curve: "Ed25519", X: jwks.Keys[0].X}, jwks.Keys[0])
+	assert.Len(t, jwks.Keys[0].X, 43)
+	assert.Equal(t, "RSA", jwks.Keys[1].KeyType)
+	assert.Equal(t, "2024-01", jwks.Keys[1].KeyID)
+	assert.Equal(t, "RS256", jwks.Keys[1].Algorithm)
+	assert.Equal(t, "AQAB", jwks.Keys[1].E)
+	assert.NotEmpty(t, jwks.Keys[1].N)
+}
+
+func TestKeySet_Parse_Rejects(t *testing.T) {
+	keys, err := NewEphemeralKeySet()
+	require.NoError(t, err)
+	other, err := NewEphemeralKeySet()
+	require.NoError(t, err)
+
+	hmac := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims())
+	hmac.Header["kid"] = keys.SigningKeyID()
+	hmacToken, err := hmac.SignedString([]byte("secret"))
+	require.NoError(t, err)
+
+	none := jwt.NewWithClaims(jwt.SigningMethodNone, testClaims())
+	none.Header["kid"] = keys.SigningKeyID()
+	noneToken, err := none.SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+
+	otherToken, err := other.Sign(testClaims())
+	require.NoError(t, err)
+
+	expired, err := keys.Sign(&jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute))})
+	require.NoError(t, err)
+
+	noExpiry, err := keys.Sign(&jwt.RegisteredClaims{Subject: "user"})
+	require.NoError(t, err)
+
+	tests := map[string]string{
+		"HS256 with a known kid": hmacToken,
+		"alg none":               noneToken,
+		"unknown kid":            otherToken,
+		"expired":                expired,
+		"without expiry":         noExpiry,
+	}
+	for name, tokenString := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, keys.Parse(tokenString, &jwt.RegisteredClaims{}))
+		})
+	}
+}
+
+func TestLoadKeySet_Errors(t *testing.T) {
+	weakPrivate, _ := rsaKeyFiles(t, 1024)
+	private, public := ed25519KeyFiles(t)
+
+	tests := []struct {
+		name      string
+		signingID string
+		keys      []config.SigningKeyConfig
+	}{
+		{name: "RSA key under 2048 bits", signingID: "weak", keys: []config.SigningKeyConfig{{ID: "weak", PrivateKeyPath: weakPrivate}}},
+		{name: "signing key not configured", signingID: "missing", keys: []config.SigningKeyConfig{{ID: "a", PrivateKeyPath: private}}},
+		{name: "signing key without private key", signingID: "a", keys: []config.SigningKeyConfig{{ID: "a", PublicKeyPath: public}}},
+		{name: "duplicate id", signingID: "a", keys: []config.SigningKeyConfig{{ID: "a", PrivateKeyPath: private}, {ID: "a", PublicKeyPath: public}}},
+		{name: "missing id", signingID: "", keys: []config.SigningKeyConfig{{PrivateKeyPath: private}}},
+		{name: "no key path", signingID: "a", keys: []config.SigningKeyConfig{{ID: "a"}}},
+		{name: "unreadable file", signingID: "a", keys: []config.SigningKeyConfig{{ID: "a", PrivateKeyPath: filepath.Join(t.TempDir(), "missing.pem")}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadKeySet(tt.signingID, tt.keys)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -58,7 +58,7 @@ func TestPerformanceAuthServiceLogin(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			user, _, err := services.Auth.Login(tt.username, tt.password)
+			user, _, err := services.Auth.Login(tt.username, tt.password, "127.0.0.1", "req-123")
 
 			if tt.expectError {
 				assert.Error(t, err)
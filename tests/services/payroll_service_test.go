@@ -1,12 +1,15 @@
 package service_test
 
 import (
+	"payslip-system/internal/domains"
+	"sync"
 	"testing"
 	"time"
 
 	"payslip-system/internal/models"
 	"payslip-system/tests"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -89,3 +92,209 @@ func TestPerformancePayrollServiceGeneratePayslip(t *testing.T) {
 	expectedTotal := payslip.AttendanceAmount + payslip.OvertimeAmount + payslip.ReimbursementAmount
 	assert.Equal(t, expectedTotal, payslip.TotalAmount)
 }
+
+func TestProcessPayrollConcurrentRunsOnlyOneWins(t *testing.T) {
+	db, cleanup := tests.SetupTestDB()
+	defer cleanup()
+
+	repos, services := tests.SetupTestServices(db)
+
+	admin := &models.User{
+		Username: "testadmin",
+		Role:     "admin",
+		IsActive: true,
+	}
+	require.NoError(t, repos.User.Create(admin))
+
+	salary := 6000000.0
+	for i := 0; i < 5; i++ {
+		employee := &models.User{
+			Username: "concurrent" + string(rune('a'+i)),
+			Role:     "employee",
+			Salary:   &salary,
+			IsActive: true,
+		}
+		require.NoError(t, repos.User.Create(employee))
+	}
+
+	period := &models.AttendancePeriod{
+		StartDate: time.Now().AddDate(0, 0, -30),
+		EndDate:   time.Now().AddDate(0, 0, -1),
+	}
+	require.NoError(t, repos.AttendancePeriod.Create(period))
+
+	const runs = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, runs)
+	start := make(chan struct{})
+	for i := 0; i < runs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			errs <- services.Payroll.ProcessPayroll(period.ID, domains.UserActor(admin.ID), "127.0.0.1", "req-concurrent")
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		if err == nil {
+			succeeded++
+		}
+	}
+	assert.Equal(t, 1, succeeded)
+
+	var payrollCount int64
+	require.NoError(t, db.Model(&models.Payroll{}).Where("attendance_period_id = ?", period.ID).Count(&payrollCount).Error)
+	assert.Equal(t, int64(1), payrollCount)
+
+	var itemCount int64
+	require.NoError(t, db.Model(&models.PayrollItem{}).Count(&itemCount).Error)
+	assert.Equal(t, int64(5), itemCount)
+}
+
+func TestApprovePayroll(t *testing.T) {
+	db, cleanup := tests.SetupTestDB()
+	defer cleanup()
+
+	repos, services := tests.SetupTestServices(db)
+
+	users := map[string]*models.User{}
+	for _, username := range []string{"preparer", "checker", "otherchecker"} {
+		user := &models.User{Username: username, Role: "admin", IsActive: true}
+		require.NoError(t, repos.User.Create(user))
+		users[username] = user
+	}
+
+	cases := []struct {
+		name              string
+		status            string
+		requiredApprovals int
+		existing          []string
+		approver          string
+		apiKey            bool
+		wantStatus        string
+		wantErr           bool
+	}{
+		{name: "success - single approval finalizes", status: models.PayrollStatusPendingApproval, requiredApprovals: 1, approver: "checker", wantStatus: models.PayrollStatusApproved},
+		{name: "success - stays pending until enough approvals", status: models.PayrollStatusPendingApproval, requiredApprovals: 2, approver: "checker", wantStatus: models.PayrollStatusPendingApproval},
+		{name: "success - second approver finalizes", status: models.PayrollStatusPendingApproval, requiredApprovals: 2, existing: []string{"checker"}, approver: "otherchecker", wantStatus: models.PayrollStatusApproved},
+		{name: "error - preparer cannot approve own run", status: models.PayrollStatusPendingApproval, requiredApprovals: 1, approver: "preparer", wantErr: true},
+		{name: "error - same admin cannot approve twice", status: models.PayrollStatusPendingApproval, requiredApprovals: 2, existing: []string{"checker"}, approver: "checker", wantErr: true},
+		{name: "error - API keys cannot approve", status: models.PayrollStatusPendingApproval, requiredApprovals: 1, apiKey: true, wantErr: true},
+		{name: "error - already final", status: models.PayrollStatusApproved, requiredApprovals: 1, approver: "checker", wantErr: true},
+	}
+
+	for i, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			period := &models.AttendancePeriod{
+				StartDate:   time.Date(2024, time.Month(i+1), 1, 0, 0, 0, 0, time.UTC),
+				EndDate:     time.Date(2024, time.Month(i+2), 0, 0, 0, 0, 0, time.UTC),
+				IsProcessed: true,
+			}
+			require.NoError(t, repos.AttendancePeriod.Create(period))
+
+			payroll := &models.Payroll{
+				AttendancePeriodID: period.ID,
+				ProcessedBy:        users["preparer"].ID,
+				Status:             tt.status,
+				RequiredApprovals:  tt.requiredApprovals,
+			}
+			require.NoError(t, repos.Payroll.Create(payroll))
+			for _, username := range tt.existing {
+				require.NoError(t, repos.Payroll.CreateApproval(&models.PayrollApproval{
+					PayrollID:  payroll.ID,
+					ApproverID: users[username].ID,
+					Decision:   models.ApprovalDecisionApproved,
+				}))
+			}
+
+			actor := domains.APIKeyActor(uuid.New())
+			if !tt.apiKey {
+				actor = domains.UserActor(users[tt.approver].ID)
+			}
+
+			got, err := services.Payroll.ApprovePayroll(period.ID, actor, "looks good", "127.0.0.1", "req-123")
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, got)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, got.Status)
+			assert.Equal(t, tt.wantStatus == models.PayrollStatusApproved, got.FinalizedAt != nil)
+		})
+	}
+}
+
+func TestApprovePayrollConcurrentApprovalsFinalizeOnce(t *testing.T) {
+	db, cleanup := tests.SetupTestDB()
+	defer cleanup()
+
+	repos, services := tests.SetupTestServices(db)
+
+	preparer := &models.User{Username: "preparer", Role: "admin", IsActive: true}
+	require.NoError(t, repos.User.Create(preparer))
+
+	const approvers = 4
+	var checkers []*models.User
+	for i := 0; i < approvers; i++ {
+		checker := &models.User{Username: "checker" + string(rune('a'+i)), Role: "admin", IsActive: true}
+		require.NoError(t, repos.User.Create(checker))
+		checkers = append(checkers, checker)
+	}
+
+	period := &models.AttendancePeriod{
+		StartDate:   time.Now().AddDate(0, 0, -30),
+		EndDate:     time.Now().AddDate(0, 0, -1),
+		IsProcessed: true,
+	}
+	require.NoError(t, repos.AttendancePeriod.Create(period))
+	payroll := &models.Payroll{
+		AttendancePeriodID: period.ID,
+		ProcessedBy:        preparer.ID,
+		Status:             models.PayrollStatusPendingApproval,
+		RequiredApprovals:  2,
+	}
+	require.NoError(t, repos.Payroll.Create(payroll))
+
+	// Every checker approves twice at once
+	var wg sync.WaitGroup
+	errs := make(chan error, 2*approvers)
+	start := make(chan struct{})
+	for _, checker := range checkers {
+		for j := 0; j < 2; j++ {
+			wg.Add(1)
+			go func(checker *models.User) {
+				defer wg.Done()
+				<-start
+				_, err := services.Payroll.ApprovePayroll(period.ID, domains.UserActor(checker.ID), "", "127.0.0.1", "req-concurrent")
+				errs <- err
+			}(checker)
+		}
+	}
+	close(start)
+	wg.Wait()
+	close(errs)
+
+	// The first two approvals finalize the run, every later one finds it final
+	succeeded := 0
+	for err := range errs {
+		if err == nil {
+			succeeded++
+		}
+	}
+	assert.Equal(t, 2, succeeded)
+
+	var approvalCount int64
+	require.NoError(t, db.Model(&models.PayrollApproval{}).Where("payroll_id = ?", payroll.ID).Count(&approvalCount).Error)
+	assert.Equal(t, int64(2), approvalCount)
+
+	run, err := repos.Payroll.GetRunByPeriodID(period.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.PayrollStatusApproved, run.Status)
+}
@@ -38,8 +38,27 @@ func SetupTestDB() (*gorm.DB, func()) {
 	cleanup := func() {
 		// Clean up test data
 		db.Exec("TRUNCATE TABLE audit_logs CASCADE")
+		db.Exec("TRUNCATE TABLE user_roles CASCADE")
+		db.Exec("DELETE FROM roles WHERE builtin = false")
+		db.Exec("TRUNCATE TABLE password_reset_tokens CASCADE")
+		db.Exec("TRUNCATE TABLE password_histories CASCADE")
+		db.Exec("TRUNCATE TABLE login_throttles CASCADE")
+		db.Exec("TRUNCATE TABLE mfa_recovery_codes CASCADE")
+		db.Exec("TRUNCATE TABLE mfa_challenges CASCADE")
+		db.Exec("TRUNCATE TABLE sso_logins CASCADE")
+		db.Exec("TRUNCATE TABLE api_key_permissions CASCADE")
+		db.Exec("TRUNCATE TABLE api_keys CASCADE")
+		db.Exec("TRUNCATE TABLE refresh_tokens CASCADE")
+		db.Exec("TRUNCATE TABLE sessions CASCADE")
+		db.Exec("TRUNCATE TABLE idempotency_keys CASCADE")
+		db.Exec("TRUNCATE TABLE languages CASCADE")
+		db.Exec("TRUNCATE TABLE email_deliveries CASCADE")
+		db.Exec("TRUNCATE TABLE bank_transactions CASCADE")
+		db.Exec("TRUNCATE TABLE tax_certificate_issues CASCADE")
+		db.Exec("TRUNCATE TABLE payroll_approvals CASCADE")
 		db.Exec("TRUNCATE TABLE payroll_items CASCADE")
 		db.Exec("TRUNCATE TABLE payrolls CASCADE")
+		db.Exec("TRUNCATE TABLE leaves CASCADE")
 		db.Exec("TRUNCATE TABLE reimbursements CASCADE")
 		db.Exec("TRUNCATE TABLE overtimes CASCADE")
 		db.Exec("TRUNCATE TABLE attendances CASCADE")
@@ -55,6 +74,6 @@ func SetupTestDB() (*gorm.DB, func()) {
 
 func SetupTestServices(db *gorm.DB) (*repository.Repositories, *providers.Services) {
 	repos := repository.NewRepositories(db)
-	services := providers.NewServices(repos)
+	services := providers.NewServices(repos, config.Load())
 	return repos, services
 }